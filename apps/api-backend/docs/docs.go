@@ -23,27 +23,26 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/registration-node-tokens": {
+        "/admin/admins": {
             "get": {
                 "security": [
                     {
                         "AdminAuth": []
                     }
                 ],
-                "description": "Return all registration tokens (active, expired, used)",
+                "description": "List the admin emails stored in the database. While none are stored, the ADMIN_EMAILS bootstrap list is authorized instead (bootstrap_active=true).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "List all tokens",
+                "summary": "List admin emails",
                 "responses": {
                     "200": {
-                        "description": "List with tokens array and count",
+                        "description": "Admin allowlist",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/services.AdminListResponse"
                         }
                     },
                     "500": {
@@ -60,7 +59,7 @@ const docTemplate = `{
                         "AdminAuth": []
                     }
                 ],
-                "description": "Create new registration token with optional expiration, usage limit, and MAC authorization",
+                "description": "Authorize an email address as admin. The first stored admin ends the ADMIN_EMAILS bootstrap, so add your own email first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,27 +69,45 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Create registration token",
+                "summary": "Add admin email",
                 "parameters": [
                     {
-                        "description": "Token configuration",
+                        "description": "Admin email",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/services.CreateTokenRequest"
+                            "$ref": "#/definitions/services.AddAdminRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Token created",
+                        "description": "Admin email added",
                         "schema": {
-                            "$ref": "#/definitions/services.CreateTokenResponse"
+                            "$ref": "#/definitions/services.AdminUserResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or validation error",
+                        "description": "Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Admin email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -104,27 +121,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/registration-node-tokens/active": {
-            "get": {
+        "/admin/admins/{email}": {
+            "delete": {
                 "security": [
                     {
                         "AdminAuth": []
                     }
                 ],
-                "description": "Return only non-expired tokens with remaining uses",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Remove an email address from the admin allowlist. Removing the last stored admin re-enables the ADMIN_EMAILS bootstrap list.",
                 "tags": [
                     "admin"
                 ],
-                "summary": "List active tokens",
+                "summary": "Remove admin email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "List with tokens array and count",
+                    "204": {
+                        "description": "Admin email removed"
+                    },
+                    "400": {
+                        "description": "Invalid email",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Admin email not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -136,27 +167,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/registration-node-tokens/cleanup": {
-            "post": {
+        "/admin/audit-log": {
+            "get": {
                 "security": [
                     {
                         "AdminAuth": []
                     }
                 ],
-                "description": "Remove all expired tokens from database",
+                "description": "Return security-relevant admin actions (admin secret rotation, node token revocation, cleanup pause/resume) filtered by action, admin, target and time range, one page at a time",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Cleanup expired tokens",
+                "summary": "Query the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Action (e.g. node_token.revoked)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin who performed the action",
+                        "name": "admin_email",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "admin",
+                            "node",
+                            "token",
+                            "cleanup",
+                            "mac"
+                        ],
+                        "type": "string",
+                        "description": "Target type",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target ID (e.g. node UUID)",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, inclusive (UTC RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest time, inclusive (UTC RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Entries per page (1-200)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort by time",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Cleanup results with deleted count",
+                        "description": "Audit log page",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/services.AuditLogListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -168,27 +274,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/registration-node-tokens/statistics": {
-            "get": {
+        "/admin/auth/inspect": {
+            "post": {
                 "security": [
                     {
                         "AdminAuth": []
                     }
                 ],
-                "description": "Return statistics about registration tokens (total, active, expired counts)",
+                "description": "Troubleshoot \"my token doesn't work\" reports: decode an admin JWT and report its claims and each check separately (signature, expiry, issued by this server, email on the allowlist), with the reasons it would be rejected.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "admin-auth"
+                ],
+                "summary": "Inspect an admin token",
+                "parameters": [
+                    {
+                        "description": "Token to inspect",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminTokenInspectRequest"
+                        }
+                    }
                 ],
-                "summary": "Get token statistics",
                 "responses": {
                     "200": {
-                        "description": "Token statistics",
+                        "description": "Decoded token and check results",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/services.AdminTokenInspectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token cannot be decoded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -196,43 +333,67 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/admin/registration-node-tokens/{token}": {
-            "get": {
-                "security": [
-                    {
-                        "AdminAuth": []
-                    }
+        "/admin/auth/reissue": {
+            "post": {
+                "description": "Recovery path when an admin's primary inbox is unavailable: email a login token to the admin's secondary address configured in ADMIN_SECONDARY_EMAILS (or explicitly to the primary). Both addresses must be authorized admins; the token authenticates as the address it is sent to. Each address may receive one token per token lifetime (ADMIN_TOKEN_TTL_HOURS). The response is the same whether or not a token was sent.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Return details of specific registration token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "admin-auth"
                 ],
-                "summary": "Get token details",
+                "summary": "Reissue admin login token to another address",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Token value",
-                        "name": "token",
-                        "in": "path",
-                        "required": true
+                        "description": "Admin email and delivery target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminTokenReissueRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Token details",
+                    "202": {
+                        "description": "Request accepted",
                         "schema": {
-                            "$ref": "#/definitions/services.TokenListResponse"
+                            "$ref": "#/definitions/handlers.AdminTokenRequestResponse"
                         }
                     },
-                    "404": {
-                        "description": "Token not found",
+                    "400": {
+                        "description": "Invalid email or delivery target",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "A token was already sent to the selected address within the token lifetime; Retry-After gives the seconds until the next request is allowed",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -242,35 +403,67 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "AdminAuth": []
-                    }
+            }
+        },
+        "/admin/auth/request": {
+            "post": {
+                "description": "Email an admin login token, valid for ADMIN_TOKEN_TTL_HOURS (default 24), to an authorized admin address. The response is the same whether or not the email is authorized. Each email may request one token per token lifetime.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Permanently remove registration token",
                 "tags": [
-                    "admin"
+                    "admin-auth"
                 ],
-                "summary": "Delete token",
+                "summary": "Request admin login token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Token value",
-                        "name": "token",
-                        "in": "path",
-                        "required": true
+                        "description": "Admin email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Token deleted"
+                    "202": {
+                        "description": "Request accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdminTokenRequestResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Token not found",
+                    "400": {
+                        "description": "Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "A token was already sent within the token lifetime; Retry-After gives the seconds until the next request is allowed",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -280,13 +473,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/nodes/register": {
+        "/admin/auth/resend": {
             "post": {
-                "description": "Register a new node or re-register existing node using registration token. Returns UUID and JWT for authentication.",
+                "description": "For an admin who did not receive the login email: email the still-valid token from the last request again instead of waiting out the one-token-per-lifetime limit. The same token is sent, so its expiry and the rate-limit window are unchanged. Each token can be resent 3 times; further resends are accepted but send nothing. Only available with ADMIN_LOGIN_METHOD=token. The response is the same whether or not a token was sent.",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,47 +493,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nodes"
+                    "admin-auth"
                 ],
-                "summary": "Register a new IoT device",
+                "summary": "Resend the current admin login token",
                 "parameters": [
                     {
-                        "description": "Registration data with token and MAC address",
+                        "description": "Admin email",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/services.RegistrationRequest"
+                            "$ref": "#/definitions/services.AdminTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Re-registration successful",
-                        "schema": {
-                            "$ref": "#/definitions/services.RegistrationResponse"
-                        }
-                    },
-                    "201": {
-                        "description": "New node registered",
+                    "202": {
+                        "description": "Request accepted",
                         "schema": {
-                            "$ref": "#/definitions/services.RegistrationResponse"
+                            "$ref": "#/definitions/handlers.AdminTokenRequestResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or validation error",
+                        "description": "Invalid email, or the login method is code",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid, expired, or unauthorized token",
+                    "413": {
+                        "description": "Request body too large",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Node is revoked",
+                    "415": {
+                        "description": "Content-Type is not application/json",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -344,171 +537,4408 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/ping": {
-            "get": {
-                "description": "Simple health check endpoint",
+        "/admin/auth/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Incident response when the admin signing key may be compromised: replace the admin JWT secret with a new random one and revoke every issued admin token. This logs out all admins, including the caller, who may then request a new login token immediately. Requires {\"confirm\": true}. The new secret is returned once and kept in memory only; store it as ADMIN_JWT_SECRET in your secret store, or a restart reverts to the previous secret. The rotation is recorded in the audit log.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin-auth"
                 ],
-                "summary": "Health check",
-                "responses": {
+                "summary": "Rotate the admin JWT secret",
+                "parameters": [
+                    {
+                        "description": "Confirmation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminSecretRotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
                     "200": {
-                        "description": "Service is healthy",
+                        "description": "Secret rotated",
                         "schema": {
-                            "$ref": "#/definitions/models.HealthResponse"
+                            "$ref": "#/definitions/services.AdminSecretRotationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Confirmation missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Admin login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/verify": {
+            "post": {
+                "description": "When ADMIN_LOGIN_METHOD=code, login emails carry a short numeric code instead of the token. Exchange it here for a 24-hour admin token. Codes are single-use and expire after 10 minutes; after 5 wrong codes the current code is locked until it expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin-auth"
+                ],
+                "summary": "Exchange a login code for an admin token",
+                "parameters": [
+                    {
+                        "description": "Admin email and login code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminCodeVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Admin token",
+                        "schema": {
+                            "$ref": "#/definitions/services.AdminCodeVerifyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid email or code format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Code is wrong, expired, locked or already used",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Admin login or login codes are not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cleanup/last-run": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Report the expired tokens removed by the most recent background cleanup run: their IDs, creation and expiry dates, and the total removed. Up to 1000 tokens are listed. The report is kept in memory and resets on restart.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Last automatic cleanup run",
+                "responses": {
+                    "200": {
+                        "description": "Last cleanup run",
+                        "schema": {
+                            "$ref": "#/definitions/services.CleanupRunResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No cleanup has run since the server started",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cleanup/pause": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Stop the background cleanup from deleting expired tokens and revocation entries, e.g. to preserve evidence during an incident. The flag is persisted and survives restarts. Cleanup on demand via POST /admin/registration-node-tokens/cleanup still works.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause automatic cleanup",
+                "responses": {
+                    "200": {
+                        "description": "Cleanup state",
+                        "schema": {
+                            "$ref": "#/definitions/services.CleanupStateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cleanup/resume": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Re-enable the background cleanup; the next run happens at the next interval",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume automatic cleanup",
+                "responses": {
+                    "200": {
+                        "description": "Cleanup state",
+                        "schema": {
+                            "$ref": "#/definitions/services.CleanupStateResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/db/version": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return the applied schema version, the version this build expects and every applied migration step",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Database schema version",
+                "responses": {
+                    "200": {
+                        "description": "Schema version",
+                        "schema": {
+                            "$ref": "#/definitions/services.SchemaVersionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.ErrorResponse": {
+        },
+        "/admin/email/test": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Verify the SMTP configuration by sending a short test email to an authorized admin address (by default the logged-in admin). Returns the mail server's error when delivery fails. The body is optional. One test email may be sent per minute.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Send a test email",
+                "parameters": [
+                    {
+                        "description": "Recipient",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/services.EmailTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test email accepted by the mail server",
+                        "schema": {
+                            "$ref": "#/definitions/services.EmailTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Recipient missing or not an authorized admin",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "A test email was sent less than a minute ago; Retry-After gives the seconds to wait",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Mail server rejected the email or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Email is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/stream": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream pushing a message whenever a node registers, re-registers, or changes status. Each message's event name is the event type and its data is an events.Event JSON object. Slow clients may miss events.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Stream fleet events",
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/mac-denylist": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return every MAC address banned from registering, most recently banned first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List denylisted MACs",
+                "responses": {
+                    "200": {
+                        "description": "Denylisted MAC addresses",
+                        "schema": {
+                            "$ref": "#/definitions/services.DeniedMACListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Ban a MAC address from registering, even after its node is deleted. Registration attempts are refused with 403 MAC_DENIED. A node that already exists keeps its JWT until it re-registers; revoke it to cut it off at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Denylist a MAC",
+                "parameters": [
+                    {
+                        "description": "MAC address to ban",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.DenyMACRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Denylist entry",
+                        "schema": {
+                            "$ref": "#/definitions/services.DeniedMACResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid MAC address or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "MAC address already denylisted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/mac-denylist/{mac}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Lift the registration ban on a MAC address. Any notation of the address is accepted.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove a MAC from the denylist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "MAC address",
+                        "name": "mac",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "MAC address removed from the denylist"
+                    },
+                    "400": {
+                        "description": "Invalid MAC address",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "MAC address is not denylisted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return registered nodes, newest first, one page at a time. Node secrets are never included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (active, disabled, revoked)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only nodes of this owner (tenant)",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Nodes per page (1-200)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nodes",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status, owner or page parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/by-mac/{mac}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Look up a node by the MAC address printed on its label. Colon, hyphen, dot and plain notations are accepted in any case.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Find node by MAC address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "aa-bb-cc-dd-ee-ff",
+                        "description": "MAC address",
+                        "name": "mac",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include the node's metadata",
+                        "name": "include_metadata",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid MAC address or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Report nodes sharing GPS coordinates, nodes sharing a name, and MAC addresses that re-registered unusually often",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Node de-duplication report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Minimum re-registrations for a MAC to be reported",
+                        "name": "re_registration_threshold",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duplicate report",
+                        "schema": {
+                            "$ref": "#/definitions/services.DuplicateReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/firmware-distribution": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return the number of nodes per reported firmware version. Nodes without a version are counted under \"unknown\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Firmware version distribution",
+                "responses": {
+                    "200": {
+                        "description": "Node counts by firmware version",
+                        "schema": {
+                            "$ref": "#/definitions/services.FirmwareDistributionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Create nodes from another system without consuming registration tokens. All records are inserted in one transaction; if any record fails, nothing is imported and the per-record report is returned with status 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import nodes in bulk",
+                "parameters": [
+                    {
+                        "description": "Nodes to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.ImportNodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Import committed",
+                        "schema": {
+                            "$ref": "#/definitions/services.ImportNodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Import rolled back, see per-record results",
+                        "schema": {
+                            "$ref": "#/definitions/services.ImportNodesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/registrations-by-day": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return how many nodes were first registered on each UTC day over the last N days, oldest first. Days without registrations are included with a zero count; re-registrations are not counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Node registrations per day",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Number of days including today (1-365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Daily node registrations",
+                        "schema": {
+                            "$ref": "#/definitions/services.RegistrationsByDayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid days parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return a single node by UUID. The node secret is never included. The response carries a Last-Modified header from the node's updated_at; send it back as If-Modified-Since to get 304 while the node is unchanged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified value of a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Node details",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Node unchanged since If-Modified-Since"
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Permanently remove a node together with registration tokens pre-authorized for its MAC address and its registration events. This cannot be undone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Permanently delete node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed node and related records",
+                        "schema": {
+                            "$ref": "#/definitions/services.ForceDeleteNodeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Update any subset of name, target firmware version, location and status in one request. Omitted or null fields are left unchanged; an empty string clears name or target firmware. Latitude and longitude must be sent together. All present fields are validated before anything is written.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update node fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.PatchNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid field value or no fields given",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name already used by another active node, or node is revoked",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/location": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Set the GPS coordinates of a node",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set node location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New coordinates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.UpdateNodeLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return all admin-assigned key/value metadata of a node",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get node metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Node metadata",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeMetadataResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/metadata/{key}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Create or update one metadata key of a node. Keys are up to 64 letters, digits, '_', '.' or '-'; values are up to 1024 characters. A node may have at most NODE_METADATA_MAX_KEYS keys.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set node metadata key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "site_code",
+                        "description": "Metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metadata value",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.SetNodeMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All metadata of the node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeMetadataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key or value",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Node already has the maximum number of keys",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Remove one metadata key of a node",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete node metadata key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "site_code",
+                        "description": "Metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Key deleted"
+                    },
+                    "404": {
+                        "description": "Node or key not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/name": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Set the display name of a node. Send null or an empty string to clear it. When UNIQUE_NODE_NAMES is enabled, a name used by another active node is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rename node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New node name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.RenameNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid name",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name already used by another active node",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/revoke-token": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Add one issued node JWT, identified by its jti claim, to the revocation list. Only that token is rejected from now on; the node keeps working after it re-registers. Entries are removed by the background cleanup once the token would have expired anyway.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke a node JWT",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Token ID to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.RevokeNodeTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revocation entry",
+                        "schema": {
+                            "$ref": "#/definitions/services.RevokedNodeTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid jti",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Token ID already revoked for another node",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/status": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Set a node to active, disabled or revoked. Disabled and revoked nodes cannot authenticate. Revocation is permanent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change node status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.UpdateNodeStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Node is revoked",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/nodes/{uuid}/target-firmware": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Set the firmware version a node should update to. It is returned in the node's heartbeat response while newer than the reported version. Send null to clear.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set node target firmware",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target firmware version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.SetTargetFirmwareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated node",
+                        "schema": {
+                            "$ref": "#/definitions/services.NodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version or not newer than current firmware",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Node not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return all registration tokens (active, expired, used)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List all tokens",
+                "responses": {
+                    "200": {
+                        "description": "List with tokens array and count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Create new registration token with optional expiration, usage limit, and MAC authorization.\nWith an Idempotency-Key header, a retried request returns the originally created token (200) instead of creating another one. Keys are scoped to the authenticated admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create registration token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client-generated key (max 255 characters) making retries safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Token configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Retry of an earlier request; original token returned",
+                        "schema": {
+                            "$ref": "#/definitions/services.CreateTokenResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Token created",
+                        "schema": {
+                            "$ref": "#/definitions/services.CreateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or validation error, or an unknown field with STRICT_JSON_REQUESTS",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with this Idempotency-Key still in progress, or MAX_ACTIVE_TOKENS reached",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key already used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Delete every token matching the filters in one transaction and return how many were removed. At least one filter is required; filters are combined. While the undo window is configured the tokens are soft-deleted and can be restored one by one. Recorded in the audit log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete tokens by filter",
+                "parameters": [
+                    {
+                        "enum": [
+                            "active",
+                            "expired",
+                            "exhausted"
+                        ],
+                        "type": "string",
+                        "description": "Token state",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tokens created before this UTC timestamp (RFC3339)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact owner (tenant) of the tokens",
+                        "name": "owner_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens deleted",
+                        "schema": {
+                            "$ref": "#/definitions/services.DeleteTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid filters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/active": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return only non-expired tokens with remaining uses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List active tokens",
+                "responses": {
+                    "200": {
+                        "description": "List with tokens array and count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/cleanup": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Remove all expired tokens from database. With dry_run=true nothing is deleted and the number of tokens that would be removed is returned; add include_ids=true to also list their IDs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cleanup expired tokens",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Preview the cleanup without deleting",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "With dry_run, list the IDs of tokens that would be deleted",
+                        "name": "include_ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cleanup results with deleted count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/revoke-all": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Breach kill switch: make every unexpired registration token expire now, in one statement. Tokens stay listed with their usage and registered nodes; no node can register until a new token is created. Requires {\"confirm\": true}. Recorded in the audit log as a high-severity event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke all tokens",
+                "parameters": [
+                    {
+                        "description": "Confirmation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.RevokeAllTokensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens revoked",
+                        "schema": {
+                            "$ref": "#/definitions/services.RevokeAllTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Confirmation missing",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/search": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Find tokens whose description or creating admin contains the search terms (case-insensitive, wildcards match literally), optionally of one owner, newest first, one page at a time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Search tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to find in the description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to find in the creating admin email",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact owner (tenant) of the tokens",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Tokens per page (1-200)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching tokens",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid search terms",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return statistics about registration tokens: total, active, expired and exhausted counts, the split into single-use, multi-use and unlimited tokens, total and average uses per token, and the configured maximum uses per token (MAX_TOKEN_USES)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get token statistics",
+                "responses": {
+                    "200": {
+                        "description": "Token statistics",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/statistics/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return daily counts of tokens created and consumed (registrations) over the last N days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get token statistics timeline",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Number of days including today (1-365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Daily token activity",
+                        "schema": {
+                            "$ref": "#/definitions/services.StatisticsTimelineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid days parameter",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/{token}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return details of specific registration token. The secret value is not included, only its fingerprint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get token details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token details",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Delete a registration token. It stops working immediately but can be restored with POST /admin/registration-node-tokens/{token}/restore within TOKEN_DELETE_RETENTION_HOURS (24 by default); cleanup then removes it permanently. With a retention of 0 the token is removed at once.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Token deleted"
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Raise or lower a token's max_uses, e.g. to extend a batch token when a provisioning run grows. The new limit may not be below the number of uses already made, nor above MAX_TOKEN_USES.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update token usage limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New usage limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.UpdateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated token",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit or below the used count",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/{token}/expire": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Stop a registration token immediately by setting its expiry to now. Unlike deletion the token stays listed with its usage history and registered nodes. Expiring an already expired token changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Expire token now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Expired token",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/{token}/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return the nodes registered or re-registered with a registration token, oldest first, one page at a time. Answers \"which devices came from this batch?\". Node secrets are never included. Nodes of a deleted token can still be listed by the token ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List nodes registered with a token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Nodes per page (1-200)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nodes registered with the token",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenNodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid page parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/{token}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Restore a deleted registration token within the undo window (TOKEN_DELETE_RETENTION_HOURS). The token keeps its expiry and remaining uses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Undo token deletion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored token",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No deleted token found, or the undo window has passed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/registration-node-tokens/{token}/reveal": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return the full secret value of a registration token. List and detail endpoints only expose a fingerprint.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reveal token value",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID or value",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token value",
+                        "schema": {
+                            "$ref": "#/definitions/services.TokenRevealResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/summary": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return node counts by status, inactive nodes, token counts and the last background cleanup time in one call",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Dashboard summary",
+                "responses": {
+                    "200": {
+                        "description": "Dashboard counts",
+                        "schema": {
+                            "$ref": "#/definitions/services.SummaryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check the database, that JWT_ENCRYPTION_KEY still decrypts stored node secrets, and, when enabled, email (SMTP) reachability. Email results are cached briefly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Subsystem health check",
+                "responses": {
+                    "200": {
+                        "description": "All checks passed",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "At least one check failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Report counters in the Prometheus text exposition format. boomchecker_token_validations_total counts registration token checks by endpoint (register, mac_status) and outcome (valid, not_found, expired, exhausted, mac_mismatch, mac_limit). Counters restart from zero with the server.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Metrics",
+                "responses": {
+                    "200": {
+                        "description": "Prometheus metrics",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/heartbeat": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lets an authenticated node report that it is alive. Updates the node's last-seen timestamp.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Node heartbeat",
+                "responses": {
+                    "200": {
+                        "description": "Heartbeat accepted",
+                        "schema": {
+                            "$ref": "#/definitions/services.HeartbeatResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid node JWT",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Node is revoked or disabled",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/register": {
+            "post": {
+                "description": "Register a new node or re-register existing node using registration token. Returns UUID and JWT for authentication.\nWith NODE_JWT_ONE_TIME set, only the first registration returns a JWT; re-registrations update the node and return jwt_issued false unless force_reissue is true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Register a new IoT device",
+                "parameters": [
+                    {
+                        "description": "Registration data with token and MAC address",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.RegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Re-registration successful",
+                        "schema": {
+                            "$ref": "#/definitions/services.RegistrationResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "New node registered",
+                        "schema": {
+                            "$ref": "#/definitions/services.RegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or validation error, or an unknown field with STRICT_JSON_REQUESTS",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired, or unauthorized token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Node is revoked, MAC is denylisted, MAC manufacturer (OUI) is not allowed, or node belongs to another owner",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Node already registered and token does not allow re-registration, or firmware downgrade rejected",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Node re-registered within RE_REGISTRATION_COOLDOWN_SECONDS; Retry-After gives the seconds until it may re-register",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Stored node secret cannot be decrypted with the configured JWT_ENCRYPTION_KEY",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/status": {
+            "get": {
+                "description": "Tells a provisioning tool whether a MAC is already registered and its status before it attempts registration. Requires a valid registration token in the X-Registration-Token header; a token pre-authorized for another MAC is rejected. No token use is consumed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Check whether a MAC is registered",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Valid registration token",
+                        "name": "X-Registration-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "AA:BB:CC:DD:EE:FF",
+                        "description": "MAC address",
+                        "name": "mac",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.MACStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid MAC address",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid, expired, or unauthorized token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/telemetry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lets an authenticated node report battery, signal strength and uptime. The reading replaces the node's previous one. Only schema_version 1 is accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Report node telemetry",
+                "parameters": [
+                    {
+                        "description": "Telemetry reading",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.TelemetryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Telemetry stored",
+                        "schema": {
+                            "$ref": "#/definitions/services.TelemetryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or unsupported schema version",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid node JWT",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Node is revoked or disabled",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "description": "Download the generated OpenAPI (Swagger) JSON document describing this API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "OpenAPI specification",
+                "responses": {
+                    "200": {
+                        "description": "OpenAPI document",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Specification not available",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Simple health check endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Service is healthy",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Report whether startup completed: database migrated and still reachable, background cleanup started once the server accepts requests. Returns 200 only when every step is done, so orchestrators can hold traffic until then. /ping only says the process is up.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "Ready to serve",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Startup incomplete or a subsystem is unreachable",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Report the version, git commit and build time of the running server, plus its Go runtime version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Build information",
+                "responses": {
+                    "200": {
+                        "description": "Build information",
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "node_uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "node.registered"
+                }
+            }
+        },
+        "handlers.AdminTokenRequestResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "If the email is authorized, a login token has been sent"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "VALIDATION_FAILED",
+                        "MAC_INVALID",
+                        "PAYLOAD_TOO_LARGE",
+                        "UNSUPPORTED_MEDIA_TYPE",
+                        "TOKEN_NOT_FOUND",
+                        "TOKEN_EXPIRED",
+                        "TOKEN_EXHAUSTED",
+                        "TOKEN_MAC_MISMATCH",
+                        "TOKEN_LIMIT_REACHED",
+                        "TOKEN_MAC_LIMIT",
+                        "NODE_NOT_FOUND",
+                        "NODE_REVOKED",
+                        "NODE_DISABLED",
+                        "NODE_ALREADY_REGISTERED",
+                        "MAC_DENIED",
+                        "OUI_NOT_ALLOWED",
+                        "OWNER_MISMATCH",
+                        "FIRMWARE_DOWNGRADE",
+                        "UNAUTHORIZED",
+                        "FORBIDDEN",
+                        "RATE_LIMITED",
+                        "ENCRYPTION_KEY_MISMATCH",
+                        "IDEMPOTENCY_KEY_REUSED",
+                        "NOT_FOUND",
+                        "CONFLICT",
+                        "SERVICE_UNAVAILABLE",
+                        "INTERNAL_ERROR"
+                    ],
+                    "example": "TOKEN_EXPIRED"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields maps each invalid request field (JSON name) to what is wrong with it\nOnly set on request body binding errors",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "retry_after": {
+                    "description": "RetryAfter is the number of seconds to wait before retrying, also sent as the Retry-After header\nOnly set on rate-limit errors that know when the limit clears",
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "models.HealthCheckResult": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "dial tcp: connection refused"
+                },
+                "status": {
+                    "description": "\"ok\" or \"error\"",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks holds per-subsystem results (only returned by /health and /ready)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.HealthCheckResult"
+                    }
+                },
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VersionResponse": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "description": "UTC timestamp (RFC3339 format) or \"unknown\"",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "commit": {
+                    "type": "string",
+                    "example": "1effd3b2c4a5e6f7a8b9c0d1e2f3a4b5c6d7e8f9"
+                },
+                "go_version": {
+                    "type": "string",
+                    "example": "go1.24.0"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "0.0.1"
+                }
+            }
+        },
+        "repositories.MACEventCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "mac_address": {
+                    "type": "string"
+                },
+                "node_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.AddAdminRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                }
+            }
+        },
+        "services.AdminCodeVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "042917"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                }
+            }
+        },
+        "services.AdminCodeVerifyResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-11T14:30:00Z"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "services.AdminListResponse": {
+            "type": "object",
+            "properties": {
+                "admins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AdminUserResponse"
+                    }
+                },
+                "bootstrap_active": {
+                    "description": "BootstrapActive is true while the table is empty and ADMIN_EMAILS is used instead",
+                    "type": "boolean",
+                    "example": false
+                },
+                "bootstrap_emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ops@example.com"
+                    ]
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "services.AdminSecretRotationRequest": {
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "description": "Confirm must be true; rotating logs out every admin, including the caller",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "services.AdminSecretRotationResponse": {
+            "type": "object",
+            "properties": {
+                "jwt_secret": {
+                    "description": "JWTSecret is the new signing secret; store it as ADMIN_JWT_SECRET or a restart reverts to the old one",
+                    "type": "string",
+                    "example": "q5mZ8c2T1u8yJcWm3rB9sVx7Qp0aLkE4nHd6fGiOjRs="
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Admin JWT secret rotated; all admins must request a new login token"
+                },
+                "revoked_tokens": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "rotated_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                }
+            }
+        },
+        "services.AdminTokenInspectRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "services.AdminTokenInspectResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "email_authorized": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expired": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expires_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-11T14:30:00Z"
+                },
+                "issued_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "issued_by_server": {
+                    "description": "IssuedByServer is true when the token's hash is stored, i.e. it was sent by this server and not revoked",
+                    "type": "boolean",
+                    "example": true
+                },
+                "issuer": {
+                    "type": "string",
+                    "example": "boomchecker-api"
+                },
+                "jti": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "problems": {
+                    "description": "Problems lists why the token is rejected; empty when Valid is true",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "token has expired"
+                    ]
+                },
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "signature_valid": {
+                    "description": "SignatureValid is true when the token is signed with the current admin secret and carries the admin role",
+                    "type": "boolean",
+                    "example": true
+                },
+                "valid": {
+                    "description": "Valid is true when the token would be accepted by the admin API right now",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "services.AdminTokenReissueRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "lang": {
+                    "description": "If not provided, defaults to ADMIN_EMAIL_LANGUAGE",
+                    "type": "string",
+                    "example": "de"
+                },
+                "send_to": {
+                    "description": "If not provided, defaults to secondary",
+                    "type": "string",
+                    "enum": [
+                        "primary",
+                        "secondary"
+                    ],
+                    "example": "secondary"
+                }
+            }
+        },
+        "services.AdminTokenRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "lang": {
+                    "description": "If not provided, defaults to ADMIN_EMAIL_LANGUAGE",
+                    "type": "string",
+                    "example": "de"
+                }
+            }
+        },
+        "services.AdminUserResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                }
+            }
+        },
+        "services.AppliedMigrationResponse": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "custom_indexes"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "services.AuditLogListResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AuditLogResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "description": "Total is the number of entries matching the filters across all pages",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "node_token.revoked"
+                },
+                "admin_email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "created_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "details": {
+                    "type": "string",
+                    "example": "revoked token d355e2e1-9bb2-4c7c-9e20-38a90c72f136"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "target_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "node"
+                }
+            }
+        },
+        "services.CleanedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "expires_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-11T14:30:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.CleanupRunResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_revocations": {
+                    "description": "DeletedRevocations counts node JWT revocation entries removed because their tokens have expired",
+                    "type": "integer",
+                    "example": 0
+                },
+                "deleted_tokens": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "disabled_nodes": {
+                    "description": "DisabledNodes counts active nodes disabled because they were not seen within InactiveNodeDisableAfter",
+                    "type": "integer",
+                    "example": 0
+                },
+                "error": {
+                    "description": "Error is set when the run stopped early; tokens removed before the failure are still listed",
+                    "type": "string",
+                    "example": "failed to cleanup expired tokens: database is locked"
+                },
+                "finished_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:01Z"
+                },
+                "purged_deleted_tokens": {
+                    "description": "PurgedDeletedTokens counts admin-deleted tokens removed for good after the undo window",
+                    "type": "integer",
+                    "example": 0
+                },
+                "started_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.CleanedTokenResponse"
+                    }
+                },
+                "truncated": {
+                    "description": "Truncated is true when more than MaxCleanupReportTokens tokens were removed and Tokens lists only the first ones",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "services.CleanupStateResponse": {
+            "type": "object",
+            "properties": {
+                "paused": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when cleanup was last paused or resumed; null if it never was",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "updated_by": {
+                    "description": "UpdatedBy is the admin who last paused or resumed cleanup",
+                    "type": "string",
+                    "example": "admin@example.com"
+                }
+            }
+        },
+        "services.CreateTokenRequest": {
+            "type": "object",
+            "properties": {
+                "allow_re_registration": {
+                    "description": "If not provided, defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "authorized_mac": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "custom_claims": {
+                    "description": "Signed into node JWTs under the \"ext\" claim",
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Token for production nodes"
+                },
+                "distinct_mac_limit": {
+                    "description": "Most distinct MACs that may register; if not provided, no cap",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50
+                },
+                "expires_in_hours": {
+                    "description": "If not provided, defaults to DEFAULT_TOKEN_EXPIRY_HOURS",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 24
+                },
+                "max_uses": {
+                    "description": "If not provided, defaults to 1",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "owner_id": {
+                    "description": "Tenant that registered nodes belong to",
+                    "type": "string",
+                    "example": "acme"
+                },
+                "token_format": {
+                    "description": "If not provided, defaults to base64url",
+                    "type": "string",
+                    "enum": [
+                        "base64url",
+                        "base32"
+                    ],
+                    "example": "base32"
+                }
+            }
+        },
+        "services.CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "allow_re_registration": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "authorized_mac": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "custom_claims": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Token for production nodes"
+                },
+                "distinct_mac_limit": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-11-11T14:30:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                }
+            }
+        },
+        "services.DeleteTokensResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_tokens": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Tokens deleted successfully"
+                },
+                "restorable": {
+                    "description": "Whether the tokens can be restored within the undo window",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "services.DeniedMACListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.DeniedMACResponse"
+                    }
+                }
+            }
+        },
+        "services.DeniedMACResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Device reported stolen"
+                }
+            }
+        },
+        "services.DenyMACRequest": {
+            "type": "object",
+            "required": [
+                "mac_address"
+            ],
+            "properties": {
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Device reported stolen"
+                }
+            }
+        },
+        "services.DuplicateReportResponse": {
+            "type": "object",
+            "properties": {
+                "duplicate_names": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.NodeGroup"
+                    }
+                },
+                "duplicate_names_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "frequent_re_registrations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repositories.MACEventCount"
+                    }
+                },
+                "frequent_re_registrations_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "re_registration_threshold": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "shared_coordinates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.NodeGroup"
+                    }
+                },
+                "shared_coordinates_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "services.EmailTestRequest": {
+            "type": "object",
+            "properties": {
+                "to": {
+                    "description": "To must be an authorized admin email; defaults to the logged-in admin",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "admin@example.com"
+                }
+            }
+        },
+        "services.EmailTestResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Test email accepted by the mail server"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "sent_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                }
+            }
+        },
+        "services.FirmwareDistributionResponse": {
+            "type": "object",
+            "properties": {
+                "total_nodes": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "versions": {
+                    "description": "firmware version -\u003e node count; \"unknown\" for nodes without a version",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "services.ForceDeleteNodeResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_events": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "deleted_token_ids": {
+                    "description": "Tokens pre-authorized for the node's MAC",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.HeartbeatResponse": {
+            "type": "object",
+            "properties": {
+                "server_time": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "target_firmware_version": {
+                    "description": "TargetFirmwareVersion is set when an admin scheduled a firmware newer than the node reports",
+                    "type": "string",
+                    "example": "1.1.0"
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.ImportNodeRecord": {
+            "type": "object",
+            "required": [
+                "mac_address"
+            ],
+            "properties": {
+                "firmware_version": {
+                    "type": "string",
+                    "example": "1.0.0"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 50.0755
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 14.4378
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Living Room Sensor"
+                }
+            }
+        },
+        "services.ImportNodeResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid MAC address format"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "status": {
+                    "description": "imported, skipped, failed, rolled_back",
+                    "type": "string",
+                    "example": "imported"
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.ImportNodesRequest": {
+            "type": "object",
+            "required": [
+                "nodes"
+            ],
+            "properties": {
+                "nodes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/services.ImportNodeRecord"
+                    }
+                },
+                "skip_duplicates": {
+                    "description": "SkipDuplicates skips records whose MAC already exists instead of failing the import",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "services.ImportNodesResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ImportNodeResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "services.MACStatusResponse": {
+            "type": "object",
+            "properties": {
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "registered": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "description": "Only set when registered",
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "services.NodeGroup": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "key": {
+                    "type": "string",
+                    "example": "50.075500,14.437800"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.NodeResponse"
+                    }
+                }
+            }
+        },
+        "services.NodeListResponse": {
+            "type": "object",
+            "properties": {
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.NodeResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "description": "Total is the number of matching nodes across all pages",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.NodeMetadataResponse": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "node_uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.NodeResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "firmware_version": {
+                    "type": "string",
+                    "example": "1.0.0"
+                },
+                "last_ip": {
+                    "description": "Client IP of the last registration",
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_registered_at": {
+                    "description": "Last registration or re-registration",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "last_seen_at": {
+                    "description": "Last authenticated request",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 50.0755
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 14.4378
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "metadata": {
+                    "description": "Metadata is only included when explicitly requested",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Living Room Sensor"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "target_firmware_version": {
+                    "type": "string",
+                    "example": "1.1.0"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.PatchNodeRequest": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "description": "Must be sent together with longitude",
+                    "type": "number",
+                    "example": 50.0755
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 14.4378
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Living Room Sensor"
+                },
+                "status": {
+                    "description": "active, disabled or revoked",
+                    "type": "string",
+                    "example": "disabled"
+                },
+                "target_firmware_version": {
+                    "type": "string",
+                    "example": "1.1.0"
+                }
+            }
+        },
+        "services.RegistrationDay": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "date": {
+                    "description": "UTC day (YYYY-MM-DD)",
+                    "type": "string",
+                    "example": "2025-11-10"
+                }
+            }
+        },
+        "services.RegistrationRequest": {
+            "type": "object",
+            "required": [
+                "mac_address",
+                "registration_token"
+            ],
+            "properties": {
+                "firmware_version": {
+                    "type": "string",
+                    "example": "1.0.0"
+                },
+                "force_reissue": {
+                    "description": "Return a JWT on re-registration even when NODE_JWT_ONE_TIME is set",
+                    "type": "boolean",
+                    "example": false
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 50.0755
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 14.4378
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "registration_token": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                }
+            }
+        },
+        "services.RegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "UTC timestamp when JWT expires (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-12-10T14:30:00Z"
+                },
+                "is_new_node": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "jwt_issued": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "jwt_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "mac_address": {
+                    "type": "string",
+                    "example": "AA:BB:CC:DD:EE:FF"
+                },
+                "uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "services.RegistrationsByDayResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.RegistrationDay"
+                    }
+                },
+                "total_nodes": {
+                    "description": "Nodes registered in the period",
+                    "type": "integer",
+                    "example": 57
+                }
+            }
+        },
+        "services.RenameNodeRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name is the new display name; null or empty clears it",
+                    "type": "string",
+                    "example": "Living Room Sensor"
+                }
+            }
+        },
+        "services.RevokeAllTokensRequest": {
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "description": "Confirm must be true; no node can register until a new token is created",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "services.RevokeAllTokensResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "All registration tokens revoked"
+                },
+                "revoked_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "revoked_tokens": {
+                    "description": "Tokens that were still unexpired",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "services.RevokeNodeTokenRequest": {
             "type": "object",
+            "required": [
+                "jti"
+            ],
             "properties": {
-                "error": {
-                    "type": "string"
+                "jti": {
+                    "description": "JTI is the jti claim of the token (readable from the token's payload)",
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                }
+            }
+        },
+        "services.RevokedNodeTokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is when the entry is removed from the revocation list; the token has expired by then",
+                    "type": "string",
+                    "example": "2025-12-10T14:30:00Z"
                 },
-                "message": {
-                    "type": "string"
+                "jti": {
+                    "type": "string",
+                    "example": "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+                },
+                "node_uuid": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "revoked_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
+                    "type": "string",
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "revoked_by": {
+                    "type": "string",
+                    "example": "admin@example.com"
                 }
             }
         },
-        "models.HealthResponse": {
+        "services.SchemaVersionResponse": {
             "type": "object",
             "properties": {
-                "service": {
-                    "type": "string"
+                "current_version": {
+                    "description": "CurrentVersion is the highest applied migration; 0 if none are recorded",
+                    "type": "integer",
+                    "example": 2
                 },
-                "status": {
-                    "type": "string"
+                "latest_version": {
+                    "description": "LatestVersion is the version this build migrates to",
+                    "type": "integer",
+                    "example": 2
                 },
-                "timestamp": {
-                    "type": "string"
+                "migrations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AppliedMigrationResponse"
+                    }
+                },
+                "up_to_date": {
+                    "description": "UpToDate is true when every migration known to this build has been applied",
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "services.CreateTokenRequest": {
+        "services.SetNodeMetadataRequest": {
             "type": "object",
             "required": [
-                "expires_in_hours"
+                "value"
             ],
             "properties": {
-                "authorized_mac": {
+                "value": {
                     "type": "string",
-                    "example": "AA:BB:CC:DD:EE:FF"
-                },
-                "description": {
+                    "example": "PRG-01"
+                }
+            }
+        },
+        "services.SetTargetFirmwareRequest": {
+            "type": "object",
+            "properties": {
+                "target_firmware_version": {
+                    "description": "TargetFirmwareVersion must be newer than the node's reported version; null clears the target",
                     "type": "string",
-                    "example": "Token for production nodes"
+                    "example": "1.1.0"
+                }
+            }
+        },
+        "services.StatisticsTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer",
+                    "example": 30
                 },
-                "expires_in_hours": {
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.TimelineDay"
+                    }
+                },
+                "total_tokens_consumed": {
                     "type": "integer",
-                    "minimum": 1,
-                    "example": 24
+                    "example": 310
                 },
-                "max_uses": {
-                    "description": "If not provided, defaults to 1",
+                "total_tokens_created": {
                     "type": "integer",
-                    "minimum": 1,
-                    "example": 1
+                    "example": 42
                 }
             }
         },
-        "services.CreateTokenResponse": {
+        "services.SummaryResponse": {
             "type": "object",
             "properties": {
-                "authorized_mac": {
-                    "type": "string",
-                    "example": "AA:BB:CC:DD:EE:FF"
+                "active_nodes": {
+                    "type": "integer",
+                    "example": 110
                 },
-                "created_at": {
-                    "type": "string",
-                    "example": "2025-11-10T14:30:00Z"
+                "active_tokens": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Token for production nodes"
+                "cleanup_paused": {
+                    "description": "CleanupPaused is true while an admin has paused automatic cleanup",
+                    "type": "boolean",
+                    "example": false
                 },
-                "expires_at": {
-                    "type": "string",
-                    "example": "2025-11-11T14:30:00Z"
+                "disabled_nodes": {
+                    "type": "integer",
+                    "example": 6
                 },
-                "max_uses": {
+                "expired_tokens": {
                     "type": "integer",
-                    "example": 1
+                    "example": 12
                 },
-                "token": {
+                "inactive_nodes": {
+                    "description": "InactiveNodes counts nodes of any status not seen within InactiveThresholdHours (or never seen)",
+                    "type": "integer",
+                    "example": 9
+                },
+                "inactive_threshold_hours": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "last_cleanup_at": {
+                    "description": "LastCleanupAt is when the last background token cleanup finished; null if none has run since startup",
                     "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                    "example": "2025-11-10T14:30:00Z"
+                },
+                "revoked_nodes": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "total_nodes": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "total_tokens": {
+                    "type": "integer",
+                    "example": 15
                 }
             }
         },
-        "services.RegistrationRequest": {
+        "services.TelemetryRequest": {
             "type": "object",
             "required": [
-                "mac_address",
-                "registration_token"
+                "schema_version"
             ],
             "properties": {
-                "firmware_version": {
-                    "type": "string",
-                    "example": "1.0.0"
-                },
-                "latitude": {
-                    "type": "number",
-                    "example": 50.0755
+                "battery_percent": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 87
                 },
-                "longitude": {
-                    "type": "number",
-                    "example": 14.4378
+                "rssi": {
+                    "description": "Signal strength in dBm",
+                    "type": "integer",
+                    "maximum": 0,
+                    "minimum": -150,
+                    "example": -67
                 },
-                "mac_address": {
-                    "type": "string",
-                    "example": "AA:BB:CC:DD:EE:FF"
+                "schema_version": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "registration_token": {
-                    "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                "uptime_seconds": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 86400
                 }
             }
         },
-        "services.RegistrationResponse": {
+        "services.TelemetryResponse": {
             "type": "object",
             "properties": {
-                "expires_at": {
-                    "description": "UTC timestamp when JWT expires (RFC3339 format)",
-                    "type": "string",
-                    "example": "2025-12-10T14:30:00Z"
-                },
-                "is_new_node": {
-                    "type": "boolean",
-                    "example": true
+                "battery_percent": {
+                    "type": "integer",
+                    "example": 87
                 },
-                "jwt_token": {
+                "node_uuid": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "mac_address": {
+                "reported_at": {
+                    "description": "UTC timestamp (RFC3339 format)",
                     "type": "string",
-                    "example": "AA:BB:CC:DD:EE:FF"
+                    "example": "2025-11-10T14:30:00Z"
                 },
-                "uuid": {
+                "rssi": {
+                    "type": "integer",
+                    "example": -67
+                },
+                "schema_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "uptime_seconds": {
+                    "type": "integer",
+                    "example": 86400
+                }
+            }
+        },
+        "services.TimelineDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "UTC day (YYYY-MM-DD)",
                     "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "2025-11-10"
+                },
+                "tokens_consumed": {
+                    "description": "Successful registrations and re-registrations",
+                    "type": "integer",
+                    "example": 12
+                },
+                "tokens_created": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
         "services.TokenListResponse": {
             "type": "object",
             "properties": {
+                "allow_re_registration": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "authorized_mac": {
                     "type": "string",
                     "example": "AA:BB:CC:DD:EE:FF"
@@ -517,14 +4947,29 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-11-10T14:30:00Z"
                 },
+                "created_by": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "custom_claims": {
+                    "type": "object"
+                },
                 "description": {
                     "type": "string",
                     "example": "Token for production nodes"
                 },
+                "distinct_mac_limit": {
+                    "type": "integer",
+                    "example": 50
+                },
                 "expires_at": {
                     "type": "string",
                     "example": "2025-11-11T14:30:00Z"
                 },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
                 "is_active": {
                     "type": "boolean",
                     "example": true
@@ -537,15 +4982,133 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
-                "token": {
+                "owner_id": {
                     "type": "string",
-                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                    "example": "acme"
+                },
+                "remaining_uses": {
+                    "description": "null for unlimited tokens, never negative",
+                    "type": "integer",
+                    "example": 1
+                },
+                "token_fingerprint": {
+                    "type": "string",
+                    "example": "a1b2c3d4...9f86d081"
                 },
                 "used_count": {
                     "type": "integer",
                     "example": 0
                 }
             }
+        },
+        "services.TokenNodesResponse": {
+            "type": "object",
+            "properties": {
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.NodeResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "token_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "total": {
+                    "description": "Total is the number of nodes registered with the token across all pages",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.TokenRevealResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                }
+            }
+        },
+        "services.TokenSearchResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.TokenListResponse"
+                    }
+                },
+                "total": {
+                    "description": "Total is the number of tokens matching the search across all pages",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.UpdateNodeLocationRequest": {
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude"
+            ],
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "example": 50.0755
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 14.4378
+                }
+            }
+        },
+        "services.UpdateNodeStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "active, disabled or revoked",
+                    "type": "string",
+                    "example": "disabled"
+                }
+            }
+        },
+        "services.UpdateTokenRequest": {
+            "type": "object",
+            "required": [
+                "max_uses"
+            ],
+            "properties": {
+                "max_uses": {
+                    "description": "MaxUses is the new usage limit; it may be lowered, but not below the token's used count",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50
+                }
+            }
         }
     },
     "securityDefinitions": {
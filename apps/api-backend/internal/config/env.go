@@ -0,0 +1,123 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetEnv reads a string from an environment variable
+// Returns fallback if the variable is not set or empty
+func GetEnv(key string, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvInt reads an integer from an environment variable
+// Returns fallback if the variable is not set or is not a valid integer
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("WARNING: invalid integer for %s=%q, using default %d", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+// GetEnvBool reads a boolean from an environment variable (true/false, 1/0, yes/no)
+// Returns fallback if the variable is not set or is not a valid boolean
+func GetEnvBool(key string, fallback bool) bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	switch value {
+	case "":
+		return fallback
+	case "true", "1", "yes", "on":
+		return true
+	case "false", "0", "no", "off":
+		return false
+	}
+
+	log.Printf("WARNING: invalid boolean for %s=%q, using default %t", key, value, fallback)
+	return fallback
+}
+
+// ListOptions controls how SplitList and GetEnvListOptions parse a list
+type ListOptions struct {
+	// Separator splits the entries; empty means ","
+	Separator string
+
+	// Lowercase folds every entry to lower case (e.g. for emails)
+	Lowercase bool
+}
+
+// SplitList splits value into trimmed entries, dropping empty ones
+// so a trailing or doubled separator never yields an empty entry.
+func SplitList(value string, opts ListOptions) []string {
+	separator := opts.Separator
+	if separator == "" {
+		separator = ","
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(value, separator) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if opts.Lowercase {
+			item = strings.ToLower(item)
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// GetEnvList reads a comma-separated list from an environment variable
+// Entries are trimmed and empty entries dropped; returns fallback if the variable is not set
+func GetEnvList(key string, fallback []string) []string {
+	return GetEnvListOptions(key, fallback, ListOptions{})
+}
+
+// GetEnvListOptions reads a list from an environment variable, parsed with SplitList
+// Returns fallback if the variable is not set
+func GetEnvListOptions(key string, fallback []string, opts ListOptions) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	return SplitList(value, opts)
+}
+
+// GetEnvMap reads comma-separated key=value pairs from an environment variable
+// Keys and values are trimmed; malformed entries are skipped with a warning.
+// Returns fallback if the variable is not set
+func GetEnvMap(key string, fallback map[string]string) map[string]string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	pairs := map[string]string{}
+	for _, item := range GetEnvList(key, nil) {
+		k, v, found := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !found || k == "" || v == "" {
+			log.Printf("WARNING: ignoring malformed entry %q in %s=%q (expected key=value)", item, key, value)
+			continue
+		}
+		pairs[k] = v
+	}
+
+	return pairs
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		opts  ListOptions
+		want  []string
+	}{
+		{"empty", "", ListOptions{}, []string{}},
+		{"trims entries", " a , b ,c", ListOptions{}, []string{"a", "b", "c"}},
+		{"drops empty entries", "a,,b,", ListOptions{}, []string{"a", "b"}},
+		{"only separators", " , ,", ListOptions{}, []string{}},
+		{"custom separator", "a; b;c,d", ListOptions{Separator: ";"}, []string{"a", "b", "c,d"}},
+		{"lowercase", "Ops@Example.com, ADMIN@example.com,", ListOptions{Lowercase: true}, []string{"ops@example.com", "admin@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitList(tt.value, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitList(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEnvListOptions(t *testing.T) {
+	fallback := []string{"fallback"}
+	if got := GetEnvListOptions("BOOMCHECKER_TEST_LIST_UNSET", fallback, ListOptions{}); !reflect.DeepEqual(got, fallback) {
+		t.Errorf("unset variable = %q, want fallback", got)
+	}
+
+	t.Setenv("BOOMCHECKER_TEST_LIST", "A@example.com,")
+	if got := GetEnvListOptions("BOOMCHECKER_TEST_LIST", fallback, ListOptions{Lowercase: true}); !reflect.DeepEqual(got, []string{"a@example.com"}) {
+		t.Errorf("GetEnvListOptions() = %q, want [a@example.com]", got)
+	}
+
+	t.Setenv("BOOMCHECKER_TEST_LIST", "")
+	if got := GetEnvList("BOOMCHECKER_TEST_LIST", fallback); len(got) != 0 {
+		t.Errorf("empty variable = %q, want no entries", got)
+	}
+}
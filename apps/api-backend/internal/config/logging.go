@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gorm.io/gorm/logger"
+)
+
+// DefaultLogLevel is used when LOG_LEVEL is not set
+const DefaultLogLevel = slog.LevelInfo
+
+// ParseLogLevel converts a level name (debug, info, warn, error) to a slog level
+// Matching is case-insensitive; "warning" is accepted as an alias for "warn"
+func ParseLogLevel(value string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	default:
+		return DefaultLogLevel, false
+	}
+}
+
+// LogLevel returns the level configured by LOG_LEVEL
+// Falls back to DefaultLogLevel if the variable is not set or invalid
+func LogLevel() slog.Level {
+	value := os.Getenv("LOG_LEVEL")
+	if value == "" {
+		return DefaultLogLevel
+	}
+
+	level, ok := ParseLogLevel(value)
+	if !ok {
+		log.Printf("WARNING: invalid LOG_LEVEL=%q, using default %s", value, DefaultLogLevel)
+	}
+	return level
+}
+
+// GormLogLevel maps a log level to the matching GORM logger verbosity
+// SQL queries are only logged at debug level
+func GormLogLevel(level slog.Level) logger.LogLevel {
+	switch {
+	case level <= slog.LevelDebug:
+		return logger.Info
+	case level >= slog.LevelError:
+		return logger.Error
+	default:
+		return logger.Warn
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"log/slog"
+	"testing"
+
+	"gorm.io/gorm/logger"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   slog.Level
+		wantOK bool
+	}{
+		{"debug", slog.LevelDebug, true},
+		{"INFO", slog.LevelInfo, true},
+		{" warn ", slog.LevelWarn, true},
+		{"warning", slog.LevelWarn, true},
+		{"error", slog.LevelError, true},
+		{"verbose", DefaultLogLevel, false},
+		{"", DefaultLogLevel, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseLogLevel(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseLogLevel(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLogLevel_FromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	if got := LogLevel(); got != slog.LevelWarn {
+		t.Errorf("LogLevel() = %v, want %v", got, slog.LevelWarn)
+	}
+
+	t.Setenv("LOG_LEVEL", "nonsense")
+	if got := LogLevel(); got != DefaultLogLevel {
+		t.Errorf("LogLevel() with invalid value = %v, want %v", got, DefaultLogLevel)
+	}
+}
+
+func TestGormLogLevel(t *testing.T) {
+	if got := GormLogLevel(slog.LevelDebug); got != logger.Info {
+		t.Errorf("GormLogLevel(debug) = %v, want logger.Info", got)
+	}
+	if got := GormLogLevel(slog.LevelWarn); got != logger.Warn {
+		t.Errorf("GormLogLevel(warn) = %v, want logger.Warn", got)
+	}
+	if got := GormLogLevel(slog.LevelError); got != logger.Error {
+		t.Errorf("GormLogLevel(error) = %v, want logger.Error", got)
+	}
+}
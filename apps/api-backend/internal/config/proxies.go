@@ -0,0 +1,12 @@
+package config
+
+// DefaultTrustedProxies trusts only loopback, i.e. a reverse proxy on the same host
+var DefaultTrustedProxies = []string{"127.0.0.1", "::1"}
+
+// TrustedProxies returns the proxies whose X-Forwarded-For / X-Real-IP headers are honored
+// Read from TRUSTED_PROXIES (comma-separated IPs or CIDRs); an empty value trusts no proxy.
+// Requests from any other peer use the connection's remote address as the client IP,
+// so IP-based features (rate limiting, IP binding) cannot be bypassed with spoofed headers.
+func TrustedProxies() []string {
+	return GetEnvList("TRUSTED_PROXIES", DefaultTrustedProxies)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIPFor sends a request with a spoofed X-Forwarded-For from remoteAddr and returns c.ClientIP()
+func clientIPFor(t *testing.T, proxies []string, remoteAddr string) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		t.Fatalf("SetTrustedProxies() error = %v", err)
+	}
+
+	var clientIP string
+	router.GET("/ip", func(c *gin.Context) {
+		clientIP = c.ClientIP()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	return clientIP
+}
+
+func TestTrustedProxies_EmptyTrustsNone(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	if got := TrustedProxies(); len(got) != 0 {
+		t.Errorf("TrustedProxies() with empty env = %v, want none", got)
+	}
+}
+
+func TestTrustedProxies_SpoofedForwardedForIgnored(t *testing.T) {
+	// Untrusted peer: X-Forwarded-For must be ignored
+	if got := clientIPFor(t, DefaultTrustedProxies, "198.51.100.20:4321"); got != "198.51.100.20" {
+		t.Errorf("ClientIP() from untrusted peer = %q, want 198.51.100.20", got)
+	}
+
+	// Trusted loopback proxy: forwarded client IP is honored
+	if got := clientIPFor(t, DefaultTrustedProxies, "127.0.0.1:4321"); got != "203.0.113.7" {
+		t.Errorf("ClientIP() via trusted proxy = %q, want 203.0.113.7", got)
+	}
+}
+
+func TestTrustedProxies_FromEnv(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", " 10.0.0.0/8 , 192.168.1.10,")
+
+	proxies := TrustedProxies()
+	if len(proxies) != 2 || proxies[0] != "10.0.0.0/8" || proxies[1] != "192.168.1.10" {
+		t.Fatalf("TrustedProxies() = %v, want [10.0.0.0/8 192.168.1.10]", proxies)
+	}
+
+	if got := clientIPFor(t, proxies, "10.1.2.3:4321"); got != "203.0.113.7" {
+		t.Errorf("ClientIP() via configured proxy = %q, want 203.0.113.7", got)
+	}
+	if got := clientIPFor(t, proxies, "127.0.0.1:4321"); got != "127.0.0.1" {
+		t.Errorf("ClientIP() from loopback when not configured = %q, want 127.0.0.1", got)
+	}
+}
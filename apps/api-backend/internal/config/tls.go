@@ -0,0 +1,45 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+)
+
+// TLSFiles returns the certificate and key paths from TLS_CERT_FILE and TLS_KEY_FILE
+// enabled is false when neither is set, meaning the server speaks plain HTTP
+// (e.g. behind a TLS-terminating proxy). Setting only one of them is an error.
+func TLSFiles() (certFile, keyFile string, enabled bool, err error) {
+	certFile = os.Getenv("TLS_CERT_FILE")
+	keyFile = os.Getenv("TLS_KEY_FILE")
+
+	switch {
+	case certFile == "" && keyFile == "":
+		return "", "", false, nil
+	case certFile == "" || keyFile == "":
+		return "", "", false, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	return certFile, keyFile, true, nil
+}
+
+// ServerTLSConfig returns the TLS settings for serving HTTPS in-process
+// TLS 1.2 is the minimum; for TLS 1.2 only ECDHE key exchange with AEAD ciphers is offered.
+// TLS 1.3 cipher suites are not configurable in Go and are all considered secure.
+func ServerTLSConfig(certificate tls.Certificate) *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
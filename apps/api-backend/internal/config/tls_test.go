@@ -0,0 +1,56 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestTLSFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		cert, key   string
+		wantEnabled bool
+		wantErr     bool
+	}{
+		{"unset serves plain HTTP", "", "", false, false},
+		{"both set", "/etc/boomchecker/cert.pem", "/etc/boomchecker/key.pem", true, false},
+		{"only cert", "/etc/boomchecker/cert.pem", "", false, true},
+		{"only key", "", "/etc/boomchecker/key.pem", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TLS_CERT_FILE", tt.cert)
+			t.Setenv("TLS_KEY_FILE", tt.key)
+
+			certFile, keyFile, enabled, err := TLSFiles()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TLSFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if enabled != tt.wantEnabled {
+				t.Errorf("TLSFiles() enabled = %v, want %v", enabled, tt.wantEnabled)
+			}
+			if enabled && (certFile != tt.cert || keyFile != tt.key) {
+				t.Errorf("TLSFiles() = %q, %q, want %q, %q", certFile, keyFile, tt.cert, tt.key)
+			}
+		})
+	}
+}
+
+func TestServerTLSConfig_ModernOnly(t *testing.T) {
+	config := ServerTLSConfig(tls.Certificate{})
+
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", config.MinVersion)
+	}
+
+	insecure := make(map[uint16]string)
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.ID] = suite.Name
+	}
+	for _, id := range config.CipherSuites {
+		if name, ok := insecure[id]; ok {
+			t.Errorf("CipherSuites contains insecure suite %s", name)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package crypto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AdminClaims represents JWT claims for admin authentication
+type AdminClaims struct {
+	Email string `json:"email"` // Admin email the token was sent to
+	Role  string `json:"role"`  // Always AdminRole
+	jwt.RegisteredClaims
+}
+
+const (
+	// AdminRole is the role claim carried by admin tokens
+	AdminRole = "admin"
+
+	// AdminJWTExpiration is the lifetime of an admin login token
+	AdminJWTExpiration = 24 * time.Hour
+
+	// MinAdminJWTSecretLength is the minimum length of ADMIN_JWT_SECRET in bytes
+	MinAdminJWTSecretLength = 32
+)
+
+// ValidateAdminJWTSecret checks that the admin signing secret is long enough for HS256
+func ValidateAdminJWTSecret(secret string) error {
+	if len(secret) < MinAdminJWTSecretLength {
+		return fmt.Errorf("admin JWT secret must be at least %d bytes, got %d", MinAdminJWTSecretLength, len(secret))
+	}
+	return nil
+}
+
+// GenerateAdminJWT generates a signed admin token for an email
+// Each token gets a unique ID (jti) so the issued token can be recorded.
+// Returns the JWT token string and its claims
+func GenerateAdminJWT(email string, secret string, expirationDuration time.Duration) (token string, claims *AdminClaims, err error) {
+	if email == "" {
+		return "", nil, fmt.Errorf("email is required")
+	}
+	if err := ValidateAdminJWTSecret(secret); err != nil {
+		return "", nil, err
+	}
+
+	if expirationDuration == 0 {
+		expirationDuration = AdminJWTExpiration
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(expirationDuration)
+
+	claims = &AdminClaims{
+		Email: email,
+		Role:  AdminRole,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    JWTIssuer,
+			Subject:   email,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	tokenString, err := signAdminJWT(claims, secret)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tokenString, claims, nil
+}
+
+// ReproduceAdminJWT signs the claims of an already issued admin token again
+// HS256 is deterministic, so with the same ID, email, issue and expiry time (whole seconds)
+// and secret the result is the original token; this lets a token be re-sent without storing it.
+func ReproduceAdminJWT(id, email, secret string, issuedAt, expiresAt time.Time) (string, error) {
+	if id == "" || email == "" {
+		return "", fmt.Errorf("token ID and email are required")
+	}
+	if err := ValidateAdminJWTSecret(secret); err != nil {
+		return "", err
+	}
+
+	return signAdminJWT(&AdminClaims{
+		Email: email,
+		Role:  AdminRole,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    JWTIssuer,
+			Subject:   email,
+			IssuedAt:  jwt.NewNumericDate(issuedAt.UTC()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt.UTC()),
+		},
+	}, secret)
+}
+
+// signAdminJWT signs admin claims with HS256
+func signAdminJWT(claims *AdminClaims, secret string) (string, error) {
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// VerifyAdminJWT verifies an admin token and returns its claims
+// Returns error if the token is invalid, expired, signed with another secret, or lacks the admin role
+func VerifyAdminJWT(tokenString string, secret string) (*AdminClaims, error) {
+	if tokenString == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("JWT secret is required")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &AdminClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	}, jwt.WithIssuer(JWTIssuer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*AdminClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	if claims.Role != AdminRole || claims.Email == "" {
+		return nil, fmt.Errorf("token is not an admin token")
+	}
+
+	return claims, nil
+}
+
+// ParseAdminJWTUnverified decodes an admin token's claims without verifying it
+// WARNING: This does NOT verify the token signature! Use only for logging/debugging
+func ParseAdminJWTUnverified(tokenString string) (*AdminClaims, error) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &AdminClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*AdminClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+// IsAdminTokenExpired checks if an admin token is expired without full verification
+func IsAdminTokenExpired(tokenString string) (bool, error) {
+	claims, err := ParseAdminJWTUnverified(tokenString)
+	if err != nil {
+		return false, err
+	}
+
+	return claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now().UTC()), nil
+}
+
+// GetEmailFromToken extracts the admin email from a token without verification
+// WARNING: This does NOT verify the token signature! Use only for logging/debugging
+func GetEmailFromToken(tokenString string) (string, error) {
+	claims, err := ParseAdminJWTUnverified(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Email, nil
+}
@@ -0,0 +1,43 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateAdminJWT_Expiration tests that the exp claim follows the requested lifetime
+func TestGenerateAdminJWT_Expiration(t *testing.T) {
+	secret := strings.Repeat("s", MinAdminJWTSecretLength)
+
+	tests := []struct {
+		name     string
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		{"custom lifetime", 2 * time.Hour, 2 * time.Hour},
+		{"week", 7 * 24 * time.Hour, 7 * 24 * time.Hour},
+		{"zero uses default", 0, AdminJWTExpiration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, claims, err := GenerateAdminJWT("ops@example.com", secret, tt.lifetime)
+			if err != nil {
+				t.Fatalf("GenerateAdminJWT() error = %v", err)
+			}
+			if got := claims.ExpiresAt.Sub(claims.IssuedAt.Time); got != tt.want {
+				t.Errorf("exp - iat = %v, want %v", got, tt.want)
+			}
+
+			// The signed token carries the same exp
+			verified, err := VerifyAdminJWT(token, secret)
+			if err != nil {
+				t.Fatalf("VerifyAdminJWT() error = %v", err)
+			}
+			if !verified.ExpiresAt.Equal(claims.ExpiresAt.Time) {
+				t.Errorf("verified exp = %v, want %v", verified.ExpiresAt, claims.ExpiresAt)
+			}
+		})
+	}
+}
@@ -70,24 +70,47 @@ func GenerateEncryptionKey() (string, error) {
 	return base64.StdEncoding.EncodeToString(key), nil
 }
 
-// Encrypt encrypts plaintext using AES-256-GCM
-// Returns base64-encoded ciphertext with nonce prepended
-// Format: [nonce(12 bytes)][ciphertext][auth_tag(16 bytes)]
-func Encrypt(plaintext string, key []byte) (string, error) {
+// aeadFor returns the AES-256-GCM cipher for the key from provider
+// Providers implementing AEADProvider (see CachedKeyProvider) reuse one cipher;
+// for all others it is built from the key on every call.
+func aeadFor(provider KeyProvider) (cipher.AEAD, error) {
+	if p, ok := provider.(AEADProvider); ok {
+		return p.AEAD()
+	}
+
+	key, err := provider.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
 	if len(key) != AES256KeySize {
-		return "", ErrInvalidKeySize
+		return nil, ErrInvalidKeySize
 	}
+	return newAESGCM(key)
+}
 
+// newAESGCM creates an AES-256-GCM cipher for key
+func newAESGCM(key []byte) (cipher.AEAD, error) {
 	// Create AES cipher block
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
 	// Create GCM mode (Galois/Counter Mode)
 	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return aesGCM, nil
+}
+
+// Encrypt encrypts plaintext using AES-256-GCM with the key from provider
+// Returns base64-encoded ciphertext with nonce prepended
+// Format: [nonce(12 bytes)][ciphertext][auth_tag(16 bytes)]
+func Encrypt(plaintext string, provider KeyProvider) (string, error) {
+	aesGCM, err := aeadFor(provider)
+	if err != nil {
+		return "", err
 	}
 
 	// Generate a random nonce (number used once)
@@ -105,11 +128,12 @@ func Encrypt(plaintext string, key []byte) (string, error) {
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
-// Decrypt decrypts base64-encoded ciphertext using AES-256-GCM
+// Decrypt decrypts base64-encoded ciphertext using AES-256-GCM with the key from provider
 // Returns original plaintext
-func Decrypt(ciphertextBase64 string, key []byte) (string, error) {
-	if len(key) != AES256KeySize {
-		return "", ErrInvalidKeySize
+func Decrypt(ciphertextBase64 string, provider KeyProvider) (string, error) {
+	aesGCM, err := aeadFor(provider)
+	if err != nil {
+		return "", err
 	}
 
 	// Decode from base64
@@ -118,18 +142,6 @@ func Decrypt(ciphertextBase64 string, key []byte) (string, error) {
 		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
-	// Create AES cipher block
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
-	}
-
-	// Create GCM mode
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
-	}
-
 	// Check minimum ciphertext length
 	nonceSize := aesGCM.NonceSize()
 	if len(ciphertext) < nonceSize {
@@ -159,15 +171,9 @@ func GenerateJWTSecret() (string, error) {
 	return base64.StdEncoding.EncodeToString(secret), nil
 }
 
-// EncryptJWTSecret generates a new JWT secret and encrypts it
+// EncryptJWTSecret generates a new JWT secret and encrypts it with the key from provider
 // Returns encrypted JWT secret ready for database storage
-func EncryptJWTSecret() (plainSecret, encryptedSecret string, err error) {
-	// Get encryption key from environment
-	key, err := GetEncryptionKey()
-	if err != nil {
-		return "", "", err
-	}
-
+func EncryptJWTSecret(provider KeyProvider) (plainSecret, encryptedSecret string, err error) {
 	// Generate random JWT secret
 	plainSecret, err = GenerateJWTSecret()
 	if err != nil {
@@ -175,7 +181,7 @@ func EncryptJWTSecret() (plainSecret, encryptedSecret string, err error) {
 	}
 
 	// Encrypt the secret
-	encryptedSecret, err = Encrypt(plainSecret, key)
+	encryptedSecret, err = Encrypt(plainSecret, provider)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to encrypt JWT secret: %w", err)
 	}
@@ -183,17 +189,11 @@ func EncryptJWTSecret() (plainSecret, encryptedSecret string, err error) {
 	return plainSecret, encryptedSecret, nil
 }
 
-// DecryptJWTSecret decrypts an encrypted JWT secret from database
+// DecryptJWTSecret decrypts an encrypted JWT secret from database with the key from provider
 // Returns the original plaintext JWT secret
-func DecryptJWTSecret(encryptedSecret string) (string, error) {
-	// Get encryption key from environment
-	key, err := GetEncryptionKey()
-	if err != nil {
-		return "", err
-	}
-
+func DecryptJWTSecret(provider KeyProvider, encryptedSecret string) (string, error) {
 	// Decrypt the secret
-	plainSecret, err := Decrypt(encryptedSecret, key)
+	plainSecret, err := Decrypt(encryptedSecret, provider)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt JWT secret: %w", err)
 	}
@@ -201,8 +201,20 @@ func DecryptJWTSecret(encryptedSecret string) (string, error) {
 	return plainSecret, nil
 }
 
-// ValidateEncryptionKey checks if the encryption key is properly configured
-func ValidateEncryptionKey() error {
-	_, err := GetEncryptionKey()
-	return err
+// IsKeyMismatch reports whether err comes from ciphertext that failed authentication
+// For secrets this server encrypted itself, that means they were encrypted with a different key.
+func IsKeyMismatch(err error) bool {
+	return errors.Is(err, ErrInvalidCiphertext)
+}
+
+// ValidateEncryptionKey checks if provider can supply a valid encryption key
+func ValidateEncryptionKey(provider KeyProvider) error {
+	key, err := provider.EncryptionKey()
+	if err != nil {
+		return err
+	}
+	if len(key) != AES256KeySize {
+		return fmt.Errorf("%w: got %d bytes, expected %d", ErrInvalidKeySize, len(key), AES256KeySize)
+	}
+	return nil
 }
@@ -0,0 +1,89 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestCachedKeyProvider tests that the cached cipher follows a rotated key
+func TestCachedKeyProvider(t *testing.T) {
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("GenerateEncryptionKey() error = %v", err)
+	}
+	t.Setenv(EnvKeyName, key)
+
+	provider := NewCachedKeyProvider(EnvKeyProvider{})
+	plain, encrypted, err := EncryptJWTSecret(provider)
+	if err != nil {
+		t.Fatalf("EncryptJWTSecret() error = %v", err)
+	}
+	if decrypted, err := DecryptJWTSecret(EnvKeyProvider{}, encrypted); err != nil || decrypted != plain {
+		t.Errorf("DecryptJWTSecret() without cache = %q, %v, want %q", decrypted, err, plain)
+	}
+	first, _ := provider.AEAD()
+	if second, _ := provider.AEAD(); second != first {
+		t.Error("AEAD() built a new cipher for an unchanged key")
+	}
+
+	rotated, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("GenerateEncryptionKey() error = %v", err)
+	}
+	t.Setenv(EnvKeyName, rotated)
+	if _, err := DecryptJWTSecret(provider, encrypted); !IsKeyMismatch(err) {
+		t.Errorf("DecryptJWTSecret() after rotation error = %v, want key mismatch", err)
+	}
+
+	t.Setenv(EnvKeyName, "")
+	if _, err := provider.AEAD(); !errors.Is(err, ErrEncryptionKeyNotSet) {
+		t.Errorf("AEAD() without key error = %v, want ErrEncryptionKeyNotSet", err)
+	}
+}
+
+// BenchmarkEncryptJWTSecret measures secret generation and encryption as done on every new registration
+func BenchmarkEncryptJWTSecret(b *testing.B) {
+	for _, bench := range []struct {
+		name     string
+		provider KeyProvider
+	}{
+		{"uncached", StaticKeyProvider(bytes.Repeat([]byte{7}, AES256KeySize))},
+		{"cached", NewCachedKeyProvider(StaticKeyProvider(bytes.Repeat([]byte{7}, AES256KeySize)))},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := EncryptJWTSecret(bench.provider); err != nil {
+					b.Fatalf("EncryptJWTSecret() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecryptJWTSecret measures decryption as done on every re-registration and node request
+func BenchmarkDecryptJWTSecret(b *testing.B) {
+	for _, bench := range []struct {
+		name     string
+		provider KeyProvider
+	}{
+		{"uncached", StaticKeyProvider(bytes.Repeat([]byte{7}, AES256KeySize))},
+		{"cached", NewCachedKeyProvider(StaticKeyProvider(bytes.Repeat([]byte{7}, AES256KeySize)))},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			_, encrypted, err := EncryptJWTSecret(bench.provider)
+			if err != nil {
+				b.Fatalf("EncryptJWTSecret() error = %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecryptJWTSecret(bench.provider, encrypted); err != nil {
+					b.Fatalf("DecryptJWTSecret() error = %v", err)
+				}
+			}
+		})
+	}
+}
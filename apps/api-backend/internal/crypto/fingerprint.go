@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// TokenFingerprintPrefixLength is the number of leading token characters kept in a fingerprint
+	TokenFingerprintPrefixLength = 8
+
+	// TokenFingerprintHashLength is the number of SHA-256 hex characters appended to a fingerprint
+	TokenFingerprintHashLength = 8
+)
+
+// TokenFingerprint returns a short identifier for a secret token value
+// Format: <first 8 chars>...<first 8 hex chars of SHA-256>
+// Safe to use in logs and error messages instead of the raw token.
+// Short values (where the prefix would reveal most of the secret) only get the hash part.
+func TokenFingerprint(tokenValue string) string {
+	if tokenValue == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(tokenValue))
+	hash := hex.EncodeToString(sum[:])[:TokenFingerprintHashLength]
+
+	if len(tokenValue) <= 2*TokenFingerprintPrefixLength {
+		return "..." + hash
+	}
+
+	return tokenValue[:TokenFingerprintPrefixLength] + "..." + hash
+}
+
+// HashToken returns the hex-encoded SHA-256 of a token value
+// Used to store issued tokens so they can be validated without keeping the token itself
+func HashToken(tokenValue string) string {
+	sum := sha256.Sum256([]byte(tokenValue))
+	return hex.EncodeToString(sum[:])
+}
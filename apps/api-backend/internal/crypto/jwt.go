@@ -6,11 +6,17 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // NodeClaims represents JWT claims for node authentication
 type NodeClaims struct {
 	NodeUUID string `json:"node_uuid"` // Node UUID
+
+	// Custom holds the claims configured on the registration token, nested under one
+	// claim so they can never collide with registered claims or node_uuid
+	Custom map[string]interface{} `json:"ext,omitempty"`
+
 	jwt.RegisteredClaims
 }
 
@@ -23,8 +29,10 @@ const (
 )
 
 // GenerateNodeJWT generates a JWT token for a node using golang-jwt/jwt
+// Each token gets a unique ID (jti) so a single issued token can be revoked.
+// custom may be nil; otherwise it is signed into the token as the "ext" claim.
 // Returns the JWT token string and expiration timestamp
-func GenerateNodeJWT(nodeUUID string, jwtSecretBase64 string, expirationDuration time.Duration) (token string, expiresAt int64, err error) {
+func GenerateNodeJWT(nodeUUID string, jwtSecretBase64 string, expirationDuration time.Duration, custom map[string]interface{}) (token string, expiresAt int64, err error) {
 	if nodeUUID == "" {
 		return "", 0, fmt.Errorf("node UUID is required")
 	}
@@ -50,7 +58,9 @@ func GenerateNodeJWT(nodeUUID string, jwtSecretBase64 string, expirationDuration
 	// Create claims
 	claims := NodeClaims{
 		NodeUUID: nodeUUID,
+		Custom:   custom,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			Issuer:    JWTIssuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(expiresAtTime),
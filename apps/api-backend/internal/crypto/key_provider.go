@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrExternalKeyProviderNotImplemented is returned by the external key provider stub
+	ErrExternalKeyProviderNotImplemented = errors.New("external key provider is not implemented")
+
+	// ErrUnknownKeyProvider is returned when the configured key provider type is not supported
+	ErrUnknownKeyProvider = errors.New("unknown key provider")
+)
+
+const (
+	// KeyProviderEnv reads the encryption key from the JWT_ENCRYPTION_KEY environment variable
+	KeyProviderEnv = "env"
+
+	// KeyProviderExternal fetches the encryption key from an external secret store (KMS/Vault)
+	KeyProviderExternal = "external"
+)
+
+// KeyProvider supplies the AES-256 key used to encrypt node JWT secrets
+// Implementations must return exactly AES256KeySize bytes or an error.
+type KeyProvider interface {
+	EncryptionKey() ([]byte, error)
+}
+
+// AEADProvider is implemented by key providers that hand out a ready AES-256-GCM cipher
+// Encrypt and Decrypt use it instead of building a cipher from the key on every call.
+type AEADProvider interface {
+	AEAD() (cipher.AEAD, error)
+}
+
+// KeyProviderConfig selects and configures a KeyProvider
+type KeyProviderConfig struct {
+	// Type is KeyProviderEnv (default) or KeyProviderExternal
+	Type string
+	// ExternalURL is the address of the external secret store
+	ExternalURL string
+	// ExternalKeyID identifies the key within the external secret store
+	ExternalKeyID string
+}
+
+// NewKeyProvider creates the key provider selected by config
+// An empty type selects the env-backed provider.
+func NewKeyProvider(config KeyProviderConfig) (KeyProvider, error) {
+	switch config.Type {
+	case "", KeyProviderEnv:
+		return EnvKeyProvider{}, nil
+	case KeyProviderExternal:
+		if config.ExternalURL == "" || config.ExternalKeyID == "" {
+			return nil, fmt.Errorf("external key provider requires a URL and key ID")
+		}
+		return &ExternalKeyProvider{URL: config.ExternalURL, KeyID: config.ExternalKeyID}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (expected %q or %q)", ErrUnknownKeyProvider, config.Type, KeyProviderEnv, KeyProviderExternal)
+	}
+}
+
+// EnvKeyProvider reads the base64-encoded key from the JWT_ENCRYPTION_KEY environment variable
+// The variable is read on every call, exactly like GetEncryptionKey.
+type EnvKeyProvider struct{}
+
+// EncryptionKey returns the key from the environment
+func (EnvKeyProvider) EncryptionKey() ([]byte, error) {
+	return GetEncryptionKey()
+}
+
+// StaticKeyProvider returns a fixed key held in memory
+// Useful for tools and tests that already have the raw key bytes.
+type StaticKeyProvider []byte
+
+// EncryptionKey returns the fixed key after checking its size
+func (p StaticKeyProvider) EncryptionKey() ([]byte, error) {
+	if len(p) != AES256KeySize {
+		return nil, fmt.Errorf("%w: got %d bytes, expected %d", ErrInvalidKeySize, len(p), AES256KeySize)
+	}
+	return p, nil
+}
+
+// ExternalKeyProvider fetches the key from an external secret store such as a KMS or Vault
+// This is a stub: the integration is not implemented yet and every call returns an error,
+// so a deployment selecting it fails at startup instead of running without a key.
+type ExternalKeyProvider struct {
+	URL   string
+	KeyID string
+}
+
+// EncryptionKey always returns ErrExternalKeyProviderNotImplemented
+func (p *ExternalKeyProvider) EncryptionKey() ([]byte, error) {
+	return nil, fmt.Errorf("%w (url %s, key %s)", ErrExternalKeyProviderNotImplemented, p.URL, p.KeyID)
+}
+
+// CachedKeyProvider wraps a KeyProvider and reuses the AES-256-GCM cipher built from its key
+// A cipher.AEAD is safe for concurrent use, so one instance serves every request. The wrapped
+// provider is still asked for the key on each call and the cipher is rebuilt when the key changes,
+// so a rotated JWT_ENCRYPTION_KEY takes effect exactly as without the cache.
+type CachedKeyProvider struct {
+	provider KeyProvider
+
+	mu   sync.RWMutex
+	key  []byte
+	aead cipher.AEAD
+}
+
+// NewCachedKeyProvider wraps provider with a cipher cache
+func NewCachedKeyProvider(provider KeyProvider) *CachedKeyProvider {
+	return &CachedKeyProvider{provider: provider}
+}
+
+// EncryptionKey returns the key of the wrapped provider
+func (p *CachedKeyProvider) EncryptionKey() ([]byte, error) {
+	return p.provider.EncryptionKey()
+}
+
+// AEAD returns the cipher for the current key, building it only when the key has changed
+func (p *CachedKeyProvider) AEAD() (cipher.AEAD, error) {
+	key, err := p.provider.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != AES256KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	p.mu.RLock()
+	aead := p.aead
+	if aead != nil && !bytes.Equal(p.key, key) {
+		aead = nil
+	}
+	p.mu.RUnlock()
+	if aead != nil {
+		return aead, nil
+	}
+
+	aead, err = newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.key = bytes.Clone(key)
+	p.aead = aead
+	p.mu.Unlock()
+	return aead, nil
+}
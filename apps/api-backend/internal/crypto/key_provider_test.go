@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// TestNewKeyProvider tests provider selection by config
+func TestNewKeyProvider(t *testing.T) {
+	provider, err := NewKeyProvider(KeyProviderConfig{})
+	if err != nil {
+		t.Fatalf("NewKeyProvider() default error = %v", err)
+	}
+	if _, ok := provider.(EnvKeyProvider); !ok {
+		t.Errorf("NewKeyProvider() default = %T, want EnvKeyProvider", provider)
+	}
+
+	provider, err = NewKeyProvider(KeyProviderConfig{Type: KeyProviderExternal, ExternalURL: "https://vault.example.com", ExternalKeyID: "boomchecker"})
+	if err != nil {
+		t.Fatalf("NewKeyProvider() external error = %v", err)
+	}
+	if _, err := provider.EncryptionKey(); !errors.Is(err, ErrExternalKeyProviderNotImplemented) {
+		t.Errorf("external EncryptionKey() error = %v, want ErrExternalKeyProviderNotImplemented", err)
+	}
+
+	if _, err := NewKeyProvider(KeyProviderConfig{Type: KeyProviderExternal}); err == nil {
+		t.Error("NewKeyProvider() external without URL should return error")
+	}
+	if _, err := NewKeyProvider(KeyProviderConfig{Type: "vault"}); !errors.Is(err, ErrUnknownKeyProvider) {
+		t.Errorf("NewKeyProvider() unknown type error = %v, want ErrUnknownKeyProvider", err)
+	}
+}
+
+// TestEncryptJWTSecret_EnvKeyProvider tests that the env provider round-trips secrets
+// and that another key cannot decrypt them
+func TestEncryptJWTSecret_EnvKeyProvider(t *testing.T) {
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("GenerateEncryptionKey() error = %v", err)
+	}
+	t.Setenv(EnvKeyName, key)
+
+	plain, encrypted, err := EncryptJWTSecret(EnvKeyProvider{})
+	if err != nil {
+		t.Fatalf("EncryptJWTSecret() error = %v", err)
+	}
+	decrypted, err := DecryptJWTSecret(EnvKeyProvider{}, encrypted)
+	if err != nil {
+		t.Fatalf("DecryptJWTSecret() error = %v", err)
+	}
+	if decrypted != plain {
+		t.Errorf("DecryptJWTSecret() = %q, want %q", decrypted, plain)
+	}
+
+	// The same key supplied in memory decrypts it too
+	keyBytes, _ := base64.StdEncoding.DecodeString(key)
+	if decrypted, err := DecryptJWTSecret(StaticKeyProvider(keyBytes), encrypted); err != nil || decrypted != plain {
+		t.Errorf("DecryptJWTSecret() with static key = %q, %v, want %q", decrypted, err, plain)
+	}
+
+	otherKey := make([]byte, AES256KeySize)
+	if _, err := DecryptJWTSecret(StaticKeyProvider(otherKey), encrypted); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("DecryptJWTSecret() with other key error = %v, want ErrInvalidCiphertext", err)
+	}
+
+	t.Setenv(EnvKeyName, "")
+	if err := ValidateEncryptionKey(EnvKeyProvider{}); !errors.Is(err, ErrEncryptionKeyNotSet) {
+		t.Errorf("ValidateEncryptionKey() without key error = %v, want ErrEncryptionKeyNotSet", err)
+	}
+}
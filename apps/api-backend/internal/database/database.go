@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/boomchecker/api-backend/internal/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -30,8 +29,21 @@ type Config struct {
 
 	// ConnMaxLifetime sets the maximum amount of time a connection may be reused
 	ConnMaxLifetime time.Duration
+
+	// ConnectAttempts is how many times InitDB tries to open the database before giving up
+	// Values below 1 are treated as a single attempt
+	ConnectAttempts int
+
+	// ConnectRetryInterval is the wait before the first retry; it doubles after each failed attempt
+	ConnectRetryInterval time.Duration
 }
 
+// maxConnectRetryInterval caps the exponential backoff between connection attempts
+const maxConnectRetryInterval = 30 * time.Second
+
+// connectAttempt performs one initialization attempt; tests replace it to simulate transient failures
+var connectAttempt = initDB
+
 // DefaultConfig returns sensible default configuration for production
 func DefaultConfig(dbPath string) *Config {
 	return &Config{
@@ -40,6 +52,10 @@ func DefaultConfig(dbPath string) *Config {
 		MaxIdleConns:    10,
 		MaxOpenConns:    100,
 		ConnMaxLifetime: time.Hour,
+
+		// Tolerate a database volume that is not ready yet during orchestrated startups
+		ConnectAttempts:      5,
+		ConnectRetryInterval: 2 * time.Second,
 	}
 }
 
@@ -51,16 +67,52 @@ func TestConfig() *Config {
 		MaxIdleConns:    5,
 		MaxOpenConns:    10,
 		ConnMaxLifetime: time.Minute * 30,
+		ConnectAttempts: 1,
 	}
 }
 
 // InitDB initializes the database connection and runs migrations
-// Returns a GORM DB instance or an error if initialization fails
+// Failed attempts are retried with exponential backoff up to config.ConnectAttempts times
+// Returns a GORM DB instance or an error if every attempt fails
 func InitDB(config *Config) (*gorm.DB, error) {
 	if config == nil {
 		config = DefaultConfig("./data/boomchecker.db")
 	}
 
+	attempts := config.ConnectAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	interval := config.ConnectRetryInterval
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		log.Printf("Connecting to database (attempt %d/%d)", attempt, attempts)
+
+		db, err := connectAttempt(config)
+		if err == nil {
+			return db, nil
+		}
+		lastErr = err
+
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("WARNING: Database initialization failed: %v (retrying in %s)", err, interval)
+		time.Sleep(interval)
+		interval *= 2
+		if interval > maxConnectRetryInterval {
+			interval = maxConnectRetryInterval
+		}
+	}
+
+	return nil, fmt.Errorf("database unavailable after %d attempt(s): %w", attempts, lastErr)
+}
+
+// initDB performs a single connection and migration attempt
+// The connection is closed again if any step after opening fails
+func initDB(config *Config) (db *gorm.DB, err error) {
 	// Create database directory if it doesn't exist (for file-based databases)
 	if config.DatabasePath != ":memory:" {
 		if err := ensureDBDirectory(config.DatabasePath); err != nil {
@@ -82,10 +134,18 @@ func InitDB(config *Config) (*gorm.DB, error) {
 	// This avoids CGO dependency required by mattn/go-sqlite3
 	// sqlite.Open() automatically uses the pure-Go driver without CGO
 	log.Printf("Opening SQLite database: %s", config.DatabasePath)
-	db, err := gorm.Open(sqlite.Open(config.DatabasePath), gormConfig)
+	db, err = gorm.Open(sqlite.Open(config.DatabasePath), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database at %s: %w", config.DatabasePath, err)
 	}
+	defer func() {
+		if err != nil {
+			if closeErr := Close(db); closeErr != nil {
+				log.Printf("WARNING: Failed to close database after failed initialization: %v", closeErr)
+			}
+			db = nil
+		}
+	}()
 
 	// Get underlying SQL database for connection pool configuration
 	sqlDB, err := db.DB()
@@ -110,7 +170,7 @@ func InitDB(config *Config) (*gorm.DB, error) {
 		log.Printf("WARNING: Failed to enable WAL mode: %v", err)
 	}
 
-	// Run auto-migrations
+	// Apply pending versioned migrations
 	if err := runMigrations(db); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -119,26 +179,6 @@ func InitDB(config *Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-// runMigrations executes GORM AutoMigrate for all models
-func runMigrations(db *gorm.DB) error {
-	// AutoMigrate will create tables, indexes, and constraints
-	// Order matters: create independent tables first
-	if err := db.AutoMigrate(
-		&models.Node{},
-		&models.RegistrationToken{},
-	); err != nil {
-		return fmt.Errorf("AutoMigrate failed: %w", err)
-	}
-
-	// Create additional indexes that GORM tags might not handle
-	if err := createCustomIndexes(db); err != nil {
-		return fmt.Errorf("failed to create custom indexes: %w", err)
-	}
-
-	log.Println("Database migrations completed")
-	return nil
-}
-
 // createCustomIndexes creates indexes that aren't automatically created by GORM tags
 func createCustomIndexes(db *gorm.DB) error {
 	indexes := []string{
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubConnectAttempt makes the first failures attempts fail before delegating to initDB
+// Returns a pointer to the number of attempts made
+func stubConnectAttempt(t *testing.T, failures int) *int {
+	t.Helper()
+
+	calls := 0
+	original := connectAttempt
+	connectAttempt = func(config *Config) (*gorm.DB, error) {
+		calls++
+		if calls <= failures {
+			return nil, errors.New("database volume not mounted yet")
+		}
+		return original(config)
+	}
+	t.Cleanup(func() { connectAttempt = original })
+	return &calls
+}
+
+// retryTestConfig returns an in-memory configuration with fast retries
+func retryTestConfig(attempts int) *Config {
+	config := TestConfig()
+	config.LogLevel = logger.Silent
+	config.ConnectAttempts = attempts
+	config.ConnectRetryInterval = time.Millisecond
+	return config
+}
+
+// TestInitDB_RetriesTransientFailure tests that a failed attempt is retried until the database opens
+func TestInitDB_RetriesTransientFailure(t *testing.T) {
+	calls := stubConnectAttempt(t, 2)
+
+	db, err := InitDB(retryTestConfig(3))
+	if err != nil {
+		t.Fatalf("InitDB() error = %v, want success after retry", err)
+	}
+	defer Close(db)
+
+	if *calls != 3 {
+		t.Errorf("connection attempts = %d, want 3", *calls)
+	}
+	if err := Ping(db); err != nil {
+		t.Errorf("Ping() after retry error = %v", err)
+	}
+}
+
+// TestInitDB_GivesUpAfterConnectAttempts tests that InitDB stops after the configured number of attempts
+func TestInitDB_GivesUpAfterConnectAttempts(t *testing.T) {
+	calls := stubConnectAttempt(t, 5)
+
+	db, err := InitDB(retryTestConfig(2))
+	if err == nil {
+		Close(db)
+		t.Fatal("InitDB() error = nil, want failure after exhausting attempts")
+	}
+
+	if *calls != 2 {
+		t.Errorf("connection attempts = %d, want 2", *calls)
+	}
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration is one versioned schema change
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+// migrations lists every schema change in the order it is applied
+// Append new steps with the next version number; never edit or reorder a step
+// that has shipped, since deployed databases have already recorded it as applied.
+// A step adding a column can AutoMigrate its model again; renames and backfills
+// use tx.Migrator().RenameColumn and tx.Exec so they run exactly once.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "initial_schema",
+		up: func(tx *gorm.DB) error {
+			// Order matters: create independent tables first
+			// AutoMigrate is idempotent, so databases created before versioning adopt this step as-is
+			return tx.AutoMigrate(
+				&models.Node{},
+				&models.RegistrationToken{},
+				&models.RegistrationEvent{},
+				&models.AdminUser{},
+				&models.NodeTelemetry{},
+				&models.IdempotencyKey{},
+				&models.AdminToken{},
+				&models.NodeMetadata{},
+			)
+		},
+	},
+	{
+		version: 2,
+		name:    "custom_indexes",
+		up:      createCustomIndexes,
+	},
+	{
+		version: 3,
+		name:    "revoked_node_tokens",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RevokedNodeToken{})
+		},
+	},
+	{
+		version: 4,
+		name:    "settings",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Setting{})
+		},
+	},
+	{
+		version: 5,
+		name:    "audit_logs",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AuditLog{})
+		},
+	},
+	{
+		version: 6,
+		name:    "registration_token_description",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RegistrationToken{})
+		},
+	},
+	{
+		version: 7,
+		name:    "node_last_registered_at",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Node{})
+		},
+	},
+	{
+		version: 8,
+		name:    "admin_login_codes",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AdminLoginCode{})
+		},
+	},
+	{
+		version: 9,
+		name:    "registration_token_soft_delete",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RegistrationToken{})
+		},
+	},
+	{
+		version: 10,
+		name:    "mac_denylist",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.DeniedMAC{})
+		},
+	},
+	{
+		version: 11,
+		name:    "owner_id",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Node{}, &models.RegistrationToken{})
+		},
+	},
+	{
+		version: 12,
+		name:    "admin_token_resend_count",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AdminToken{})
+		},
+	},
+	{
+		version: 13,
+		name:    "node_last_ip",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Node{})
+		},
+	},
+	{
+		version: 14,
+		name:    "token_custom_claims",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RegistrationToken{})
+		},
+	},
+	{
+		version: 15,
+		name:    "token_distinct_mac_limit",
+		up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RegistrationToken{})
+		},
+	},
+}
+
+// LatestSchemaVersion returns the version this build migrates the database to
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// runMigrations applies every migration not yet recorded in schema_migrations
+// Each step runs in its own transaction together with its schema_migrations row,
+// so an interrupted deploy resumes from the first unrecorded step on the next start.
+func runMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var applied []models.SchemaMigration
+	if err := db.Find(&applied).Error; err != nil {
+		return fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+
+	appliedVersions := make(map[int]bool, len(applied))
+	for _, m := range applied {
+		appliedVersions[m.Version] = true
+		if m.Version > LatestSchemaVersion() {
+			log.Printf("WARNING: Database has migration %d (%s) which is newer than this build (latest %d)",
+				m.Version, m.Name, LatestSchemaVersion())
+		}
+	}
+
+	for _, m := range migrations {
+		if appliedVersions[m.version] {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.SchemaMigration{Version: m.version, Name: m.name}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+
+		log.Printf("Applied migration %d (%s)", m.version, m.name)
+	}
+
+	log.Printf("Database migrations completed (schema version %d)", LatestSchemaVersion())
+	return nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestMigrations_Sequential tests that migration versions start at 1 and have no gaps
+func TestMigrations_Sequential(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migrations[%d].version = %d, want %d", i, m.version, i+1)
+		}
+		if m.name == "" || m.up == nil {
+			t.Errorf("migration %d is missing a name or up function", m.version)
+		}
+	}
+}
+
+// newMigrationTestDB opens an empty in-memory database
+func newMigrationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+// loadApplied returns the recorded migrations ordered by version
+func loadApplied(t *testing.T, db *gorm.DB) []models.SchemaMigration {
+	t.Helper()
+
+	var applied []models.SchemaMigration
+	if err := db.Order("version ASC").Find(&applied).Error; err != nil {
+		t.Fatalf("failed to load applied migrations: %v", err)
+	}
+	return applied
+}
+
+// TestRunMigrations_RecordsEachStep tests that every step is applied and recorded
+func TestRunMigrations_RecordsEachStep(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+
+	applied := loadApplied(t, db)
+	if len(applied) != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if last := applied[len(applied)-1].Version; last != LatestSchemaVersion() {
+		t.Errorf("last applied version = %d, want %d", last, LatestSchemaVersion())
+	}
+	if !db.Migrator().HasTable(&models.Node{}) {
+		t.Error("nodes table was not created")
+	}
+}
+
+// TestRunMigrations_Idempotent tests that a second run applies nothing and leaves the records untouched
+func TestRunMigrations_Idempotent(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("runMigrations() first run error = %v", err)
+	}
+	first := loadApplied(t, db)
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("runMigrations() second run error = %v", err)
+	}
+	second := loadApplied(t, db)
+
+	if len(second) != len(first) {
+		t.Fatalf("second run recorded %d migrations, want %d", len(second), len(first))
+	}
+	for i := range first {
+		if !second[i].AppliedAt.Equal(first[i].AppliedAt) {
+			t.Errorf("migration %d re-applied: applied_at %v, want %v",
+				first[i].Version, second[i].AppliedAt, first[i].AppliedAt)
+		}
+	}
+}
+
+// TestRunMigrations_AdoptsUnversionedDatabase tests that a database created by bare AutoMigrate
+// is brought under versioning without losing data
+func TestRunMigrations_AdoptsUnversionedDatabase(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	if err := db.Exec("INSERT INTO nodes (uuid, mac_address, jwt_secret, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		"550e8400-e29b-41d4-a716-446655440000", "AA:BB:CC:DD:EE:FF", "encrypted", "active", "2025-11-10 14:30:00", "2025-11-10 14:30:00").Error; err != nil {
+		t.Fatalf("failed to insert legacy node: %v", err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+
+	if applied := loadApplied(t, db); len(applied) != len(migrations) {
+		t.Errorf("applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	var count int64
+	if err := db.Model(&models.Node{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count nodes: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("node count = %d, want 1", count)
+	}
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types published by the services
+const (
+	TypeNodeRegistered    = "node.registered"
+	TypeNodeReRegistered  = "node.re_registered"
+	TypeNodeStatusChanged = "node.status_changed"
+)
+
+// DefaultSubscriberBuffer is the number of events buffered per subscriber
+// Events published while a subscriber's buffer is full are dropped for that subscriber
+const DefaultSubscriberBuffer = 64
+
+// Event is a fleet change pushed to live subscribers (e.g. admin dashboards)
+type Event struct {
+	Type       string    `json:"type" example:"node.registered"`
+	NodeUUID   string    `json:"node_uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MacAddress string    `json:"mac_address,omitempty" example:"AA:BB:CC:DD:EE:FF"`
+	Status     string    `json:"status,omitempty" example:"active"`
+	OccurredAt time.Time `json:"occurred_at" example:"2025-11-10T14:30:00Z"`
+}
+
+// Broker is an in-process publish/subscribe hub for events
+// Publishing never blocks: slow subscribers lose events instead of stalling the publisher
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker creates a new event broker
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events published after it was created
+type Subscription struct {
+	events  chan Event
+	dropped atomic.Int64
+	once    sync.Once
+	broker  *Broker
+}
+
+// Events returns the channel delivering events; it is closed on Unsubscribe
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns how many events were discarded because the buffer was full
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops delivery and closes the events channel
+// Safe to call more than once
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.broker.mu.Lock()
+		delete(s.broker.subscribers, s)
+		s.broker.mu.Unlock()
+		close(s.events)
+	})
+}
+
+// Subscribe registers a new subscriber with the given buffer size
+// If bufferSize is below 1, DefaultSubscriberBuffer is used
+func (b *Broker) Subscribe(bufferSize int) *Subscription {
+	if bufferSize < 1 {
+		bufferSize = DefaultSubscriberBuffer
+	}
+
+	sub := &Subscription{
+		events: make(chan Event, bufferSize),
+		broker: b,
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Publish delivers an event to all current subscribers without blocking
+// A nil broker is a no-op so services can run without live streaming
+func (b *Broker) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Broker) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
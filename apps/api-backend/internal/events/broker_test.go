@@ -0,0 +1,67 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBrokerPublishSubscribe(t *testing.T) {
+	broker := NewBroker()
+	first := broker.Subscribe(4)
+	second := broker.Subscribe(4)
+
+	broker.Publish(Event{Type: TypeNodeRegistered, NodeUUID: "node-1"})
+
+	for name, sub := range map[string]*Subscription{"first": first, "second": second} {
+		select {
+		case event := <-sub.Events():
+			if event.Type != TypeNodeRegistered || event.NodeUUID != "node-1" {
+				t.Errorf("%s subscriber got %+v", name, event)
+			}
+			if event.OccurredAt.IsZero() {
+				t.Errorf("%s subscriber event has zero OccurredAt", name)
+			}
+		default:
+			t.Errorf("%s subscriber received no event", name)
+		}
+	}
+}
+
+func TestBrokerDropsWhenBufferFull(t *testing.T) {
+	broker := NewBroker()
+	sub := broker.Subscribe(2)
+
+	// Publishing must not block even though nobody is reading
+	for i := 0; i < 5; i++ {
+		broker.Publish(Event{Type: TypeNodeReRegistered})
+	}
+
+	if got := len(sub.Events()); got != 2 {
+		t.Errorf("buffered events = %d, want 2", got)
+	}
+	if got := sub.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+}
+
+func TestBrokerUnsubscribe(t *testing.T) {
+	broker := NewBroker()
+	sub := broker.Subscribe(1)
+
+	sub.Unsubscribe()
+	sub.Unsubscribe() // Must be safe to call twice
+
+	if broker.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount() = %d, want 0", broker.SubscriberCount())
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Events() channel should be closed after Unsubscribe()")
+	}
+
+	// Publishing after unsubscribe must not panic on the closed channel
+	broker.Publish(Event{Type: TypeNodeStatusChanged})
+}
+
+func TestNilBrokerPublish(t *testing.T) {
+	var broker *Broker
+	broker.Publish(Event{Type: TypeNodeRegistered}) // Must be a no-op
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuthHandler handles the admin email login flow
+type AdminAuthHandler struct {
+	authService *services.AdminAuthService
+}
+
+// NewAdminAuthHandler creates a new admin auth handler
+func NewAdminAuthHandler(authService *services.AdminAuthService) *AdminAuthHandler {
+	return &AdminAuthHandler{
+		authService: authService,
+	}
+}
+
+// AdminTokenRequestResponse confirms a login token request
+type AdminTokenRequestResponse struct {
+	Message string `json:"message" example:"If the email is authorized, a login token has been sent"`
+}
+
+// credentialName names what login emails carry, for response messages
+func (h *AdminAuthHandler) credentialName() string {
+	if h.authService.LoginMethod() == services.AdminLoginMethodCode {
+		return "login code"
+	}
+	return "login token"
+}
+
+// RequestToken handles POST /admin/auth/request
+// @Summary Request admin login token
+// @Description Email an admin login token, valid for ADMIN_TOKEN_TTL_HOURS (default 24), to an authorized admin address. The response is the same whether or not the email is authorized. Each email may request one token per token lifetime.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Param request body services.AdminTokenRequest true "Admin email"
+// @Success 202 {object} AdminTokenRequestResponse "Request accepted"
+// @Failure 400 {object} ErrorResponse "Invalid email"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 429 {object} ErrorResponse "A token was already sent within the token lifetime; Retry-After gives the seconds until the next request is allowed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login is not configured"
+// @Router /admin/auth/request [post]
+func (h *AdminAuthHandler) RequestToken(c *gin.Context) {
+	var req services.AdminTokenRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.RequestToken(&req); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "rate limit"):
+			statusCode = http.StatusTooManyRequests
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:       errorCode(err, statusCode),
+			Error:      "Failed to request login token",
+			Message:    err.Error(),
+			RetryAfter: retryAfter(c, err),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, AdminTokenRequestResponse{
+		Message: "If the email is authorized, a " + h.credentialName() + " has been sent",
+	})
+}
+
+// ReissueToken handles POST /admin/auth/reissue
+// @Summary Reissue admin login token to another address
+// @Description Recovery path when an admin's primary inbox is unavailable: email a login token to the admin's secondary address configured in ADMIN_SECONDARY_EMAILS (or explicitly to the primary). Both addresses must be authorized admins; the token authenticates as the address it is sent to. Each address may receive one token per token lifetime (ADMIN_TOKEN_TTL_HOURS). The response is the same whether or not a token was sent.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Param request body services.AdminTokenReissueRequest true "Admin email and delivery target"
+// @Success 202 {object} AdminTokenRequestResponse "Request accepted"
+// @Failure 400 {object} ErrorResponse "Invalid email or delivery target"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 429 {object} ErrorResponse "A token was already sent to the selected address within the token lifetime; Retry-After gives the seconds until the next request is allowed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login is not configured"
+// @Router /admin/auth/reissue [post]
+func (h *AdminAuthHandler) ReissueToken(c *gin.Context) {
+	var req services.AdminTokenReissueRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.ReissueToken(&req); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "rate limit"):
+			statusCode = http.StatusTooManyRequests
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:       errorCode(err, statusCode),
+			Error:      "Failed to reissue login token",
+			Message:    err.Error(),
+			RetryAfter: retryAfter(c, err),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, AdminTokenRequestResponse{
+		Message: "If the email and selected address are authorized, a " + h.credentialName() + " has been sent",
+	})
+}
+
+// ResendToken handles POST /admin/auth/resend
+// @Summary Resend the current admin login token
+// @Description For an admin who did not receive the login email: email the still-valid token from the last request again instead of waiting out the one-token-per-lifetime limit. The same token is sent, so its expiry and the rate-limit window are unchanged. Each token can be resent 3 times; further resends are accepted but send nothing. Only available with ADMIN_LOGIN_METHOD=token. The response is the same whether or not a token was sent.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Param request body services.AdminTokenRequest true "Admin email"
+// @Success 202 {object} AdminTokenRequestResponse "Request accepted"
+// @Failure 400 {object} ErrorResponse "Invalid email, or the login method is code"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login is not configured"
+// @Router /admin/auth/resend [post]
+func (h *AdminAuthHandler) ResendToken(c *gin.Context) {
+	var req services.AdminTokenRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.ResendToken(&req); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to resend login token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, AdminTokenRequestResponse{
+		Message: "If the email is authorized and has a valid login token, it has been sent again",
+	})
+}
+
+// VerifyCode handles POST /admin/auth/verify
+// @Summary Exchange a login code for an admin token
+// @Description When ADMIN_LOGIN_METHOD=code, login emails carry a short numeric code instead of the token. Exchange it here for a 24-hour admin token. Codes are single-use and expire after 10 minutes; after 5 wrong codes the current code is locked until it expires.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Param request body services.AdminCodeVerifyRequest true "Admin email and login code"
+// @Success 200 {object} services.AdminCodeVerifyResponse "Admin token"
+// @Failure 400 {object} ErrorResponse "Invalid email or code format"
+// @Failure 401 {object} ErrorResponse "Code is wrong, expired, locked or already used"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login or login codes are not configured"
+// @Router /admin/auth/verify [post]
+func (h *AdminAuthHandler) VerifyCode(c *gin.Context) {
+	var req services.AdminCodeVerifyRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.authService.VerifyCode(&req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "login code rejected"),
+			strings.Contains(err.Error(), "no longer authorized"):
+			statusCode = http.StatusUnauthorized
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to verify login code",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// RotateSecret handles POST /admin/auth/rotate-secret
+// @Summary Rotate the admin JWT secret
+// @Description Incident response when the admin signing key may be compromised: replace the admin JWT secret with a new random one and revoke every issued admin token. This logs out all admins, including the caller, who may then request a new login token immediately. Requires {"confirm": true}. The new secret is returned once and kept in memory only; store it as ADMIN_JWT_SECRET in your secret store, or a restart reverts to the previous secret. The rotation is recorded in the audit log.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.AdminSecretRotationRequest true "Confirmation"
+// @Success 200 {object} services.AdminSecretRotationResponse "Secret rotated"
+// @Failure 400 {object} ErrorResponse "Confirmation missing"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login is not configured"
+// @Router /admin/auth/rotate-secret [post]
+func (h *AdminAuthHandler) RotateSecret(c *gin.Context) {
+	var req services.AdminSecretRotationRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rotatedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	response, err := h.authService.RotateJWTSecret(&req, rotatedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to rotate admin JWT secret",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	respond(c, http.StatusOK, response)
+}
+
+// InspectToken handles POST /admin/auth/inspect
+// @Summary Inspect an admin token
+// @Description Troubleshoot "my token doesn't work" reports: decode an admin JWT and report its claims and each check separately (signature, expiry, issued by this server, email on the allowlist), with the reasons it would be rejected.
+// @Tags admin-auth
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.AdminTokenInspectRequest true "Token to inspect"
+// @Success 200 {object} services.AdminTokenInspectResponse "Decoded token and check results"
+// @Failure 400 {object} ErrorResponse "Token cannot be decoded"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Admin login is not configured"
+// @Router /admin/auth/inspect [post]
+func (h *AdminAuthHandler) InspectToken(c *gin.Context) {
+	var req services.AdminTokenInspectRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.authService.InspectToken(&req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to inspect admin token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
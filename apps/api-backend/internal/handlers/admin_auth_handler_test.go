@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/boomchecker/api-backend/internal/templates"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestAdminAuthHandler_RequestTokenRetryAfter tests that a rate-limited login request reports when to retry
+func TestAdminAuthHandler_RequestTokenRetryAfter(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	service := services.NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, &services.AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       strings.Repeat("s", crypto.MinAdminJWTSecretLength),
+	})
+
+	// A token sent an hour ago leaves 23 hours of the request window
+	if err := tokenRepo.Create(&models.AdminToken{
+		ID: "jti-1", Email: "ops@example.com", TokenHash: crypto.HashToken("token"),
+		RequestedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(23 * time.Hour),
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/auth/request", NewAdminAuthHandler(service).RequestToken)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/auth/request", strings.NewReader(`{"email": "ops@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+
+	header, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("Retry-After header = %q, want seconds", recorder.Header().Get("Retry-After"))
+	}
+	want := int((23 * time.Hour).Seconds())
+	if header < want-60 || header > want {
+		t.Errorf("Retry-After = %d, want about %d", header, want)
+	}
+
+	var body ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if body.Code != string(services.ErrCodeRateLimited) || body.RetryAfter != header {
+		t.Errorf("body = %+v, want RATE_LIMITED with retry_after %d", body, header)
+	}
+}
+
+// jwtPattern finds a compact JWT in an email body
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+// TestAdminAuthFlow_EmailedToken tests the admin login flow end to end: a token is requested,
+// read from the recorded email and accepted by the admin middleware
+func TestAdminAuthFlow_EmailedToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	renderer, err := templates.NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	emailer := services.NewMemoryEmailer()
+	service := services.NewAdminAuthService(repositories.NewAdminUserRepository(db), repositories.NewAdminTokenRepository(db), nil, emailer, renderer, nil, &services.AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       strings.Repeat("s", crypto.MinAdminJWTSecretLength),
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/auth/request", NewAdminAuthHandler(service).RequestToken)
+	router.GET("/admin/whoami", middleware.AdminAuthMiddleware(service), func(c *gin.Context) {
+		email, _ := middleware.GetAuthenticatedAdminEmail(c)
+		c.String(http.StatusOK, email)
+	})
+
+	send := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+	request := func(email string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/auth/request", strings.NewReader(`{"email": "`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return send(req).Code
+	}
+	whoami := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return send(req)
+	}
+
+	// An unauthorized email gets the same answer but no email
+	if status := request("intruder@example.com"); status != http.StatusAccepted {
+		t.Fatalf("request for unauthorized email status = %d, want %d", status, http.StatusAccepted)
+	}
+	if sent := emailer.Sent(); len(sent) != 0 {
+		t.Fatalf("emails after unauthorized request = %d, want 0", len(sent))
+	}
+
+	if status := request("ops@example.com"); status != http.StatusAccepted {
+		t.Fatalf("request status = %d, want %d", status, http.StatusAccepted)
+	}
+	sent := emailer.Sent()
+	if len(sent) != 1 || sent[0].To != "ops@example.com" {
+		t.Fatalf("emails = %+v, want one to ops@example.com", sent)
+	}
+	token := jwtPattern.FindString(sent[0].HTMLBody)
+	if token == "" {
+		t.Fatalf("email body carries no token: %s", sent[0].HTMLBody)
+	}
+
+	if recorder := whoami(token); recorder.Code != http.StatusOK || recorder.Body.String() != "ops@example.com" {
+		t.Errorf("with emailed token: status = %d, body = %q, want 200 ops@example.com", recorder.Code, recorder.Body.String())
+	}
+	if recorder := whoami(token + "x"); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("with tampered token: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
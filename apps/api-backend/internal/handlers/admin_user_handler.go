@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminUserHandler handles HTTP requests for managing the admin email allowlist
+type AdminUserHandler struct {
+	adminService *services.AdminAuthService
+}
+
+// NewAdminUserHandler creates a new admin user handler
+func NewAdminUserHandler(adminService *services.AdminAuthService) *AdminUserHandler {
+	return &AdminUserHandler{
+		adminService: adminService,
+	}
+}
+
+// ListAdmins handles GET /admin/admins
+// @Summary List admin emails
+// @Description List the admin emails stored in the database. While none are stored, the ADMIN_EMAILS bootstrap list is authorized instead (bootstrap_active=true).
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.AdminListResponse "Admin allowlist"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/admins [get]
+func (h *AdminUserHandler) ListAdmins(c *gin.Context) {
+	admins, err := h.adminService.ListAdmins()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to list admins",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, admins)
+}
+
+// AddAdmin handles POST /admin/admins
+// @Summary Add admin email
+// @Description Authorize an email address as admin. The first stored admin ends the ADMIN_EMAILS bootstrap, so add your own email first.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.AddAdminRequest true "Admin email"
+// @Success 201 {object} services.AdminUserResponse "Admin email added"
+// @Failure 400 {object} ErrorResponse "Invalid email"
+// @Failure 409 {object} ErrorResponse "Admin email already exists"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/admins [post]
+func (h *AdminUserHandler) AddAdmin(c *gin.Context) {
+	var req services.AddAdminRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	admin, err := h.adminService.AddAdmin(&req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to add admin",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusCreated, admin)
+}
+
+// RemoveAdmin handles DELETE /admin/admins/:email
+// @Summary Remove admin email
+// @Description Remove an email address from the admin allowlist. Removing the last stored admin re-enables the ADMIN_EMAILS bootstrap list.
+// @Tags admin
+// @Security AdminAuth
+// @Param email path string true "Admin email"
+// @Success 204 "Admin email removed"
+// @Failure 400 {object} ErrorResponse "Invalid email"
+// @Failure 404 {object} ErrorResponse "Admin email not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/admins/{email} [delete]
+func (h *AdminUserHandler) RemoveAdmin(c *gin.Context) {
+	if err := h.adminService.RemoveAdmin(c.Param("email")); err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "admin email not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to remove admin",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogHandler serves the audit log of admin actions
+type AuditLogHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(auditService *services.AuditService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEntries handles GET /admin/audit-log
+// @Summary Query the audit log
+// @Description Return security-relevant admin actions (admin secret rotation, node token revocation, cleanup pause/resume) filtered by action, admin, target and time range, one page at a time
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param action query string false "Action (e.g. node_token.revoked)"
+// @Param admin_email query string false "Admin who performed the action"
+// @Param target_type query string false "Target type" Enums(admin, node, token, cleanup, mac)
+// @Param target_id query string false "Target ID (e.g. node UUID)"
+// @Param from query string false "Earliest time, inclusive (UTC RFC3339)"
+// @Param to query string false "Latest time, inclusive (UTC RFC3339)"
+// @Param page query int false "Page number (1-based)" default(1)
+// @Param page_size query int false "Entries per page (1-200)" default(50)
+// @Param sort query string false "Sort by time" Enums(asc, desc) default(desc)
+// @Success 200 {object} services.AuditLogListResponse "Audit log page"
+// @Failure 400 {object} ErrorResponse "Invalid filter"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/audit-log [get]
+func (h *AuditLogHandler) ListEntries(c *gin.Context) {
+	var query services.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	entries, err := h.auditService.Query(&query)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to query audit log",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, entries)
+}
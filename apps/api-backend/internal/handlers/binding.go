@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindJSON binds the JSON request body into obj and writes the error response on failure
+// Bodies cut off by the body size limit are reported as 413, all other binding errors as 400.
+// Errors about specific fields are listed in the response's fields map, keyed by JSON field name.
+// Returns false if the handler should stop.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	return respondBindError(c, obj, c.ShouldBindJSON(obj))
+}
+
+// bindJSONStrict is bindJSON that also rejects fields the request type does not define
+// A misspelled field (e.g. "mac_adress") is then reported by name instead of surfacing as
+// a confusing "is required" error for the field the client meant.
+func bindJSONStrict(c *gin.Context, obj interface{}) bool {
+	if c.Request.Body == nil {
+		return respondBindError(c, obj, errors.New("invalid request"))
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(obj)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(obj)
+	}
+	return respondBindError(c, obj, err)
+}
+
+// respondBindError writes the error response for a failed bind
+// Returns true if err is nil and the handler should continue.
+func respondBindError(c *gin.Context, obj interface{}, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Code:    string(services.ErrCodePayloadTooLarge),
+			Error:   "Request body too large",
+			Message: fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit),
+		})
+		return false
+	}
+
+	message := err.Error()
+	fields := bindingFieldErrors(obj, err)
+	if len(fields) > 0 {
+		message = "validation failed: " + formatFieldErrors(fields)
+	}
+
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Code:    string(services.ErrCodeValidationFailed),
+		Error:   "Invalid request format",
+		Message: message,
+		Fields:  fields,
+	})
+	return false
+}
+
+// bindingFieldErrors translates a binding error into readable messages keyed by JSON field path
+// (e.g. "mac_address" or "nodes[0].mac_address"). Returns nil when the error is not about
+// specific fields, such as malformed JSON.
+func bindingFieldErrors(obj interface{}, err error) map[string]string {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make(map[string]string, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields[jsonFieldPath(reflect.TypeOf(obj), fieldErr.StructNamespace())] = validationRuleMessage(fieldErr)
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string]string{typeErr.Field: "must be " + jsonTypeName(typeErr.Type)}
+	}
+
+	// The decoder reports unknown fields only as text: json: unknown field "name"
+	if quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if field, unquoteErr := strconv.Unquote(quoted); unquoteErr == nil {
+			return map[string]string{field: "is not a known field"}
+		}
+	}
+
+	return nil
+}
+
+// formatFieldErrors joins field errors into one message, sorted by field for stable output
+func formatFieldErrors(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + " " + fields[name]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// validationRuleMessage describes a failed binding rule in words
+func validationRuleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min", "gte":
+		return "must be at least " + param + sizeUnit(fieldErr.Kind(), param)
+	case "max", "lte":
+		return "must be at most " + param + sizeUnit(fieldErr.Kind(), param)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	default:
+		if param != "" {
+			return fmt.Sprintf("failed the %s=%s rule", fieldErr.Tag(), param)
+		}
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
+// sizeUnit names what a min/max limit counts for values of the given kind
+func sizeUnit(kind reflect.Kind, limit string) string {
+	unit := ""
+	switch kind {
+	case reflect.String:
+		unit = " character"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " item"
+	default:
+		return ""
+	}
+	if limit != "1" {
+		unit += "s"
+	}
+	return unit
+}
+
+// jsonTypeName names the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// jsonFieldPath converts a validator struct namespace such as "ImportNodesRequest.Nodes[0].MacAddress"
+// into the JSON path the client sent ("nodes[0].mac_address")
+// Fields without a JSON tag keep their Go name.
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		// The first part is the name of the bound struct itself
+		parts = parts[1:]
+	}
+
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name, index, hasIndex := strings.Cut(part, "[")
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		var field reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			field, found = t.FieldByName(name)
+		}
+		if !found {
+			path = append(path, part)
+			t = nil
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			jsonName = field.Name
+		}
+
+		t = field.Type
+		if hasIndex {
+			jsonName += "[" + index
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+
+		path = append(path, jsonName)
+	}
+
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postJSON sends body to path on router and decodes the error response
+func postJSON(t *testing.T, router *gin.Engine, path, body string) (int, ErrorResponse) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, req)
+
+	var response ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+// TestBindJSON_FieldErrors tests that binding failures are reported per JSON field
+// The services are never reached, so the handlers are built without them.
+func TestBindJSON_FieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/nodes/register", NewNodeRegistrationHandler(nil, false).RegisterNode)
+	router.POST("/admin/registration-node-tokens", NewTokenManagementHandler(nil, false).CreateToken)
+	router.POST("/admin/nodes/import", NewNodeManagementHandler(nil, nil, nil).ImportNodes)
+
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		wantFields  map[string]string
+		wantMessage string
+	}{
+		{
+			name: "registration without required fields",
+			path: "/nodes/register",
+			body: `{"firmware_version": "1.0.0"}`,
+			wantFields: map[string]string{
+				"registration_token": "is required",
+				"mac_address":        "is required",
+			},
+			wantMessage: "validation failed: mac_address is required; registration_token is required",
+		},
+		{
+			name:        "registration with wrong type",
+			path:        "/nodes/register",
+			body:        `{"registration_token": "t", "mac_address": "AA:BB:CC:DD:EE:FF", "latitude": "north"}`,
+			wantFields:  map[string]string{"latitude": "must be a number"},
+			wantMessage: "validation failed: latitude must be a number",
+		},
+		{
+			name:        "token creation below minimum",
+			path:        "/admin/registration-node-tokens",
+			body:        `{"max_uses": 0}`,
+			wantFields:  map[string]string{"max_uses": "must be at least 1"},
+			wantMessage: "validation failed: max_uses must be at least 1",
+		},
+		{
+			name:        "token creation with wrong type",
+			path:        "/admin/registration-node-tokens",
+			body:        `{"expires_in_hours": "tomorrow"}`,
+			wantFields:  map[string]string{"expires_in_hours": "must be a number"},
+			wantMessage: "validation failed: expires_in_hours must be a number",
+		},
+		{
+			name:        "empty import",
+			path:        "/admin/nodes/import",
+			body:        `{"nodes": []}`,
+			wantFields:  map[string]string{"nodes": "must be at least 1 item"},
+			wantMessage: "validation failed: nodes must be at least 1 item",
+		},
+		{
+			name:        "import record without MAC",
+			path:        "/admin/nodes/import",
+			body:        `{"nodes": [{"mac_address": "AA:BB:CC:DD:EE:FF"}, {"name": "Garden"}]}`,
+			wantFields:  map[string]string{"nodes[1].mac_address": "is required"},
+			wantMessage: "validation failed: nodes[1].mac_address is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := postJSON(t, router, tt.path, tt.body)
+
+			if status != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+			}
+			if response.Code != "VALIDATION_FAILED" {
+				t.Errorf("code = %s, want VALIDATION_FAILED", response.Code)
+			}
+			if len(response.Fields) != len(tt.wantFields) {
+				t.Errorf("fields = %v, want %v", response.Fields, tt.wantFields)
+			}
+			for field, want := range tt.wantFields {
+				if got := response.Fields[field]; got != want {
+					t.Errorf("fields[%s] = %q, want %q", field, got, want)
+				}
+			}
+			if response.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", response.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+// TestBindJSON_MalformedBody tests that JSON syntax errors carry no field map
+func TestBindJSON_MalformedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/nodes/register", NewNodeRegistrationHandler(nil, false).RegisterNode)
+
+	status, response := postJSON(t, router, "/nodes/register", `{"mac_address": `)
+	if status != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if response.Fields != nil {
+		t.Errorf("fields = %v, want none", response.Fields)
+	}
+	if response.Message == "" {
+		t.Error("message is empty")
+	}
+}
+
+// TestBindJSONStrict_UnknownFields tests that strict handlers name an unexpected field
+// while lenient handlers keep ignoring it
+func TestBindJSONStrict_UnknownFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	strict := gin.New()
+	strict.POST("/nodes/register", NewNodeRegistrationHandler(nil, true).RegisterNode)
+	strict.POST("/admin/registration-node-tokens", NewTokenManagementHandler(nil, true).CreateToken)
+	lenient := gin.New()
+	lenient.POST("/nodes/register", NewNodeRegistrationHandler(nil, false).RegisterNode)
+
+	tests := []struct {
+		name        string
+		router      *gin.Engine
+		path        string
+		body        string
+		wantFields  map[string]string
+		wantMessage string
+	}{
+		{
+			name:        "strict registration with misspelled field",
+			router:      strict,
+			path:        "/nodes/register",
+			body:        `{"registration_token": "t", "mac_adress": "AA:BB:CC:DD:EE:FF"}`,
+			wantFields:  map[string]string{"mac_adress": "is not a known field"},
+			wantMessage: "validation failed: mac_adress is not a known field",
+		},
+		{
+			name:        "strict token creation with extra field",
+			router:      strict,
+			path:        "/admin/registration-node-tokens",
+			body:        `{"max_uses": 5, "expires_in": 24}`,
+			wantFields:  map[string]string{"expires_in": "is not a known field"},
+			wantMessage: "validation failed: expires_in is not a known field",
+		},
+		{
+			name:        "strict registration still validates known fields",
+			router:      strict,
+			path:        "/nodes/register",
+			body:        `{"registration_token": "t"}`,
+			wantFields:  map[string]string{"mac_address": "is required"},
+			wantMessage: "validation failed: mac_address is required",
+		},
+		{
+			name:        "lenient registration ignores the misspelled field",
+			router:      lenient,
+			path:        "/nodes/register",
+			body:        `{"registration_token": "t", "mac_adress": "AA:BB:CC:DD:EE:FF"}`,
+			wantFields:  map[string]string{"mac_address": "is required"},
+			wantMessage: "validation failed: mac_address is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := postJSON(t, tt.router, tt.path, tt.body)
+
+			if status != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", status, http.StatusBadRequest)
+			}
+			if len(response.Fields) != len(tt.wantFields) {
+				t.Errorf("fields = %v, want %v", response.Fields, tt.wantFields)
+			}
+			for field, want := range tt.wantFields {
+				if got := response.Fields[field]; got != want {
+					t.Errorf("fields[%s] = %q, want %q", field, got, want)
+				}
+			}
+			if response.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", response.Message, tt.wantMessage)
+			}
+		})
+	}
+}
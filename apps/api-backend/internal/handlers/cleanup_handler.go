@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CleanupHandler exposes and controls the background token cleanup job
+type CleanupHandler struct {
+	cleanupService *services.CleanupService
+}
+
+// NewCleanupHandler creates a new cleanup handler
+func NewCleanupHandler(cleanupService *services.CleanupService) *CleanupHandler {
+	return &CleanupHandler{
+		cleanupService: cleanupService,
+	}
+}
+
+// GetLastRun handles GET /admin/cleanup/last-run
+// @Summary Last automatic cleanup run
+// @Description Report the expired tokens removed by the most recent background cleanup run: their IDs, creation and expiry dates, and the total removed. Up to 1000 tokens are listed. The report is kept in memory and resets on restart.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.CleanupRunResponse "Last cleanup run"
+// @Failure 404 {object} ErrorResponse "No cleanup has run since the server started"
+// @Router /admin/cleanup/last-run [get]
+func (h *CleanupHandler) GetLastRun(c *gin.Context) {
+	run := h.cleanupService.LastRun()
+	if run == nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    string(services.ErrCodeNotFound),
+			Error:   "No cleanup run",
+			Message: "no cleanup has run since the server started",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, run)
+}
+
+// Pause handles POST /admin/cleanup/pause
+// @Summary Pause automatic cleanup
+// @Description Stop the background cleanup from deleting expired tokens and revocation entries, e.g. to preserve evidence during an incident. The flag is persisted and survives restarts. Cleanup on demand via POST /admin/registration-node-tokens/cleanup still works.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.CleanupStateResponse "Cleanup state"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/cleanup/pause [post]
+func (h *CleanupHandler) Pause(c *gin.Context) {
+	pausedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	state, err := h.cleanupService.Pause(pausedBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to pause cleanup",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, state)
+}
+
+// Resume handles POST /admin/cleanup/resume
+// @Summary Resume automatic cleanup
+// @Description Re-enable the background cleanup; the next run happens at the next interval
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.CleanupStateResponse "Cleanup state"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/cleanup/resume [post]
+func (h *CleanupHandler) Resume(c *gin.Context) {
+	resumedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	state, err := h.cleanupService.Resume(resumedBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to resume cleanup",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, state)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailHandler serves email setup diagnostics
+type EmailHandler struct {
+	diagnosticsService *services.EmailDiagnosticsService
+}
+
+// NewEmailHandler creates a new email handler
+func NewEmailHandler(diagnosticsService *services.EmailDiagnosticsService) *EmailHandler {
+	return &EmailHandler{
+		diagnosticsService: diagnosticsService,
+	}
+}
+
+// SendTestEmail handles POST /admin/email/test
+// @Summary Send a test email
+// @Description Verify the SMTP configuration by sending a short test email to an authorized admin address (by default the logged-in admin). Returns the mail server's error when delivery fails. The body is optional. One test email may be sent per minute.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.EmailTestRequest false "Recipient"
+// @Success 200 {object} services.EmailTestResponse "Test email accepted by the mail server"
+// @Failure 400 {object} ErrorResponse "Recipient missing or not an authorized admin"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 429 {object} ErrorResponse "A test email was sent less than a minute ago; Retry-After gives the seconds to wait"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Mail server rejected the email or could not be reached"
+// @Failure 503 {object} ErrorResponse "Email is not configured"
+// @Router /admin/email/test [post]
+func (h *EmailHandler) SendTestEmail(c *gin.Context) {
+	var req services.EmailTestRequest
+
+	// The recipient is optional, so an empty body is accepted
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	requestedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	response, err := h.diagnosticsService.SendTestEmail(&req, requestedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "rate limit"):
+			statusCode = http.StatusTooManyRequests
+		case strings.Contains(err.Error(), "not configured"):
+			statusCode = http.StatusServiceUnavailable
+		case strings.Contains(err.Error(), "failed to send test email"):
+			statusCode = http.StatusBadGateway
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:       errorCode(err, statusCode),
+			Error:      "Failed to send test email",
+			Message:    err.Error(),
+			RetryAfter: retryAfter(c, err),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
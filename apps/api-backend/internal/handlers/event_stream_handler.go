@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/events"
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamKeepAlive is how often a comment is sent to keep idle connections open through proxies
+const eventStreamKeepAlive = 15 * time.Second
+
+// EventStreamHandler streams fleet events to admin dashboards via Server-Sent Events
+type EventStreamHandler struct {
+	broker *events.Broker
+}
+
+// NewEventStreamHandler creates a new event stream handler
+func NewEventStreamHandler(broker *events.Broker) *EventStreamHandler {
+	return &EventStreamHandler{
+		broker: broker,
+	}
+}
+
+// Stream handles GET /admin/events/stream
+// @Summary Stream fleet events
+// @Description Server-Sent Events stream pushing a message whenever a node registers, re-registers, or changes status. Each message's event name is the event type and its data is an events.Event JSON object. Slow clients may miss events.
+// @Tags admin
+// @Produce text/event-stream
+// @Security AdminAuth
+// @Success 200 {object} events.Event "Event stream"
+// @Router /admin/events/stream [get]
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	sub := h.broker.Subscribe(events.DefaultSubscriberBuffer)
+	defer sub.Unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+	// Flush headers immediately so clients know the stream is open
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			// Client disconnected
+			return false
+		case event, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+			return true
+		}
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/boomchecker/api-backend/internal/version"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,3 +26,71 @@ func PingHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// VersionHandler handles the /version endpoint
+// @Summary Build information
+// @Description Report the version, git commit and build time of the running server, plus its Go runtime version
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.VersionResponse "Build information"
+// @Router /version [get]
+func VersionHandler(c *gin.Context) {
+	info := version.Get()
+
+	c.JSON(http.StatusOK, models.VersionResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	})
+}
+
+// HealthHandler handles the /health and /ready endpoints
+type HealthHandler struct {
+	healthService    *services.HealthService
+	readinessService *services.ReadinessService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService *services.HealthService, readinessService *services.ReadinessService) *HealthHandler {
+	return &HealthHandler{
+		healthService:    healthService,
+		readinessService: readinessService,
+	}
+}
+
+// Health handles GET /health
+// @Summary Subsystem health check
+// @Description Check the database, that JWT_ENCRYPTION_KEY still decrypts stored node secrets, and, when enabled, email (SMTP) reachability. Email results are cached briefly.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "All checks passed"
+// @Failure 503 {object} models.HealthResponse "At least one check failed"
+// @Router /health [get]
+func (h *HealthHandler) Health(c *gin.Context) {
+	response, healthy := h.healthService.Check(c.Request.Context())
+	if !healthy {
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Ready handles GET /ready
+// @Summary Readiness check
+// @Description Report whether startup completed: database migrated and still reachable, background cleanup started once the server accepts requests. Returns 200 only when every step is done, so orchestrators can hold traffic until then. /ping only says the process is up.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse "Ready to serve"
+// @Failure 503 {object} models.HealthResponse "Startup incomplete or a subsystem is unreachable"
+// @Router /ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	response, ready := h.readinessService.Check(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TestHealthHandler_Ready tests that /ready answers 503 while a startup step is pending
+func TestHealthHandler_Ready(t *testing.T) {
+	readinessService := services.NewReadinessService(services.ReadinessStepDatabase, services.ReadinessStepCleanupService)
+	readinessService.MarkReady(services.ReadinessStepDatabase, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ready", NewHealthHandler(services.NewHealthService(), readinessService).Ready)
+
+	get := func() (*httptest.ResponseRecorder, models.HealthResponse) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		var response models.HealthResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return recorder, response
+	}
+
+	pending, response := get()
+	if pending.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status while cleanup pending = %d, want %d", pending.Code, http.StatusServiceUnavailable)
+	}
+	if check := response.Checks[services.ReadinessStepCleanupService]; check == nil || check.Status == services.HealthStatusOK {
+		t.Errorf("cleanup_service check = %+v, want not initialized", check)
+	}
+
+	readinessService.MarkReady(services.ReadinessStepCleanupService, nil)
+	if ready, _ := get(); ready.Code != http.StatusOK {
+		t.Errorf("status after startup = %d, want %d", ready.Code, http.StatusOK)
+	}
+}
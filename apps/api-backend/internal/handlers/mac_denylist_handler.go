@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MACDenylistHandler handles HTTP requests for the MAC denylist
+type MACDenylistHandler struct {
+	denylistService *services.MACDenylistService
+}
+
+// NewMACDenylistHandler creates a new MAC denylist handler
+func NewMACDenylistHandler(denylistService *services.MACDenylistService) *MACDenylistHandler {
+	return &MACDenylistHandler{
+		denylistService: denylistService,
+	}
+}
+
+// ListDeniedMACs handles GET /admin/mac-denylist
+// @Summary List denylisted MACs
+// @Description Return every MAC address banned from registering, most recently banned first
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.DeniedMACListResponse "Denylisted MAC addresses"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/mac-denylist [get]
+func (h *MACDenylistHandler) ListDeniedMACs(c *gin.Context) {
+	response, err := h.denylistService.ListDeniedMACs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    string(services.ErrCodeInternal),
+			Error:   "Failed to list denylisted MAC addresses",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// DenyMAC handles POST /admin/mac-denylist
+// @Summary Denylist a MAC
+// @Description Ban a MAC address from registering, even after its node is deleted. Registration attempts are refused with 403 MAC_DENIED. A node that already exists keeps its JWT until it re-registers; revoke it to cut it off at once.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.DenyMACRequest true "MAC address to ban"
+// @Success 201 {object} services.DeniedMACResponse "Denylist entry"
+// @Failure 400 {object} ErrorResponse "Invalid MAC address or reason"
+// @Failure 409 {object} ErrorResponse "MAC address already denylisted"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/mac-denylist [post]
+func (h *MACDenylistHandler) DenyMAC(c *gin.Context) {
+	var req services.DenyMACRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	deniedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
+	response, err := h.denylistService.DenyMAC(&req, deniedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "already denylisted"):
+			statusCode = http.StatusConflict
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to denylist MAC address",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusCreated, response)
+}
+
+// AllowMAC handles DELETE /admin/mac-denylist/:mac
+// @Summary Remove a MAC from the denylist
+// @Description Lift the registration ban on a MAC address. Any notation of the address is accepted.
+// @Tags admin
+// @Security AdminAuth
+// @Param mac path string true "MAC address"
+// @Success 204 "MAC address removed from the denylist"
+// @Failure 400 {object} ErrorResponse "Invalid MAC address"
+// @Failure 404 {object} ErrorResponse "MAC address is not denylisted"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/mac-denylist/{mac} [delete]
+func (h *MACDenylistHandler) AllowMAC(c *gin.Context) {
+	allowedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
+	if err := h.denylistService.AllowMAC(c.Param("mac"), allowedBy); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to remove MAC address from denylist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler serves operational counters in the Prometheus text format
+type MetricsHandler struct {
+	tokenMetrics *services.TokenValidationMetrics
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(tokenMetrics *services.TokenValidationMetrics) *MetricsHandler {
+	return &MetricsHandler{
+		tokenMetrics: tokenMetrics,
+	}
+}
+
+// Metrics handles GET /metrics
+// @Summary Metrics
+// @Description Report counters in the Prometheus text exposition format. boomchecker_token_validations_total counts registration token checks by endpoint (register, mac_status) and outcome (valid, not_found, expired, exhausted, mac_mismatch, mac_limit). Counters restart from zero with the server.
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Prometheus metrics"
+// @Router /metrics [get]
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	var b strings.Builder
+	b.WriteString("# HELP boomchecker_token_validations_total Registration token validations by endpoint and outcome.\n")
+	b.WriteString("# TYPE boomchecker_token_validations_total counter\n")
+	for _, count := range h.tokenMetrics.Counts() {
+		fmt.Fprintf(&b, "boomchecker_token_validations_total{endpoint=%q,outcome=%q} %d\n", count.Endpoint, count.Outcome, count.Count)
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// NodeHandler handles HTTP requests from authenticated nodes
+type NodeHandler struct {
+	authService      *services.NodeAuthService
+	telemetryService *services.NodeTelemetryService
+}
+
+// NewNodeHandler creates a new node handler
+func NewNodeHandler(authService *services.NodeAuthService, telemetryService *services.NodeTelemetryService) *NodeHandler {
+	return &NodeHandler{
+		authService:      authService,
+		telemetryService: telemetryService,
+	}
+}
+
+// Heartbeat handles POST /nodes/heartbeat
+// @Summary Node heartbeat
+// @Description Lets an authenticated node report that it is alive. Updates the node's last-seen timestamp.
+// @Tags nodes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} services.HeartbeatResponse "Heartbeat accepted"
+// @Failure 401 {object} ErrorResponse "Missing or invalid node JWT"
+// @Failure 403 {object} ErrorResponse "Node is revoked or disabled"
+// @Router /nodes/heartbeat [post]
+func (h *NodeHandler) Heartbeat(c *gin.Context) {
+	node, ok := middleware.GetAuthenticatedNode(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Code:    string(services.ErrCodeUnauthorized),
+			Error:   "Unauthorized",
+			Message: "Node authentication required",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, h.authService.Heartbeat(node))
+}
+
+// ReportTelemetry handles POST /nodes/telemetry
+// @Summary Report node telemetry
+// @Description Lets an authenticated node report battery, signal strength and uptime. The reading replaces the node's previous one. Only schema_version 1 is accepted.
+// @Tags nodes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.TelemetryRequest true "Telemetry reading"
+// @Success 200 {object} services.TelemetryResponse "Telemetry stored"
+// @Failure 400 {object} ErrorResponse "Invalid request or unsupported schema version"
+// @Failure 401 {object} ErrorResponse "Missing or invalid node JWT"
+// @Failure 403 {object} ErrorResponse "Node is revoked or disabled"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /nodes/telemetry [post]
+func (h *NodeHandler) ReportTelemetry(c *gin.Context) {
+	node, ok := middleware.GetAuthenticatedNode(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Code:    string(services.ErrCodeUnauthorized),
+			Error:   "Unauthorized",
+			Message: "Node authentication required",
+		})
+		return
+	}
+
+	var req services.TelemetryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.telemetryService.RecordTelemetry(node, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to store telemetry",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
@@ -0,0 +1,768 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// NodeManagementHandler handles HTTP requests for admin node management
+type NodeManagementHandler struct {
+	nodeService       *services.NodeService
+	metadataService   *services.NodeMetadataService
+	revocationService *services.NodeTokenRevocationService
+}
+
+// NewNodeManagementHandler creates a new node management handler
+func NewNodeManagementHandler(
+	nodeService *services.NodeService,
+	metadataService *services.NodeMetadataService,
+	revocationService *services.NodeTokenRevocationService,
+) *NodeManagementHandler {
+	return &NodeManagementHandler{
+		nodeService:       nodeService,
+		metadataService:   metadataService,
+		revocationService: revocationService,
+	}
+}
+
+// ListNodes handles GET /admin/nodes
+// @Summary List nodes
+// @Description Return registered nodes, newest first, one page at a time. Node secrets are never included.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param status query string false "Filter by status (active, disabled, revoked)"
+// @Param owner_id query string false "Only nodes of this owner (tenant)"
+// @Param page query int false "Page number (1-based)" default(1)
+// @Param page_size query int false "Nodes per page (1-200)" default(50)
+// @Success 200 {object} services.NodeListResponse "Nodes"
+// @Failure 400 {object} ErrorResponse "Invalid status, owner or page parameters"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes [get]
+func (h *NodeManagementHandler) ListNodes(c *gin.Context) {
+	var query services.NodeListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	nodes, err := h.nodeService.ListNodes(&query)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to list nodes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, nodes)
+}
+
+// GetNode handles GET /admin/nodes/:uuid
+// @Summary Get node
+// @Description Return a single node by UUID. The node secret is never included. The response carries a Last-Modified header from the node's updated_at; send it back as If-Modified-Since to get 304 while the node is unchanged.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param If-Modified-Since header string false "Last-Modified value of a previous response"
+// @Success 200 {object} services.NodeResponse "Node details"
+// @Success 304 "Node unchanged since If-Modified-Since"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid} [get]
+func (h *NodeManagementHandler) GetNode(c *gin.Context) {
+	node, err := h.nodeService.GetNode(c.Param("uuid"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "node not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to get node",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if updatedAt, err := time.Parse(time.RFC3339, node.UpdatedAt); err == nil && notModifiedSince(c, updatedAt) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// notModifiedSince sets Last-Modified and reports whether the request's If-Modified-Since
+// shows the client already has this version
+// HTTP dates have one-second precision, so a resource changed during the current second gets no
+// Last-Modified: a later change within that second would otherwise be answered with 304.
+func notModifiedSince(c *gin.Context, lastModified time.Time) bool {
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.Before(time.Now().UTC().Truncate(time.Second)) {
+		return false
+	}
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+// UpdateNodeStatus handles PUT /admin/nodes/:uuid/status
+// @Summary Change node status
+// @Description Set a node to active, disabled or revoked. Disabled and revoked nodes cannot authenticate. Revocation is permanent.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.UpdateNodeStatusRequest true "New status"
+// @Success 200 {object} services.NodeResponse "Updated node"
+// @Failure 400 {object} ErrorResponse "Invalid status"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 409 {object} ErrorResponse "Node is revoked"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/status [put]
+func (h *NodeManagementHandler) UpdateNodeStatus(c *gin.Context) {
+	var req services.UpdateNodeStatusRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	changedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
+	node, err := h.nodeService.UpdateStatus(c.Param("uuid"), &req, changedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "node is revoked"):
+			statusCode = http.StatusConflict
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to update node status",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// UpdateNodeLocation handles PUT /admin/nodes/:uuid/location
+// @Summary Set node location
+// @Description Set the GPS coordinates of a node
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.UpdateNodeLocationRequest true "New coordinates"
+// @Success 200 {object} services.NodeResponse "Updated node"
+// @Failure 400 {object} ErrorResponse "Invalid coordinates"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/location [put]
+func (h *NodeManagementHandler) UpdateNodeLocation(c *gin.Context) {
+	var req services.UpdateNodeLocationRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	node, err := h.nodeService.UpdateLocation(c.Param("uuid"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to update node location",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// GetDuplicateReport handles GET /admin/nodes/duplicates
+// @Summary Node de-duplication report
+// @Description Report nodes sharing GPS coordinates, nodes sharing a name, and MAC addresses that re-registered unusually often
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param re_registration_threshold query int false "Minimum re-registrations for a MAC to be reported" default(5)
+// @Success 200 {object} services.DuplicateReportResponse "Duplicate report"
+// @Failure 400 {object} ErrorResponse "Invalid query parameter"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/duplicates [get]
+func (h *NodeManagementHandler) GetDuplicateReport(c *gin.Context) {
+	threshold := services.DefaultReRegistrationThreshold
+	if value := c.Query("re_registration_threshold"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    string(services.ErrCodeValidationFailed),
+				Error:   "Invalid request format",
+				Message: "re_registration_threshold must be a positive integer",
+			})
+			return
+		}
+		threshold = parsed
+	}
+
+	report, err := h.nodeService.GetDuplicateReport(threshold)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to build duplicate report",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, report)
+}
+
+// ImportNodes handles POST /admin/nodes/import
+// @Summary Import nodes in bulk
+// @Description Create nodes from another system without consuming registration tokens. All records are inserted in one transaction; if any record fails, nothing is imported and the per-record report is returned with status 422.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.ImportNodesRequest true "Nodes to import"
+// @Success 201 {object} services.ImportNodesResponse "Import committed"
+// @Failure 400 {object} ErrorResponse "Invalid request format"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 422 {object} services.ImportNodesResponse "Import rolled back, see per-record results"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/import [post]
+func (h *NodeManagementHandler) ImportNodes(c *gin.Context) {
+	var req services.ImportNodesRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.nodeService.ImportNodes(&req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to import nodes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !response.Committed {
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	respond(c, http.StatusCreated, response)
+}
+
+// GetFirmwareDistribution handles GET /admin/nodes/firmware-distribution
+// @Summary Firmware version distribution
+// @Description Return the number of nodes per reported firmware version. Nodes without a version are counted under "unknown".
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.FirmwareDistributionResponse "Node counts by firmware version"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/firmware-distribution [get]
+func (h *NodeManagementHandler) GetFirmwareDistribution(c *gin.Context) {
+	distribution, err := h.nodeService.GetFirmwareDistribution()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to get firmware distribution",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, distribution)
+}
+
+// GetRegistrationsByDay handles GET /admin/nodes/registrations-by-day
+// @Summary Node registrations per day
+// @Description Return how many nodes were first registered on each UTC day over the last N days, oldest first. Days without registrations are included with a zero count; re-registrations are not counted.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param days query int false "Number of days including today (1-365)" default(30)
+// @Success 200 {object} services.RegistrationsByDayResponse "Daily node registrations"
+// @Failure 400 {object} ErrorResponse "Invalid days parameter"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/registrations-by-day [get]
+func (h *NodeManagementHandler) GetRegistrationsByDay(c *gin.Context) {
+	days := 30
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    string(services.ErrCodeValidationFailed),
+				Error:   "Invalid request format",
+				Message: "days must be an integer",
+			})
+			return
+		}
+		days = parsed
+	}
+
+	series, err := h.nodeService.GetRegistrationsByDay(days)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to get registrations by day",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, series)
+}
+
+// GetNodeByMAC handles GET /admin/nodes/by-mac/:mac
+// @Summary Find node by MAC address
+// @Description Look up a node by the MAC address printed on its label. Colon, hyphen, dot and plain notations are accepted in any case.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param mac path string true "MAC address" example(aa-bb-cc-dd-ee-ff)
+// @Param include_metadata query bool false "Include the node's metadata" default(false)
+// @Success 200 {object} services.NodeResponse "Node"
+// @Failure 400 {object} ErrorResponse "Invalid MAC address or query parameter"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/by-mac/{mac} [get]
+func (h *NodeManagementHandler) GetNodeByMAC(c *gin.Context) {
+	includeMetadata, ok := queryBool(c, "include_metadata")
+	if !ok {
+		return
+	}
+
+	node, err := h.nodeService.GetNodeByMAC(c.Param("mac"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to find node",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if includeMetadata {
+		metadata, err := h.metadataService.GetMetadataMap(node.UUID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Code:    errorCode(err, http.StatusInternalServerError),
+				Error:   "Failed to get node metadata",
+				Message: err.Error(),
+			})
+			return
+		}
+		node.Metadata = metadata
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// SetTargetFirmware handles PUT /admin/nodes/:uuid/target-firmware
+// @Summary Set node target firmware
+// @Description Set the firmware version a node should update to. It is returned in the node's heartbeat response while newer than the reported version. Send null to clear.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.SetTargetFirmwareRequest true "Target firmware version"
+// @Success 200 {object} services.NodeResponse "Updated node"
+// @Failure 400 {object} ErrorResponse "Invalid version or not newer than current firmware"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/target-firmware [put]
+func (h *NodeManagementHandler) SetTargetFirmware(c *gin.Context) {
+	var req services.SetTargetFirmwareRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	node, err := h.nodeService.SetTargetFirmware(c.Param("uuid"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to set target firmware",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// RenameNode handles PUT /admin/nodes/:uuid/name
+// @Summary Rename node
+// @Description Set the display name of a node. Send null or an empty string to clear it. When UNIQUE_NODE_NAMES is enabled, a name used by another active node is rejected.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.RenameNodeRequest true "New node name"
+// @Success 200 {object} services.NodeResponse "Updated node"
+// @Failure 400 {object} ErrorResponse "Invalid name"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 409 {object} ErrorResponse "Name already used by another active node"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/name [put]
+func (h *NodeManagementHandler) RenameNode(c *gin.Context) {
+	var req services.RenameNodeRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	node, err := h.nodeService.RenameNode(c.Param("uuid"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "already in use"):
+			statusCode = http.StatusConflict
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to rename node",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// PatchNode handles PATCH /admin/nodes/:uuid
+// @Summary Update node fields
+// @Description Update any subset of name, target firmware version, location and status in one request. Omitted or null fields are left unchanged; an empty string clears name or target firmware. Latitude and longitude must be sent together. All present fields are validated before anything is written.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.PatchNodeRequest true "Fields to change"
+// @Success 200 {object} services.NodeResponse "Updated node"
+// @Failure 400 {object} ErrorResponse "Invalid field value or no fields given"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 409 {object} ErrorResponse "Name already used by another active node, or node is revoked"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid} [patch]
+func (h *NodeManagementHandler) PatchNode(c *gin.Context) {
+	var req services.PatchNodeRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	changedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
+	node, err := h.nodeService.PatchNode(c.Param("uuid"), &req, changedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "already in use"),
+			strings.Contains(err.Error(), "node is revoked"):
+			statusCode = http.StatusConflict
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to update node",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, node)
+}
+
+// ForceDeleteNode handles DELETE /admin/nodes/:uuid
+// @Summary Permanently delete node
+// @Description Permanently remove a node together with registration tokens pre-authorized for its MAC address and its registration events. This cannot be undone.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Success 200 {object} services.ForceDeleteNodeResponse "Removed node and related records"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid} [delete]
+func (h *NodeManagementHandler) ForceDeleteNode(c *gin.Context) {
+	response, err := h.nodeService.ForceDeleteNode(c.Param("uuid"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "node not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to delete node",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// RevokeNodeToken handles POST /admin/nodes/:uuid/revoke-token
+// @Summary Revoke a node JWT
+// @Description Add one issued node JWT, identified by its jti claim, to the revocation list. Only that token is rejected from now on; the node keeps working after it re-registers. Entries are removed by the background cleanup once the token would have expired anyway.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param request body services.RevokeNodeTokenRequest true "Token ID to revoke"
+// @Success 200 {object} services.RevokedNodeTokenResponse "Revocation entry"
+// @Failure 400 {object} ErrorResponse "Invalid jti"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 409 {object} ErrorResponse "Token ID already revoked for another node"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/revoke-token [post]
+func (h *NodeManagementHandler) RevokeNodeToken(c *gin.Context) {
+	var req services.RevokeNodeTokenRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	revokedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
+	response, err := h.revocationService.RevokeToken(c.Param("uuid"), &req, revokedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case strings.Contains(err.Error(), "another node"):
+			statusCode = http.StatusConflict
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to revoke node token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// GetNodeMetadata handles GET /admin/nodes/:uuid/metadata
+// @Summary Get node metadata
+// @Description Return all admin-assigned key/value metadata of a node
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Success 200 {object} services.NodeMetadataResponse "Node metadata"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/metadata [get]
+func (h *NodeManagementHandler) GetNodeMetadata(c *gin.Context) {
+	metadata, err := h.metadataService.GetMetadata(c.Param("uuid"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "node not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to get node metadata",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, metadata)
+}
+
+// SetNodeMetadata handles PUT /admin/nodes/:uuid/metadata/:key
+// @Summary Set node metadata key
+// @Description Create or update one metadata key of a node. Keys are up to 64 letters, digits, '_', '.' or '-'; values are up to 1024 characters. A node may have at most NODE_METADATA_MAX_KEYS keys.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param key path string true "Metadata key" example(site_code)
+// @Param request body services.SetNodeMetadataRequest true "Metadata value"
+// @Success 200 {object} services.NodeMetadataResponse "All metadata of the node"
+// @Failure 400 {object} ErrorResponse "Invalid key or value"
+// @Failure 404 {object} ErrorResponse "Node not found"
+// @Failure 409 {object} ErrorResponse "Node already has the maximum number of keys"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/metadata/{key} [put]
+func (h *NodeManagementHandler) SetNodeMetadata(c *gin.Context) {
+	var req services.SetNodeMetadataRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	metadata, err := h.metadataService.SetMetadata(c.Param("uuid"), c.Param("key"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case strings.Contains(err.Error(), "node not found"):
+			statusCode = http.StatusNotFound
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "limit reached"):
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to set node metadata",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, metadata)
+}
+
+// DeleteNodeMetadata handles DELETE /admin/nodes/:uuid/metadata/:key
+// @Summary Delete node metadata key
+// @Description Remove one metadata key of a node
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param uuid path string true "Node UUID"
+// @Param key path string true "Metadata key" example(site_code)
+// @Success 204 "Key deleted"
+// @Failure 404 {object} ErrorResponse "Node or key not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/nodes/{uuid}/metadata/{key} [delete]
+func (h *NodeManagementHandler) DeleteNodeMetadata(c *gin.Context) {
+	if err := h.metadataService.DeleteMetadata(c.Param("uuid"), c.Param("key")); err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to delete node metadata",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
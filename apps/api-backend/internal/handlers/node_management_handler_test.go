@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestNodeManagementHandler_GetNodeConditional tests Last-Modified and If-Modified-Since on the node detail
+func TestNodeManagementHandler_GetNodeConditional(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	updatedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	if err := db.Model(&models.Node{}).Where("uuid = ?", node.UUID).UpdateColumn("updated_at", updatedAt).Error; err != nil {
+		t.Fatalf("failed to backdate node: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/nodes/:uuid", NewNodeManagementHandler(services.NewNodeService(nodeRepo, nil, nil, nil, nil, nil, nil), nil, nil).GetNode)
+
+	get := func(ifModifiedSince string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/nodes/"+node.UUID, nil)
+		if ifModifiedSince != "" {
+			req.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := get("")
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", first.Code, http.StatusOK)
+	}
+	lastModified := first.Header().Get("Last-Modified")
+	if lastModified != updatedAt.Format(http.TimeFormat) {
+		t.Fatalf("Last-Modified = %q, want %q", lastModified, updatedAt.Format(http.TimeFormat))
+	}
+
+	if unchanged := get(lastModified); unchanged.Code != http.StatusNotModified || unchanged.Body.Len() != 0 {
+		t.Errorf("unchanged node: status = %d, body = %q, want 304 without body", unchanged.Code, unchanged.Body.String())
+	}
+
+	if older := get(updatedAt.Add(-time.Minute).Format(http.TimeFormat)); older.Code != http.StatusOK {
+		t.Errorf("If-Modified-Since before the change: status = %d, want %d", older.Code, http.StatusOK)
+	}
+
+	// Any update moves UpdatedAt past the client's copy
+	if err := nodeRepo.UpdateLocation(node.UUID, 50.0755, 14.4378); err != nil {
+		t.Fatalf("UpdateLocation() error = %v", err)
+	}
+	if changed := get(lastModified); changed.Code != http.StatusOK {
+		t.Errorf("updated node: status = %d, want %d", changed.Code, http.StatusOK)
+	}
+}
+
+// TestNodeManagementHandler_GetNodeByMAC tests the status codes of the MAC lookup
+func TestNodeManagementHandler_GetNodeByMAC(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/nodes/by-mac/:mac", NewNodeManagementHandler(services.NewNodeService(nodeRepo, nil, nil, nil, nil, nil, nil), nil, nil).GetNodeByMAC)
+
+	tests := []struct {
+		name       string
+		mac        string
+		wantStatus int
+		wantCode   services.ErrorCode
+	}{
+		{"lowercase dashes", "aa-bb-cc-dd-ee-01", http.StatusOK, ""},
+		{"unknown", "AA:BB:CC:DD:EE:99", http.StatusNotFound, services.ErrCodeNodeNotFound},
+		{"invalid", "not-a-mac", http.StatusBadRequest, services.ErrCodeMACInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/nodes/by-mac/"+tt.mac, nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var found services.NodeResponse
+				if err := json.Unmarshal(recorder.Body.Bytes(), &found); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if found.UUID != node.UUID {
+					t.Errorf("uuid = %s, want %s", found.UUID, node.UUID)
+				}
+				return
+			}
+
+			var response ErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if response.Code != string(tt.wantCode) {
+				t.Errorf("code = %s, want %s", response.Code, tt.wantCode)
+			}
+		})
+	}
+}
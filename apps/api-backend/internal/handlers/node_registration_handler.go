@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/boomchecker/api-backend/internal/services"
@@ -11,49 +13,63 @@ import (
 // NodeRegistrationHandler handles HTTP requests for node registration
 type NodeRegistrationHandler struct {
 	registrationService *services.NodeRegistrationService
+	strictJSON          bool
 }
 
 // NewNodeRegistrationHandler creates a new node registration handler
-func NewNodeRegistrationHandler(registrationService *services.NodeRegistrationService) *NodeRegistrationHandler {
+// With strictJSON, registration requests with fields the API does not define are rejected.
+func NewNodeRegistrationHandler(registrationService *services.NodeRegistrationService, strictJSON bool) *NodeRegistrationHandler {
 	return &NodeRegistrationHandler{
 		registrationService: registrationService,
+		strictJSON:          strictJSON,
 	}
 }
 
 // RegisterNode handles POST /nodes/register
 // @Summary Register a new IoT device
 // @Description Register a new node or re-register existing node using registration token. Returns UUID and JWT for authentication.
+// @Description With NODE_JWT_ONE_TIME set, only the first registration returns a JWT; re-registrations update the node and return jwt_issued false unless force_reissue is true.
 // @Tags nodes
 // @Accept json
 // @Produce json
 // @Param request body services.RegistrationRequest true "Registration data with token and MAC address"
 // @Success 200 {object} services.RegistrationResponse "Re-registration successful"
 // @Success 201 {object} services.RegistrationResponse "New node registered"
-// @Failure 400 {object} ErrorResponse "Invalid request or validation error"
+// @Failure 400 {object} ErrorResponse "Invalid request or validation error, or an unknown field with STRICT_JSON_REQUESTS"
 // @Failure 401 {object} ErrorResponse "Invalid, expired, or unauthorized token"
-// @Failure 403 {object} ErrorResponse "Node is revoked"
+// @Failure 403 {object} ErrorResponse "Node is revoked, MAC is denylisted, MAC manufacturer (OUI) is not allowed, or node belongs to another owner"
+// @Failure 409 {object} ErrorResponse "Node already registered and token does not allow re-registration, or firmware downgrade rejected"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 429 {object} ErrorResponse "Node re-registered within RE_REGISTRATION_COOLDOWN_SECONDS; Retry-After gives the seconds until it may re-register"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Stored node secret cannot be decrypted with the configured JWT_ENCRYPTION_KEY"
 // @Router /nodes/register [post]
 func (h *NodeRegistrationHandler) RegisterNode(c *gin.Context) {
 	var req services.RegistrationRequest
 
 	// Bind and validate JSON request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request format",
-			Message: err.Error(),
-		})
+	bind := bindJSON
+	if h.strictJSON {
+		bind = bindJSONStrict
+	}
+	if !bind(c, &req) {
 		return
 	}
 
+	// Record where the node registered from; ClientIP honors X-Forwarded-For only from trusted proxies
+	req.ClientIP = c.ClientIP()
+
 	// Call registration service
 	response, err := h.registrationService.RegisterNode(&req)
 	if err != nil {
 		// Determine appropriate status code based on error type
 		statusCode := determineErrorStatusCode(err)
 		c.JSON(statusCode, ErrorResponse{
-			Error:   "Registration failed",
-			Message: err.Error(),
+			Code:       errorCode(err, statusCode),
+			Error:      "Registration failed",
+			Message:    err.Error(),
+			RetryAfter: retryAfter(c, err),
 		})
 		return
 	}
@@ -64,13 +80,110 @@ func (h *NodeRegistrationHandler) RegisterNode(c *gin.Context) {
 		statusCode = http.StatusCreated
 	}
 
-	c.JSON(statusCode, response)
+	respond(c, statusCode, response)
+}
+
+// RegistrationTokenHeader carries the registration token on requests without a JSON body
+// A header keeps the token out of URLs and access logs
+const RegistrationTokenHeader = "X-Registration-Token"
+
+// GetMACStatus handles GET /nodes/status
+// @Summary Check whether a MAC is registered
+// @Description Tells a provisioning tool whether a MAC is already registered and its status before it attempts registration. Requires a valid registration token in the X-Registration-Token header; a token pre-authorized for another MAC is rejected. No token use is consumed.
+// @Tags nodes
+// @Produce json
+// @Param X-Registration-Token header string true "Valid registration token"
+// @Param mac query string true "MAC address" example(AA:BB:CC:DD:EE:FF)
+// @Success 200 {object} services.MACStatusResponse
+// @Failure 400 {object} ErrorResponse "Missing or invalid MAC address"
+// @Failure 401 {object} ErrorResponse "Missing, invalid, expired, or unauthorized token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /nodes/status [get]
+func (h *NodeRegistrationHandler) GetMACStatus(c *gin.Context) {
+	mac := c.Query("mac")
+	if mac == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request",
+			Message: "mac query parameter is required",
+		})
+		return
+	}
+
+	response, err := h.registrationService.CheckMACStatus(c.GetHeader(RegistrationTokenHeader), mac)
+	if err != nil {
+		statusCode := determineErrorStatusCode(err)
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Status check failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
 }
 
 // ErrorResponse represents an error response
+// Code is stable and machine-readable (see services.ErrorCode); Error and Message are for humans.
 type ErrorResponse struct {
+	Code    string `json:"code" example:"TOKEN_EXPIRED" enums:"VALIDATION_FAILED,MAC_INVALID,PAYLOAD_TOO_LARGE,UNSUPPORTED_MEDIA_TYPE,TOKEN_NOT_FOUND,TOKEN_EXPIRED,TOKEN_EXHAUSTED,TOKEN_MAC_MISMATCH,TOKEN_LIMIT_REACHED,TOKEN_MAC_LIMIT,NODE_NOT_FOUND,NODE_REVOKED,NODE_DISABLED,NODE_ALREADY_REGISTERED,MAC_DENIED,OUI_NOT_ALLOWED,OWNER_MISMATCH,FIRMWARE_DOWNGRADE,UNAUTHORIZED,FORBIDDEN,RATE_LIMITED,ENCRYPTION_KEY_MISMATCH,IDEMPOTENCY_KEY_REUSED,NOT_FOUND,CONFLICT,SERVICE_UNAVAILABLE,INTERNAL_ERROR"`
 	Error   string `json:"error"`
 	Message string `json:"message"`
+
+	// RetryAfter is the number of seconds to wait before retrying, also sent as the Retry-After header
+	// Only set on rate-limit errors that know when the limit clears
+	RetryAfter int `json:"retry_after,omitempty" example:"3600"`
+
+	// Fields maps each invalid request field (JSON name) to what is wrong with it
+	// Only set on request body binding errors
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// retryAfter sets the Retry-After header when err carries a retry delay and returns it in whole seconds
+// Returns 0 when the error has no delay
+func retryAfter(c *gin.Context, err error) int {
+	delay, ok := services.RetryAfterOf(err)
+	if !ok {
+		return 0
+	}
+
+	seconds := int(math.Ceil(delay.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	return seconds
+}
+
+// errorCode returns the code of a typed service error, or a generic code for the HTTP status
+func errorCode(err error, statusCode int) string {
+	if code := services.ErrorCodeOf(err); code != "" {
+		return string(code)
+	}
+	return string(statusErrorCode(statusCode))
+}
+
+// statusErrorCode maps an HTTP status to the generic error code used when no specific code applies
+func statusErrorCode(statusCode int) services.ErrorCode {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return services.ErrCodeValidationFailed
+	case http.StatusUnauthorized:
+		return services.ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return services.ErrCodeForbidden
+	case http.StatusNotFound:
+		return services.ErrCodeNotFound
+	case http.StatusConflict:
+		return services.ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return services.ErrCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return services.ErrCodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return services.ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return services.ErrCodeServiceUnavailable
+	}
+	return services.ErrCodeInternal
 }
 
 // determineErrorStatusCode maps error types to HTTP status codes
@@ -94,11 +207,30 @@ func determineErrorStatusCode(err error) int {
 		return http.StatusBadRequest
 	}
 
-	// Revoked node -> 403 Forbidden
-	if strings.Contains(errMsg, "node is revoked") {
+	// Revoked node, denylisted MAC, manufacturer not on the OUI allowlist or another tenant's node -> 403 Forbidden
+	if strings.Contains(errMsg, "node is revoked") ||
+		strings.Contains(errMsg, "mac address is denylisted") ||
+		strings.Contains(errMsg, "manufacturer is not allowed") ||
+		strings.Contains(errMsg, "belongs to another owner") {
 		return http.StatusForbidden
 	}
 
+	// Re-registration not permitted by token, or firmware downgrade refused by policy -> 409 Conflict
+	if strings.Contains(errMsg, "node already registered") ||
+		strings.Contains(errMsg, "firmware downgrade rejected") {
+		return http.StatusConflict
+	}
+
+	// Re-registration within the cooldown -> 429 Too Many Requests
+	if strings.Contains(errMsg, "rate limit exceeded") {
+		return http.StatusTooManyRequests
+	}
+
+	// Stored node secret encrypted under another JWT_ENCRYPTION_KEY -> 503 Service Unavailable
+	if strings.Contains(errMsg, "encryption key mismatch") {
+		return http.StatusServiceUnavailable
+	}
+
 	// Default to 500 Internal Server Error
 	return http.StatusInternalServerError
 }
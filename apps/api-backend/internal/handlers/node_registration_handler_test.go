@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestErrorResponseCodeEnums tests that the swagger enums of ErrorResponse.Code list every
+// ErrorCode constant declared in services/errors.go, so generated clients know all codes
+func TestErrorResponseCodeEnums(t *testing.T) {
+	field, _ := reflect.TypeOf(ErrorResponse{}).FieldByName("Code")
+	enums := make(map[string]bool)
+	for _, code := range strings.Split(field.Tag.Get("enums"), ",") {
+		enums[code] = true
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../services/errors.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse services/errors.go: %v", err)
+	}
+
+	declared := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "ErrorCode" {
+			return true
+		}
+		for _, value := range spec.Values {
+			lit, ok := value.(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			code, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatalf("failed to unquote %s: %v", lit.Value, err)
+			}
+			declared++
+			if !enums[code] {
+				t.Errorf("ErrorResponse.Code enums is missing %s", code)
+			}
+		}
+		return true
+	})
+
+	if declared == 0 {
+		t.Fatal("no ErrorCode constants found in services/errors.go")
+	}
+	if declared != len(enums) {
+		t.Errorf("ErrorResponse.Code enums lists %d codes, services/errors.go declares %d", len(enums), declared)
+	}
+}
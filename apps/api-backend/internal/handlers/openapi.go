@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+// OpenAPIHandler handles the /openapi.json endpoint
+// Serves the same generated spec as the Swagger UI so clients can generate SDKs from a stable URL
+// @Summary OpenAPI specification
+// @Description Download the generated OpenAPI (Swagger) JSON document describing this API
+// @Tags health
+// @Produce json
+// @Success 200 {object} object "OpenAPI document"
+// @Failure 500 {object} ErrorResponse "Specification not available"
+// @Router /openapi.json [get]
+func OpenAPIHandler(c *gin.Context) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to load OpenAPI specification",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(doc))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/boomchecker/api-backend/docs" // Registers the generated spec with swag
+	"github.com/gin-gonic/gin"
+)
+
+// TestOpenAPIHandler tests that the generated spec is served as a JSON document with the API's paths
+func TestOpenAPIHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/openapi.json", OpenAPIHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	var spec struct {
+		Swagger string                     `json:"swagger"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("failed to parse OpenAPI document: %v", err)
+	}
+	if spec.Swagger == "" {
+		t.Error("swagger version is empty, want an OpenAPI document")
+	}
+	for _, path := range []string{"/admin/nodes", "/admin/nodes/{uuid}", "/admin/registration-node-tokens", "/nodes/register", "/nodes/heartbeat"} {
+		if _, ok := spec.Paths[path]; !ok {
+			t.Errorf("paths missing %s", path)
+		}
+	}
+}
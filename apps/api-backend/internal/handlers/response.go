@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// SuccessResponse wraps successful responses when the envelope is enabled
+// (RESPONSE_ENVELOPE=true or the X-Response-Envelope: true request header).
+// Error responses keep the ErrorResponse shape either way.
+type SuccessResponse struct {
+	Data interface{}  `json:"data"`
+	Meta ResponseMeta `json:"meta"`
+}
+
+// ResponseMeta describes the request a SuccessResponse answers
+type ResponseMeta struct {
+	Timestamp string `json:"timestamp" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	RequestID string `json:"request_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// respond writes a successful JSON response, wrapped in a SuccessResponse when the envelope is enabled
+func respond(c *gin.Context, status int, data interface{}) {
+	if middleware.UseResponseEnvelope(c) {
+		data = SuccessResponse{
+			Data: data,
+			Meta: ResponseMeta{
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				RequestID: middleware.GetRequestID(c),
+			},
+		}
+	}
+	c.JSON(status, data)
+}
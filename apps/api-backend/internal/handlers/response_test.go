@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRespond_Envelope tests that successful responses are wrapped only when the envelope is enabled
+// and that errors keep their bare shape either way
+func TestRespond_Envelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		defaultOn    bool
+		header       string
+		wantEnvelope bool
+	}{
+		{name: "default off", defaultOn: false, wantEnvelope: false},
+		{name: "header opts in", defaultOn: false, header: "true", wantEnvelope: true},
+		{name: "default on", defaultOn: true, wantEnvelope: true},
+		{name: "header opts out", defaultOn: true, header: "false", wantEnvelope: false},
+		{name: "invalid header ignored", defaultOn: true, header: "maybe", wantEnvelope: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.RequestIDMiddleware(), middleware.ResponseEnvelopeMiddleware(tt.defaultOn))
+			router.GET("/ok", func(c *gin.Context) {
+				respond(c, http.StatusOK, gin.H{"message": "ok"})
+			})
+			router.GET("/fail", func(c *gin.Context) {
+				c.JSON(http.StatusNotFound, ErrorResponse{Code: "NOT_FOUND", Error: "Not found"})
+			})
+
+			serve := func(path string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set(middleware.RequestIDHeader, "test-request-1")
+				if tt.header != "" {
+					req.Header.Set(middleware.ResponseEnvelopeHeader, tt.header)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec
+			}
+
+			rec := serve("/ok")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if vary := rec.Header().Get("Vary"); vary != middleware.ResponseEnvelopeHeader {
+				t.Errorf("Vary = %q, want %q", vary, middleware.ResponseEnvelopeHeader)
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+			}
+
+			if !tt.wantEnvelope {
+				if _, ok := body["data"]; ok {
+					t.Errorf("response was wrapped: %s", rec.Body.String())
+				}
+				if string(body["message"]) != `"ok"` {
+					t.Errorf("message = %s, want \"ok\"", body["message"])
+				}
+			} else {
+				var envelope struct {
+					Data struct {
+						Message string `json:"message"`
+					} `json:"data"`
+					Meta ResponseMeta `json:"meta"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+					t.Fatalf("failed to decode envelope %q: %v", rec.Body.String(), err)
+				}
+				if envelope.Data.Message != "ok" {
+					t.Errorf("data.message = %q, want \"ok\"", envelope.Data.Message)
+				}
+				if envelope.Meta.RequestID != "test-request-1" {
+					t.Errorf("meta.request_id = %q, want test-request-1", envelope.Meta.RequestID)
+				}
+				timestamp, err := time.Parse(time.RFC3339, envelope.Meta.Timestamp)
+				if err != nil {
+					t.Fatalf("meta.timestamp %q is not RFC3339: %v", envelope.Meta.Timestamp, err)
+				}
+				if _, offset := timestamp.Zone(); offset != 0 {
+					t.Errorf("meta.timestamp %q is not UTC", envelope.Meta.Timestamp)
+				}
+				if time.Since(timestamp) > time.Minute {
+					t.Errorf("meta.timestamp %q is not current", envelope.Meta.Timestamp)
+				}
+			}
+
+			rec = serve("/fail")
+			var errResponse ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &errResponse); err != nil {
+				t.Fatalf("failed to decode error %q: %v", rec.Body.String(), err)
+			}
+			if errResponse.Code != "NOT_FOUND" {
+				t.Errorf("error response was altered: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestRespond_WithoutMiddleware tests that handlers mounted without the middleware return the bare payload
+func TestRespond_WithoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ok", func(c *gin.Context) {
+		respond(c, http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	req.Header.Set(middleware.ResponseEnvelopeHeader, "true")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Body.String() != `{"message":"ok"}` {
+		t.Errorf("body = %s, want bare payload", rec.Body.String())
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaHandler serves the database schema version
+type SchemaHandler struct {
+	schemaService *services.SchemaService
+}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler(schemaService *services.SchemaService) *SchemaHandler {
+	return &SchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// GetVersion handles GET /admin/db/version
+// @Summary Database schema version
+// @Description Return the applied schema version, the version this build expects and every applied migration step
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.SchemaVersionResponse "Schema version"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/db/version [get]
+func (h *SchemaHandler) GetVersion(c *gin.Context) {
+	version, err := h.schemaService.GetVersion()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to get schema version",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, version)
+}
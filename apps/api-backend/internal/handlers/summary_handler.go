@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SummaryHandler serves the admin dashboard summary
+type SummaryHandler struct {
+	summaryService *services.SummaryService
+}
+
+// NewSummaryHandler creates a new summary handler
+func NewSummaryHandler(summaryService *services.SummaryService) *SummaryHandler {
+	return &SummaryHandler{
+		summaryService: summaryService,
+	}
+}
+
+// GetSummary handles GET /admin/summary
+// @Summary Dashboard summary
+// @Description Return node counts by status, inactive nodes, token counts and the last background cleanup time in one call
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} services.SummaryResponse "Dashboard counts"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/summary [get]
+func (h *SummaryHandler) GetSummary(c *gin.Context) {
+	summary, err := h.summaryService.GetSummary()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
+			Error:   "Failed to get summary",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, summary)
+}
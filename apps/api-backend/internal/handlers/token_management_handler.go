@@ -2,95 +2,100 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/boomchecker/api-backend/internal/middleware"
 	"github.com/boomchecker/api-backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-// TODO: Admin Authentication Implementation Required
-// These endpoints currently use AdminAuthMiddleware which is a placeholder.
-// 
-// Required implementation:
-// 1. Admin Login Flow (POST /admin/auth/request):
-//    - Admin provides email address
-//    - System generates JWT token valid for 24 hours
-//    - Token contains claims: { email, role: "admin", exp, iat }
-//    - Token is sent to admin's email address
-//    - Admin uses this token for subsequent API calls
-//
-// 2. JWT Token Structure:
-//    - Use separate signing key from node JWTs (ADMIN_JWT_SECRET in .env)
-//    - Include: email, role=admin, iat (issued at), exp (expires 24h)
-//    - Sign with HS256 or RS256
-//
-// 3. Middleware Updates:
-//    - internal/middleware/admin_auth.go needs to validate JWT
-//    - Extract token from Authorization: Bearer <token>
-//    - Verify signature, expiration, and admin role claim
-//
-// 4. Email Service:
-//    - Configure SMTP or use service (SendGrid, Mailgun, AWS SES)
-//    - Template for login email with token
-//    - Rate limiting to prevent email spam
-//
-// 5. Security Considerations:
-//    - Store admin emails in config or database
-//    - Consider single-use tokens or token revocation
-//    - Add IP binding or additional security measures
-//    - Log all admin actions for audit trail
-
 // TokenManagementHandler handles HTTP requests for registration token management
 type TokenManagementHandler struct {
 	tokenService *services.TokenManagementService
+	strictJSON   bool
 }
 
+// Idempotency headers for token creation
+const (
+	// IdempotencyKeyHeader carries the client-generated key that makes a create request safe to retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses returning the result of an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 // NewTokenManagementHandler creates a new token management handler
-func NewTokenManagementHandler(tokenService *services.TokenManagementService) *TokenManagementHandler {
+// With strictJSON, token creation requests with fields the API does not define are rejected.
+func NewTokenManagementHandler(tokenService *services.TokenManagementService, strictJSON bool) *TokenManagementHandler {
 	return &TokenManagementHandler{
 		tokenService: tokenService,
+		strictJSON:   strictJSON,
 	}
 }
 
 // CreateToken handles POST /admin/registration-node-tokens
 // @Summary Create registration token
-// @Description Create new registration token with optional expiration, usage limit, and MAC authorization
+// @Description Create new registration token with optional expiration, usage limit, and MAC authorization.
+// @Description With an Idempotency-Key header, a retried request returns the originally created token (200) instead of creating another one. Keys are scoped to the authenticated admin.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security AdminAuth
+// @Param Idempotency-Key header string false "Client-generated key (max 255 characters) making retries safe"
 // @Param request body services.CreateTokenRequest true "Token configuration"
+// @Success 200 {object} services.CreateTokenResponse "Retry of an earlier request; original token returned"
 // @Success 201 {object} services.CreateTokenResponse "Token created"
-// @Failure 400 {object} ErrorResponse "Invalid request or validation error"
+// @Failure 400 {object} ErrorResponse "Invalid request or validation error, or an unknown field with STRICT_JSON_REQUESTS"
+// @Failure 409 {object} ErrorResponse "Request with this Idempotency-Key still in progress, or MAX_ACTIVE_TOKENS reached"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 422 {object} ErrorResponse "Idempotency-Key already used with a different request"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /admin/registration-node-tokens [post]
 func (h *TokenManagementHandler) CreateToken(c *gin.Context) {
 	var req services.CreateTokenRequest
 
 	// Bind and validate JSON request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request format",
-			Message: err.Error(),
-		})
+	bind := bindJSON
+	if h.strictJSON {
+		bind = bindJSONStrict
+	}
+	if !bind(c, &req) {
 		return
 	}
 
+	// Record the creating admin; empty when admin login is disabled
+	createdBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+
 	// Call token service
-	response, err := h.tokenService.CreateToken(&req)
+	response, replayed, err := h.tokenService.CreateTokenIdempotent(c.GetHeader(IdempotencyKeyHeader), &req, createdBy)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if isValidationError(err) {
+		switch {
+		case isValidationError(err):
 			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "idempotency key reused"):
+			statusCode = http.StatusUnprocessableEntity
+		case strings.Contains(err.Error(), "idempotency key"),
+			strings.Contains(err.Error(), "active token limit reached"):
+			statusCode = http.StatusConflict
 		}
 
 		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
 			Error:   "Failed to create token",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	if replayed {
+		c.Header(IdempotentReplayedHeader, "true")
+		respond(c, http.StatusOK, response)
+		return
+	}
+
+	respond(c, http.StatusCreated, response)
 }
 
 // ListAllTokens handles GET /admin/registration-node-tokens
@@ -106,13 +111,14 @@ func (h *TokenManagementHandler) ListAllTokens(c *gin.Context) {
 	tokens, err := h.tokenService.ListAllTokens()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
 			Error:   "Failed to list tokens",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"tokens": tokens,
 		"count":  len(tokens),
 	})
@@ -131,59 +137,180 @@ func (h *TokenManagementHandler) ListActiveTokens(c *gin.Context) {
 	tokens, err := h.tokenService.ListActiveTokens()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
 			Error:   "Failed to list active tokens",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"tokens": tokens,
 		"count":  len(tokens),
 	})
 }
 
+// SearchTokens handles GET /admin/registration-node-tokens/search
+// @Summary Search tokens
+// @Description Find tokens whose description or creating admin contains the search terms (case-insensitive, wildcards match literally), optionally of one owner, newest first, one page at a time
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param q query string false "Text to find in the description"
+// @Param created_by query string false "Text to find in the creating admin email"
+// @Param owner_id query string false "Exact owner (tenant) of the tokens"
+// @Param page query int false "Page number (1-based)" default(1)
+// @Param page_size query int false "Tokens per page (1-200)" default(50)
+// @Success 200 {object} services.TokenSearchResponse "Matching tokens"
+// @Failure 400 {object} ErrorResponse "Missing or invalid search terms"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/search [get]
+func (h *TokenManagementHandler) SearchTokens(c *gin.Context) {
+	var query services.TokenSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	results, err := h.tokenService.SearchTokens(&query)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to search tokens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, results)
+}
+
 // GetToken handles GET /admin/registration-node-tokens/:token
 // @Summary Get token details
-// @Description Return details of specific registration token
+// @Description Return details of specific registration token. The secret value is not included, only its fingerprint.
 // @Tags admin
 // @Produce json
 // @Security AdminAuth
-// @Param token path string true "Token value"
+// @Param token path string true "Token ID or value"
 // @Success 200 {object} services.TokenListResponse "Token details"
 // @Failure 404 {object} ErrorResponse "Token not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /admin/registration-node-tokens/{token} [get]
 func (h *TokenManagementHandler) GetToken(c *gin.Context) {
-	tokenValue := c.Param("token")
+	tokenRef := c.Param("token")
+
+	token, err := h.tokenService.GetToken(tokenRef)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    string(services.ErrCodeTokenNotFound),
+			Error:   "Token not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, token)
+}
+
+// ListTokenNodes handles GET /admin/registration-node-tokens/:token/nodes
+// @Summary List nodes registered with a token
+// @Description Return the nodes registered or re-registered with a registration token, oldest first, one page at a time. Answers "which devices came from this batch?". Node secrets are never included. Nodes of a deleted token can still be listed by the token ID.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param token path string true "Token ID or value"
+// @Param page query int false "Page number (1-based)" default(1)
+// @Param page_size query int false "Nodes per page (1-200)" default(50)
+// @Success 200 {object} services.TokenNodesResponse "Nodes registered with the token"
+// @Failure 400 {object} ErrorResponse "Invalid page parameters"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/{token}/nodes [get]
+func (h *TokenManagementHandler) ListTokenNodes(c *gin.Context) {
+	var query services.TokenNodesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	nodes, err := h.tokenService.ListTokenNodes(c.Param("token"), &query)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "token not found"):
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to list token nodes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, nodes)
+}
+
+// RevealToken handles POST /admin/registration-node-tokens/:token/reveal
+// @Summary Reveal token value
+// @Description Return the full secret value of a registration token. List and detail endpoints only expose a fingerprint.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param token path string true "Token ID or value"
+// @Success 200 {object} services.TokenRevealResponse "Token value"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Router /admin/registration-node-tokens/{token}/reveal [post]
+func (h *TokenManagementHandler) RevealToken(c *gin.Context) {
+	tokenRef := c.Param("token")
 
-	token, err := h.tokenService.GetToken(tokenValue)
+	token, err := h.tokenService.RevealToken(tokenRef)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    string(services.ErrCodeTokenNotFound),
 			Error:   "Token not found",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, token)
+	// The response carries a live credential
+	c.Header("Cache-Control", "no-store")
+	respond(c, http.StatusOK, token)
 }
 
 // DeleteToken handles DELETE /admin/registration-node-tokens/:token
 // @Summary Delete token
-// @Description Permanently remove registration token
+// @Description Delete a registration token. It stops working immediately but can be restored with POST /admin/registration-node-tokens/{token}/restore within TOKEN_DELETE_RETENTION_HOURS (24 by default); cleanup then removes it permanently. With a retention of 0 the token is removed at once.
 // @Tags admin
 // @Security AdminAuth
-// @Param token path string true "Token value"
+// @Param token path string true "Token ID or value"
 // @Success 204 "Token deleted"
 // @Failure 404 {object} ErrorResponse "Token not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /admin/registration-node-tokens/{token} [delete]
 func (h *TokenManagementHandler) DeleteToken(c *gin.Context) {
-	tokenValue := c.Param("token")
+	tokenRef := c.Param("token")
 
-	if err := h.tokenService.DeleteToken(tokenValue); err != nil {
+	if err := h.tokenService.DeleteToken(tokenRef); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:    string(services.ErrCodeTokenNotFound),
 			Error:   "Failed to delete token",
 			Message: err.Error(),
 		})
@@ -193,34 +320,269 @@ func (h *TokenManagementHandler) DeleteToken(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreToken handles POST /admin/registration-node-tokens/:token/restore
+// @Summary Undo token deletion
+// @Description Restore a deleted registration token within the undo window (TOKEN_DELETE_RETENTION_HOURS). The token keeps its expiry and remaining uses.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param token path string true "Token ID or value"
+// @Success 200 {object} services.TokenListResponse "Restored token"
+// @Failure 404 {object} ErrorResponse "No deleted token found, or the undo window has passed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/{token}/restore [post]
+func (h *TokenManagementHandler) RestoreToken(c *gin.Context) {
+	token, err := h.tokenService.RestoreToken(c.Param("token"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "token not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to restore token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, token)
+}
+
+// UpdateToken handles PATCH /admin/registration-node-tokens/:token
+// @Summary Update token usage limit
+// @Description Raise or lower a token's max_uses, e.g. to extend a batch token when a provisioning run grows. The new limit may not be below the number of uses already made, nor above MAX_TOKEN_USES.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param token path string true "Token ID or value"
+// @Param request body services.UpdateTokenRequest true "New usage limit"
+// @Success 200 {object} services.TokenListResponse "Updated token"
+// @Failure 400 {object} ErrorResponse "Invalid limit or below the used count"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/{token} [patch]
+func (h *TokenManagementHandler) UpdateToken(c *gin.Context) {
+	var req services.UpdateTokenRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	token, err := h.tokenService.UpdateToken(c.Param("token"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case isValidationError(err):
+			statusCode = http.StatusBadRequest
+		case strings.Contains(err.Error(), "token not found"):
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to update token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, token)
+}
+
+// ExpireToken handles POST /admin/registration-node-tokens/:token/expire
+// @Summary Expire token now
+// @Description Stop a registration token immediately by setting its expiry to now. Unlike deletion the token stays listed with its usage history and registered nodes. Expiring an already expired token changes nothing.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param token path string true "Token ID or value"
+// @Success 200 {object} services.TokenListResponse "Expired token"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/{token}/expire [post]
+func (h *TokenManagementHandler) ExpireToken(c *gin.Context) {
+	token, err := h.tokenService.ExpireToken(c.Param("token"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "token not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to expire token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, token)
+}
+
+// DeleteTokens handles DELETE /admin/registration-node-tokens
+// @Summary Delete tokens by filter
+// @Description Delete every token matching the filters in one transaction and return how many were removed. At least one filter is required; filters are combined. While the undo window is configured the tokens are soft-deleted and can be restored one by one. Recorded in the audit log.
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param status query string false "Token state" Enums(active, expired, exhausted)
+// @Param created_before query string false "Only tokens created before this UTC timestamp (RFC3339)"
+// @Param owner_id query string false "Exact owner (tenant) of the tokens"
+// @Success 200 {object} services.DeleteTokensResponse "Tokens deleted"
+// @Failure 400 {object} ErrorResponse "Missing or invalid filters"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens [delete]
+func (h *TokenManagementHandler) DeleteTokens(c *gin.Context) {
+	var query services.DeleteTokensQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	deletedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	response, err := h.tokenService.DeleteTokens(&query, deletedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to delete tokens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// RevokeAllTokens handles POST /admin/registration-node-tokens/revoke-all
+// @Summary Revoke all tokens
+// @Description Breach kill switch: make every unexpired registration token expire now, in one statement. Tokens stay listed with their usage and registered nodes; no node can register until a new token is created. Requires {"confirm": true}. Recorded in the audit log as a high-severity event.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Param request body services.RevokeAllTokensRequest true "Confirmation"
+// @Success 200 {object} services.RevokeAllTokensResponse "Tokens revoked"
+// @Failure 400 {object} ErrorResponse "Confirmation missing"
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 415 {object} ErrorResponse "Content-Type is not application/json"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/revoke-all [post]
+func (h *TokenManagementHandler) RevokeAllTokens(c *gin.Context) {
+	var req services.RevokeAllTokensRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	revokedBy, _ := middleware.GetAuthenticatedAdminEmail(c)
+	response, err := h.tokenService.RevokeAllTokens(&req, revokedBy)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to revoke all tokens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
 // CleanupExpiredTokens handles POST /admin/registration-node-tokens/cleanup
 // @Summary Cleanup expired tokens
-// @Description Remove all expired tokens from database
+// @Description Remove all expired tokens from database. With dry_run=true nothing is deleted and the number of tokens that would be removed is returned; add include_ids=true to also list their IDs.
 // @Tags admin
 // @Produce json
 // @Security AdminAuth
+// @Param dry_run query bool false "Preview the cleanup without deleting" default(false)
+// @Param include_ids query bool false "With dry_run, list the IDs of tokens that would be deleted" default(false)
 // @Success 200 {object} map[string]interface{} "Cleanup results with deleted count"
+// @Failure 400 {object} ErrorResponse "Invalid query parameter"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /admin/registration-node-tokens/cleanup [post]
 func (h *TokenManagementHandler) CleanupExpiredTokens(c *gin.Context) {
-	count, err := h.tokenService.CleanupExpiredTokens()
+	dryRun, ok := queryBool(c, "dry_run")
+	if !ok {
+		return
+	}
+	includeIDs, ok := queryBool(c, "include_ids")
+	if !ok {
+		return
+	}
+
+	count, ids, err := h.tokenService.CleanupExpiredTokens(dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
 			Error:   "Failed to cleanup expired tokens",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	if dryRun {
+		response := gin.H{
+			"message":             "Dry run: no tokens were deleted",
+			"dry_run":             true,
+			"would_delete_tokens": count,
+		}
+		if includeIDs {
+			if ids == nil {
+				ids = []string{}
+			}
+			response["token_ids"] = ids
+		}
+		respond(c, http.StatusOK, response)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
 		"message":        "Expired tokens cleaned up successfully",
+		"dry_run":        false,
 		"deleted_tokens": count,
 	})
 }
 
+// queryBool parses an optional boolean query parameter, defaulting to false
+// On an invalid value it writes a 400 response and returns ok=false.
+func queryBool(c *gin.Context, name string) (value bool, ok bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, true
+	}
+
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:    string(services.ErrCodeValidationFailed),
+			Error:   "Invalid request format",
+			Message: name + " must be true or false",
+		})
+		return false, false
+	}
+	return parsed, true
+}
+
 // GetStatistics handles GET /admin/registration-node-tokens/statistics
 // @Summary Get token statistics
-// @Description Return statistics about registration tokens (total, active, expired counts)
+// @Description Return statistics about registration tokens: total, active, expired and exhausted counts, the split into single-use, multi-use and unlimited tokens, total and average uses per token, and the configured maximum uses per token (MAX_TOKEN_USES)
 // @Tags admin
 // @Produce json
 // @Security AdminAuth
@@ -231,13 +593,58 @@ func (h *TokenManagementHandler) GetStatistics(c *gin.Context) {
 	stats, err := h.tokenService.GetStatistics()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:    errorCode(err, http.StatusInternalServerError),
 			Error:   "Failed to get statistics",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	respond(c, http.StatusOK, stats)
+}
+
+// GetStatisticsTimeline handles GET /admin/registration-node-tokens/statistics/timeline
+// @Summary Get token statistics timeline
+// @Description Return daily counts of tokens created and consumed (registrations) over the last N days
+// @Tags admin
+// @Produce json
+// @Security AdminAuth
+// @Param days query int false "Number of days including today (1-365)" default(30)
+// @Success 200 {object} services.StatisticsTimelineResponse "Daily token activity"
+// @Failure 400 {object} ErrorResponse "Invalid days parameter"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/registration-node-tokens/statistics/timeline [get]
+func (h *TokenManagementHandler) GetStatisticsTimeline(c *gin.Context) {
+	days := 30
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:    string(services.ErrCodeValidationFailed),
+				Error:   "Invalid request format",
+				Message: "days must be an integer",
+			})
+			return
+		}
+		days = parsed
+	}
+
+	timeline, err := h.tokenService.GetStatisticsTimeline(days)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if isValidationError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Code:    errorCode(err, statusCode),
+			Error:   "Failed to get statistics timeline",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, timeline)
 }
 
 // isValidationError checks if an error is a validation error
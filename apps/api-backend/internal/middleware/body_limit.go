@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultMaxRequestBodyBytes caps request bodies for the small JSON payloads of this API (64 KiB)
+	DefaultMaxRequestBodyBytes int64 = 64 << 10
+
+	// originalBodyContextKey keeps the unwrapped body so a route can raise the global limit
+	originalBodyContextKey = "original_request_body"
+)
+
+// BodyLimitMiddleware caps the request body at limit bytes
+// Reading past the limit fails with *http.MaxBytesError, which handlers report as 413.
+// Applied again on a route, the innermost limit replaces the global one.
+func BodyLimitMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := c.Request.Body
+		if original, ok := c.Get(originalBodyContextKey); ok {
+			body = original.(io.ReadCloser)
+		} else {
+			c.Set(originalBodyContextKey, body)
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, body, limit)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newBodyLimitRouter builds a router with a global limit and a route that raises it
+func newBodyLimitRouter(globalLimit, routeLimit int64) *gin.Engine {
+	readBody := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	}
+
+	router := gin.New()
+	router.Use(BodyLimitMiddleware(globalLimit))
+	router.POST("/small", readBody)
+	router.POST("/large", BodyLimitMiddleware(routeLimit), readBody)
+	return router
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	router := newBodyLimitRouter(16, 64)
+
+	tests := []struct {
+		name          string
+		path          string
+		size          int
+		unknownLength bool
+		wantStatus    int
+	}{
+		{"within global limit", "/small", 16, false, http.StatusOK},
+		{"over global limit", "/small", 17, false, http.StatusRequestEntityTooLarge},
+		{"streamed body over limit", "/small", 17, true, http.StatusRequestEntityTooLarge},
+		{"route raises limit", "/large", 64, false, http.StatusOK},
+		{"streamed body within raised limit", "/large", 64, true, http.StatusOK},
+		{"over raised limit", "/large", 65, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(strings.Repeat("x", tt.size)))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
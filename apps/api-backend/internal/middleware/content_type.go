@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSONMiddleware rejects request bodies that are not declared as application/json
+// Only POST, PUT and PATCH requests that carry a body are checked, so body-less actions
+// (e.g. POST /nodes/heartbeat) pass. Register it on routes or groups that accept JSON only;
+// endpoints taking other media types should not use it.
+func RequireJSONMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		// ContentLength is -1 for chunked bodies of unknown size
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		contentType := c.GetHeader("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != "application/json" {
+			if contentType == "" {
+				contentType = "none"
+			}
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"code":    services.ErrCodeUnsupportedMediaType,
+				"error":   "Unsupported Media Type",
+				"message": "Request body must be JSON with Content-Type: application/json (got: " + contentType + ")",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
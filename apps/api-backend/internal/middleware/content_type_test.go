@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireJSONMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(RequireJSONMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/json", ok)
+	router.GET("/json", ok)
+
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		wantStatus  int
+	}{
+		{"json body", http.MethodPost, `{"a":1}`, "application/json", http.StatusOK},
+		{"json with charset", http.MethodPost, `{"a":1}`, "application/json; charset=utf-8", http.StatusOK},
+		{"form body", http.MethodPost, "a=1", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"missing content type", http.MethodPost, `{"a":1}`, "", http.StatusUnsupportedMediaType},
+		{"empty body", http.MethodPost, "", "", http.StatusOK},
+		{"GET ignored", http.MethodGet, "a=1", "text/plain", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/json", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultGzipMinBytes is the response size below which compression is skipped;
+// gzip overhead outweighs the savings on tiny bodies
+const DefaultGzipMinBytes = 1024
+
+// incompressibleContentTypes are already compressed or must be streamed unbuffered
+var incompressibleContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/gzip",
+	"application/zip",
+	"application/octet-stream",
+	"text/event-stream",
+}
+
+// GzipMiddleware gzip-compresses responses for clients that send Accept-Encoding: gzip
+// Bodies smaller than minBytes, responses that already set Content-Encoding, and
+// incompressible content types (images, archives, event streams) are sent unchanged.
+func GzipMiddleware(minBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Add rather than set, so Vary values from earlier middleware (e.g. X-Response-Envelope) are kept
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, minBytes: minBytes}
+		c.Writer = writer
+		defer writer.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether compressing pays off
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minBytes int
+
+	buffer      bytes.Buffer
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+// Write buffers data until minBytes are collected, then compresses or passes it through
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(data)
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minBytes {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// WriteString implements gin.ResponseWriter on top of Write
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends buffered data immediately; a response flushed before reaching minBytes
+// is still compressed unless its content type is incompressible
+func (w *gzipResponseWriter) Flush() {
+	if w.gz == nil && !w.passthrough {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide starts compression or passthrough and writes out the buffered data
+func (w *gzipResponseWriter) decide(compress bool) error {
+	header := w.Header()
+	if !compress || header.Get("Content-Encoding") != "" || isIncompressible(header.Get("Content-Type")) {
+		w.passthrough = true
+	} else {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	data := w.buffer.Bytes()
+	w.buffer.Reset()
+	if w.gz != nil {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// finish writes a small buffered response uncompressed, or completes the gzip stream
+func (w *gzipResponseWriter) finish() {
+	if w.gz == nil && !w.passthrough {
+		_ = w.decide(false)
+		return
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
+
+// isIncompressible reports whether a content type should not be compressed
+func isIncompressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range incompressibleContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newGzipRouter builds a router serving bodies of various sizes and types through GzipMiddleware
+func newGzipRouter(minBytes int) *gin.Engine {
+	router := gin.New()
+	router.Use(GzipMiddleware(minBytes))
+	router.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("node ", 100))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 500)))
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("event: ping\n\n")
+	})
+	return router
+}
+
+func TestGzipMiddleware(t *testing.T) {
+	router := newGzipRouter(64)
+	large := strings.Repeat("node ", 100)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantGzip       bool
+		wantBody       string
+	}{
+		{"large response compressed", "/large", "gzip, deflate", true, large},
+		{"no Accept-Encoding", "/large", "", false, large},
+		{"gzip refused with q=0", "/large", "gzip;q=0, deflate", false, large},
+		{"wildcard encoding", "/large", "*", true, large},
+		{"small response not compressed", "/small", "gzip", false, "ok"},
+		{"image not compressed", "/image", "gzip", false, strings.Repeat("x", 500)},
+		{"event stream not compressed", "/stream", "gzip", false, "event: ping\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+
+			body := rec.Body.String()
+			gotGzip := rec.Header().Get("Content-Encoding") == "gzip"
+			if gotGzip != tt.wantGzip {
+				t.Fatalf("Content-Encoding gzip = %v, want %v", gotGzip, tt.wantGzip)
+			}
+			if gotGzip {
+				reader, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader() error = %v", err)
+				}
+				decoded, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("reading gzip body error = %v", err)
+				}
+				body = string(decoded)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestGzipMiddleware_KeepsVary tests that GzipMiddleware adds to Vary values set by earlier middleware
+func TestGzipMiddleware_KeepsVary(t *testing.T) {
+	router := gin.New()
+	router.Use(ResponseEnvelopeMiddleware(false), GzipMiddleware(64))
+	router.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("node ", 100))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	vary := rec.Header().Values("Vary")
+	want := []string{ResponseEnvelopeHeader, "Accept-Encoding"}
+	if strings.Join(vary, ",") != strings.Join(want, ",") {
+		t.Errorf("Vary = %q, want %q", vary, want)
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+}
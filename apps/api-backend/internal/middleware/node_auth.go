@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// NodeContextKey is the gin context key holding the authenticated *models.Node
+const NodeContextKey = "node"
+
+// NodeAuthMiddleware validates node JWTs from the Authorization header
+// On success the node is stored in the context under NodeContextKey
+// and its LastSeenAt is updated (debounced by NodeAuthService)
+func NodeAuthMiddleware(authService *services.NodeAuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			unauthorizedResponse(c, "Node authentication required")
+			return
+		}
+
+		tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok {
+			unauthorizedResponse(c, "Invalid authorization header format. Expected: Bearer <token>")
+			return
+		}
+
+		node, err := authService.Authenticate(tokenString)
+		if err != nil {
+			if strings.Contains(err.Error(), "revoked") || strings.Contains(err.Error(), "disabled") {
+				c.JSON(http.StatusForbidden, gin.H{
+					"code":    services.ErrorCodeOf(err),
+					"error":   "Forbidden",
+					"message": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+			if strings.Contains(err.Error(), "encryption key mismatch") {
+				// Server misconfiguration, not a bad token; a 401 would send the node into re-registration
+				c.JSON(http.StatusServiceUnavailable, gin.H{
+					"code":    services.ErrorCodeOf(err),
+					"error":   "Service Unavailable",
+					"message": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+			unauthorizedResponse(c, err.Error())
+			return
+		}
+
+		c.Set(NodeContextKey, node)
+
+		if _, err := authService.TouchLastSeen(node.UUID); err != nil {
+			// Activity tracking must not block the request
+			log.Printf("Warning: failed to update last seen for node %s: %v", node.UUID, err)
+		}
+
+		c.Next()
+	}
+}
+
+// GetAuthenticatedNode returns the node set by NodeAuthMiddleware
+func GetAuthenticatedNode(c *gin.Context) (*models.Node, bool) {
+	value, exists := c.Get(NodeContextKey)
+	if !exists {
+		return nil, false
+	}
+	node, ok := value.(*models.Node)
+	return node, ok
+}
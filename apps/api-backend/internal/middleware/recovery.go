@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware converts panics into a 500 JSON error response
+// The panic and stack trace are logged server-side with the request ID.
+// The client only gets the request ID for correlation; the panic value is
+// added to the message outside release mode, stack traces are never sent.
+func RecoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			requestID := GetRequestID(c)
+
+			// A client that went away cannot receive a response
+			if isBrokenPipe(recovered) {
+				logger.Warn("connection lost while writing response",
+					slog.String("request_id", requestID),
+					slog.Any("error", recovered),
+				)
+				c.Abort()
+				return
+			}
+
+			logger.Error("panic recovered",
+				slog.String("request_id", requestID),
+				slog.String("method", c.Request.Method),
+				slog.String("route", c.FullPath()),
+				slog.Any("panic", recovered),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			message := fmt.Sprintf("An unexpected error occurred (request ID: %s)", requestID)
+			if gin.Mode() != gin.ReleaseMode {
+				message = fmt.Sprintf("%s: %v", message, recovered)
+			}
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":    services.ErrCodeInternal,
+				"error":   "Internal server error",
+				"message": message,
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+// isBrokenPipe reports whether a panic was caused by the client closing the connection
+func isBrokenPipe(recovered any) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+
+	var syscallErr *os.SyscallError
+	if errors.As(opErr, &syscallErr) {
+		msg := strings.ToLower(syscallErr.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+	return false
+}
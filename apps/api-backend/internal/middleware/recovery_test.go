@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newPanicRouter builds a router with request IDs and recovery and a route that panics
+func newPanicRouter(logOutput io.Writer) *gin.Engine {
+	logger := slog.New(slog.NewTextHandler(logOutput, nil))
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), RecoveryMiddleware(logger))
+	router.GET("/panic", func(c *gin.Context) {
+		panic("database exploded")
+	})
+	return router
+}
+
+func TestRecoveryMiddleware_ReturnsJSON(t *testing.T) {
+	previousMode := gin.Mode()
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(previousMode)
+
+	var logs bytes.Buffer
+	router := newPanicRouter(&logs)
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(RequestIDHeader, "test-request-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v (%s)", err, rec.Body.String())
+	}
+	if len(body) != 3 || body["code"] != "INTERNAL_ERROR" || body["error"] != "Internal server error" || body["message"] == "" {
+		t.Errorf("body = %v, want {code, error, message}", body)
+	}
+	if !strings.Contains(body["message"], "test-request-1") {
+		t.Errorf("message %q should contain the request ID", body["message"])
+	}
+
+	// Release mode: neither the panic value nor the stack reaches the client
+	if strings.Contains(rec.Body.String(), "database exploded") || strings.Contains(rec.Body.String(), "goroutine") {
+		t.Errorf("response leaks panic details: %s", rec.Body.String())
+	}
+
+	// Server-side log has the panic, stack and request ID
+	for _, want := range []string{"panic recovered", "database exploded", "test-request-1", "recovery_test.go"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log output missing %q", want)
+		}
+	}
+}
+
+func TestRecoveryMiddleware_DebugModeIncludesPanicValue(t *testing.T) {
+	previousMode := gin.Mode()
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(previousMode)
+
+	router := newPanicRouter(io.Discard)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	if !strings.Contains(body["message"], "database exploded") {
+		t.Errorf("debug message %q should include the panic value", body["message"])
+	}
+	if strings.Contains(body["message"], "goroutine") {
+		t.Errorf("debug message should not include the stack trace")
+	}
+}
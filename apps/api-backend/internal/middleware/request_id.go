@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader is the header carrying the request ID in requests and responses
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDContextKey is the gin context key holding the request ID
+	RequestIDContextKey = "request_id"
+
+	// maxRequestIDLength limits client-supplied request IDs
+	maxRequestIDLength = 64
+)
+
+// RequestIDMiddleware assigns every request an ID for log correlation
+// A well-formed X-Request-ID from the client (e.g. set by a proxy) is reused, otherwise a UUID is generated
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(RequestIDContextKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the request ID set by RequestIDMiddleware
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDContextKey)
+}
+
+// isValidRequestID accepts short IDs made of letters, digits, '-', '_' and '.'
+// so client-supplied values cannot inject content into logs
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
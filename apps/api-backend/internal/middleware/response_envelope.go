@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ResponseEnvelopeHeader lets a client choose the response shape per request:
+	// "true" wraps successful responses in {data, meta}, "false" returns the bare payload
+	ResponseEnvelopeHeader = "X-Response-Envelope"
+
+	// ResponseEnvelopeContextKey is the gin context key holding whether to wrap the response
+	ResponseEnvelopeContextKey = "response_envelope"
+)
+
+// ResponseEnvelopeMiddleware decides whether successful responses are wrapped in an envelope
+// enabledByDefault is the rollout switch; X-Response-Envelope overrides it for one request, so
+// clients can migrate before the default flips and old clients can keep the bare shape after.
+// Unparsable header values are ignored.
+func ResponseEnvelopeMiddleware(enabledByDefault bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled := enabledByDefault
+		if value := c.GetHeader(ResponseEnvelopeHeader); value != "" {
+			if parsed, err := strconv.ParseBool(value); err == nil {
+				enabled = parsed
+			}
+		}
+
+		c.Set(ResponseEnvelopeContextKey, enabled)
+		// The same URL answers in two shapes, so caches must key on the header
+		c.Writer.Header().Add("Vary", ResponseEnvelopeHeader)
+
+		c.Next()
+	}
+}
+
+// UseResponseEnvelope reports whether ResponseEnvelopeMiddleware chose the envelope for this request
+// Returns false when the middleware did not run
+func UseResponseEnvelope(c *gin.Context) bool {
+	return c.GetBool(ResponseEnvelopeContextKey)
+}
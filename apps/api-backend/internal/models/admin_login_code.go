@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdminLoginCode records a short numeric login code sent by email.
+// Only the SHA-256 hash of the code is stored. A code is exchanged once for an admin
+// token and is deleted on use, on too many wrong guesses, or when a new code replaces it.
+// All timestamps are stored in UTC.
+type AdminLoginCode struct {
+	// ID is the code record's UUID
+	ID string `gorm:"primaryKey;type:text;not null" json:"id"`
+
+	// Email is the normalized admin email the code was sent to
+	Email string `gorm:"type:text;not null;index" json:"email"`
+
+	// CodeHash is the hex-encoded SHA-256 of the code
+	CodeHash string `gorm:"type:text;not null" json:"-"`
+
+	// FailedAttempts counts wrong codes submitted for this email since the code was sent
+	FailedAttempts int `gorm:"type:integer;not null;default:0" json:"failed_attempts"`
+
+	// RequestedAt is when the code was requested
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	RequestedAt time.Time `gorm:"type:datetime;not null" json:"requested_at"`
+
+	// ExpiresAt is when the code stops being accepted
+	// Stored in UTC, format: 2025-11-10T14:40:00Z
+	ExpiresAt time.Time `gorm:"type:datetime;not null" json:"expires_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (AdminLoginCode) TableName() string {
+	return "admin_login_codes"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (c *AdminLoginCode) BeforeCreate(tx *gorm.DB) error {
+	if c.RequestedAt.IsZero() {
+		c.RequestedAt = time.Now().UTC()
+	} else {
+		c.RequestedAt = c.RequestedAt.UTC()
+	}
+	c.ExpiresAt = c.ExpiresAt.UTC()
+	return nil
+}
+
+// IsExpired checks if the code has expired
+func (c *AdminLoginCode) IsExpired() bool {
+	return !time.Now().UTC().Before(c.ExpiresAt)
+}
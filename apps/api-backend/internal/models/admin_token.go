@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdminToken records an admin login token sent by email.
+// Only the SHA-256 hash of the JWT is stored; the token itself exists only in the email.
+// The record lets the server rate-limit login requests and reject tokens it did not issue.
+// All timestamps are stored in UTC.
+type AdminToken struct {
+	// ID is the token's JWT ID (jti claim)
+	ID string `gorm:"primaryKey;type:text;not null" json:"id"`
+
+	// Email is the normalized admin email the token was sent to
+	Email string `gorm:"type:text;not null;index" json:"email"`
+
+	// TokenHash is the hex-encoded SHA-256 of the signed JWT
+	TokenHash string `gorm:"type:text;not null;uniqueIndex" json:"-"`
+
+	// RequestedAt is when the login token was requested
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	RequestedAt time.Time `gorm:"type:datetime;not null" json:"requested_at"`
+
+	// ExpiresAt is when the token stops being accepted
+	// Stored in UTC, format: 2025-11-11T14:30:00Z
+	ExpiresAt time.Time `gorm:"type:datetime;not null;index" json:"expires_at"`
+
+	// ResendCount is how many times this same token was emailed again via /admin/auth/resend
+	ResendCount int `gorm:"type:integer;not null;default:0" json:"resend_count"`
+}
+
+// TableName overrides the default table name for GORM
+func (AdminToken) TableName() string {
+	return "admin_tokens"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (t *AdminToken) BeforeCreate(tx *gorm.DB) error {
+	if t.RequestedAt.IsZero() {
+		t.RequestedAt = time.Now().UTC()
+	} else {
+		t.RequestedAt = t.RequestedAt.UTC()
+	}
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	return nil
+}
+
+// IsExpired checks if the token has expired
+func (t *AdminToken) IsExpired() bool {
+	return !time.Now().UTC().Before(t.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdminUser is an email address authorized to use the admin API.
+// Entries are managed at runtime through the admin endpoints; the ADMIN_EMAILS
+// environment variable is only consulted while this table is empty (bootstrap).
+// All timestamps are stored in UTC.
+type AdminUser struct {
+	// Email is the normalized (trimmed, lowercase) admin email address
+	Email string `gorm:"primaryKey;type:text;not null" json:"email"`
+
+	// CreatedAt is when the email was authorized
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null" json:"created_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (AdminUser) TableName() string {
+	return "admin_users"
+}
+
+// BeforeCreate is a GORM hook that ensures the timestamp is in UTC
+func (a *AdminUser) BeforeCreate(tx *gorm.DB) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	} else {
+		a.CreatedAt = a.CreatedAt.UTC()
+	}
+	return nil
+}
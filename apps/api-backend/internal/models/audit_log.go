@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit log target types
+const (
+	AuditTargetAdmin   = "admin"
+	AuditTargetNode    = "node"
+	AuditTargetToken   = "token"
+	AuditTargetCleanup = "cleanup"
+	AuditTargetMAC     = "mac"
+)
+
+// AuditLog records a security-relevant admin action
+// Entries are append-only; the filtered columns are indexed so a reviewer can
+// reconstruct a timeline by action, admin, target and time range.
+// All timestamps are stored in UTC.
+type AuditLog struct {
+	// ID is the entry's UUID
+	ID string `gorm:"primaryKey;type:text;not null" json:"id"`
+
+	// Action identifies what happened (e.g. "node_token.revoked")
+	Action string `gorm:"type:text;not null;index" json:"action"`
+
+	// AdminEmail is the admin who performed the action; NULL if admin login is disabled
+	AdminEmail *string `gorm:"type:text;index" json:"admin_email,omitempty"`
+
+	// TargetType is the kind of object acted on (AuditTargetAdmin, AuditTargetNode, ...)
+	TargetType string `gorm:"type:text;not null;index:idx_audit_logs_target,priority:1" json:"target_type"`
+
+	// TargetID identifies the object acted on (node UUID, token ID, ...); NULL for global actions
+	TargetID *string `gorm:"type:text;index:idx_audit_logs_target,priority:2" json:"target_id,omitempty"`
+
+	// Details is a human-readable description of the action
+	Details *string `gorm:"type:text" json:"details,omitempty"`
+
+	// CreatedAt is when the action happened
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null;index" json:"created_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	} else {
+		a.CreatedAt = a.CreatedAt.UTC()
+	}
+	return nil
+}
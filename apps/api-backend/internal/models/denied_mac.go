@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeniedMAC is a MAC address that may never register
+// The ban outlives the node: deleting a revoked node does not let its MAC register again.
+// All timestamps are stored in UTC.
+type DeniedMAC struct {
+	// MacAddress is the banned MAC in normalized form (AA:BB:CC:DD:EE:FF)
+	MacAddress string `gorm:"primaryKey;type:text;not null" json:"mac_address"`
+
+	// Reason is a free-text note on why the MAC was banned
+	Reason *string `gorm:"type:text" json:"reason,omitempty"`
+
+	// CreatedBy is the email of the admin who banned the MAC; NULL if admin login is disabled
+	CreatedBy *string `gorm:"type:text" json:"created_by,omitempty"`
+
+	// CreatedAt is when the MAC was banned
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null" json:"created_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (DeniedMAC) TableName() string {
+	return "mac_denylist"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (d *DeniedMAC) BeforeCreate(tx *gorm.DB) error {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now().UTC()
+	} else {
+		d.CreatedAt = d.CreatedAt.UTC()
+	}
+	return nil
+}
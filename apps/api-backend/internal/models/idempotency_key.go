@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey records a client-supplied Idempotency-Key so a retried request
+// returns the original result instead of repeating its side effect.
+// Only the ID of the created resource is stored, never the response body.
+// All timestamps are stored in UTC.
+type IdempotencyKey struct {
+	// Scope identifies the operation the key belongs to (e.g. "registration_token.create")
+	Scope string `gorm:"primaryKey;type:text;not null" json:"scope"`
+
+	// Key is the client-supplied Idempotency-Key header value
+	Key string `gorm:"primaryKey;type:text;not null" json:"key"`
+
+	// RequestHash is the SHA-256 of the request, used to reject reuse of a key for a different request
+	RequestHash string `gorm:"type:text;not null" json:"-"`
+
+	// ResourceID is the ID of the created resource; NULL while the first request is still in progress
+	ResourceID *string `gorm:"type:text" json:"resource_id,omitempty"`
+
+	// CreatedAt is when the key was first used
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null" json:"created_at"`
+
+	// ExpiresAt is when the key may be reused for a new request
+	// Stored in UTC, format: 2025-11-11T14:30:00Z
+	ExpiresAt time.Time `gorm:"type:datetime;not null;index" json:"expires_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (k *IdempotencyKey) BeforeCreate(tx *gorm.DB) error {
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now().UTC()
+	} else {
+		k.CreatedAt = k.CreatedAt.UTC()
+	}
+	k.ExpiresAt = k.ExpiresAt.UTC()
+	return nil
+}
+
+// IsExpired checks if the key's retention window has passed
+func (k *IdempotencyKey) IsExpired() bool {
+	return !time.Now().UTC().Before(k.ExpiresAt)
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/boomchecker/api-backend/internal/validators"
 	"gorm.io/gorm"
 )
 
@@ -29,6 +30,10 @@ type Node struct {
 	// Format: "1.0.0", "2.1.3-beta"
 	FirmwareVersion *string `gorm:"type:text;size:50" json:"firmware_version,omitempty"`
 
+	// TargetFirmwareVersion is the firmware version an admin wants this node to run
+	// Returned in heartbeat responses while newer than FirmwareVersion, so the device knows to update
+	TargetFirmwareVersion *string `gorm:"type:text;size:50" json:"target_firmware_version,omitempty"`
+
 	// Latitude is the GPS latitude for node location tracking
 	// Valid range: -90.0 to 90.0
 	Latitude *float64 `gorm:"type:real" json:"latitude,omitempty"`
@@ -37,10 +42,26 @@ type Node struct {
 	// Valid range: -180.0 to 180.0
 	Longitude *float64 `gorm:"type:real" json:"longitude,omitempty"`
 
-	// LastSeenAt is automatically updated on each authenticated API request
+	// LastSeenAt is automatically updated on authenticated API requests
+	// Writes are debounced per node (see NodeAuthService.TouchLastSeen)
 	// Stored in UTC, format: 2025-11-10T14:30:00Z
 	LastSeenAt *time.Time `gorm:"type:datetime" json:"last_seen_at,omitempty"`
 
+	// LastRegisteredAt is when the node last registered or re-registered
+	// Unlike LastSeenAt it is not touched by authenticated API requests
+	// Used to enforce the re-registration cooldown; NULL for nodes registered before it was tracked
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	LastRegisteredAt *time.Time `gorm:"type:datetime" json:"last_registered_at,omitempty"`
+
+	// LastIP is the client IP of the node's last registration or re-registration
+	// Taken from the connection, or from X-Forwarded-For only behind a trusted proxy; normalized
+	// (IPv4-mapped IPv6 stored as IPv4). NULL for nodes registered before it was tracked
+	LastIP *string `gorm:"type:text" json:"last_ip,omitempty"`
+
+	// OwnerID is the optional tenant (customer) the node belongs to
+	// Copied from the registration token at first registration; NULL for single-tenant deployments
+	OwnerID *string `gorm:"type:text;index" json:"owner_id,omitempty"`
+
 	// Status represents the node's operational state
 	// Valid values: "active" (normal operation), "disabled" (temporarily inactive), "revoked" (permanently banned)
 	Status string `gorm:"type:text;not null;default:active" json:"status"`
@@ -59,6 +80,22 @@ func (Node) TableName() string {
 	return "nodes"
 }
 
+// BeforeSave is a GORM hook that rejects out-of-range GPS coordinates
+// Runs on create and update as a safety net behind service-layer validation
+func (n *Node) BeforeSave(tx *gorm.DB) error {
+	if n.Latitude != nil {
+		if err := validators.ValidateLatitude(*n.Latitude, "latitude"); err != nil {
+			return err
+		}
+	}
+	if n.Longitude != nil {
+		if err := validators.ValidateLongitude(*n.Longitude, "longitude"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // BeforeCreate is a GORM hook that ensures timestamps are in UTC
 func (n *Node) BeforeCreate(tx *gorm.DB) error {
 	n.CreatedAt = time.Now().UTC()
@@ -67,19 +104,46 @@ func (n *Node) BeforeCreate(tx *gorm.DB) error {
 		utcTime := n.LastSeenAt.UTC()
 		n.LastSeenAt = &utcTime
 	}
+	if n.LastRegisteredAt != nil {
+		utcTime := n.LastRegisteredAt.UTC()
+		n.LastRegisteredAt = &utcTime
+	}
 	return nil
 }
 
-// BeforeUpdate is a GORM hook that ensures UpdatedAt is in UTC
+// BeforeUpdate is a GORM hook that ensures timestamps are in UTC
+// GORM only runs it for updates of a whole model (Save, or Updates with a struct);
+// column updates with a map must pass UTC values themselves.
 func (n *Node) BeforeUpdate(tx *gorm.DB) error {
 	n.UpdatedAt = time.Now().UTC()
 	if n.LastSeenAt != nil {
 		utcTime := n.LastSeenAt.UTC()
 		n.LastSeenAt = &utcTime
 	}
+	if n.LastRegisteredAt != nil {
+		utcTime := n.LastRegisteredAt.UTC()
+		n.LastRegisteredAt = &utcTime
+	}
 	return nil
 }
 
+// PendingFirmwareUpdate returns the target firmware version if it is newer than the reported one
+// Returns nil when no target is set or the node already runs the target (or a later) version
+func (n *Node) PendingFirmwareUpdate() *string {
+	if n.TargetFirmwareVersion == nil || *n.TargetFirmwareVersion == "" {
+		return nil
+	}
+	if n.FirmwareVersion == nil || *n.FirmwareVersion == "" {
+		return n.TargetFirmwareVersion
+	}
+
+	cmp, err := validators.CompareSemanticVersions(*n.TargetFirmwareVersion, *n.FirmwareVersion)
+	if err != nil || cmp <= 0 {
+		return nil
+	}
+	return n.TargetFirmwareVersion
+}
+
 // NodeStatus constants for type safety
 const (
 	NodeStatusActive   = "active"
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NodeMetadata is one free-form key/value pair attached to a node by admins
+// (e.g. install date, installer, site code). Keys are unique per node.
+// All timestamps are stored in UTC.
+type NodeMetadata struct {
+	// NodeUUID is the node the entry belongs to
+	NodeUUID string `gorm:"primaryKey;type:text;not null" json:"node_uuid"`
+
+	// Key is the metadata key (e.g. site_code)
+	Key string `gorm:"primaryKey;type:text;not null" json:"key"`
+
+	// Value is the metadata value
+	Value string `gorm:"type:text;not null" json:"value"`
+
+	// CreatedAt is when the key was first set
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null" json:"created_at"`
+
+	// UpdatedAt is when the value last changed
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	UpdatedAt time.Time `gorm:"type:datetime;not null" json:"updated_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (NodeMetadata) TableName() string {
+	return "node_metadata"
+}
+
+// BeforeSave is a GORM hook that ensures timestamps are in UTC
+func (m *NodeMetadata) BeforeSave(tx *gorm.DB) error {
+	now := time.Now().UTC()
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	} else {
+		m.CreatedAt = m.CreatedAt.UTC()
+	}
+	m.UpdatedAt = now
+	return nil
+}
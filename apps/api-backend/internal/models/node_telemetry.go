@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NodeTelemetry holds the latest telemetry reading reported by a node.
+// Only one row per node is kept; each report replaces the previous one.
+// All timestamps are stored in UTC.
+type NodeTelemetry struct {
+	// NodeUUID is the node that reported the reading
+	NodeUUID string `gorm:"primaryKey;type:text;not null" json:"node_uuid"`
+
+	// SchemaVersion is the telemetry payload version the node sent
+	SchemaVersion int `gorm:"type:integer;not null" json:"schema_version"`
+
+	// BatteryPercent is the remaining battery charge (0-100)
+	BatteryPercent *int `gorm:"type:integer" json:"battery_percent,omitempty"`
+
+	// RSSI is the received signal strength in dBm (-150 to 0)
+	RSSI *int `gorm:"column:rssi;type:integer" json:"rssi,omitempty"`
+
+	// UptimeSeconds is the time since the node last booted
+	UptimeSeconds *int64 `gorm:"type:integer" json:"uptime_seconds,omitempty"`
+
+	// ReportedAt is when the server received the reading
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	ReportedAt time.Time `gorm:"type:datetime;not null" json:"reported_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (NodeTelemetry) TableName() string {
+	return "node_telemetry"
+}
+
+// BeforeSave is a GORM hook that ensures the timestamp is in UTC
+func (t *NodeTelemetry) BeforeSave(tx *gorm.DB) error {
+	if t.ReportedAt.IsZero() {
+		t.ReportedAt = time.Now().UTC()
+	} else {
+		t.ReportedAt = t.ReportedAt.UTC()
+	}
+	return nil
+}
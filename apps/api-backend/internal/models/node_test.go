@@ -3,6 +3,10 @@ package models
 import (
 	"testing"
 	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // TestNodeTableName tests the table name override
@@ -132,3 +136,116 @@ func TestNodeCreation(t *testing.T) {
 		t.Error("Node GPS coordinates should not be nil")
 	}
 }
+
+func TestNodeBeforeSave(t *testing.T) {
+	valid := 50.0
+	tooNorth := 90.5
+	tooWest := -180.5
+
+	tests := []struct {
+		name      string
+		latitude  *float64
+		longitude *float64
+		wantErr   bool
+	}{
+		{"no coordinates", nil, nil, false},
+		{"valid coordinates", &valid, &valid, false},
+		{"latitude out of range", &tooNorth, &valid, true},
+		{"longitude out of range", &valid, &tooWest, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &Node{Latitude: tt.latitude, Longitude: tt.longitude}
+			err := node.BeforeSave(nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BeforeSave() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodePendingFirmwareUpdate(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		current *string
+		target  *string
+		want    *string
+	}{
+		{"no target", strPtr("1.0.0"), nil, nil},
+		{"target newer", strPtr("1.0.0"), strPtr("1.1.0"), strPtr("1.1.0")},
+		{"already on target", strPtr("1.1.0"), strPtr("1.1.0"), nil},
+		{"ahead of target", strPtr("1.2.0"), strPtr("1.1.0"), nil},
+		{"unknown current version", nil, strPtr("1.1.0"), strPtr("1.1.0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &Node{FirmwareVersion: tt.current, TargetFirmwareVersion: tt.target}
+			got := node.PendingFirmwareUpdate()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("PendingFirmwareUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNodeHooksStoreUTC tests that GORM runs the node hooks, so timestamps given in
+// another zone are stored as UTC on create and on save
+func TestNodeHooksStoreUTC(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	// storedText returns a timestamp column exactly as SQLite holds it
+	storedText := func(column string) string {
+		t.Helper()
+		var text string
+		if err := db.Raw("SELECT CAST("+column+" AS TEXT) FROM nodes WHERE uuid = ?", "node-1").Scan(&text).Error; err != nil {
+			t.Fatalf("failed to read %s: %v", column, err)
+		}
+		return text
+	}
+
+	prague := time.FixedZone("CEST", 2*60*60)
+	seen := time.Date(2025, 11, 10, 16, 30, 0, 0, prague)
+	node := &Node{
+		UUID:             "node-1",
+		MacAddress:       "AA:BB:CC:DD:EE:FF",
+		JWTSecret:        "encrypted_secret",
+		Status:           NodeStatusActive,
+		LastSeenAt:       &seen,
+		LastRegisteredAt: &seen,
+		CreatedAt:        seen,
+	}
+	if err := db.Create(node).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	want := "2025-11-10 14:30:00+00:00"
+	for _, column := range []string{"last_seen_at", "last_registered_at"} {
+		if got := storedText(column); got != want {
+			t.Errorf("after Create %s stored as %q, want %q", column, got, want)
+		}
+	}
+	if node.CreatedAt.Location() != time.UTC || node.UpdatedAt.Location() != time.UTC {
+		t.Errorf("CreatedAt = %v, UpdatedAt = %v, want UTC set by BeforeCreate", node.CreatedAt, node.UpdatedAt)
+	}
+
+	registered := time.Date(2025, 11, 11, 9, 0, 0, 0, prague)
+	node.LastRegisteredAt = &registered
+	if err := db.Save(node).Error; err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if got, want := storedText("last_registered_at"), "2025-11-11 07:00:00+00:00"; got != want {
+		t.Errorf("after Save last_registered_at stored as %q, want %q", got, want)
+	}
+}
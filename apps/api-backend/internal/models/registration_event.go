@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RegistrationEvent records every successful registration or re-registration of a node.
+// Events are append-only and reference the token by ID (never by its secret value).
+// All timestamps are stored in UTC.
+type RegistrationEvent struct {
+	// ID is the event identifier (UUID)
+	ID string `gorm:"primaryKey;type:text;not null" json:"id"`
+
+	// NodeUUID is the node that was registered or re-registered
+	NodeUUID string `gorm:"type:text;not null;index" json:"node_uuid"`
+
+	// MacAddress is the normalized MAC address used for the registration
+	// Format: AA:BB:CC:DD:EE:FF (uppercase, colon-separated)
+	MacAddress string `gorm:"type:text;not null;index" json:"mac_address"`
+
+	// TokenID is the ID of the registration token that was consumed
+	TokenID string `gorm:"type:text;not null;index" json:"token_id"`
+
+	// EventType is either "registered" (new node) or "re_registered" (existing node)
+	EventType string `gorm:"type:text;not null" json:"event_type"`
+
+	// CreatedAt is the event timestamp
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	CreatedAt time.Time `gorm:"type:datetime;not null;index" json:"created_at"`
+}
+
+// RegistrationEvent type constants
+const (
+	RegistrationEventRegistered   = "registered"
+	RegistrationEventReRegistered = "re_registered"
+)
+
+// TableName overrides the default table name for GORM
+func (RegistrationEvent) TableName() string {
+	return "registration_events"
+}
+
+// BeforeCreate is a GORM hook that ensures the timestamp is in UTC
+func (e *RegistrationEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	} else {
+		e.CreatedAt = e.CreatedAt.UTC()
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/boomchecker/api-backend/internal/validators"
 	"gorm.io/gorm"
 )
 
@@ -29,6 +33,11 @@ type RegistrationToken struct {
 	// Positive N = max N uses
 	UsageLimit *int `gorm:"type:integer" json:"usage_limit,omitempty"`
 
+	// DistinctMacLimit optionally caps how many different MAC addresses may register with this token
+	// NULL = no cap. Counted from the token's registration events, so re-registering a device
+	// already registered with the token never counts twice. Bounds the reach of unlimited tokens.
+	DistinctMacLimit *int `gorm:"type:integer" json:"distinct_mac_limit,omitempty"`
+
 	// UsedCount is incremented each time token is successfully used for registration
 	UsedCount int `gorm:"type:integer;not null;default:0" json:"used_count"`
 
@@ -38,6 +47,25 @@ type RegistrationToken struct {
 	// NOTE: This is a soft reference - the MAC address doesn't need to exist yet in nodes table
 	PreAuthorizedMacAddress *string `gorm:"type:text" json:"pre_authorized_mac_address,omitempty"`
 
+	// AllowReRegistration controls whether the token may re-register a node whose MAC already exists
+	// NULL or true = re-registration allowed (default), false = token can only create new nodes
+	AllowReRegistration *bool `gorm:"type:boolean;default:true" json:"allow_re_registration,omitempty"`
+
+	// Description is an optional admin note, e.g. the device batch the token is for
+	Description *string `gorm:"type:text" json:"description,omitempty"`
+
+	// OwnerID is the optional tenant (customer) that nodes registered with this token belong to
+	// NULL = tenant-less token; re-registration keeps the node's existing owner
+	OwnerID *string `gorm:"type:text;index" json:"owner_id,omitempty"`
+
+	// CustomClaims is an optional JSON object signed into the JWTs of nodes registered with this token
+	// NULL = no custom claims; see ParsedCustomClaims
+	CustomClaims *string `gorm:"type:text" json:"custom_claims,omitempty"`
+
+	// CreatedBy is the email of the admin who created the token
+	// NULL when the token was created while admin login was disabled
+	CreatedBy *string `gorm:"type:text;index" json:"created_by,omitempty"`
+
 	// CreatedAt is the token creation timestamp
 	// Stored in UTC, format: 2025-11-10T14:30:00Z
 	CreatedAt time.Time `gorm:"type:datetime;not null" json:"created_at"`
@@ -45,6 +73,10 @@ type RegistrationToken struct {
 	// UpdatedAt is the last modification timestamp
 	// Stored in UTC, format: 2025-11-10T14:30:00Z
 	UpdatedAt time.Time `gorm:"type:datetime;not null" json:"updated_at"`
+
+	// DeletedAt is set when an admin deletes the token
+	// Deleted tokens are excluded from all queries and can be restored until cleanup purges them
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName overrides the default table name for GORM
@@ -52,13 +84,24 @@ func (RegistrationToken) TableName() string {
 	return "registration_tokens"
 }
 
+// AllowPastExpiryKey is the GORM setting that permits creating a token whose ExpiresAt
+// is not in the future, e.g. db.Set(AllowPastExpiryKey, true).Create(token)
+const AllowPastExpiryKey = "registration_token:allow_past_expiry"
+
 // BeforeCreate is a GORM hook that ensures timestamps are in UTC
+// and rejects an ExpiresAt that is not in the future unless AllowPastExpiryKey is set
 func (rt *RegistrationToken) BeforeCreate(tx *gorm.DB) error {
 	rt.CreatedAt = time.Now().UTC()
 	rt.UpdatedAt = time.Now().UTC()
 	if rt.ExpiresAt != nil {
 		utcTime := rt.ExpiresAt.UTC()
 		rt.ExpiresAt = &utcTime
+
+		if allowed, _ := tx.Get(AllowPastExpiryKey); allowed != true {
+			if err := validators.ValidateFutureTimestamp(utcTime, "expires_at"); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -94,6 +137,19 @@ func (rt *RegistrationToken) HasRemainingUses() bool {
 	return rt.UsedCount < *rt.UsageLimit
 }
 
+// RemainingUses returns how many more registrations the token allows
+// Returns nil for unlimited tokens; never negative.
+func (rt *RegistrationToken) RemainingUses() *int {
+	if rt.UsageLimit == nil || *rt.UsageLimit == 0 {
+		return nil
+	}
+	remaining := *rt.UsageLimit - rt.UsedCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
 // IsValid checks if the token is valid (not expired and has remaining uses)
 func (rt *RegistrationToken) IsValid() bool {
 	return !rt.IsExpired() && rt.HasRemainingUses()
@@ -109,3 +165,25 @@ func (rt *RegistrationToken) CanBeUsedForMac(macAddress string) bool {
 	}
 	return strings.EqualFold(*rt.PreAuthorizedMacAddress, macAddress)
 }
+
+// AllowsReRegistration checks if the token may be used to re-register an existing node
+// Returns true if AllowReRegistration is NULL (default) or true
+func (rt *RegistrationToken) AllowsReRegistration() bool {
+	return rt.AllowReRegistration == nil || *rt.AllowReRegistration
+}
+
+// ParsedCustomClaims decodes CustomClaims, returning nil if the token has none
+// Numbers are kept as json.Number so they are signed into JWTs exactly as stored.
+func (rt *RegistrationToken) ParsedCustomClaims() (map[string]interface{}, error) {
+	if rt.CustomClaims == nil || *rt.CustomClaims == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(*rt.CustomClaims)))
+	decoder.UseNumber()
+	var claims map[string]interface{}
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode custom claims: %w", err)
+	}
+	return claims, nil
+}
@@ -73,6 +73,39 @@ func TestRegistrationTokenHasRemainingUses(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenRemainingUses tests the remaining uses count
+func TestRegistrationTokenRemainingUses(t *testing.T) {
+	zero := 0
+	maxUses5 := 5
+
+	tests := []struct {
+		name       string
+		usageLimit *int
+		usedCount  int
+		want       *int
+	}{
+		{"unlimited token", nil, 100, nil},
+		{"zero limit is unlimited", &zero, 3, nil},
+		{"unused with limit", &maxUses5, 0, &maxUses5},
+		{"exactly at limit", &maxUses5, 5, &zero},
+		{"over limit is never negative", &maxUses5, 7, &zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &RegistrationToken{
+				UsageLimit: tt.usageLimit,
+				UsedCount:  tt.usedCount,
+			}
+			got := token.RemainingUses()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("RegistrationToken.RemainingUses() = %v, want %v (limit=%v, used=%d)",
+					got, tt.want, tt.usageLimit, tt.usedCount)
+			}
+		})
+	}
+}
+
 // TestRegistrationTokenIsValid tests overall token validity
 func TestRegistrationTokenIsValid(t *testing.T) {
 	now := time.Now().UTC()
@@ -137,6 +170,31 @@ func TestRegistrationTokenCanBeUsedForMac(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenAllowsReRegistration tests the re-registration flag
+func TestRegistrationTokenAllowsReRegistration(t *testing.T) {
+	allow := true
+	deny := false
+
+	tests := []struct {
+		name  string
+		value *bool
+		want  bool
+	}{
+		{"default (nil)", nil, true},
+		{"explicitly allowed", &allow, true},
+		{"explicitly denied", &deny, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &RegistrationToken{AllowReRegistration: tt.value}
+			if got := token.AllowsReRegistration(); got != tt.want {
+				t.Errorf("RegistrationToken.AllowsReRegistration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestRegistrationTokenCreation tests basic token structure
 func TestRegistrationTokenCreation(t *testing.T) {
 	now := time.Now().UTC()
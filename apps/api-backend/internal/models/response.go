@@ -7,4 +7,22 @@ type HealthResponse struct {
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
 	Service   string    `json:"service"`
+
+	// Checks holds per-subsystem results (only returned by /health and /ready)
+	Checks map[string]*HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is the outcome of a single subsystem check
+type HealthCheckResult struct {
+	Status    string `json:"status" example:"ok"` // "ok" or "error"
+	Message   string `json:"message,omitempty" example:"dial tcp: connection refused"`
+	CheckedAt string `json:"checked_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// VersionResponse describes the build of the running server
+type VersionResponse struct {
+	Version   string `json:"version" example:"0.0.1"`
+	Commit    string `json:"commit" example:"1effd3b2c4a5e6f7a8b9c0d1e2f3a4b5c6d7e8f9"`
+	BuildTime string `json:"build_time" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format) or "unknown"
+	GoVersion string `json:"go_version" example:"go1.24.0"`
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevokedNodeToken records a node JWT that must no longer be accepted
+// Tokens are identified by their jti claim, so only this token is rejected; the node
+// itself keeps working with a token from a new registration.
+// All timestamps are stored in UTC.
+type RevokedNodeToken struct {
+	// ID is the revoked token's JWT ID (jti claim)
+	ID string `gorm:"primaryKey;type:text;not null" json:"jti"`
+
+	// NodeUUID is the node the token was issued to
+	NodeUUID string `gorm:"type:text;not null;index" json:"node_uuid"`
+
+	// RevokedBy is the email of the admin who revoked the token; NULL if admin login is disabled
+	RevokedBy *string `gorm:"type:text" json:"revoked_by,omitempty"`
+
+	// RevokedAt is when the token was revoked
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	RevokedAt time.Time `gorm:"type:datetime;not null" json:"revoked_at"`
+
+	// ExpiresAt is when every token with this jti has expired on its own, after which the entry can be removed
+	// Stored in UTC, format: 2025-12-10T14:30:00Z
+	ExpiresAt time.Time `gorm:"type:datetime;not null;index" json:"expires_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (RevokedNodeToken) TableName() string {
+	return "revoked_node_tokens"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (t *RevokedNodeToken) BeforeCreate(tx *gorm.DB) error {
+	if t.RevokedAt.IsZero() {
+		t.RevokedAt = time.Now().UTC()
+	} else {
+		t.RevokedAt = t.RevokedAt.UTC()
+	}
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration records one applied step of the versioned database migrations
+// A row is written in the same transaction as the step itself, so a step is either
+// fully applied and recorded or not applied at all.
+// All timestamps are stored in UTC.
+type SchemaMigration struct {
+	// Version is the step's position in the migration list (1, 2, 3, ...)
+	Version int `gorm:"primaryKey;autoIncrement:false" json:"version"`
+
+	// Name is a short description of the step (e.g. "initial_schema")
+	Name string `gorm:"type:text;not null" json:"name"`
+
+	// AppliedAt is when the step was applied to this database
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	AppliedAt time.Time `gorm:"type:datetime;not null" json:"applied_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// BeforeCreate is a GORM hook that ensures timestamps are in UTC
+func (m *SchemaMigration) BeforeCreate(tx *gorm.DB) error {
+	if m.AppliedAt.IsZero() {
+		m.AppliedAt = time.Now().UTC()
+	} else {
+		m.AppliedAt = m.AppliedAt.UTC()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Setting is a runtime setting changed by admins through the API
+// Settings persist across restarts, unlike configuration read from the environment.
+// All timestamps are stored in UTC.
+type Setting struct {
+	// Key identifies the setting (e.g. "cleanup.paused")
+	Key string `gorm:"primaryKey;type:text;not null" json:"key"`
+
+	// Value is the setting's value encoded as text
+	Value string `gorm:"type:text;not null" json:"value"`
+
+	// UpdatedBy is the email of the admin who last changed the setting; NULL if admin login is disabled
+	UpdatedBy *string `gorm:"type:text" json:"updated_by,omitempty"`
+
+	// UpdatedAt is when the setting was last changed
+	// Stored in UTC, format: 2025-11-10T14:30:00Z
+	UpdatedAt time.Time `gorm:"type:datetime;not null" json:"updated_at"`
+}
+
+// TableName overrides the default table name for GORM
+func (Setting) TableName() string {
+	return "settings"
+}
+
+// BeforeSave is a GORM hook that ensures timestamps are in UTC
+func (s *Setting) BeforeSave(tx *gorm.DB) error {
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = time.Now().UTC()
+	} else {
+		s.UpdatedAt = s.UpdatedAt.UTC()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AdminLoginCodeRepository handles database operations for emailed admin login codes
+// Each email has at most one code; storing a new one replaces the previous code
+type AdminLoginCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminLoginCodeRepository creates a new admin login code repository instance
+func NewAdminLoginCodeRepository(db *gorm.DB) *AdminLoginCodeRepository {
+	return &AdminLoginCodeRepository{db: db}
+}
+
+// Replace stores a login code, deleting any earlier code for the same email
+func (r *AdminLoginCodeRepository) Replace(code *models.AdminLoginCode) error {
+	if code == nil {
+		return fmt.Errorf("admin login code cannot be nil")
+	}
+	if code.ID == "" || code.Email == "" || code.CodeHash == "" {
+		return fmt.Errorf("admin login code ID, email and hash are required")
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("email = ?", code.Email).Delete(&models.AdminLoginCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(code).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store admin login code: %w", err)
+	}
+
+	return nil
+}
+
+// FindByEmail returns the current login code for an email
+func (r *AdminLoginCodeRepository) FindByEmail(email string) (*models.AdminLoginCode, error) {
+	if email == "" {
+		return nil, fmt.Errorf("admin email is required")
+	}
+
+	var code models.AdminLoginCode
+	if err := r.db.Where("email = ?", email).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("admin login code not found for: %s", email)
+		}
+		return nil, fmt.Errorf("failed to find admin login code: %w", err)
+	}
+
+	return &code, nil
+}
+
+// RecordFailedAttempt increments the failed attempt count of a code
+// Returns the new count
+func (r *AdminLoginCodeRepository) RecordFailedAttempt(id string) (int, error) {
+	if err := r.db.Model(&models.AdminLoginCode{}).Where("id = ?", id).
+		UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + 1")).Error; err != nil {
+		return 0, fmt.Errorf("failed to record failed login code attempt: %w", err)
+	}
+
+	var code models.AdminLoginCode
+	if err := r.db.Select("failed_attempts").Where("id = ?", id).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, fmt.Errorf("admin login code not found")
+		}
+		return 0, fmt.Errorf("failed to find admin login code: %w", err)
+	}
+
+	return code.FailedAttempts, nil
+}
+
+// Consume deletes a code so it cannot be used again
+// Returns an error if the code was already consumed, so concurrent verifications cannot both succeed
+func (r *AdminLoginCodeRepository) Consume(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.AdminLoginCode{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to consume admin login code: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("admin login code not found")
+	}
+
+	return nil
+}
+
+// Delete removes a code by ID
+func (r *AdminLoginCodeRepository) Delete(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&models.AdminLoginCode{}).Error; err != nil {
+		return fmt.Errorf("failed to delete admin login code: %w", err)
+	}
+
+	return nil
+}
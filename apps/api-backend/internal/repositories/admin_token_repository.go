@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AdminTokenRepository handles database operations for issued admin login tokens
+// Tokens are looked up by the SHA-256 hash of the JWT, never by the JWT itself
+type AdminTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminTokenRepository creates a new admin token repository instance
+func NewAdminTokenRepository(db *gorm.DB) *AdminTokenRepository {
+	return &AdminTokenRepository{db: db}
+}
+
+// Create stores an issued admin token
+func (r *AdminTokenRepository) Create(token *models.AdminToken) error {
+	if token == nil {
+		return fmt.Errorf("admin token cannot be nil")
+	}
+	if token.ID == "" {
+		return fmt.Errorf("admin token ID is required")
+	}
+	if token.Email == "" {
+		return fmt.Errorf("admin email is required")
+	}
+	if token.TokenHash == "" {
+		return fmt.Errorf("admin token hash is required")
+	}
+
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create admin token: %w", err)
+	}
+
+	return nil
+}
+
+// FindLatestByEmail returns the most recently requested token for an email
+func (r *AdminTokenRepository) FindLatestByEmail(email string) (*models.AdminToken, error) {
+	if email == "" {
+		return nil, fmt.Errorf("admin email is required")
+	}
+
+	var token models.AdminToken
+	if err := r.db.Where("email = ?", email).
+		Order("requested_at DESC, id DESC").
+		First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("admin token not found for: %s", email)
+		}
+		return nil, fmt.Errorf("failed to find admin token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// ValidateToken checks that a JWT was issued by this server and has not expired
+// The JWT signature itself is verified separately by crypto.VerifyAdminJWT
+func (r *AdminTokenRepository) ValidateToken(tokenValue string) (*models.AdminToken, error) {
+	if tokenValue == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	var token models.AdminToken
+	if err := r.db.Where("token_hash = ?", crypto.HashToken(tokenValue)).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("admin token not found")
+		}
+		return nil, fmt.Errorf("failed to find admin token: %w", err)
+	}
+
+	if token.IsExpired() {
+		return nil, fmt.Errorf("admin token has expired")
+	}
+
+	return &token, nil
+}
+
+// IncrementResendCount records one resend of a token unless it was already resent max times
+// The check and the increment are one statement, so concurrent resends cannot exceed max.
+// Returns false when the limit was reached.
+func (r *AdminTokenRepository) IncrementResendCount(id string, max int) (bool, error) {
+	result := r.db.Model(&models.AdminToken{}).
+		Where("id = ? AND resend_count < ?", id, max).
+		Update("resend_count", gorm.Expr("resend_count + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record admin token resend: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete removes an admin token by ID
+func (r *AdminTokenRepository) Delete(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&models.AdminToken{}).Error; err != nil {
+		return fmt.Errorf("failed to delete admin token: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAll removes every admin token, logging all admins out and resetting their request limits
+// Returns the number of tokens deleted
+func (r *AdminTokenRepository) DeleteAll() (int64, error) {
+	result := r.db.Where("1 = 1").Delete(&models.AdminToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete admin tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteExpired removes tokens that have expired
+// Returns the number of tokens deleted
+func (r *AdminTokenRepository) DeleteExpired() (int64, error) {
+	result := r.db.Where("expires_at <= ?", time.Now().UTC()).Delete(&models.AdminToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired admin tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
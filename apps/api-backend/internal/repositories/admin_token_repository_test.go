@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestAdminTokenRepository_ValidateToken tests looking up issued admin tokens by hash
+func TestAdminTokenRepository_ValidateToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAdminTokenRepository(db)
+
+	now := time.Now().UTC()
+	tokens := []*models.AdminToken{
+		{ID: "jti-1", Email: "admin@example.com", TokenHash: crypto.HashToken("valid-jwt"), RequestedAt: now.Add(-time.Hour), ExpiresAt: now.Add(23 * time.Hour)},
+		{ID: "jti-2", Email: "admin@example.com", TokenHash: crypto.HashToken("expired-jwt"), RequestedAt: now.Add(-25 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	}
+	for _, token := range tokens {
+		if err := repo.Create(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.ValidateToken("valid-jwt")
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if found.ID != "jti-1" {
+		t.Errorf("ValidateToken() ID = %s, want jti-1", found.ID)
+	}
+
+	if _, err := repo.ValidateToken("expired-jwt"); err == nil {
+		t.Error("ValidateToken() expected error for expired token, got nil")
+	}
+	if _, err := repo.ValidateToken("unknown-jwt"); err == nil {
+		t.Error("ValidateToken() expected error for unknown token, got nil")
+	}
+
+	latest, err := repo.FindLatestByEmail("admin@example.com")
+	if err != nil {
+		t.Fatalf("FindLatestByEmail() error = %v", err)
+	}
+	if latest.ID != "jti-1" {
+		t.Errorf("FindLatestByEmail() ID = %s, want jti-1", latest.ID)
+	}
+
+	deleted, err := repo.DeleteExpired()
+	if err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", deleted)
+	}
+}
+
+// TestAdminTokenRepository_IncrementResendCount tests that resends stop at the limit
+func TestAdminTokenRepository_IncrementResendCount(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAdminTokenRepository(db)
+
+	if err := repo.Create(&models.AdminToken{ID: "jti-1", Email: "admin@example.com", TokenHash: crypto.HashToken("jwt"), ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := repo.IncrementResendCount("jti-1", 2); err != nil || !ok {
+			t.Fatalf("IncrementResendCount() #%d = %t, %v, want true", i+1, ok, err)
+		}
+	}
+	if ok, err := repo.IncrementResendCount("jti-1", 2); err != nil || ok {
+		t.Errorf("IncrementResendCount() over the limit = %t, %v, want false", ok, err)
+	}
+
+	token, err := repo.FindLatestByEmail("admin@example.com")
+	if err != nil {
+		t.Fatalf("FindLatestByEmail() error = %v", err)
+	}
+	if token.ResendCount != 2 {
+		t.Errorf("ResendCount = %d, want 2", token.ResendCount)
+	}
+}
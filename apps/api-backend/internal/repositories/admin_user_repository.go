@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AdminUserRepository handles database operations for the admin email allowlist
+// Emails are expected to be normalized (trimmed, lowercase) by the caller
+type AdminUserRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminUserRepository creates a new admin user repository instance
+func NewAdminUserRepository(db *gorm.DB) *AdminUserRepository {
+	return &AdminUserRepository{db: db}
+}
+
+// Create inserts a new admin email
+// Returns an "admin email already exists" error if the email is already authorized
+func (r *AdminUserRepository) Create(admin *models.AdminUser) error {
+	if admin == nil {
+		return fmt.Errorf("admin user cannot be nil")
+	}
+	if admin.Email == "" {
+		return fmt.Errorf("admin email is required")
+	}
+
+	exists, err := r.Exists(admin.Email)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("admin email already exists: %s", admin.Email)
+	}
+
+	if err := r.db.Create(admin).Error; err != nil {
+		return fmt.Errorf("failed to create admin user: %w", err)
+	}
+
+	return nil
+}
+
+// FindByEmail retrieves an admin user by email
+func (r *AdminUserRepository) FindByEmail(email string) (*models.AdminUser, error) {
+	if email == "" {
+		return nil, fmt.Errorf("admin email is required")
+	}
+
+	var admin models.AdminUser
+	if err := r.db.Where("email = ?", email).First(&admin).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("admin email not found: %s", email)
+		}
+		return nil, fmt.Errorf("failed to find admin user: %w", err)
+	}
+
+	return &admin, nil
+}
+
+// ListAll retrieves all admin users ordered by email
+func (r *AdminUserRepository) ListAll() ([]*models.AdminUser, error) {
+	var admins []*models.AdminUser
+	if err := r.db.Order("email ASC").Find(&admins).Error; err != nil {
+		return nil, fmt.Errorf("failed to list admin users: %w", err)
+	}
+
+	return admins, nil
+}
+
+// Delete removes an admin email from the allowlist
+func (r *AdminUserRepository) Delete(email string) error {
+	if email == "" {
+		return fmt.Errorf("admin email is required")
+	}
+
+	result := r.db.Where("email = ?", email).Delete(&models.AdminUser{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete admin user: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("admin email not found: %s", email)
+	}
+
+	return nil
+}
+
+// Exists checks if an email is in the allowlist
+func (r *AdminUserRepository) Exists(email string) (bool, error) {
+	if email == "" {
+		return false, fmt.Errorf("admin email is required")
+	}
+
+	var count int64
+	if err := r.db.Model(&models.AdminUser{}).
+		Where("email = ?", email).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check admin user existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Count returns the number of admin users in the allowlist
+func (r *AdminUserRepository) Count() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.AdminUser{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count admin users: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestAdminUserRepository_CRUD tests adding, listing, finding and removing admin emails
+func TestAdminUserRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAdminUserRepository(db)
+
+	for _, email := range []string{"zoe@example.com", "adam@example.com"} {
+		if err := repo.Create(&models.AdminUser{Email: email}); err != nil {
+			t.Fatalf("Create(%q) error = %v", email, err)
+		}
+	}
+
+	if err := repo.Create(&models.AdminUser{Email: "adam@example.com"}); err == nil {
+		t.Error("Create() with duplicate email should return error")
+	}
+	if err := repo.Create(&models.AdminUser{}); err == nil {
+		t.Error("Create() without email should return error")
+	}
+
+	admins, err := repo.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(admins) != 2 || admins[0].Email != "adam@example.com" {
+		t.Errorf("ListAll() = %v, want 2 admins ordered by email", admins)
+	}
+
+	found, err := repo.FindByEmail("zoe@example.com")
+	if err != nil {
+		t.Fatalf("FindByEmail() error = %v", err)
+	}
+	if found.CreatedAt.IsZero() {
+		t.Errorf("FindByEmail() = %+v, want CreatedAt set", found)
+	}
+
+	if err := repo.Delete("zoe@example.com"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete("zoe@example.com"); err == nil {
+		t.Error("Delete() of missing email should return error")
+	}
+
+	exists, err := repo.Exists("zoe@example.com")
+	if err != nil {
+		t.Fatalf("Exists() error = %v", err)
+	}
+	if exists {
+		t.Error("Exists() = true after Delete(), want false")
+	}
+
+	count, err := repo.Count()
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Count() = %d, want 1", count)
+	}
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter selects audit log entries; empty fields do not filter
+type AuditLogFilter struct {
+	Action     string
+	AdminEmail string
+	TargetType string
+	TargetID   string
+	// From and To bound CreatedAt (both inclusive)
+	From *time.Time
+	To   *time.Time
+
+	// Limit and Offset select one page; Limit <= 0 returns all matches
+	Limit  int
+	Offset int
+	// Ascending sorts oldest first; the default is newest first
+	Ascending bool
+}
+
+// AuditLogRepository handles database operations for the audit log
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository instance
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create appends an entry to the audit log
+func (r *AuditLogRepository) Create(entry *models.AuditLog) error {
+	if entry == nil {
+		return fmt.Errorf("audit log entry cannot be nil")
+	}
+	if entry.ID == "" || entry.Action == "" || entry.TargetType == "" {
+		return fmt.Errorf("audit log ID, action and target type are required")
+	}
+
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns one page of entries matching the filter and the total number of matches
+func (r *AuditLogRepository) Query(filter AuditLogFilter) ([]*models.AuditLog, int64, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.AdminEmail != "" {
+		query = query.Where("admin_email = ?", filter.AdminEmail)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", filter.To.UTC())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	// ID breaks ties between entries written in the same instant so pages are stable
+	order := "created_at DESC, id DESC"
+	if filter.Ascending {
+		order = "created_at ASC, id ASC"
+	}
+	query = query.Order(order)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var entries []*models.AuditLog
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit log: %w", err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestAuditLogRepository_Query tests filtering, time ranges, sorting and pagination
+func TestAuditLogRepository_Query(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAuditLogRepository(db)
+
+	admin := "admin@example.com"
+	other := "ops@example.com"
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	start := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+
+	// One entry per hour: 5 node revocations by admin, then 2 cleanup pauses by ops
+	for i := 0; i < 7; i++ {
+		entry := &models.AuditLog{
+			ID:         fmt.Sprintf("entry-%d", i),
+			Action:     "node_token.revoked",
+			AdminEmail: &admin,
+			TargetType: models.AuditTargetNode,
+			TargetID:   &nodeUUID,
+			CreatedAt:  start.Add(time.Duration(i) * time.Hour),
+		}
+		if i >= 5 {
+			entry.Action = "cleanup.paused"
+			entry.AdminEmail = &other
+			entry.TargetType = models.AuditTargetCleanup
+			entry.TargetID = nil
+		}
+		if err := repo.Create(entry); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		filter    AuditLogFilter
+		wantIDs   []string
+		wantTotal int64
+	}{
+		{"all newest first", AuditLogFilter{Limit: 3}, []string{"entry-6", "entry-5", "entry-4"}, 7},
+		{"second page oldest first", AuditLogFilter{Limit: 3, Offset: 3, Ascending: true}, []string{"entry-3", "entry-4", "entry-5"}, 7},
+		{"by action", AuditLogFilter{Action: "cleanup.paused"}, []string{"entry-6", "entry-5"}, 2},
+		{"by admin", AuditLogFilter{AdminEmail: other, Ascending: true}, []string{"entry-5", "entry-6"}, 2},
+		{"by target", AuditLogFilter{TargetType: models.AuditTargetNode, TargetID: nodeUUID, Limit: 1}, []string{"entry-4"}, 5},
+		{"time range inclusive", AuditLogFilter{From: timePtr(start.Add(time.Hour)), To: timePtr(start.Add(2 * time.Hour)), Ascending: true}, []string{"entry-1", "entry-2"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, total, err := repo.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("Query() total = %d, want %d", total, tt.wantTotal)
+			}
+			ids := make([]string, len(entries))
+			for i, entry := range entries {
+				ids[i] = entry.ID
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("Query() = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// timePtr returns a pointer to t
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// DeniedMACRepository handles database operations for the MAC denylist
+// MAC addresses are normalized before every lookup, like in NodeRepository.
+type DeniedMACRepository struct {
+	db *gorm.DB
+}
+
+// NewDeniedMACRepository creates a new denied MAC repository instance
+func NewDeniedMACRepository(db *gorm.DB) *DeniedMACRepository {
+	return &DeniedMACRepository{db: db}
+}
+
+// Create adds a MAC address to the denylist
+func (r *DeniedMACRepository) Create(entry *models.DeniedMAC) error {
+	if entry == nil {
+		return fmt.Errorf("denylist entry cannot be nil")
+	}
+	if entry.MacAddress == "" {
+		return fmt.Errorf("mac address is required")
+	}
+	entry.MacAddress = normalizeMAC(entry.MacAddress)
+
+	denied, err := r.IsDenied(entry.MacAddress)
+	if err != nil {
+		return err
+	}
+	if denied {
+		return fmt.Errorf("mac address already denylisted: %s", entry.MacAddress)
+	}
+
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to denylist mac address: %w", err)
+	}
+
+	return nil
+}
+
+// FindByMAC retrieves the denylist entry for a MAC address
+func (r *DeniedMACRepository) FindByMAC(macAddress string) (*models.DeniedMAC, error) {
+	macAddress = normalizeMAC(macAddress)
+
+	var entry models.DeniedMAC
+	if err := r.db.Where("mac_address = ?", macAddress).First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("denylist entry not found: %s", macAddress)
+		}
+		return nil, fmt.Errorf("failed to find denylist entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// IsDenied reports whether a MAC address is on the denylist
+func (r *DeniedMACRepository) IsDenied(macAddress string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.DeniedMAC{}).Where("mac_address = ?", normalizeMAC(macAddress)).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check mac denylist: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ListAll retrieves every denylisted MAC address, most recently banned first
+func (r *DeniedMACRepository) ListAll() ([]*models.DeniedMAC, error) {
+	var entries []*models.DeniedMAC
+	if err := r.db.Order("created_at DESC, mac_address ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list denylisted mac addresses: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Delete removes a MAC address from the denylist
+func (r *DeniedMACRepository) Delete(macAddress string) error {
+	macAddress = normalizeMAC(macAddress)
+
+	result := r.db.Where("mac_address = ?", macAddress).Delete(&models.DeniedMAC{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove mac address from denylist: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("denylist entry not found: %s", macAddress)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestDeniedMACRepository_CreateAndDelete tests adding, looking up and removing denylisted MACs in any notation
+func TestDeniedMACRepository_CreateAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewDeniedMACRepository(db)
+
+	reason := "stolen device"
+	if err := repo.Create(&models.DeniedMAC{MacAddress: "aa-bb-cc-dd-ee-ff", Reason: &reason}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(&models.DeniedMAC{MacAddress: "AA:BB:CC:DD:EE:FF"}); err == nil {
+		t.Error("Create() duplicate error = nil, want error")
+	}
+
+	found, err := repo.FindByMAC("aa:bb:cc:dd:ee:ff")
+	if err != nil {
+		t.Fatalf("FindByMAC() error = %v", err)
+	}
+	if found.MacAddress != "AA:BB:CC:DD:EE:FF" || found.Reason == nil || *found.Reason != reason {
+		t.Errorf("FindByMAC() = %+v, want normalized MAC with reason", found)
+	}
+
+	for mac, want := range map[string]bool{"AA:BB:CC:DD:EE:FF": true, "aabbccddeeff": true, "AA:BB:CC:DD:EE:00": false} {
+		denied, err := repo.IsDenied(mac)
+		if err != nil {
+			t.Fatalf("IsDenied(%s) error = %v", mac, err)
+		}
+		if denied != want {
+			t.Errorf("IsDenied(%s) = %v, want %v", mac, denied, want)
+		}
+	}
+
+	if err := repo.Delete("aa-bb-cc-dd-ee-ff"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete("AA:BB:CC:DD:EE:FF"); err == nil {
+		t.Error("Delete() of removed entry error = nil, want not found")
+	}
+	entries, err := repo.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("ListAll() len = %d, want 0", len(entries))
+	}
+}
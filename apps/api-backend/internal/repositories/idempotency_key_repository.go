@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository handles database operations for idempotency keys
+type IdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository instance
+func NewIdempotencyKeyRepository(db *gorm.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Reserve stores a new idempotency key before the operation runs
+// Returns reserved=false (and no error) if the key already exists in this scope,
+// so concurrent retries cannot both perform the operation.
+func (r *IdempotencyKeyRepository) Reserve(key *models.IdempotencyKey) (bool, error) {
+	if key == nil {
+		return false, fmt.Errorf("idempotency key cannot be nil")
+	}
+	if key.Scope == "" || key.Key == "" {
+		return false, fmt.Errorf("idempotency scope and key are required")
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Find retrieves an idempotency key
+func (r *IdempotencyKeyRepository) Find(scope, key string) (*models.IdempotencyKey, error) {
+	if scope == "" || key == "" {
+		return nil, fmt.Errorf("idempotency scope and key are required")
+	}
+
+	var record models.IdempotencyKey
+	if err := r.db.Where("scope = ? AND key = ?", scope, key).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("idempotency key not found")
+		}
+		return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Complete records the ID of the resource created for a reserved key
+func (r *IdempotencyKeyRepository) Complete(scope, key, resourceID string) error {
+	if resourceID == "" {
+		return fmt.Errorf("resource ID is required")
+	}
+
+	result := r.db.Model(&models.IdempotencyKey{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Update("resource_id", resourceID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("idempotency key not found")
+	}
+
+	return nil
+}
+
+// Delete removes an idempotency key, e.g. to release a reservation after the operation failed
+func (r *IdempotencyKeyRepository) Delete(scope, key string) error {
+	if err := r.db.Where("scope = ? AND key = ?", scope, key).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes keys whose retention window has passed
+// Returns the number of keys deleted
+func (r *IdempotencyKeyRepository) DeleteExpired() (int64, error) {
+	result := r.db.Where("expires_at <= ?", time.Now().UTC()).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
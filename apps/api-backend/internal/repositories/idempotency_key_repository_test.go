@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestIdempotencyKeyRepository_ReserveAndComplete tests reserving, completing and expiring keys
+func TestIdempotencyKeyRepository_ReserveAndComplete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewIdempotencyKeyRepository(db)
+
+	newKey := func(key string, expiresAt time.Time) *models.IdempotencyKey {
+		return &models.IdempotencyKey{Scope: "test.create", Key: key, RequestHash: "hash", ExpiresAt: expiresAt}
+	}
+	future := time.Now().UTC().Add(time.Hour)
+
+	reserved, err := repo.Reserve(newKey("key-1", future))
+	if err != nil || !reserved {
+		t.Fatalf("Reserve() = %v, %v, want true, nil", reserved, err)
+	}
+
+	// Second reservation of the same key in the same scope loses
+	reserved, err = repo.Reserve(newKey("key-1", future))
+	if err != nil || reserved {
+		t.Errorf("Reserve() duplicate = %v, %v, want false, nil", reserved, err)
+	}
+
+	// Same key in another scope is independent
+	other := newKey("key-1", future)
+	other.Scope = "other.create"
+	if reserved, err := repo.Reserve(other); err != nil || !reserved {
+		t.Errorf("Reserve() other scope = %v, %v, want true, nil", reserved, err)
+	}
+
+	if err := repo.Complete("test.create", "key-1", "resource-1"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	found, err := repo.Find("test.create", "key-1")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if found.ResourceID == nil || *found.ResourceID != "resource-1" {
+		t.Errorf("ResourceID = %v, want resource-1", found.ResourceID)
+	}
+
+	// Expired keys are removed by DeleteExpired
+	if _, err := repo.Reserve(newKey("key-old", time.Now().UTC().Add(-time.Minute))); err != nil {
+		t.Fatalf("Reserve() expired key error = %v", err)
+	}
+	deleted, err := repo.DeleteExpired()
+	if err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", deleted)
+	}
+	if _, err := repo.Find("test.create", "key-old"); err == nil {
+		t.Error("Find() after DeleteExpired should return error")
+	}
+
+	if err := repo.Delete("test.create", "key-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.Find("test.create", "key-1"); err == nil {
+		t.Error("Find() after Delete should return error")
+	}
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NodeMetadataRepository handles database operations for node metadata
+type NodeMetadataRepository struct {
+	db *gorm.DB
+}
+
+// NewNodeMetadataRepository creates a new node metadata repository instance
+func NewNodeMetadataRepository(db *gorm.DB) *NodeMetadataRepository {
+	return &NodeMetadataRepository{db: db}
+}
+
+// Set creates or updates one metadata entry of a node
+// A new key is rejected once the node already has maxKeys entries; updating an
+// existing key is always allowed. maxKeys <= 0 means no limit.
+func (r *NodeMetadataRepository) Set(entry *models.NodeMetadata, maxKeys int) error {
+	if entry == nil {
+		return fmt.Errorf("metadata cannot be nil")
+	}
+	if entry.NodeUUID == "" {
+		return fmt.Errorf("node UUID is required")
+	}
+	if entry.Key == "" {
+		return fmt.Errorf("metadata key is required")
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.NodeMetadata
+		err := tx.Where("node_uuid = ? AND key = ?", entry.NodeUUID, entry.Key).First(&existing).Error
+		switch {
+		case err == nil:
+			entry.CreatedAt = existing.CreatedAt
+		case err == gorm.ErrRecordNotFound:
+			if maxKeys > 0 {
+				var count int64
+				if err := tx.Model(&models.NodeMetadata{}).
+					Where("node_uuid = ?", entry.NodeUUID).
+					Count(&count).Error; err != nil {
+					return fmt.Errorf("failed to count metadata: %w", err)
+				}
+				if count >= int64(maxKeys) {
+					return fmt.Errorf("metadata key limit reached: node already has %d keys", count)
+				}
+			}
+		default:
+			return fmt.Errorf("failed to find metadata: %w", err)
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "node_uuid"}, {Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to store metadata: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByNode retrieves all metadata entries of a node, ordered by key
+func (r *NodeMetadataRepository) FindByNode(nodeUUID string) ([]*models.NodeMetadata, error) {
+	if nodeUUID == "" {
+		return nil, fmt.Errorf("node UUID is required")
+	}
+
+	var entries []*models.NodeMetadata
+	if err := r.db.Where("node_uuid = ?", nodeUUID).Order("key ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find metadata: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Delete removes one metadata entry of a node
+func (r *NodeMetadataRepository) Delete(nodeUUID, key string) error {
+	if nodeUUID == "" {
+		return fmt.Errorf("node UUID is required")
+	}
+
+	result := r.db.Where("node_uuid = ? AND key = ?", nodeUUID, key).Delete(&models.NodeMetadata{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete metadata: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("metadata key not found: %s", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestNodeMetadataRepository_Set tests creating, updating and the per-node key limit
+func TestNodeMetadataRepository_Set(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeMetadataRepository(db)
+
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID, Key: "site_code", Value: "PRG-01"}, 2); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID, Key: "installer", Value: "alice"}, 2); err != nil {
+		t.Fatalf("Set() second key error = %v", err)
+	}
+
+	// Updating an existing key is allowed at the limit
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID, Key: "site_code", Value: "PRG-02"}, 2); err != nil {
+		t.Fatalf("Set() update error = %v", err)
+	}
+
+	// A third key is rejected
+	err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID, Key: "install_date", Value: "2025-11-10"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "metadata key limit reached") {
+		t.Errorf("Set() over limit error = %v, want key limit error", err)
+	}
+
+	// The limit is per node
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: "550e8400-e29b-41d4-a716-446655440001", Key: "site_code", Value: "BRN-01"}, 2); err != nil {
+		t.Fatalf("Set() other node error = %v", err)
+	}
+
+	entries, err := repo.FindByNode(nodeUUID)
+	if err != nil {
+		t.Fatalf("FindByNode() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("FindByNode() count = %d, want 2", len(entries))
+	}
+	if entries[0].Key != "installer" || entries[1].Key != "site_code" || entries[1].Value != "PRG-02" {
+		t.Errorf("FindByNode() = [%s=%s %s=%s], want [installer=alice site_code=PRG-02]",
+			entries[0].Key, entries[0].Value, entries[1].Key, entries[1].Value)
+	}
+
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID}, 0); err == nil {
+		t.Error("Set() without key should return error")
+	}
+}
+
+// TestNodeMetadataRepository_Delete tests removing a single key
+func TestNodeMetadataRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeMetadataRepository(db)
+
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	if err := repo.Set(&models.NodeMetadata{NodeUUID: nodeUUID, Key: "site_code", Value: "PRG-01"}, 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := repo.Delete(nodeUUID, "site_code"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(nodeUUID, "site_code"); err == nil || !strings.Contains(err.Error(), "metadata key not found") {
+		t.Errorf("Delete() missing key error = %v, want not found", err)
+	}
+
+	entries, err := repo.FindByNode(nodeUUID)
+	if err != nil {
+		t.Fatalf("FindByNode() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("FindByNode() count = %d, want 0", len(entries))
+	}
+}
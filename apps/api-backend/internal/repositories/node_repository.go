@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/validators"
 	"gorm.io/gorm"
 )
 
@@ -18,12 +19,22 @@ func NewNodeRepository(db *gorm.DB) *NodeRepository {
 	return &NodeRepository{db: db}
 }
 
+// Transaction runs fn inside a database transaction
+// The repository passed to fn is bound to the transaction; returning an error rolls it back
+func (r *NodeRepository) Transaction(fn func(txRepo *NodeRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&NodeRepository{db: tx})
+	})
+}
+
 // Create inserts a new node into the database
+// The MAC address is stored in canonical form (AA:BB:CC:DD:EE:FF)
 // Returns error if node with same UUID or MAC already exists
 func (r *NodeRepository) Create(node *models.Node) error {
 	if node == nil {
 		return fmt.Errorf("node cannot be nil")
 	}
+	node.MacAddress = normalizeMAC(node.MacAddress)
 
 	// Check for duplicate UUID
 	if err := r.checkDuplicateUUID(node.UUID); err != nil {
@@ -66,6 +77,7 @@ func (r *NodeRepository) FindByUUID(uuid string) (*models.Node, error) {
 }
 
 // FindByMAC retrieves a node by its MAC address
+// The address is normalized first, so any case or separator style finds the stored node
 // Returns gorm.ErrRecordNotFound if node doesn't exist
 func (r *NodeRepository) FindByMAC(macAddress string) (*models.Node, error) {
 	if macAddress == "" {
@@ -73,9 +85,9 @@ func (r *NodeRepository) FindByMAC(macAddress string) (*models.Node, error) {
 	}
 
 	var node models.Node
-	if err := r.db.Where("mac_address = ?", macAddress).First(&node).Error; err != nil {
+	if err := r.db.Where("mac_address = ?", normalizeMAC(macAddress)).First(&node).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("node not found with MAC: %s", macAddress)
+			return nil, fmt.Errorf("node not found with given MAC address")
 		}
 		return nil, fmt.Errorf("failed to find node: %w", err)
 	}
@@ -96,7 +108,8 @@ func (r *NodeRepository) Update(node *models.Node) error {
 	// Ensure UpdatedAt is current
 	node.UpdatedAt = time.Now().UTC()
 
-	result := r.db.Model(&models.Node{}).Where("uuid = ?", node.UUID).Updates(node)
+	// Model(node) so that the node's BeforeSave/BeforeUpdate hooks run on the values being written
+	result := r.db.Model(node).Where("uuid = ?", node.UUID).Updates(node)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update node: %w", result.Error)
 	}
@@ -172,6 +185,11 @@ func (r *NodeRepository) UpdateLocation(uuid string, latitude, longitude float64
 		return fmt.Errorf("uuid is required")
 	}
 
+	// Map updates bypass the Node BeforeSave hook, so validate here
+	if err := validators.ValidateGPSCoordinates(latitude, longitude); err != nil {
+		return fmt.Errorf("invalid location: %w", err)
+	}
+
 	result := r.db.Model(&models.Node{}).
 		Where("uuid = ?", uuid).
 		Updates(map[string]interface{}{
@@ -191,6 +209,30 @@ func (r *NodeRepository) UpdateLocation(uuid string, latitude, longitude float64
 	return nil
 }
 
+// UpdateTargetFirmware sets or clears (nil) the target firmware version for a node
+func (r *NodeRepository) UpdateTargetFirmware(uuid string, version *string) error {
+	if uuid == "" {
+		return fmt.Errorf("uuid is required")
+	}
+
+	result := r.db.Model(&models.Node{}).
+		Where("uuid = ?", uuid).
+		Updates(map[string]interface{}{
+			"target_firmware_version": version,
+			"updated_at":              time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update target firmware: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("node not found: %s", uuid)
+	}
+
+	return nil
+}
+
 // ListByStatus retrieves all nodes with a specific status
 func (r *NodeRepository) ListByStatus(status string) ([]*models.Node, error) {
 	if status == "" {
@@ -202,7 +244,7 @@ func (r *NodeRepository) ListByStatus(status string) ([]*models.Node, error) {
 	}
 
 	var nodes []*models.Node
-	if err := r.db.Where("status = ?", status).Order("created_at DESC").Find(&nodes).Error; err != nil {
+	if err := r.db.Where("status = ?", status).Order("created_at DESC, uuid ASC").Find(&nodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to list nodes by status: %w", err)
 	}
 
@@ -212,13 +254,60 @@ func (r *NodeRepository) ListByStatus(status string) ([]*models.Node, error) {
 // ListAll retrieves all nodes
 func (r *NodeRepository) ListAll() ([]*models.Node, error) {
 	var nodes []*models.Node
-	if err := r.db.Order("created_at DESC").Find(&nodes).Error; err != nil {
+	if err := r.db.Order("created_at DESC, uuid ASC").Find(&nodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to list all nodes: %w", err)
 	}
 
 	return nodes, nil
 }
 
+// List returns one page of nodes, newest first, and the total number of matching nodes
+// An empty status lists nodes of every status and an empty ownerID nodes of every owner.
+// A limit of 0 returns all of them.
+func (r *NodeRepository) List(status, ownerID string, limit, offset int) ([]*models.Node, int64, error) {
+	query := r.db.Model(&models.Node{})
+	if status != "" {
+		if !isValidStatus(status) {
+			return nil, 0, fmt.Errorf("invalid status: %s", status)
+		}
+		query = query.Where("status = ?", status)
+	}
+	if ownerID != "" {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count nodes: %w", err)
+	}
+
+	query = query.Order("created_at DESC, uuid ASC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var nodes []*models.Node
+	if err := query.Find(&nodes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return nodes, total, nil
+}
+
+// FindOldest returns the earliest registered node
+// Returns nil without error when no nodes exist
+func (r *NodeRepository) FindOldest() (*models.Node, error) {
+	var node models.Node
+	if err := r.db.Order("created_at ASC, uuid ASC").First(&node).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find oldest node: %w", err)
+	}
+
+	return &node, nil
+}
+
 // FindInactive returns nodes that haven't been seen within the threshold duration
 // Example: FindInactive(24 * time.Hour) returns nodes inactive for more than 24 hours
 func (r *NodeRepository) FindInactive(threshold time.Duration) ([]*models.Node, error) {
@@ -226,7 +315,7 @@ func (r *NodeRepository) FindInactive(threshold time.Duration) ([]*models.Node,
 
 	var nodes []*models.Node
 	if err := r.db.Where("last_seen_at < ? OR last_seen_at IS NULL", cutoffTime).
-		Order("last_seen_at ASC").
+		Order("last_seen_at ASC, uuid ASC").
 		Find(&nodes).Error; err != nil {
 		return nil, fmt.Errorf("failed to find inactive nodes: %w", err)
 	}
@@ -234,6 +323,161 @@ func (r *NodeRepository) FindInactive(threshold time.Duration) ([]*models.Node,
 	return nodes, nil
 }
 
+// CountInactive returns the number of nodes not seen within the threshold duration
+// Uses the same rule as FindInactive: nodes never seen are counted as inactive
+func (r *NodeRepository) CountInactive(threshold time.Duration) (int64, error) {
+	cutoffTime := time.Now().UTC().Add(-threshold)
+
+	var count int64
+	if err := r.db.Model(&models.Node{}).
+		Where("last_seen_at < ? OR last_seen_at IS NULL", cutoffTime).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count inactive nodes: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindSharedCoordinates returns nodes whose GPS coordinates are identical to another node's
+// Ordered by coordinates so nodes sharing a location are adjacent
+func (r *NodeRepository) FindSharedCoordinates() ([]*models.Node, error) {
+	var nodes []*models.Node
+	if err := r.db.Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+		Where("EXISTS (SELECT 1 FROM nodes AS other WHERE other.latitude = nodes.latitude AND other.longitude = nodes.longitude AND other.uuid <> nodes.uuid)").
+		Order("latitude ASC, longitude ASC, created_at ASC, uuid ASC").
+		Find(&nodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find nodes with shared coordinates: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// FindDuplicateNames returns nodes whose name is also used by another node
+// Nodes without a name are ignored. Ordered by name so duplicates are adjacent
+func (r *NodeRepository) FindDuplicateNames() ([]*models.Node, error) {
+	var nodes []*models.Node
+	if err := r.db.Where("name IS NOT NULL AND name <> ''").
+		Where("EXISTS (SELECT 1 FROM nodes AS other WHERE other.name = nodes.name AND other.uuid <> nodes.uuid)").
+		Order("name ASC, created_at ASC, uuid ASC").
+		Find(&nodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find nodes with duplicate names: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// IsNameTaken reports whether an active node other than excludeUUID already uses name
+// Empty names are never considered taken; pass an empty excludeUUID to check all nodes
+func (r *NodeRepository) IsNameTaken(name string, excludeUUID string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+
+	var count int64
+	if err := r.db.Model(&models.Node{}).
+		Where("name = ? AND status = ? AND uuid <> ?", name, models.NodeStatusActive, excludeUUID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check node name: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// UpdateFields writes several columns of a node in one statement
+// Keys are column names; nil values clear nullable columns. Callers validate the values,
+// since map updates bypass the Node BeforeSave hook.
+func (r *NodeRepository) UpdateFields(uuid string, fields map[string]interface{}) error {
+	if uuid == "" {
+		return fmt.Errorf("uuid is required")
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	updates := make(map[string]interface{}, len(fields)+1)
+	for column, value := range fields {
+		updates[column] = value
+	}
+	updates["updated_at"] = time.Now().UTC()
+
+	result := r.db.Model(&models.Node{}).
+		Where("uuid = ?", uuid).
+		Updates(updates)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update node: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("node not found: %s", uuid)
+	}
+
+	return nil
+}
+
+// UpdateName sets or clears (nil) the display name of a node
+func (r *NodeRepository) UpdateName(uuid string, name *string) error {
+	if uuid == "" {
+		return fmt.Errorf("uuid is required")
+	}
+
+	result := r.db.Model(&models.Node{}).
+		Where("uuid = ?", uuid).
+		Updates(map[string]interface{}{
+			"name":       name,
+			"updated_at": time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update node name: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("node not found: %s", uuid)
+	}
+
+	return nil
+}
+
+// FirmwareCount is the number of nodes reporting one firmware version
+// FirmwareVersion is empty for nodes that never reported a version
+type FirmwareCount struct {
+	FirmwareVersion string
+	Count           int64
+}
+
+// CountByFirmwareVersion returns the number of nodes per reported firmware version
+// Nodes without a firmware version are grouped under an empty version
+func (r *NodeRepository) CountByFirmwareVersion() ([]*FirmwareCount, error) {
+	var counts []*FirmwareCount
+	if err := r.db.Model(&models.Node{}).
+		Select("COALESCE(firmware_version, '') AS firmware_version, COUNT(*) AS count").
+		Group("COALESCE(firmware_version, '')").
+		Order("count DESC, firmware_version ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count nodes by firmware version: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountByDay returns the number of nodes registered per UTC day since the given time
+// Only first registrations count (by created_at); days without registrations are omitted and
+// results are ordered oldest first
+func (r *NodeRepository) CountByDay(since time.Time) ([]*DailyCount, error) {
+	var counts []*DailyCount
+	if err := r.db.Model(&models.Node{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since.UTC()).
+		Group("day").
+		Order("day ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count nodes by day: %w", err)
+	}
+
+	return counts, nil
+}
+
 // Delete performs a soft delete by setting status to 'revoked'
 // Use this for audit trail preservation
 func (r *NodeRepository) Delete(uuid string) error {
@@ -263,6 +507,78 @@ func (r *NodeRepository) HardDelete(uuid string) error {
 	return nil
 }
 
+// ForceDeleteResult describes everything removed by ForceDelete
+type ForceDeleteResult struct {
+	Node            *models.Node
+	DeletedTokenIDs []string
+	DeletedEvents   int64
+}
+
+// ForceDelete permanently removes a node together with the records that reference it:
+// registration tokens pre-authorized for its MAC address, its registration events and telemetry.
+// Tokens are deleted rather than un-restricted, since clearing the MAC would turn a
+// single-device token into one any device could use. Runs in a single transaction.
+// WARNING: This cannot be undone
+func (r *NodeRepository) ForceDelete(uuid string) (*ForceDeleteResult, error) {
+	if uuid == "" {
+		return nil, fmt.Errorf("uuid is required")
+	}
+
+	result := &ForceDeleteResult{DeletedTokenIDs: []string{}}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var node models.Node
+		if err := tx.Where("uuid = ?", uuid).First(&node).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("node not found: %s", uuid)
+			}
+			return fmt.Errorf("failed to find node: %w", err)
+		}
+		result.Node = &node
+
+		// Tokens pre-authorized for this node's MAC
+		if err := tx.Unscoped().Model(&models.RegistrationToken{}).
+			Where("pre_authorized_mac_address = ?", node.MacAddress).
+			Pluck("id", &result.DeletedTokenIDs).Error; err != nil {
+			return fmt.Errorf("failed to find tokens for node: %w", err)
+		}
+		if len(result.DeletedTokenIDs) > 0 {
+			if err := tx.Unscoped().Where("id IN ?", result.DeletedTokenIDs).
+				Delete(&models.RegistrationToken{}).Error; err != nil {
+				return fmt.Errorf("failed to delete tokens for node: %w", err)
+			}
+		}
+
+		// Registration history of the node
+		events := tx.Where("node_uuid = ?", node.UUID).Delete(&models.RegistrationEvent{})
+		if events.Error != nil {
+			return fmt.Errorf("failed to delete registration events for node: %w", events.Error)
+		}
+		result.DeletedEvents = events.RowsAffected
+
+		// Latest telemetry reading
+		if err := tx.Where("node_uuid = ?", node.UUID).Delete(&models.NodeTelemetry{}).Error; err != nil {
+			return fmt.Errorf("failed to delete telemetry for node: %w", err)
+		}
+
+		// Admin-assigned metadata
+		if err := tx.Where("node_uuid = ?", node.UUID).Delete(&models.NodeMetadata{}).Error; err != nil {
+			return fmt.Errorf("failed to delete metadata for node: %w", err)
+		}
+
+		if err := tx.Where("uuid = ?", node.UUID).Delete(&models.Node{}).Error; err != nil {
+			return fmt.Errorf("failed to delete node: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // Exists checks if a node with the given UUID exists
 func (r *NodeRepository) Exists(uuid string) (bool, error) {
 	if uuid == "" {
@@ -316,15 +632,26 @@ func (r *NodeRepository) checkDuplicateUUID(uuid string) error {
 
 func (r *NodeRepository) checkDuplicateMAC(macAddress string) error {
 	var count int64
-	if err := r.db.Model(&models.Node{}).Where("mac_address = ?", macAddress).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Node{}).Where("mac_address = ?", normalizeMAC(macAddress)).Count(&count).Error; err != nil {
 		return fmt.Errorf("failed to check MAC address: %w", err)
 	}
 	if count > 0 {
-		return fmt.Errorf("node with MAC address %s already exists", macAddress)
+		return fmt.Errorf("node with this MAC address already exists")
 	}
 	return nil
 }
 
+// normalizeMAC returns the canonical form of a MAC address (uppercase, colon-separated)
+// Invalid addresses are returned unchanged; validating them is the service layer's job,
+// and they cannot match a stored canonical address anyway.
+func normalizeMAC(macAddress string) string {
+	normalized, err := validators.NormalizeMACAddress(macAddress)
+	if err != nil {
+		return macAddress
+	}
+	return normalized
+}
+
 func isValidStatus(status string) bool {
 	return status == models.NodeStatusActive ||
 		status == models.NodeStatusDisabled ||
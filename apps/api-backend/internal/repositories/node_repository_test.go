@@ -30,7 +30,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}
 
 	// Auto-migrate models
-	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}); err != nil {
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}, &models.AdminUser{}, &models.NodeTelemetry{}, &models.IdempotencyKey{}, &models.AdminToken{}, &models.NodeMetadata{}, &models.SchemaMigration{}, &models.RevokedNodeToken{}, &models.Setting{}, &models.AuditLog{}, &models.AdminLoginCode{}, &models.DeniedMAC{}); err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
 
@@ -168,6 +168,47 @@ func TestNodeRepository_FindByMAC(t *testing.T) {
 	}
 }
 
+// TestNodeRepository_MACNormalization tests that MAC case and separators do not affect lookups
+func TestNodeRepository_MACNormalization(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	node := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440000",
+		MacAddress: "aa-bb-cc-dd-ee-0f",
+		JWTSecret:  "secret",
+		Status:     models.NodeStatusActive,
+	}
+	if err := repo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if node.MacAddress != "AA:BB:CC:DD:EE:0F" {
+		t.Errorf("stored MacAddress = %q, want AA:BB:CC:DD:EE:0F", node.MacAddress)
+	}
+
+	for _, mac := range []string{"AA:BB:CC:DD:EE:0F", "aa:bb:cc:dd:ee:0f", "aabbccddee0f"} {
+		found, err := repo.FindByMAC(mac)
+		if err != nil {
+			t.Errorf("FindByMAC(%q) error = %v", mac, err)
+			continue
+		}
+		if found.UUID != node.UUID {
+			t.Errorf("FindByMAC(%q) UUID = %v, want %v", mac, found.UUID, node.UUID)
+		}
+	}
+
+	// A differently-cased MAC is a duplicate, not a new node
+	duplicate := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440001",
+		MacAddress: "aa:bb:cc:dd:ee:0f",
+		JWTSecret:  "secret",
+		Status:     models.NodeStatusActive,
+	}
+	if err := repo.Create(duplicate); err == nil {
+		t.Error("Create() with differently-cased MAC should return duplicate error")
+	}
+}
+
 // TestNodeRepository_Update tests updating a node
 func TestNodeRepository_Update(t *testing.T) {
 	db := setupTestDB(t)
@@ -411,6 +452,353 @@ func TestNodeRepository_CountByStatus(t *testing.T) {
 	}
 }
 
+// TestNodeRepository_FindSharedCoordinates tests finding nodes reporting identical coordinates
+func TestNodeRepository_FindSharedCoordinates(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Latitude: float64Ptr(50.0755), Longitude: float64Ptr(14.4378)},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Latitude: float64Ptr(50.0755), Longitude: float64Ptr(14.4378)},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", Latitude: float64Ptr(49.1951), Longitude: float64Ptr(16.6068)},
+		{UUID: "550e8400-e29b-41d4-a716-446655440004", MacAddress: "AA:BB:CC:DD:EE:04", JWTSecret: "s4"},
+		{UUID: "550e8400-e29b-41d4-a716-446655440005", MacAddress: "AA:BB:CC:DD:EE:05", JWTSecret: "s5"},
+	}
+
+	for _, n := range nodes {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindSharedCoordinates()
+	if err != nil {
+		t.Fatalf("FindSharedCoordinates() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("FindSharedCoordinates() count = %d, want 2", len(found))
+	}
+	for _, n := range found {
+		if n.MacAddress != "AA:BB:CC:DD:EE:01" && n.MacAddress != "AA:BB:CC:DD:EE:02" {
+			t.Errorf("FindSharedCoordinates() returned unexpected node %s", n.MacAddress)
+		}
+	}
+}
+
+// TestNodeRepository_FindDuplicateNames tests finding nodes sharing a name
+func TestNodeRepository_FindDuplicateNames(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Name: stringPtr("Garden")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Name: stringPtr("Garden")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", Name: stringPtr("Garage")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440004", MacAddress: "AA:BB:CC:DD:EE:04", JWTSecret: "s4"},
+	}
+
+	for _, n := range nodes {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindDuplicateNames()
+	if err != nil {
+		t.Fatalf("FindDuplicateNames() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("FindDuplicateNames() count = %d, want 2", len(found))
+	}
+	for _, n := range found {
+		if n.Name == nil || *n.Name != "Garden" {
+			t.Errorf("FindDuplicateNames() returned unexpected node %s", n.MacAddress)
+		}
+	}
+}
+
+// TestNodeRepository_IsNameTaken tests the name check used for optional unique node names
+func TestNodeRepository_IsNameTaken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Name: stringPtr("Garden"), Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Name: stringPtr("Garage"), Status: models.NodeStatusDisabled},
+	}
+	for _, n := range nodes {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		nodeName    string
+		excludeUUID string
+		want        bool
+	}{
+		{"used by active node", "Garden", "", true},
+		{"node's own name", "Garden", "550e8400-e29b-41d4-a716-446655440001", false},
+		{"used only by disabled node", "Garage", "", false},
+		{"unused name", "Kitchen", "", false},
+		{"empty name", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.IsNameTaken(tt.nodeName, tt.excludeUUID)
+			if err != nil {
+				t.Fatalf("IsNameTaken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsNameTaken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNodeRepository_RejectsOutOfRangeCoordinates tests that the Node BeforeSave hook blocks invalid coordinates
+func TestNodeRepository_RejectsOutOfRangeCoordinates(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	// Create with out-of-range latitude
+	invalid := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440001",
+		MacAddress: "AA:BB:CC:DD:EE:01",
+		JWTSecret:  "secret",
+		Latitude:   float64Ptr(91.0),
+		Longitude:  float64Ptr(14.4378),
+	}
+	if err := repo.Create(invalid); err == nil {
+		t.Error("Create() with latitude 91.0 should return error")
+	}
+	if _, err := repo.FindByUUID(invalid.UUID); err == nil {
+		t.Error("node with invalid latitude should not be persisted")
+	}
+
+	// Update with out-of-range longitude
+	node := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440002",
+		MacAddress: "AA:BB:CC:DD:EE:02",
+		JWTSecret:  "secret",
+		Latitude:   float64Ptr(50.0755),
+		Longitude:  float64Ptr(14.4378),
+	}
+	if err := repo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	node.Longitude = float64Ptr(-180.5)
+	if err := repo.Update(node); err == nil {
+		t.Error("Update() with longitude -180.5 should return error")
+	}
+
+	if err := repo.UpdateLocation(node.UUID, 50.0755, 200.0); err == nil {
+		t.Error("UpdateLocation() with longitude 200.0 should return error")
+	}
+
+	found, err := repo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if *found.Longitude != 14.4378 {
+		t.Errorf("Longitude = %v, want 14.4378 (unchanged)", *found.Longitude)
+	}
+}
+
+// TestNodeRepository_Transaction tests that errors returned from the callback roll back all writes
+func TestNodeRepository_Transaction(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	err := repo.Transaction(func(txRepo *NodeRepository) error {
+		if err := txRepo.Create(&models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1"}); err != nil {
+			return err
+		}
+		// Duplicate MAC fails and rolls back the first insert
+		return txRepo.Create(&models.Node{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s2"})
+	})
+	if err == nil {
+		t.Fatal("Transaction() should return error from callback")
+	}
+
+	count, err := repo.Count()
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Count() after rollback = %d, want 0", count)
+	}
+
+	err = repo.Transaction(func(txRepo *NodeRepository) error {
+		return txRepo.Create(&models.Node{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3"})
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v", err)
+	}
+	if _, err := repo.FindByMAC("AA:BB:CC:DD:EE:03"); err != nil {
+		t.Errorf("FindByMAC() after commit error = %v", err)
+	}
+}
+
+// TestNodeRepository_CountByFirmwareVersion tests grouping nodes by firmware version
+func TestNodeRepository_CountByFirmwareVersion(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", FirmwareVersion: stringPtr("1.0.0")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", FirmwareVersion: stringPtr("1.1.0")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", FirmwareVersion: stringPtr("1.1.0")},
+		{UUID: "550e8400-e29b-41d4-a716-446655440004", MacAddress: "AA:BB:CC:DD:EE:04", JWTSecret: "s4"},
+	}
+
+	for _, n := range nodes {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	counts, err := repo.CountByFirmwareVersion()
+	if err != nil {
+		t.Fatalf("CountByFirmwareVersion() error = %v", err)
+	}
+
+	got := map[string]int64{}
+	for _, c := range counts {
+		got[c.FirmwareVersion] = c.Count
+	}
+	want := map[string]int64{"1.1.0": 2, "1.0.0": 1, "": 1}
+	if len(got) != len(want) {
+		t.Fatalf("CountByFirmwareVersion() = %v, want %v", got, want)
+	}
+	for version, count := range want {
+		if got[version] != count {
+			t.Errorf("count for %q = %d, want %d", version, got[version], count)
+		}
+	}
+	if counts[0].FirmwareVersion != "1.1.0" {
+		t.Errorf("first bucket = %q, want most common version 1.1.0", counts[0].FirmwareVersion)
+	}
+}
+
+// TestNodeRepository_UpdateTargetFirmware tests setting and clearing the target firmware version
+func TestNodeRepository_UpdateTargetFirmware(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	node := &models.Node{
+		UUID:            "550e8400-e29b-41d4-a716-446655440000",
+		MacAddress:      "AA:BB:CC:DD:EE:FF",
+		JWTSecret:       "secret",
+		FirmwareVersion: stringPtr("1.0.0"),
+	}
+	if err := repo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := repo.UpdateTargetFirmware(node.UUID, stringPtr("1.1.0")); err != nil {
+		t.Fatalf("UpdateTargetFirmware() error = %v", err)
+	}
+	found, err := repo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if found.TargetFirmwareVersion == nil || *found.TargetFirmwareVersion != "1.1.0" {
+		t.Errorf("TargetFirmwareVersion = %v, want 1.1.0", found.TargetFirmwareVersion)
+	}
+
+	if err := repo.UpdateTargetFirmware(node.UUID, nil); err != nil {
+		t.Fatalf("UpdateTargetFirmware(nil) error = %v", err)
+	}
+	found, err = repo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if found.TargetFirmwareVersion != nil {
+		t.Errorf("TargetFirmwareVersion after clear = %v, want nil", *found.TargetFirmwareVersion)
+	}
+
+	if err := repo.UpdateTargetFirmware("550e8400-e29b-41d4-a716-446655449999", stringPtr("1.1.0")); err == nil {
+		t.Error("UpdateTargetFirmware() for unknown node should return error")
+	}
+}
+
+// TestNodeRepository_ForceDelete tests removing a node with its pre-authorized tokens and events
+func TestNodeRepository_ForceDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+	tokenRepo := NewRegistrationTokenRepository(db)
+	eventRepo := NewRegistrationEventRepository(db)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1"}
+	other := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2"}
+	for _, n := range []*models.Node{node, other} {
+		if err := repo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	tokens := []*models.RegistrationToken{
+		{ID: "token-node", Token: "value_node", ExpiresAt: &expiresAt, PreAuthorizedMacAddress: stringPtr(node.MacAddress)},
+		{ID: "token-other", Token: "value_other", ExpiresAt: &expiresAt, PreAuthorizedMacAddress: stringPtr(other.MacAddress)},
+		{ID: "token-open", Token: "value_open", ExpiresAt: &expiresAt},
+	}
+	for _, token := range tokens {
+		if err := tokenRepo.Create(token); err != nil {
+			t.Fatalf("Create(token) error = %v", err)
+		}
+	}
+
+	for i, n := range []*models.Node{node, node, other} {
+		if err := eventRepo.Create(&models.RegistrationEvent{
+			ID:         "event-" + string(rune('a'+i)),
+			NodeUUID:   n.UUID,
+			MacAddress: n.MacAddress,
+			TokenID:    "token-open",
+			EventType:  models.RegistrationEventRegistered,
+		}); err != nil {
+			t.Fatalf("Create(event) error = %v", err)
+		}
+	}
+
+	result, err := repo.ForceDelete(node.UUID)
+	if err != nil {
+		t.Fatalf("ForceDelete() error = %v", err)
+	}
+	if result.Node.UUID != node.UUID {
+		t.Errorf("ForceDelete() node = %s, want %s", result.Node.UUID, node.UUID)
+	}
+	if len(result.DeletedTokenIDs) != 1 || result.DeletedTokenIDs[0] != "token-node" {
+		t.Errorf("DeletedTokenIDs = %v, want [token-node]", result.DeletedTokenIDs)
+	}
+	if result.DeletedEvents != 2 {
+		t.Errorf("DeletedEvents = %d, want 2", result.DeletedEvents)
+	}
+
+	if _, err := repo.FindByUUID(node.UUID); err == nil {
+		t.Error("node should be deleted")
+	}
+	if _, err := tokenRepo.FindByToken("value_node"); err == nil {
+		t.Error("token pre-authorized for the node should be deleted")
+	}
+	for _, value := range []string{"value_other", "value_open"} {
+		if _, err := tokenRepo.FindByToken(value); err != nil {
+			t.Errorf("unrelated token %s should remain: %v", value, err)
+		}
+	}
+	if events, _ := eventRepo.ListByNode(other.UUID); len(events) != 1 {
+		t.Errorf("events of other node = %d, want 1", len(events))
+	}
+
+	if _, err := repo.ForceDelete(node.UUID); err == nil {
+		t.Error("ForceDelete() of missing node should return error")
+	}
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -419,3 +807,100 @@ func stringPtr(s string) *string {
 func float64Ptr(f float64) *float64 {
 	return &f
 }
+
+// TestNodeRepository_MutationsBumpUpdatedAt tests that every node update moves UpdatedAt,
+// which conditional GETs rely on
+func TestNodeRepository_MutationsBumpUpdatedAt(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := repo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	name := "Garden"
+	version := "2.0.0"
+	mutations := map[string]func() error{
+		"Update": func() error {
+			return repo.Update(&models.Node{UUID: node.UUID, FirmwareVersion: &version})
+		},
+		"UpdateLastSeen":       func() error { return repo.UpdateLastSeen(node.UUID) },
+		"UpdateStatus":         func() error { return repo.UpdateStatus(node.UUID, models.NodeStatusDisabled) },
+		"UpdateLocation":       func() error { return repo.UpdateLocation(node.UUID, 50.0755, 14.4378) },
+		"UpdateTargetFirmware": func() error { return repo.UpdateTargetFirmware(node.UUID, &version) },
+		"UpdateName":           func() error { return repo.UpdateName(node.UUID, &name) },
+	}
+
+	for name, mutate := range mutations {
+		stale := time.Now().UTC().Add(-time.Hour)
+		if err := db.Model(&models.Node{}).Where("uuid = ?", node.UUID).UpdateColumn("updated_at", stale).Error; err != nil {
+			t.Fatalf("failed to backdate node: %v", err)
+		}
+
+		if err := mutate(); err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+
+		found, err := repo.FindByUUID(node.UUID)
+		if err != nil {
+			t.Fatalf("FindByUUID() error = %v", err)
+		}
+		if !found.UpdatedAt.After(stale.Add(time.Minute)) {
+			t.Errorf("%s() left UpdatedAt at %v", name, found.UpdatedAt)
+		}
+	}
+}
+
+// TestNodeRepository_List_StableOrderWithEqualTimestamps tests that nodes created in the same
+// instant (bulk import) page in the same order every time, without gaps or repeats
+func TestNodeRepository_List_StableOrderWithEqualTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeRepository(db)
+
+	// Insert in non-sorted UUID order so the tie-break, not insertion order, decides
+	uuids := []string{
+		"550e8400-e29b-41d4-a716-446655440004",
+		"550e8400-e29b-41d4-a716-446655440001",
+		"550e8400-e29b-41d4-a716-446655440005",
+		"550e8400-e29b-41d4-a716-446655440003",
+		"550e8400-e29b-41d4-a716-446655440002",
+	}
+	for i, uuid := range uuids {
+		node := &models.Node{UUID: uuid, MacAddress: "AA:BB:CC:DD:EE:0" + uuid[len(uuid)-1:], JWTSecret: "s", Status: models.NodeStatusActive}
+		if err := repo.Create(node); err != nil {
+			t.Fatalf("Create(%d) error = %v", i, err)
+		}
+	}
+	sameInstant := time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC)
+	if err := db.Model(&models.Node{}).Where("1 = 1").UpdateColumn("created_at", sameInstant).Error; err != nil {
+		t.Fatalf("failed to set equal timestamps: %v", err)
+	}
+
+	fetchAll := func() []string {
+		var seen []string
+		for offset := 0; offset < len(uuids); offset += 2 {
+			page, _, err := repo.List("", "", 2, offset)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			for _, node := range page {
+				seen = append(seen, node.UUID)
+			}
+		}
+		return seen
+	}
+
+	first, second := fetchAll(), fetchAll()
+	if len(first) != len(uuids) {
+		t.Fatalf("pages returned %d nodes, want %d", len(first), len(uuids))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("paginated order differs between fetches: %v vs %v", first, second)
+		}
+		if i > 0 && first[i-1] >= first[i] {
+			t.Errorf("nodes with equal created_at not ordered by UUID: %v", first)
+		}
+	}
+}
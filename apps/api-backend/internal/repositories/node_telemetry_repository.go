@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NodeTelemetryRepository handles database operations for node telemetry
+type NodeTelemetryRepository struct {
+	db *gorm.DB
+}
+
+// NewNodeTelemetryRepository creates a new node telemetry repository instance
+func NewNodeTelemetryRepository(db *gorm.DB) *NodeTelemetryRepository {
+	return &NodeTelemetryRepository{db: db}
+}
+
+// Upsert stores a node's latest telemetry reading, replacing any previous one
+func (r *NodeTelemetryRepository) Upsert(telemetry *models.NodeTelemetry) error {
+	if telemetry == nil {
+		return fmt.Errorf("telemetry cannot be nil")
+	}
+	if telemetry.NodeUUID == "" {
+		return fmt.Errorf("node UUID is required")
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "node_uuid"}},
+		UpdateAll: true,
+	}).Create(telemetry).Error; err != nil {
+		return fmt.Errorf("failed to store telemetry: %w", err)
+	}
+
+	return nil
+}
+
+// FindByNode retrieves the latest telemetry reading of a node
+func (r *NodeTelemetryRepository) FindByNode(nodeUUID string) (*models.NodeTelemetry, error) {
+	if nodeUUID == "" {
+		return nil, fmt.Errorf("node UUID is required")
+	}
+
+	var telemetry models.NodeTelemetry
+	if err := r.db.Where("node_uuid = ?", nodeUUID).First(&telemetry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("telemetry not found for node: %s", nodeUUID)
+		}
+		return nil, fmt.Errorf("failed to find telemetry: %w", err)
+	}
+
+	return &telemetry, nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestNodeTelemetryRepository_Upsert tests that a new reading replaces the previous one
+func TestNodeTelemetryRepository_Upsert(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewNodeTelemetryRepository(db)
+
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	battery := 80
+	first := &models.NodeTelemetry{
+		NodeUUID:       nodeUUID,
+		SchemaVersion:  1,
+		BatteryPercent: &battery,
+		ReportedAt:     time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC),
+	}
+	if err := repo.Upsert(first); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	rssi := -67
+	second := &models.NodeTelemetry{
+		NodeUUID:      nodeUUID,
+		SchemaVersion: 1,
+		RSSI:          &rssi,
+		ReportedAt:    time.Date(2025, 11, 10, 15, 0, 0, 0, time.UTC),
+	}
+	if err := repo.Upsert(second); err != nil {
+		t.Fatalf("Upsert() second reading error = %v", err)
+	}
+
+	found, err := repo.FindByNode(nodeUUID)
+	if err != nil {
+		t.Fatalf("FindByNode() error = %v", err)
+	}
+	if found.BatteryPercent != nil {
+		t.Errorf("BatteryPercent = %d, want nil (replaced by second reading)", *found.BatteryPercent)
+	}
+	if found.RSSI == nil || *found.RSSI != rssi {
+		t.Errorf("RSSI = %v, want %d", found.RSSI, rssi)
+	}
+	if !found.ReportedAt.Equal(second.ReportedAt) {
+		t.Errorf("ReportedAt = %v, want %v", found.ReportedAt, second.ReportedAt)
+	}
+
+	var count int64
+	db.Model(&models.NodeTelemetry{}).Count(&count)
+	if count != 1 {
+		t.Errorf("telemetry rows = %d, want 1", count)
+	}
+
+	if _, err := repo.FindByNode("550e8400-e29b-41d4-a716-446655440001"); err == nil {
+		t.Error("FindByNode() for node without telemetry should return error")
+	}
+	if err := repo.Upsert(&models.NodeTelemetry{}); err == nil {
+		t.Error("Upsert() without node UUID should return error")
+	}
+}
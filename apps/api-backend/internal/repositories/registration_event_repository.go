@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RegistrationEventRepository handles database operations for registration events
+type RegistrationEventRepository struct {
+	db *gorm.DB
+}
+
+// NewRegistrationEventRepository creates a new registration event repository instance
+func NewRegistrationEventRepository(db *gorm.DB) *RegistrationEventRepository {
+	return &RegistrationEventRepository{db: db}
+}
+
+// MACEventCount is the number of events of one type recorded for a MAC address
+type MACEventCount struct {
+	MacAddress string `json:"mac_address"`
+	NodeUUID   string `json:"node_uuid"`
+	Count      int64  `json:"count"`
+}
+
+// Create inserts a new registration event
+func (r *RegistrationEventRepository) Create(event *models.RegistrationEvent) error {
+	if event == nil {
+		return fmt.Errorf("event cannot be nil")
+	}
+	if event.ID == "" {
+		return fmt.Errorf("event ID is required")
+	}
+
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create registration event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByNode retrieves all events for a node (newest first)
+func (r *RegistrationEventRepository) ListByNode(nodeUUID string) ([]*models.RegistrationEvent, error) {
+	if nodeUUID == "" {
+		return nil, fmt.Errorf("node UUID is required")
+	}
+
+	var events []*models.RegistrationEvent
+	if err := r.db.Where("node_uuid = ?", nodeUUID).
+		Order("created_at DESC, id DESC").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list registration events: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListNodesByToken returns one page of the nodes registered or re-registered with a token, oldest first,
+// and the total number of such nodes. A limit of 0 returns all of them.
+func (r *RegistrationEventRepository) ListNodesByToken(tokenID string, limit, offset int) ([]*models.Node, int64, error) {
+	if tokenID == "" {
+		return nil, 0, fmt.Errorf("token ID is required")
+	}
+
+	nodeUUIDs := r.db.Model(&models.RegistrationEvent{}).Select("node_uuid").Where("token_id = ?", tokenID)
+	query := r.db.Model(&models.Node{}).Where("uuid IN (?)", nodeUUIDs)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count nodes registered with token: %w", err)
+	}
+
+	query = query.Order("created_at ASC, uuid ASC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var nodes []*models.Node
+	if err := query.Find(&nodes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list nodes registered with token: %w", err)
+	}
+
+	return nodes, total, nil
+}
+
+// FindFrequentReRegistrations returns MAC addresses that re-registered at least minCount times
+// Ordered by re-registration count (highest first)
+func (r *RegistrationEventRepository) FindFrequentReRegistrations(minCount int) ([]*MACEventCount, error) {
+	if minCount < 1 {
+		return nil, fmt.Errorf("minimum count must be at least 1")
+	}
+
+	var counts []*MACEventCount
+	if err := r.db.Model(&models.RegistrationEvent{}).
+		Select("mac_address, node_uuid, COUNT(*) AS count").
+		Where("event_type = ?", models.RegistrationEventReRegistered).
+		Group("mac_address, node_uuid").
+		Having("COUNT(*) >= ?", minCount).
+		Order("count DESC, mac_address ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to find frequent re-registrations: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountByDay returns the number of registration events (token uses) per UTC day since the given time
+// Days without events are omitted; results are ordered oldest first
+func (r *RegistrationEventRepository) CountByDay(since time.Time) ([]*DailyCount, error) {
+	var counts []*DailyCount
+	if err := r.db.Model(&models.RegistrationEvent{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since.UTC()).
+		Group("day").
+		Order("day ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count registration events by day: %w", err)
+	}
+
+	return counts, nil
+}
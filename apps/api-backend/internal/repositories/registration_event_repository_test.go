@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestRegistrationEventRepository_CreateAndList tests creating and listing events for a node
+func TestRegistrationEventRepository_CreateAndList(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationEventRepository(db)
+
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	events := []*models.RegistrationEvent{
+		{ID: "event-1", NodeUUID: nodeUUID, MacAddress: "AA:BB:CC:DD:EE:FF", TokenID: "token-1", EventType: models.RegistrationEventRegistered},
+		{ID: "event-2", NodeUUID: nodeUUID, MacAddress: "AA:BB:CC:DD:EE:FF", TokenID: "token-1", EventType: models.RegistrationEventReRegistered},
+		{ID: "event-3", NodeUUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", TokenID: "token-1", EventType: models.RegistrationEventRegistered},
+	}
+
+	for _, e := range events {
+		if err := repo.Create(e); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.ListByNode(nodeUUID)
+	if err != nil {
+		t.Fatalf("ListByNode() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("ListByNode() count = %d, want 2", len(found))
+	}
+
+	if err := repo.Create(&models.RegistrationEvent{}); err == nil {
+		t.Error("Create() without ID should return error")
+	}
+}
+
+// TestRegistrationEventRepository_FindFrequentReRegistrations tests the re-registration threshold
+func TestRegistrationEventRepository_FindFrequentReRegistrations(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationEventRepository(db)
+
+	// Noisy node re-registers 5 times, quiet node twice
+	for i := 0; i < 5; i++ {
+		if err := repo.Create(&models.RegistrationEvent{
+			ID:         fmt.Sprintf("noisy-%d", i),
+			NodeUUID:   "550e8400-e29b-41d4-a716-446655440001",
+			MacAddress: "AA:BB:CC:DD:EE:01",
+			TokenID:    "token-1",
+			EventType:  models.RegistrationEventReRegistered,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.Create(&models.RegistrationEvent{
+			ID:         fmt.Sprintf("quiet-%d", i),
+			NodeUUID:   "550e8400-e29b-41d4-a716-446655440002",
+			MacAddress: "AA:BB:CC:DD:EE:02",
+			TokenID:    "token-1",
+			EventType:  models.RegistrationEventReRegistered,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	counts, err := repo.FindFrequentReRegistrations(5)
+	if err != nil {
+		t.Fatalf("FindFrequentReRegistrations() error = %v", err)
+	}
+	if len(counts) != 1 {
+		t.Fatalf("FindFrequentReRegistrations(5) count = %d, want 1", len(counts))
+	}
+	if counts[0].MacAddress != "AA:BB:CC:DD:EE:01" || counts[0].Count != 5 {
+		t.Errorf("FindFrequentReRegistrations(5) = %s/%d, want AA:BB:CC:DD:EE:01/5", counts[0].MacAddress, counts[0].Count)
+	}
+
+	counts, err = repo.FindFrequentReRegistrations(2)
+	if err != nil {
+		t.Fatalf("FindFrequentReRegistrations() error = %v", err)
+	}
+	if len(counts) != 2 {
+		t.Errorf("FindFrequentReRegistrations(2) count = %d, want 2", len(counts))
+	}
+
+	if _, err := repo.FindFrequentReRegistrations(0); err == nil {
+		t.Error("FindFrequentReRegistrations(0) should return error")
+	}
+}
+
+// TestRegistrationEventRepository_CountByDay tests grouping events by UTC day
+func TestRegistrationEventRepository_CountByDay(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationEventRepository(db)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	timestamps := []time.Time{
+		today.Add(30 * time.Minute),
+		today.AddDate(0, 0, -1).Add(12 * time.Hour),
+		today.AddDate(0, 0, -1).Add(13 * time.Hour),
+		today.AddDate(0, 0, -10),
+	}
+
+	for i, ts := range timestamps {
+		if err := repo.Create(&models.RegistrationEvent{
+			ID:         fmt.Sprintf("event-%d", i),
+			NodeUUID:   "550e8400-e29b-41d4-a716-446655440000",
+			MacAddress: "AA:BB:CC:DD:EE:FF",
+			TokenID:    "token-1",
+			EventType:  models.RegistrationEventReRegistered,
+			CreatedAt:  ts,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	counts, err := repo.CountByDay(today.AddDate(0, 0, -6))
+	if err != nil {
+		t.Fatalf("CountByDay() error = %v", err)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("CountByDay() returned %d days, want 2", len(counts))
+	}
+	if counts[0].Day != today.AddDate(0, 0, -1).Format("2006-01-02") || counts[0].Count != 2 {
+		t.Errorf("counts[0] = %s/%d, want yesterday/2", counts[0].Day, counts[0].Count)
+	}
+	if counts[1].Day != today.Format("2006-01-02") || counts[1].Count != 1 {
+		t.Errorf("counts[1] = %s/%d, want today/1", counts[1].Day, counts[1].Count)
+	}
+}
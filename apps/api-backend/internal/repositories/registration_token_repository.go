@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/boomchecker/api-backend/internal/crypto"
 	"github.com/boomchecker/api-backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -19,8 +22,19 @@ func NewRegistrationTokenRepository(db *gorm.DB) *RegistrationTokenRepository {
 }
 
 // Create inserts a new registration token into the database
-// Returns error if token with same value already exists
+// Returns error if token with same value already exists or ExpiresAt is not in the future
 func (r *RegistrationTokenRepository) Create(token *models.RegistrationToken) error {
+	return r.create(r.db, token)
+}
+
+// CreateAllowPastExpiry inserts a token even if its ExpiresAt is not in the future
+// Use only when an already-expired token is intended, e.g. migrating historical records
+func (r *RegistrationTokenRepository) CreateAllowPastExpiry(token *models.RegistrationToken) error {
+	return r.create(r.db.Set(models.AllowPastExpiryKey, true), token)
+}
+
+// create inserts a token using db, which may carry GORM settings for the model hooks
+func (r *RegistrationTokenRepository) create(db *gorm.DB, token *models.RegistrationToken) error {
 	if token == nil {
 		return fmt.Errorf("token cannot be nil")
 	}
@@ -35,7 +49,7 @@ func (r *RegistrationTokenRepository) Create(token *models.RegistrationToken) er
 	token.CreatedAt = now
 	token.UpdatedAt = now
 
-	if err := r.db.Create(token).Error; err != nil {
+	if err := db.Create(token).Error; err != nil {
 		return fmt.Errorf("failed to create registration token: %w", err)
 	}
 
@@ -52,7 +66,24 @@ func (r *RegistrationTokenRepository) FindByToken(tokenValue string) (*models.Re
 	var token models.RegistrationToken
 	if err := r.db.Where("token = ?", tokenValue).First(&token).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("token not found: %s", tokenValue)
+			return nil, fmt.Errorf("token not found: %s", crypto.TokenFingerprint(tokenValue))
+		}
+		return nil, fmt.Errorf("failed to find token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// FindByID retrieves a registration token by its ID
+func (r *RegistrationTokenRepository) FindByID(id string) (*models.RegistrationToken, error) {
+	if id == "" {
+		return nil, fmt.Errorf("token ID is required")
+	}
+
+	var token models.RegistrationToken
+	if err := r.db.Where("id = ?", id).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("token not found: %s", id)
 		}
 		return nil, fmt.Errorf("failed to find token: %w", err)
 	}
@@ -79,18 +110,116 @@ func (r *RegistrationTokenRepository) IncrementUsedCount(tokenValue string) erro
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("token not found: %s", tokenValue)
+		return fmt.Errorf("token not found: %s", crypto.TokenFingerprint(tokenValue))
+	}
+
+	return nil
+}
+
+// ConsumeUse atomically records one use of a token
+// Expiry and the usage limit are checked in the same UPDATE as the increment, so concurrent
+// registrations cannot push used_count past usage_limit. Zero rows affected means the token
+// is missing, expired or exhausted; the returned error says which.
+func (r *RegistrationTokenRepository) ConsumeUse(tokenValue string) error {
+	if tokenValue == "" {
+		return fmt.Errorf("token value is required")
+	}
+
+	now := time.Now().UTC()
+	result := r.db.Model(&models.RegistrationToken{}).
+		Where("token = ?", tokenValue).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("usage_limit IS NULL OR usage_limit = 0 OR used_count < usage_limit").
+		Updates(map[string]interface{}{
+			"used_count": gorm.Expr("used_count + 1"),
+			"updated_at": now,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to consume token use: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		token, err := r.FindByToken(tokenValue)
+		if err != nil {
+			return err
+		}
+		if token.IsExpired() {
+			return fmt.Errorf("token has expired")
+		}
+		return fmt.Errorf("token has no remaining uses")
+	}
+
+	return nil
+}
+
+// ReleaseUse gives back a use taken by ConsumeUse when the registration it was taken for failed
+func (r *RegistrationTokenRepository) ReleaseUse(tokenValue string) error {
+	if tokenValue == "" {
+		return fmt.Errorf("token value is required")
+	}
+
+	result := r.db.Model(&models.RegistrationToken{}).
+		Where("token = ? AND used_count > 0", tokenValue).
+		Updates(map[string]interface{}{
+			"used_count": gorm.Expr("used_count - 1"),
+			"updated_at": time.Now().UTC(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to release token use: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("token not found: %s", crypto.TokenFingerprint(tokenValue))
 	}
 
 	return nil
 }
 
+// TokenRejectReason says why ValidateToken refused a token
+type TokenRejectReason string
+
+const (
+	TokenRejectNotFound    TokenRejectReason = "not_found"
+	TokenRejectExpired     TokenRejectReason = "expired"
+	TokenRejectExhausted   TokenRejectReason = "exhausted"
+	TokenRejectMACMismatch TokenRejectReason = "mac_mismatch"
+	TokenRejectMACLimit    TokenRejectReason = "mac_limit"
+)
+
+// TokenRejectedError is returned by ValidateToken when the token itself is unusable
+// Database failures are returned as plain errors so they are never mistaken for a rejection.
+type TokenRejectedError struct {
+	Reason TokenRejectReason
+	Err    error
+}
+
+func (e *TokenRejectedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TokenRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// TokenRejectReasonOf returns the rejection reason anywhere in err's chain
+func TokenRejectReasonOf(err error) (TokenRejectReason, bool) {
+	var rejected *TokenRejectedError
+	if errors.As(err, &rejected) {
+		return rejected.Reason, true
+	}
+	return "", false
+}
+
 // ValidateToken checks if a token is valid for use
 // A token is valid if:
 // - It exists
 // - It hasn't expired
 // - It has remaining uses (or is unlimited)
 // - If mac is provided, it matches the authorized MAC (if any)
+// - If mac is provided, it is not a new MAC beyond the token's DistinctMacLimit (if any)
+// A refused token yields a *TokenRejectedError carrying the reason.
 func (r *RegistrationTokenRepository) ValidateToken(tokenValue string, macAddress *string) (*models.RegistrationToken, error) {
 	if tokenValue == "" {
 		return nil, fmt.Errorf("token value is required")
@@ -98,36 +227,67 @@ func (r *RegistrationTokenRepository) ValidateToken(tokenValue string, macAddres
 
 	token, err := r.FindByToken(tokenValue)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "token not found") {
+			return nil, &TokenRejectedError{Reason: TokenRejectNotFound, Err: err}
+		}
 		return nil, err
 	}
 
 	// Check expiration
 	if token.IsExpired() {
-		return nil, fmt.Errorf("token has expired")
+		return nil, &TokenRejectedError{Reason: TokenRejectExpired, Err: fmt.Errorf("token has expired")}
 	}
 
 	// Check remaining uses
 	if !token.HasRemainingUses() {
-		return nil, fmt.Errorf("token has no remaining uses")
+		return nil, &TokenRejectedError{Reason: TokenRejectExhausted, Err: fmt.Errorf("token has no remaining uses")}
 	}
 
 	// Check MAC authorization if MAC is provided
 	if macAddress != nil {
 		if !token.CanBeUsedForMac(*macAddress) {
-			return nil, fmt.Errorf("token cannot be used for MAC address: %s", *macAddress)
+			return nil, &TokenRejectedError{Reason: TokenRejectMACMismatch, Err: fmt.Errorf("token cannot be used for MAC address")}
+		}
+		if token.DistinctMacLimit != nil {
+			if err := r.checkDistinctMacLimit(token, *macAddress); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return token, nil
 }
 
+// checkDistinctMacLimit refuses a MAC new to the token once it registered DistinctMacLimit distinct MACs
+// The count comes from the token's registration events. Two new devices registering at the same
+// moment may both pass the check, so the limit can be exceeded by concurrent registrations.
+func (r *RegistrationTokenRepository) checkDistinctMacLimit(token *models.RegistrationToken, macAddress string) error {
+	var macs []string
+	if err := r.db.Model(&models.RegistrationEvent{}).
+		Where("token_id = ?", token.ID).
+		Distinct("mac_address").
+		Pluck("mac_address", &macs).Error; err != nil {
+		return fmt.Errorf("failed to count token MAC addresses: %w", err)
+	}
+
+	for _, mac := range macs {
+		if mac == macAddress {
+			return nil
+		}
+	}
+	if len(macs) >= *token.DistinctMacLimit {
+		return &TokenRejectedError{Reason: TokenRejectMACLimit, Err: fmt.Errorf("token has reached its limit of %d distinct MAC addresses", *token.DistinctMacLimit)}
+	}
+	return nil
+}
+
 // CleanupExpired removes expired tokens from the database
 // Returns the number of tokens deleted
 // Use this periodically to keep the database clean
 func (r *RegistrationTokenRepository) CleanupExpired() (int64, error) {
 	now := time.Now().UTC()
 
-	result := r.db.Where("expires_at < ?", now).Delete(&models.RegistrationToken{})
+	result := r.db.Unscoped().Where("expires_at < ?", now).Delete(&models.RegistrationToken{})
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to cleanup expired tokens: %w", result.Error)
 	}
@@ -135,11 +295,128 @@ func (r *RegistrationTokenRepository) CleanupExpired() (int64, error) {
 	return result.RowsAffected, nil
 }
 
+// ExpireAll makes every token that has not yet expired expire at the given time, in one statement
+// Tokens without an expiry are included, and so are soft-deleted tokens, so restoring one
+// afterwards cannot bring back a usable token. Returns the number of tokens changed.
+func (r *RegistrationTokenRepository) ExpireAll(at time.Time) (int64, error) {
+	at = at.UTC()
+
+	result := r.db.Unscoped().Model(&models.RegistrationToken{}).
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Updates(map[string]interface{}{"expires_at": at, "updated_at": at})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Expire sets the expiry of a single token to the given time
+// Only expires_at and updated_at are written, so concurrent use counting is not overwritten
+func (r *RegistrationTokenRepository) Expire(id string, at time.Time) error {
+	if id == "" {
+		return fmt.Errorf("token ID is required")
+	}
+	at = at.UTC()
+
+	result := r.db.Model(&models.RegistrationToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"expires_at": at, "updated_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to expire token: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("token not found: %s", id)
+	}
+
+	return nil
+}
+
+// CleanupExpiredBatched removes expired tokens in batches of at most batchSize rows
+// Each batch is its own short transaction, so SQLite's write lock is released between
+// batches and registrations are not blocked behind one long delete. A failed batch
+// leaves earlier batches committed; calling again simply continues where it stopped.
+// Returns the total number of tokens deleted. batchSize <= 0 deletes in one statement.
+func (r *RegistrationTokenRepository) CleanupExpiredBatched(batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return r.CleanupExpired()
+	}
+
+	now := time.Now().UTC()
+	var total int64
+
+	for {
+		deleted, err := r.DeleteExpiredBatch(batchSize, now)
+		if err != nil {
+			return total, err
+		}
+
+		total += int64(len(deleted))
+		if len(deleted) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// DeleteExpiredBatch removes up to limit tokens that expired before the given time
+// and returns them (only ID, CreatedAt and ExpiresAt are loaded), oldest expiry first.
+// The batch is selected and deleted in one transaction; limit <= 0 removes all of them.
+// Callers loop until fewer than limit tokens are returned.
+func (r *RegistrationTokenRepository) DeleteExpiredBatch(limit int, before time.Time) ([]*models.RegistrationToken, error) {
+	var tokens []*models.RegistrationToken
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Select("id", "created_at", "expires_at").
+			Where("expires_at < ?", before.UTC()).
+			Order("expires_at ASC, id ASC")
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+		if err := query.Find(&tokens).Error; err != nil {
+			return fmt.Errorf("failed to find expired tokens: %w", err)
+		}
+		if len(tokens) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(tokens))
+		for i, token := range tokens {
+			ids[i] = token.ID
+		}
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.RegistrationToken{}).Error; err != nil {
+			return fmt.Errorf("failed to cleanup expired tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// FindExpiredIDs returns the IDs of tokens that CleanupExpired would delete
+// Ordered by expiration (oldest first)
+func (r *RegistrationTokenRepository) FindExpiredIDs() ([]string, error) {
+	now := time.Now().UTC()
+
+	var ids []string
+	if err := r.db.Model(&models.RegistrationToken{}).
+		Where("expires_at < ?", now).
+		Order("expires_at ASC, id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find expired tokens: %w", err)
+	}
+
+	return ids, nil
+}
+
 // ListAll retrieves all registration tokens
 // Ordered by creation date (newest first)
 func (r *RegistrationTokenRepository) ListAll() ([]*models.RegistrationToken, error) {
 	var tokens []*models.RegistrationToken
-	if err := r.db.Order("created_at DESC").Find(&tokens).Error; err != nil {
+	if err := r.db.Order("created_at DESC, id DESC").Find(&tokens).Error; err != nil {
 		return nil, fmt.Errorf("failed to list all tokens: %w", err)
 	}
 
@@ -147,14 +424,15 @@ func (r *RegistrationTokenRepository) ListAll() ([]*models.RegistrationToken, er
 }
 
 // ListActive retrieves all non-expired tokens with remaining uses
+// Tokens without an expiry never expire and are always included
 func (r *RegistrationTokenRepository) ListActive() ([]*models.RegistrationToken, error) {
 	now := time.Now().UTC()
 
 	var tokens []*models.RegistrationToken
 	// Find tokens that are not expired and either unlimited or have remaining uses
-	if err := r.db.Where("expires_at > ?", now).
+	if err := r.db.Where("expires_at IS NULL OR expires_at > ?", now).
 		Where("usage_limit IS NULL OR used_count < usage_limit").
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Find(&tokens).Error; err != nil {
 		return nil, fmt.Errorf("failed to list active tokens: %w", err)
 	}
@@ -162,6 +440,125 @@ func (r *RegistrationTokenRepository) ListActive() ([]*models.RegistrationToken,
 	return tokens, nil
 }
 
+// TokenSearchFilter selects registration tokens by description, creator and owner
+// Description and creator match case-insensitively anywhere in the field, the owner
+// exactly; empty terms are ignored.
+type TokenSearchFilter struct {
+	Description string
+	CreatedBy   string
+	OwnerID     string
+	Limit       int
+	Offset      int
+}
+
+// likeEscaper escapes LIKE wildcards so search terms match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns a case-insensitive LIKE pattern matching term as a substring
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(term)) + "%"
+}
+
+// Search returns one page of tokens matching the filter and the total number of matches
+// Ordered by creation date (newest first)
+func (r *RegistrationTokenRepository) Search(filter TokenSearchFilter) ([]*models.RegistrationToken, int64, error) {
+	query := r.db.Model(&models.RegistrationToken{})
+	if filter.Description != "" {
+		query = query.Where(`LOWER(description) LIKE ? ESCAPE '\'`, containsPattern(filter.Description))
+	}
+	if filter.CreatedBy != "" {
+		query = query.Where(`LOWER(created_by) LIKE ? ESCAPE '\'`, containsPattern(filter.CreatedBy))
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count matching tokens: %w", err)
+	}
+
+	query = query.Order("created_at DESC, id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var tokens []*models.RegistrationToken
+	if err := query.Find(&tokens).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search tokens: %w", err)
+	}
+
+	return tokens, total, nil
+}
+
+// Token states accepted by TokenDeleteFilter.Status
+const (
+	TokenStateActive    = "active"
+	TokenStateExpired   = "expired"
+	TokenStateExhausted = "exhausted"
+)
+
+// TokenDeleteFilter selects the tokens removed by DeleteMatching
+// Empty fields are ignored; set fields are combined with AND.
+type TokenDeleteFilter struct {
+	Status        string     // TokenStateActive, TokenStateExpired or TokenStateExhausted
+	CreatedBefore *time.Time // Only tokens created strictly before this time
+	OwnerID       string
+}
+
+// DeleteMatching removes every live token matching the filter and returns how many were removed
+// The matching tokens are selected and deleted in one transaction. With hard the rows are removed
+// permanently, otherwise they are soft-deleted and can be restored like with Delete.
+func (r *RegistrationTokenRepository) DeleteMatching(filter TokenDeleteFilter, hard bool) (int64, error) {
+	now := time.Now().UTC()
+	var deleted int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.RegistrationToken{})
+		switch filter.Status {
+		case "":
+		case TokenStateActive:
+			query = query.Where("(expires_at IS NULL OR expires_at > ?) AND (usage_limit IS NULL OR usage_limit = 0 OR used_count < usage_limit)", now)
+		case TokenStateExpired:
+			query = query.Where("expires_at < ?", now)
+		case TokenStateExhausted:
+			// usage_limit 0 means unlimited on legacy tokens, so those are never exhausted
+			query = query.Where("usage_limit > 0 AND used_count >= usage_limit")
+		default:
+			return fmt.Errorf("unknown token status: %s", filter.Status)
+		}
+		if filter.CreatedBefore != nil {
+			query = query.Where("created_at < ?", filter.CreatedBefore.UTC())
+		}
+		if filter.OwnerID != "" {
+			query = query.Where("owner_id = ?", filter.OwnerID)
+		}
+
+		var ids []string
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find matching tokens: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if hard {
+			tx = tx.Unscoped()
+		}
+		result := tx.Where("id IN ?", ids).Delete(&models.RegistrationToken{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete matching tokens: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
 // FindByMacAddress retrieves all tokens authorized for a specific MAC address
 func (r *RegistrationTokenRepository) FindByMacAddress(macAddress string) ([]*models.RegistrationToken, error) {
 	if macAddress == "" {
@@ -169,8 +566,8 @@ func (r *RegistrationTokenRepository) FindByMacAddress(macAddress string) ([]*mo
 	}
 
 	var tokens []*models.RegistrationToken
-	if err := r.db.Where("authorized_mac = ?", macAddress).
-		Order("created_at DESC").
+	if err := r.db.Where("pre_authorized_mac_address = ?", macAddress).
+		Order("created_at DESC, id DESC").
 		Find(&tokens).Error; err != nil {
 		return nil, fmt.Errorf("failed to find tokens by MAC address: %w", err)
 	}
@@ -178,25 +575,102 @@ func (r *RegistrationTokenRepository) FindByMacAddress(macAddress string) ([]*mo
 	return tokens, nil
 }
 
-// Delete permanently removes a token from the database
-// WARNING: This cannot be undone
+// Delete soft-deletes a token by setting DeletedAt
+// The token stops working immediately but can be restored until PurgeDeleted removes it
 func (r *RegistrationTokenRepository) Delete(tokenValue string) error {
+	return r.delete(r.db, tokenValue)
+}
+
+// HardDelete permanently removes a token from the database
+// WARNING: This cannot be undone
+func (r *RegistrationTokenRepository) HardDelete(tokenValue string) error {
+	return r.delete(r.db.Unscoped(), tokenValue)
+}
+
+// delete removes a live token through db, which decides between soft and hard delete
+func (r *RegistrationTokenRepository) delete(db *gorm.DB, tokenValue string) error {
 	if tokenValue == "" {
 		return fmt.Errorf("token value is required")
 	}
 
-	result := r.db.Where("token = ?", tokenValue).Delete(&models.RegistrationToken{})
+	result := db.Where("token = ? AND deleted_at IS NULL", tokenValue).Delete(&models.RegistrationToken{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete token: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("token not found: %s", tokenValue)
+		return fmt.Errorf("token not found: %s", crypto.TokenFingerprint(tokenValue))
+	}
+
+	return nil
+}
+
+// FindDeleted retrieves a soft-deleted token by its ID or value
+func (r *RegistrationTokenRepository) FindDeleted(tokenRef string) (*models.RegistrationToken, error) {
+	if tokenRef == "" {
+		return nil, fmt.Errorf("token ID or value is required")
+	}
+
+	var token models.RegistrationToken
+	if err := r.db.Unscoped().
+		Where("(id = ? OR token = ?) AND deleted_at IS NOT NULL", tokenRef, tokenRef).
+		First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("deleted token not found: %s", crypto.TokenFingerprint(tokenRef))
+		}
+		return nil, fmt.Errorf("failed to find deleted token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Restore clears DeletedAt on a soft-deleted token, making it usable again
+func (r *RegistrationTokenRepository) Restore(id string) error {
+	if id == "" {
+		return fmt.Errorf("token ID is required")
+	}
+
+	result := r.db.Unscoped().Model(&models.RegistrationToken{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to restore token: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("deleted token not found: %s", id)
 	}
 
 	return nil
 }
 
+// PurgeDeleted permanently removes tokens soft-deleted before the given time
+// Returns the number of tokens removed
+func (r *RegistrationTokenRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC()).
+		Delete(&models.RegistrationToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge deleted tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// SetUsageLimit changes the usage limit of a token, unless it has already been used more often
+// The used count is checked in the same statement, so a registration racing the change cannot
+// leave the token used beyond its new limit. Returns false if the token was used more than limit times.
+func (r *RegistrationTokenRepository) SetUsageLimit(id string, limit int) (bool, error) {
+	result := r.db.Model(&models.RegistrationToken{}).
+		Where("id = ? AND used_count <= ?", id, limit).
+		Updates(map[string]interface{}{"usage_limit": limit, "updated_at": time.Now().UTC()})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update token usage limit: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 // Update updates an existing token
 // Typically used to update metadata or extend expiration
 func (r *RegistrationTokenRepository) Update(token *models.RegistrationToken) error {
@@ -219,7 +693,7 @@ func (r *RegistrationTokenRepository) Update(token *models.RegistrationToken) er
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("token not found: %s", token.Token)
+		return fmt.Errorf("token not found: %s", crypto.TokenFingerprint(token.Token))
 	}
 
 	return nil
@@ -252,12 +726,13 @@ func (r *RegistrationTokenRepository) Count() (int64, error) {
 }
 
 // CountActive returns the number of non-expired tokens with remaining uses
+// Tokens without an expiry never expire and are always counted
 func (r *RegistrationTokenRepository) CountActive() (int64, error) {
 	now := time.Now().UTC()
 
 	var count int64
 	if err := r.db.Model(&models.RegistrationToken{}).
-		Where("expires_at > ?", now).
+		Where("expires_at IS NULL OR expires_at > ?", now).
 		Where("usage_limit IS NULL OR used_count < usage_limit").
 		Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("failed to count active tokens: %w", err)
@@ -267,12 +742,13 @@ func (r *RegistrationTokenRepository) CountActive() (int64, error) {
 }
 
 // CountExpired returns the number of expired tokens
+// Tokens without an expiry never expire and are never counted
 func (r *RegistrationTokenRepository) CountExpired() (int64, error) {
 	now := time.Now().UTC()
 
 	var count int64
 	if err := r.db.Model(&models.RegistrationToken{}).
-		Where("expires_at < ?", now).
+		Where("expires_at IS NOT NULL AND expires_at < ?", now).
 		Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("failed to count expired tokens: %w", err)
 	}
@@ -280,6 +756,65 @@ func (r *RegistrationTokenRepository) CountExpired() (int64, error) {
 	return count, nil
 }
 
+// DailyCount is the number of records created on one UTC day
+type DailyCount struct {
+	Day   string `json:"day"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
+// CountCreatedByDay returns the number of tokens created per UTC day since the given time
+// Days without tokens are omitted; results are ordered oldest first
+func (r *RegistrationTokenRepository) CountCreatedByDay(since time.Time) ([]*DailyCount, error) {
+	var counts []*DailyCount
+	if err := r.db.Model(&models.RegistrationToken{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since.UTC()).
+		Group("day").
+		Order("day ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tokens by day: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Token usage kinds reported by CountByUsageKind
+const (
+	TokenUsageUnlimited = "unlimited"
+	TokenUsageSingleUse = "single_use"
+	TokenUsageMultiUse  = "multi_use"
+)
+
+// TokenUsageCount summarizes the tokens of one usage kind
+type TokenUsageCount struct {
+	Kind      string `json:"kind"`      // unlimited, single_use or multi_use
+	Count     int64  `json:"count"`     // Tokens of this kind
+	Uses      int64  `json:"uses"`      // Sum of their used counts
+	Exhausted int64  `json:"exhausted"` // Tokens with no remaining uses (never set for unlimited tokens)
+}
+
+// CountByUsageKind groups tokens by usage limit: unlimited (NULL or 0), single-use (1) and multi-use (>1)
+// Kinds without tokens are omitted
+func (r *RegistrationTokenRepository) CountByUsageKind() ([]*TokenUsageCount, error) {
+	var counts []*TokenUsageCount
+	if err := r.db.Model(&models.RegistrationToken{}).
+		Select(`CASE
+				WHEN usage_limit IS NULL OR usage_limit = 0 THEN ?
+				WHEN usage_limit = 1 THEN ?
+				ELSE ?
+			END AS kind,
+			COUNT(*) AS count,
+			COALESCE(SUM(used_count), 0) AS uses,
+			SUM(CASE WHEN usage_limit > 0 AND used_count >= usage_limit THEN 1 ELSE 0 END) AS exhausted`,
+			TokenUsageUnlimited, TokenUsageSingleUse, TokenUsageMultiUse).
+		Group("kind").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tokens by usage kind: %w", err)
+	}
+
+	return counts, nil
+}
+
 // Helper functions
 
 func (r *RegistrationTokenRepository) checkDuplicateToken(tokenValue string) error {
@@ -288,7 +823,7 @@ func (r *RegistrationTokenRepository) checkDuplicateToken(tokenValue string) err
 		return err
 	}
 	if exists {
-		return fmt.Errorf("token already exists: %s", tokenValue)
+		return fmt.Errorf("token already exists: %s", crypto.TokenFingerprint(tokenValue))
 	}
 	return nil
 }
@@ -1,10 +1,17 @@
 package repositories
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // TestRegistrationTokenRepository_Create tests creating a new token
@@ -65,6 +72,31 @@ func TestRegistrationTokenRepository_Create_DuplicateToken(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenRepository_Create_PastExpiry tests that a non-future expiry is rejected unless explicitly allowed
+func TestRegistrationTokenRepository_Create_PastExpiry(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	pastExpiry := time.Now().UTC().Add(-time.Hour)
+	err := repo.Create(&models.RegistrationToken{ID: "token-past", Token: "past_token", ExpiresAt: &pastExpiry})
+	if err == nil || !strings.Contains(err.Error(), "must be in the future") {
+		t.Errorf("Create() with past expiry error = %v, want future timestamp error", err)
+	}
+	if _, err := repo.FindByID("token-past"); err == nil {
+		t.Error("Create() with past expiry should not store the token")
+	}
+
+	// Explicitly allowed, e.g. for historical records
+	if err := repo.CreateAllowPastExpiry(&models.RegistrationToken{ID: "token-past", Token: "past_token", ExpiresAt: &pastExpiry}); err != nil {
+		t.Errorf("CreateAllowPastExpiry() error = %v", err)
+	}
+
+	// Tokens without expiry are unaffected
+	if err := repo.Create(&models.RegistrationToken{ID: "token-no-expiry", Token: "no_expiry_token"}); err != nil {
+		t.Errorf("Create() without expiry error = %v", err)
+	}
+}
+
 // TestRegistrationTokenRepository_IncrementUsedCount tests incrementing usage count
 func TestRegistrationTokenRepository_IncrementUsedCount(t *testing.T) {
 	db := setupTestDB(t)
@@ -110,6 +142,112 @@ func TestRegistrationTokenRepository_IncrementUsedCount(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenRepository_ConsumeUse tests the atomic usage-limit check
+func TestRegistrationTokenRepository_ConsumeUse(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	limit := 2
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	pastExpiry := time.Now().UTC().Add(-time.Hour)
+	tokens := []*models.RegistrationToken{
+		{ID: "token-limited", Token: "limited_token", ExpiresAt: &expiresAt, UsageLimit: &limit},
+		{ID: "token-unlimited", Token: "unlimited_token", ExpiresAt: &expiresAt},
+		{ID: "token-expired", Token: "expired_token", ExpiresAt: &pastExpiry},
+	}
+	for _, token := range tokens {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
+			t.Fatalf("Create(%s) error = %v", token.ID, err)
+		}
+	}
+
+	for i := 0; i < limit; i++ {
+		if err := repo.ConsumeUse("limited_token"); err != nil {
+			t.Fatalf("ConsumeUse() use %d error = %v", i+1, err)
+		}
+	}
+	if err := repo.ConsumeUse("limited_token"); err == nil || !strings.Contains(err.Error(), "no remaining uses") {
+		t.Errorf("ConsumeUse() past limit error = %v, want no remaining uses", err)
+	}
+
+	// A released use can be consumed again
+	if err := repo.ReleaseUse("limited_token"); err != nil {
+		t.Fatalf("ReleaseUse() error = %v", err)
+	}
+	if err := repo.ConsumeUse("limited_token"); err != nil {
+		t.Errorf("ConsumeUse() after release error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := repo.ConsumeUse("unlimited_token"); err != nil {
+			t.Fatalf("ConsumeUse(unlimited) error = %v", err)
+		}
+	}
+
+	if err := repo.ConsumeUse("expired_token"); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("ConsumeUse(expired) error = %v, want expired", err)
+	}
+}
+
+// TestRegistrationTokenRepository_ConsumeUse_Concurrent tests that concurrent uses of a
+// single-use token succeed exactly once
+func TestRegistrationTokenRepository_ConsumeUse_Concurrent(t *testing.T) {
+	// A file database lets each goroutine use its own connection, like the server does
+	dsn := filepath.Join(t.TempDir(), "concurrent.db") + "?_busy_timeout=5000&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	repo := NewRegistrationTokenRepository(db)
+
+	limit := 1
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	if err := repo.Create(&models.RegistrationToken{ID: "token-single", Token: "single_use", ExpiresAt: &expiresAt, UsageLimit: &limit}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	const attempts = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := repo.ConsumeUse("single_use"); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else if !strings.Contains(err.Error(), "no remaining uses") {
+				t.Errorf("ConsumeUse() unexpected error = %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("successful uses = %d, want 1", succeeded)
+	}
+
+	found, err := repo.FindByToken("single_use")
+	if err != nil {
+		t.Fatalf("FindByToken() error = %v", err)
+	}
+	if found.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1", found.UsedCount)
+	}
+}
+
 // TestRegistrationTokenRepository_ValidateToken tests token validation
 func TestRegistrationTokenRepository_ValidateToken(t *testing.T) {
 	db := setupTestDB(t)
@@ -123,7 +261,7 @@ func TestRegistrationTokenRepository_ValidateToken(t *testing.T) {
 			Token:     "expired_token",
 			ExpiresAt: &expiredAt,
 		}
-		if err := repo.Create(token); err != nil {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
 			t.Fatalf("Create() error = %v", err)
 		}
 
@@ -131,6 +269,9 @@ func TestRegistrationTokenRepository_ValidateToken(t *testing.T) {
 		if err == nil {
 			t.Error("ValidateToken() expected error for expired token, got nil")
 		}
+		if reason, _ := TokenRejectReasonOf(err); reason != TokenRejectExpired {
+			t.Errorf("ValidateToken() reason = %q, want %q", reason, TokenRejectExpired)
+		}
 	})
 
 	// Test token with no remaining uses
@@ -152,6 +293,9 @@ func TestRegistrationTokenRepository_ValidateToken(t *testing.T) {
 		if err == nil {
 			t.Error("ValidateToken() expected error for exhausted token, got nil")
 		}
+		if reason, _ := TokenRejectReasonOf(err); reason != TokenRejectExhausted {
+			t.Errorf("ValidateToken() reason = %q, want %q", reason, TokenRejectExhausted)
+		}
 	})
 
 	// Test valid token
@@ -235,7 +379,7 @@ func TestRegistrationTokenRepository_CleanupExpired(t *testing.T) {
 	}
 
 	for _, token := range tokens {
-		if err := repo.Create(token); err != nil {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
 			t.Fatalf("Create() error = %v", err)
 		}
 	}
@@ -283,7 +427,7 @@ func TestRegistrationTokenRepository_ListActive(t *testing.T) {
 	}
 
 	for _, token := range tokens {
-		if err := repo.Create(token); err != nil {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
 			t.Fatalf("Create() error = %v", err)
 		}
 	}
@@ -299,6 +443,63 @@ func TestRegistrationTokenRepository_ListActive(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenRepository_Search tests case-insensitive matching, literal wildcards and paging
+func TestRegistrationTokenRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	strPtr := func(s string) *string { return &s }
+	tokens := []*models.RegistrationToken{
+		{ID: "batch-7a", Token: "value_7a", ExpiresAt: &expiresAt, Description: strPtr("Batch 7 sensors"), CreatedBy: strPtr("ops@example.com")},
+		{ID: "batch-7b", Token: "value_7b", ExpiresAt: &expiresAt, Description: strPtr("batch 7 gateways"), CreatedBy: strPtr("alice@example.com"), OwnerID: strPtr("acme")},
+		{ID: "discount", Token: "value_pct", ExpiresAt: &expiresAt, Description: strPtr("100% rollout")},
+		{ID: "underscore", Token: "value_us", ExpiresAt: &expiresAt, Description: strPtr("lab_a")},
+		{ID: "no-description", Token: "value_none", ExpiresAt: &expiresAt},
+	}
+	for _, token := range tokens {
+		if err := repo.Create(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filter  TokenSearchFilter
+		wantIDs int
+	}{
+		{"case-insensitive description", TokenSearchFilter{Description: "BATCH 7"}, 2},
+		{"description and creator", TokenSearchFilter{Description: "batch", CreatedBy: "OPS@"}, 1},
+		{"percent matches literally", TokenSearchFilter{Description: "%"}, 1},
+		{"underscore matches literally", TokenSearchFilter{Description: "h_7"}, 0},
+		{"underscore found literally", TokenSearchFilter{Description: "lab_"}, 1},
+		{"no match", TokenSearchFilter{Description: "batch 8"}, 0},
+		{"owner", TokenSearchFilter{OwnerID: "acme"}, 1},
+		{"owner is exact", TokenSearchFilter{OwnerID: "ACME"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, total, err := repo.Search(tt.filter)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(found) != tt.wantIDs || total != int64(tt.wantIDs) {
+				t.Errorf("Search() = %d tokens (total %d), want %d", len(found), total, tt.wantIDs)
+			}
+		})
+	}
+
+	// Pages report the total across all pages
+	page, total, err := repo.Search(TokenSearchFilter{Description: "batch", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(page) != 1 || total != 2 {
+		t.Errorf("Search() page = %d tokens (total %d), want 1 (total 2)", len(page), total)
+	}
+}
+
 // TestRegistrationTokenRepository_ForeignKey tests foreign key constraint
 func TestRegistrationTokenRepository_ForeignKey(t *testing.T) {
 	db := setupTestDB(t)
@@ -374,7 +575,7 @@ func TestRegistrationTokenRepository_Count(t *testing.T) {
 	}
 
 	for _, token := range tokens {
-		if err := repo.Create(token); err != nil {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
 			t.Fatalf("Create() error = %v", err)
 		}
 	}
@@ -407,6 +608,55 @@ func TestRegistrationTokenRepository_Count(t *testing.T) {
 	}
 }
 
+// TestRegistrationTokenRepository_NeverExpiring tests that tokens without ExpiresAt count as active
+func TestRegistrationTokenRepository_NeverExpiring(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+	maxUses := 1
+
+	tokens := []*models.RegistrationToken{
+		{ID: "never-expires", Token: "never_expires_token"},
+		{ID: "never-expires-exhausted", Token: "never_expires_exhausted_token", UsageLimit: &maxUses, UsedCount: 1},
+		{ID: "expired", Token: "expired_token", ExpiresAt: &expiredAt},
+	}
+	for _, token := range tokens {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	activeTokens, err := repo.ListActive()
+	if err != nil {
+		t.Fatalf("ListActive() error = %v", err)
+	}
+	if len(activeTokens) != 1 || activeTokens[0].ID != "never-expires" {
+		t.Errorf("ListActive() = %d tokens, want only never-expires", len(activeTokens))
+	}
+
+	activeCount, err := repo.CountActive()
+	if err != nil {
+		t.Fatalf("CountActive() error = %v", err)
+	}
+	if activeCount != 1 {
+		t.Errorf("CountActive() = %d, want 1", activeCount)
+	}
+
+	expiredCount, err := repo.CountExpired()
+	if err != nil {
+		t.Fatalf("CountExpired() error = %v", err)
+	}
+	if expiredCount != 1 {
+		t.Errorf("CountExpired() = %d, want 1", expiredCount)
+	}
+
+	// A never-expiring token can also be used for registration
+	if err := repo.ConsumeUse("never_expires_token"); err != nil {
+		t.Errorf("ConsumeUse() error = %v", err)
+	}
+}
+
 // TestRegistrationTokenRepository_Update tests updating a token
 func TestRegistrationTokenRepository_Update(t *testing.T) {
 	db := setupTestDB(t)
@@ -470,3 +720,346 @@ func TestRegistrationTokenRepository_Delete(t *testing.T) {
 		t.Error("FindByToken() after Delete() should return error, got nil")
 	}
 }
+
+// TestRegistrationTokenRepository_ErrorsDoNotLeakToken tests that error messages never contain the raw token value
+func TestRegistrationTokenRepository_ErrorsDoNotLeakToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	existingSecret := "Xq3vK9pL2mN8rT5wY1zB4cD7fG0hJ6kS"
+	missingSecret := "Mw8nQ2rV5tY9uB3xC6zE1gH4jK7lP0sA"
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	token := &models.RegistrationToken{
+		ID:        "token-id-secret",
+		Token:     existingSecret,
+		ExpiresAt: &expiresAt,
+	}
+	if err := repo.Create(token); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	otherMAC := "11:22:33:44:55:66"
+	restricted := &models.RegistrationToken{
+		ID:                      "token-id-restricted",
+		Token:                   "Rr7tY2uI5oP8aS1dF4gH9jK3lZ6xC0vB",
+		ExpiresAt:               &expiresAt,
+		PreAuthorizedMacAddress: stringPtr("AA:BB:CC:DD:EE:FF"),
+	}
+	if err := repo.Create(restricted); err != nil {
+		t.Fatalf("Create(restricted) error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		call   func() error
+	}{
+		{"duplicate create", existingSecret, func() error {
+			return repo.Create(&models.RegistrationToken{ID: "token-id-dup", Token: existingSecret, ExpiresAt: &expiresAt})
+		}},
+		{"find missing", missingSecret, func() error {
+			_, err := repo.FindByToken(missingSecret)
+			return err
+		}},
+		{"validate missing", missingSecret, func() error {
+			_, err := repo.ValidateToken(missingSecret, nil)
+			return err
+		}},
+		{"validate wrong MAC", otherMAC, func() error {
+			_, err := repo.ValidateToken(restricted.Token, &otherMAC)
+			return err
+		}},
+		{"increment missing", missingSecret, func() error {
+			return repo.IncrementUsedCount(missingSecret)
+		}},
+		{"consume missing", missingSecret, func() error {
+			return repo.ConsumeUse(missingSecret)
+		}},
+		{"release missing", missingSecret, func() error {
+			return repo.ReleaseUse(missingSecret)
+		}},
+		{"update missing", missingSecret, func() error {
+			return repo.Update(&models.RegistrationToken{Token: missingSecret})
+		}},
+		{"delete missing", missingSecret, func() error {
+			return repo.Delete(missingSecret)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if strings.Contains(err.Error(), tt.secret) {
+				t.Errorf("error message leaks secret value: %q", err.Error())
+			}
+		})
+	}
+}
+
+// TestRegistrationTokenRepository_AllowReRegistration tests the re-registration flag default and explicit false
+func TestRegistrationTokenRepository_AllowReRegistration(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	deny := false
+
+	defaultToken := &models.RegistrationToken{ID: "token-default", Token: "token_default", ExpiresAt: &expiresAt}
+	deniedToken := &models.RegistrationToken{ID: "token-denied", Token: "token_denied", ExpiresAt: &expiresAt, AllowReRegistration: &deny}
+
+	for _, token := range []*models.RegistrationToken{defaultToken, deniedToken} {
+		if err := repo.Create(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByToken(defaultToken.Token)
+	if err != nil {
+		t.Fatalf("FindByToken(default) error = %v", err)
+	}
+	if !found.AllowsReRegistration() {
+		t.Error("token created without flag should allow re-registration")
+	}
+
+	found, err = repo.FindByToken(deniedToken.Token)
+	if err != nil {
+		t.Fatalf("FindByToken(denied) error = %v", err)
+	}
+	if found.AllowsReRegistration() {
+		t.Error("token created with allow_re_registration=false should not allow re-registration")
+	}
+}
+
+// TestRegistrationTokenRepository_CountCreatedByDay tests grouping token creation by UTC day
+func TestRegistrationTokenRepository_CountCreatedByDay(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// Two tokens today, one three days ago, one outside the window
+	createdAt := map[string]time.Time{
+		"token-1": today.Add(1 * time.Hour),
+		"token-2": today.Add(2 * time.Hour),
+		"token-3": today.AddDate(0, 0, -3).Add(23 * time.Hour),
+		"token-4": today.AddDate(0, 0, -40),
+	}
+	for id, ts := range createdAt {
+		if err := repo.Create(&models.RegistrationToken{ID: id, Token: "value_" + id, ExpiresAt: &expiresAt}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		// BeforeCreate always stamps now, so backdate directly
+		if err := db.Model(&models.RegistrationToken{}).Where("id = ?", id).UpdateColumn("created_at", ts).Error; err != nil {
+			t.Fatalf("failed to backdate token: %v", err)
+		}
+	}
+
+	counts, err := repo.CountCreatedByDay(today.AddDate(0, 0, -29))
+	if err != nil {
+		t.Fatalf("CountCreatedByDay() error = %v", err)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("CountCreatedByDay() returned %d days, want 2", len(counts))
+	}
+
+	wantOldest := today.AddDate(0, 0, -3).Format("2006-01-02")
+	if counts[0].Day != wantOldest || counts[0].Count != 1 {
+		t.Errorf("counts[0] = %s/%d, want %s/1", counts[0].Day, counts[0].Count, wantOldest)
+	}
+	if counts[1].Day != today.Format("2006-01-02") || counts[1].Count != 2 {
+		t.Errorf("counts[1] = %s/%d, want %s/2", counts[1].Day, counts[1].Count, today.Format("2006-01-02"))
+	}
+}
+
+// TestRegistrationTokenRepository_FindByID tests lookup by token ID
+func TestRegistrationTokenRepository_FindByID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	token := &models.RegistrationToken{ID: "token-id-1", Token: "token_value_1", ExpiresAt: &expiresAt}
+	if err := repo.Create(token); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := repo.FindByID(token.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if found.Token != token.Token {
+		t.Errorf("FindByID() token = %v, want %v", found.Token, token.Token)
+	}
+
+	if _, err := repo.FindByID(token.Token); err == nil {
+		t.Error("FindByID() with token value should return error")
+	}
+	if _, err := repo.FindByID(""); err == nil {
+		t.Error("FindByID() with empty ID should return error")
+	}
+}
+
+// TestRegistrationTokenRepository_FindByMacAddress tests lookup of tokens pre-authorized for a MAC
+func TestRegistrationTokenRepository_FindByMacAddress(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	tokens := []*models.RegistrationToken{
+		{ID: "token-1", Token: "value_1", ExpiresAt: &expiresAt, PreAuthorizedMacAddress: stringPtr("AA:BB:CC:DD:EE:FF")},
+		{ID: "token-2", Token: "value_2", ExpiresAt: &expiresAt, PreAuthorizedMacAddress: stringPtr("AA:BB:CC:DD:EE:FF")},
+		{ID: "token-3", Token: "value_3", ExpiresAt: &expiresAt},
+	}
+	for _, token := range tokens {
+		if err := repo.Create(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	found, err := repo.FindByMacAddress("AA:BB:CC:DD:EE:FF")
+	if err != nil {
+		t.Fatalf("FindByMacAddress() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("FindByMacAddress() count = %d, want 2", len(found))
+	}
+}
+
+// TestRegistrationTokenRepository_FindExpiredIDs tests the cleanup preview without deleting anything
+func TestRegistrationTokenRepository_FindExpiredIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiredAt1 := time.Now().UTC().Add(-2 * time.Hour)
+	expiredAt2 := time.Now().UTC().Add(-1 * time.Hour)
+	validAt := time.Now().UTC().Add(24 * time.Hour)
+
+	tokens := []*models.RegistrationToken{
+		{ID: "expired-2", Token: "expired_token_2", ExpiresAt: &expiredAt2},
+		{ID: "expired-1", Token: "expired_token_1", ExpiresAt: &expiredAt1},
+		{ID: "valid-1", Token: "valid_token_1", ExpiresAt: &validAt},
+	}
+	for _, token := range tokens {
+		if err := repo.CreateAllowPastExpiry(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	ids, err := repo.FindExpiredIDs()
+	if err != nil {
+		t.Fatalf("FindExpiredIDs() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "expired-1" || ids[1] != "expired-2" {
+		t.Errorf("FindExpiredIDs() = %v, want [expired-1 expired-2]", ids)
+	}
+
+	// Nothing is deleted by the preview
+	count, err := repo.Count()
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+}
+
+// TestRegistrationTokenRepository_CleanupExpiredBatched tests batched cleanup of a large expired backlog
+func TestRegistrationTokenRepository_CleanupExpiredBatched(t *testing.T) {
+	const expiredCount = 1050
+
+	tests := []struct {
+		name      string
+		batchSize int
+	}{
+		{"uneven batches", 100},
+		{"exact multiple of batch size", 50},
+		{"batch larger than backlog", 5000},
+		{"single statement", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := NewRegistrationTokenRepository(db)
+
+			expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+			validAt := time.Now().UTC().Add(24 * time.Hour)
+
+			for i := 0; i < expiredCount; i++ {
+				token := &models.RegistrationToken{
+					ID:        fmt.Sprintf("expired-%d", i),
+					Token:     fmt.Sprintf("expired_token_%d", i),
+					ExpiresAt: &expiredAt,
+				}
+				if err := repo.CreateAllowPastExpiry(token); err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+			}
+			if err := repo.Create(&models.RegistrationToken{ID: "valid-1", Token: "valid_token_1", ExpiresAt: &validAt}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			deletedCount, err := repo.CleanupExpiredBatched(tt.batchSize)
+			if err != nil {
+				t.Fatalf("CleanupExpiredBatched() error = %v", err)
+			}
+			if deletedCount != expiredCount {
+				t.Errorf("CleanupExpiredBatched() deleted count = %d, want %d", deletedCount, expiredCount)
+			}
+
+			// Verify only the valid token remains
+			remaining, err := repo.ListAll()
+			if err != nil {
+				t.Fatalf("ListAll() error = %v", err)
+			}
+			if len(remaining) != 1 || remaining[0].ID != "valid-1" {
+				t.Errorf("ListAll() = %d tokens, want only valid-1", len(remaining))
+			}
+		})
+	}
+}
+
+// TestRegistrationTokenRepository_Search_StableOrderWithEqualTimestamps tests that tokens created
+// in the same instant page in a consistent order across fetches
+func TestRegistrationTokenRepository_Search_StableOrderWithEqualTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRegistrationTokenRepository(db)
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	ids := []string{"token-c", "token-a", "token-e", "token-b", "token-d"}
+	for _, id := range ids {
+		if err := repo.Create(&models.RegistrationToken{ID: id, Token: "value_" + id, ExpiresAt: &expiresAt, Description: stringPtr("bulk")}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	sameInstant := time.Now().UTC().Truncate(time.Second)
+	if err := db.Model(&models.RegistrationToken{}).Where("1 = 1").UpdateColumn("created_at", sameInstant).Error; err != nil {
+		t.Fatalf("failed to set equal timestamps: %v", err)
+	}
+
+	fetchAll := func() []string {
+		var seen []string
+		for offset := 0; offset < len(ids); offset += 2 {
+			page, _, err := repo.Search(TokenSearchFilter{Description: "bulk", Limit: 2, Offset: offset})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			for _, token := range page {
+				seen = append(seen, token.ID)
+			}
+		}
+		return seen
+	}
+
+	first, second := fetchAll(), fetchAll()
+	want := []string{"token-e", "token-d", "token-c", "token-b", "token-a"}
+	for i := range want {
+		if i >= len(first) || first[i] != want[i] || second[i] != want[i] {
+			t.Fatalf("paginated order = %v then %v, want %v", first, second, want)
+		}
+	}
+}
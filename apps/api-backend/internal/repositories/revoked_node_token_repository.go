@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedNodeTokenRepository handles database operations for the node JWT revocation list
+type RevokedNodeTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRevokedNodeTokenRepository creates a new revoked node token repository instance
+func NewRevokedNodeTokenRepository(db *gorm.DB) *RevokedNodeTokenRepository {
+	return &RevokedNodeTokenRepository{db: db}
+}
+
+// Create adds a token to the revocation list
+// Revoking an already revoked jti is not an error; the original entry is kept.
+func (r *RevokedNodeTokenRepository) Create(token *models.RevokedNodeToken) error {
+	if token == nil {
+		return fmt.Errorf("revoked token cannot be nil")
+	}
+	if token.ID == "" {
+		return fmt.Errorf("token ID is required")
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to revoke node token: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a revocation entry by jti
+func (r *RevokedNodeTokenRepository) FindByID(id string) (*models.RevokedNodeToken, error) {
+	var token models.RevokedNodeToken
+	if err := r.db.Where("id = ?", id).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("revoked token not found")
+		}
+		return nil, fmt.Errorf("failed to find revoked token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// IsRevoked reports whether a jti is on the revocation list
+func (r *RevokedNodeTokenRepository) IsRevoked(id string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.RevokedNodeToken{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpired removes entries whose tokens have expired before the given time
+// Returns the number of entries deleted
+func (r *RevokedNodeTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", before.UTC()).Delete(&models.RevokedNodeToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired revoked tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
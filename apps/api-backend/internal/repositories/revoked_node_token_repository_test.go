@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestRevokedNodeTokenRepository_RevokeAndCleanup tests revocation lookups and removal of expired entries
+func TestRevokedNodeTokenRepository_RevokeAndCleanup(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRevokedNodeTokenRepository(db)
+
+	now := time.Now().UTC()
+	entries := []*models.RevokedNodeToken{
+		{ID: "jti-current", NodeUUID: "node-1", RevokedAt: now, ExpiresAt: now.Add(30 * 24 * time.Hour)},
+		{ID: "jti-expired", NodeUUID: "node-1", RevokedAt: now.Add(-31 * 24 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(entry); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// Revoking again keeps the original entry
+	if err := repo.Create(&models.RevokedNodeToken{ID: "jti-current", NodeUUID: "node-2", ExpiresAt: now}); err != nil {
+		t.Fatalf("Create() duplicate error = %v", err)
+	}
+	found, err := repo.FindByID("jti-current")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if found.NodeUUID != "node-1" {
+		t.Errorf("FindByID() NodeUUID = %q, want node-1", found.NodeUUID)
+	}
+
+	for id, want := range map[string]bool{"jti-current": true, "jti-expired": true, "jti-unknown": false} {
+		revoked, err := repo.IsRevoked(id)
+		if err != nil {
+			t.Fatalf("IsRevoked(%q) error = %v", id, err)
+		}
+		if revoked != want {
+			t.Errorf("IsRevoked(%q) = %v, want %v", id, revoked, want)
+		}
+	}
+
+	deleted, err := repo.DeleteExpired(now)
+	if err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", deleted)
+	}
+	if revoked, _ := repo.IsRevoked("jti-current"); !revoked {
+		t.Error("DeleteExpired() removed an unexpired entry")
+	}
+}
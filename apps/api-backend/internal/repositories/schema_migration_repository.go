@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SchemaMigrationRepository reads the record of applied database migrations
+type SchemaMigrationRepository struct {
+	db *gorm.DB
+}
+
+// NewSchemaMigrationRepository creates a new schema migration repository instance
+func NewSchemaMigrationRepository(db *gorm.DB) *SchemaMigrationRepository {
+	return &SchemaMigrationRepository{db: db}
+}
+
+// ListApplied returns all applied migrations ordered by version (oldest first)
+func (r *SchemaMigrationRepository) ListApplied() ([]models.SchemaMigration, error) {
+	var migrations []models.SchemaMigration
+	if err := r.db.Order("version ASC").Find(&migrations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	return migrations, nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingRepository handles database operations for runtime settings
+type SettingRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingRepository creates a new setting repository instance
+func NewSettingRepository(db *gorm.DB) *SettingRepository {
+	return &SettingRepository{db: db}
+}
+
+// Get retrieves a setting by key
+func (r *SettingRepository) Get(key string) (*models.Setting, error) {
+	var setting models.Setting
+	if err := r.db.Where("key = ?", key).First(&setting).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("setting not found: %s", key)
+		}
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+
+	return &setting, nil
+}
+
+// Set creates or replaces a setting
+func (r *SettingRepository) Set(setting *models.Setting) error {
+	if setting == nil {
+		return fmt.Errorf("setting cannot be nil")
+	}
+	if setting.Key == "" {
+		return fmt.Errorf("setting key is required")
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		return fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	return nil
+}
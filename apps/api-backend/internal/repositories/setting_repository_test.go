@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// TestSettingRepository_SetOverwrites tests that setting a key again replaces its value
+func TestSettingRepository_SetOverwrites(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewSettingRepository(db)
+
+	if _, err := repo.Get("cleanup.paused"); err == nil {
+		t.Error("Get() of unset key should return an error")
+	}
+
+	admin := "admin@example.com"
+	if err := repo.Set(&models.Setting{Key: "cleanup.paused", Value: "true", UpdatedBy: &admin}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := repo.Set(&models.Setting{Key: "cleanup.paused", Value: "false"}); err != nil {
+		t.Fatalf("Set() overwrite error = %v", err)
+	}
+
+	setting, err := repo.Get("cleanup.paused")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if setting.Value != "false" || setting.UpdatedBy != nil {
+		t.Errorf("Get() = %q by %v, want \"false\" by nil", setting.Value, setting.UpdatedBy)
+	}
+	if setting.UpdatedAt.IsZero() {
+		t.Error("Get() UpdatedAt should be set")
+	}
+}
@@ -0,0 +1,660 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/templates"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestAdminAuthService_BootstrapFallback tests that ADMIN_EMAILS only applies while the table is empty
+func TestAdminAuthService_BootstrapFallback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), nil, nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{" Ops@Example.com ", "not-an-email"},
+	})
+
+	assertAuthorized := func(email string, want bool) {
+		t.Helper()
+		got, err := service.IsAuthorizedEmail(email)
+		if err != nil {
+			t.Fatalf("IsAuthorizedEmail(%q) error = %v", email, err)
+		}
+		if got != want {
+			t.Errorf("IsAuthorizedEmail(%q) = %v, want %v", email, got, want)
+		}
+	}
+
+	// Empty table: bootstrap emails are authorized (case-insensitive)
+	assertAuthorized("ops@example.com", true)
+	assertAuthorized("OPS@example.com", true)
+	assertAuthorized("alice@example.com", false)
+	assertAuthorized("", false)
+
+	// First stored admin makes the table authoritative
+	if _, err := service.AddAdmin(&AddAdminRequest{Email: "Alice@Example.com"}); err != nil {
+		t.Fatalf("AddAdmin() error = %v", err)
+	}
+	assertAuthorized("alice@example.com", true)
+	assertAuthorized("ops@example.com", false)
+
+	if _, err := service.AddAdmin(&AddAdminRequest{Email: "alice@example.com"}); err == nil {
+		t.Error("AddAdmin() with existing email should return error")
+	}
+	if _, err := service.AddAdmin(&AddAdminRequest{Email: "alice"}); err == nil {
+		t.Error("AddAdmin() with invalid email should return error")
+	}
+
+	list, err := service.ListAdmins()
+	if err != nil {
+		t.Fatalf("ListAdmins() error = %v", err)
+	}
+	if list.Count != 1 || list.BootstrapActive || len(list.BootstrapEmails) != 1 {
+		t.Errorf("ListAdmins() = %+v, want 1 admin, bootstrap inactive, 1 bootstrap email", list)
+	}
+
+	// Removing the last stored admin re-enables the bootstrap list
+	if err := service.RemoveAdmin("ALICE@example.com"); err != nil {
+		t.Fatalf("RemoveAdmin() error = %v", err)
+	}
+	assertAuthorized("alice@example.com", false)
+	assertAuthorized("ops@example.com", true)
+}
+
+// TestAdminAuthService_AuthenticateToken tests that only issued, unexpired tokens of authorized admins are accepted
+func TestAdminAuthService_AuthenticateToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	secret := strings.Repeat("s", crypto.MinAdminJWTSecretLength)
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       secret,
+	})
+
+	issue := func(email string) string {
+		t.Helper()
+		token, claims, err := crypto.GenerateAdminJWT(email, secret, time.Hour)
+		if err != nil {
+			t.Fatalf("GenerateAdminJWT() error = %v", err)
+		}
+		if err := tokenRepo.Create(&models.AdminToken{
+			ID: claims.ID, Email: email, TokenHash: crypto.HashToken(token), ExpiresAt: claims.ExpiresAt.Time,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return token
+	}
+
+	email, err := service.AuthenticateToken(issue("ops@example.com"))
+	if err != nil || email != "ops@example.com" {
+		t.Fatalf("AuthenticateToken() = %q, %v, want ops@example.com", email, err)
+	}
+
+	// Correctly signed but never issued by this server
+	unissued, _, err := crypto.GenerateAdminJWT("ops@example.com", secret, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateAdminJWT() error = %v", err)
+	}
+	if _, err := service.AuthenticateToken(unissued); err == nil || !strings.HasPrefix(err.Error(), "invalid admin token") {
+		t.Errorf("AuthenticateToken() unissued error = %v, want invalid admin token", err)
+	}
+
+	// Issued to an email that is not on the allowlist
+	if _, err := service.AuthenticateToken(issue("former@example.com")); err == nil || !strings.Contains(err.Error(), "no longer authorized") {
+		t.Errorf("AuthenticateToken() removed admin error = %v, want no longer authorized", err)
+	}
+}
+
+// TestAdminAuthService_ReissueToken tests delivery target selection for the recovery path
+func TestAdminAuthService_ReissueToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com", "ops.backup@example.com", "alice@example.com"},
+		JWTSecret:       strings.Repeat("s", crypto.MinAdminJWTSecretLength),
+		SecondaryEmails: map[string]string{
+			"Ops@Example.com":   "ops.backup@example.com",
+			"alice@example.com": "alice@personal.example.com", // not on the allowlist
+		},
+	})
+
+	// A recent token for an address makes a send to it hit the rate limit before email is
+	// attempted, which shows which address the request resolved to
+	sentRecently := func(email string) {
+		t.Helper()
+		if err := tokenRepo.Create(&models.AdminToken{
+			ID: "jti-" + email, Email: email, TokenHash: crypto.HashToken(email), ExpiresAt: time.Now().Add(time.Hour),
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	sentRecently("ops.backup@example.com")
+
+	tests := []struct {
+		name      string
+		req       *AdminTokenReissueRequest
+		wantError string // "" means silently accepted without sending
+	}{
+		{"secondary by default", &AdminTokenReissueRequest{Email: "ops@example.com"}, "rate limit"},
+		{"explicit secondary", &AdminTokenReissueRequest{Email: "ops@example.com", SendTo: AdminTokenSendToSecondary}, "rate limit"},
+		{"invalid target", &AdminTokenReissueRequest{Email: "ops@example.com", SendTo: "other"}, "validation failed"},
+		{"no secondary configured", &AdminTokenReissueRequest{Email: "ops.backup@example.com"}, ""},
+		{"secondary not authorized", &AdminTokenReissueRequest{Email: "alice@example.com"}, ""},
+		{"admin not authorized", &AdminTokenReissueRequest{Email: "mallory@example.com"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ReissueToken(tt.req)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("ReissueToken() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("ReissueToken() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+
+	// The primary address is rate limited on its own
+	sentRecently("ops@example.com")
+	if err := tokenRepo.Delete("jti-ops.backup@example.com"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	err = service.ReissueToken(&AdminTokenReissueRequest{Email: "ops@example.com", SendTo: AdminTokenSendToPrimary})
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("ReissueToken() to primary error = %v, want rate limit", err)
+	}
+}
+
+// TestAdminAuthService_ResendToken tests that a stored token record reproduces the issued token
+// and the cases in which nothing is resent
+func TestAdminAuthService_ResendToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	secret := strings.Repeat("s", crypto.MinAdminJWTSecretLength)
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	config := &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com", "legacy@example.com"},
+		JWTSecret:       secret,
+	}
+	renderer, err := templates.NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+	emailer := NewMemoryEmailer()
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, emailer, renderer, nil, config)
+
+	// The record as read back from the database re-signs to exactly the issued token
+	issued, _, err := service.issueLoginToken("ops@example.com")
+	if err != nil {
+		t.Fatalf("issueLoginToken() error = %v", err)
+	}
+	stored, err := tokenRepo.FindLatestByEmail("ops@example.com")
+	if err != nil {
+		t.Fatalf("FindLatestByEmail() error = %v", err)
+	}
+	reproduced, err := crypto.ReproduceAdminJWT(stored.ID, stored.Email, secret, stored.RequestedAt, stored.ExpiresAt)
+	if err != nil {
+		t.Fatalf("ReproduceAdminJWT() error = %v", err)
+	}
+	if reproduced != issued {
+		t.Error("ReproduceAdminJWT() did not reproduce the issued token")
+	}
+
+	resendCount := func() int {
+		t.Helper()
+		found, err := tokenRepo.FindLatestByEmail("ops@example.com")
+		if err != nil {
+			t.Fatalf("FindLatestByEmail() error = %v", err)
+		}
+		return found.ResendCount
+	}
+
+	// A resend emails the same token and is counted
+	if err := service.ResendToken(&AdminTokenRequest{Email: "ops@example.com"}); err != nil {
+		t.Fatalf("ResendToken() error = %v", err)
+	}
+	if sent := emailer.Sent(); len(sent) != 1 || !strings.Contains(sent[0].HTMLBody, issued) {
+		t.Fatalf("sent emails = %d, want one carrying the issued token", len(sent))
+	}
+	if got := resendCount(); got != 1 {
+		t.Errorf("resend count = %d, want 1", got)
+	}
+
+	// A failed send does not use up a resend
+	failing := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, failingEmailer{}, renderer, nil, config)
+	if err := failing.ResendToken(&AdminTokenRequest{Email: "ops@example.com"}); err == nil || !strings.Contains(err.Error(), "failed to send login email") {
+		t.Errorf("ResendToken() with failing emailer error = %v, want send failure", err)
+	}
+	if got := resendCount(); got != 1 {
+		t.Errorf("resend count after failed send = %d, want 1", got)
+	}
+
+	// At the cap the request is accepted like any other but nothing is sent,
+	// so the response does not reveal that the email belongs to an admin
+	if err := db.Model(stored).Update("resend_count", AdminTokenMaxResends).Error; err != nil {
+		t.Fatalf("failed to set resend count: %v", err)
+	}
+	if err := service.ResendToken(&AdminTokenRequest{Email: "ops@example.com"}); err != nil {
+		t.Errorf("ResendToken() at the cap error = %v, want nil", err)
+	}
+	if sent := emailer.Sent(); len(sent) != 1 {
+		t.Errorf("sent emails at the cap = %d, want still 1", len(sent))
+	}
+
+	// A token issued without its exact issue time cannot be reproduced and is not resent
+	if err := tokenRepo.Create(&models.AdminToken{
+		ID: "jti-legacy", Email: "legacy@example.com", TokenHash: crypto.HashToken("legacy"), ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		req       *AdminTokenRequest
+		wantError string // "" means silently accepted without sending
+	}{
+		{"not reproducible", &AdminTokenRequest{Email: "legacy@example.com"}, ""},
+		{"admin not authorized", &AdminTokenRequest{Email: "mallory@example.com"}, ""},
+		{"invalid email", &AdminTokenRequest{Email: "not-an-email"}, "validation failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ResendToken(tt.req)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("ResendToken() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("ResendToken() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+
+	// Without a token there is nothing to resend
+	if err := tokenRepo.Delete(stored.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := service.ResendToken(&AdminTokenRequest{Email: "ops@example.com"}); err != nil {
+		t.Errorf("ResendToken() without a token error = %v, want nil", err)
+	}
+
+	// Login codes are never stored in a reproducible form
+	config.LoginMethod = AdminLoginMethodCode
+	codeService := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, config)
+	if err := codeService.ResendToken(&AdminTokenRequest{Email: "ops@example.com"}); err == nil || !strings.Contains(err.Error(), "token login method") {
+		t.Errorf("ResendToken() with code login error = %v, want token login method only", err)
+	}
+}
+
+// TestAdminAuthService_RotateJWTSecret tests that rotation requires confirmation and invalidates every token
+func TestAdminAuthService_RotateJWTSecret(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	secret := strings.Repeat("s", crypto.MinAdminJWTSecretLength)
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       secret,
+	})
+
+	token, claims, err := crypto.GenerateAdminJWT("ops@example.com", secret, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateAdminJWT() error = %v", err)
+	}
+	if err := tokenRepo.Create(&models.AdminToken{
+		ID: claims.ID, Email: "ops@example.com", TokenHash: crypto.HashToken(token), ExpiresAt: claims.ExpiresAt.Time,
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Without confirmation nothing changes
+	if _, err := service.RotateJWTSecret(&AdminSecretRotationRequest{}, "ops@example.com"); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("RotateJWTSecret() without confirm error = %v, want validation error", err)
+	}
+	if _, err := service.AuthenticateToken(token); err != nil {
+		t.Fatalf("AuthenticateToken() before rotation error = %v", err)
+	}
+
+	response, err := service.RotateJWTSecret(&AdminSecretRotationRequest{Confirm: true}, "ops@example.com")
+	if err != nil {
+		t.Fatalf("RotateJWTSecret() error = %v", err)
+	}
+	if response.RevokedTokens != 1 {
+		t.Errorf("RevokedTokens = %d, want 1", response.RevokedTokens)
+	}
+	if response.JWTSecret == secret || crypto.ValidateAdminJWTSecret(response.JWTSecret) != nil {
+		t.Errorf("JWTSecret = %q, want a new valid secret", response.JWTSecret)
+	}
+
+	// The old token is rejected, and its email can request a new one right away
+	if _, err := service.AuthenticateToken(token); err == nil {
+		t.Error("AuthenticateToken() after rotation should fail")
+	}
+	if _, err := tokenRepo.FindLatestByEmail("ops@example.com"); err == nil {
+		t.Error("issued admin tokens should be deleted by rotation")
+	}
+
+	if service.currentJWTSecret() != response.JWTSecret {
+		t.Error("service should sign new tokens with the returned secret")
+	}
+
+	// Rotation is unavailable while admin login is disabled
+	disabled := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, nil)
+	if _, err := disabled.RotateJWTSecret(&AdminSecretRotationRequest{Confirm: true}, ""); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("RotateJWTSecret() with login disabled error = %v, want not configured", err)
+	}
+}
+
+// TestAdminAuthService_InspectToken tests that each failed check is reported separately
+func TestAdminAuthService_InspectToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	secret := strings.Repeat("s", crypto.MinAdminJWTSecretLength)
+	tokenRepo := repositories.NewAdminTokenRepository(db)
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), tokenRepo, nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       secret,
+	})
+
+	issue := func(email, signingSecret string, lifetime time.Duration) string {
+		t.Helper()
+		token, claims, err := crypto.GenerateAdminJWT(email, signingSecret, lifetime)
+		if err != nil {
+			t.Fatalf("GenerateAdminJWT() error = %v", err)
+		}
+		if err := tokenRepo.Create(&models.AdminToken{
+			ID: claims.ID, Email: email, TokenHash: crypto.HashToken(token), ExpiresAt: claims.ExpiresAt.Time,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return token
+	}
+
+	valid, err := service.InspectToken(&AdminTokenInspectRequest{Token: issue("ops@example.com", secret, time.Hour)})
+	if err != nil {
+		t.Fatalf("InspectToken() error = %v", err)
+	}
+	if !valid.Valid || len(valid.Problems) != 0 || valid.Email != "ops@example.com" || valid.JTI == "" {
+		t.Errorf("InspectToken() valid token = %+v", valid)
+	}
+
+	expired, err := service.InspectToken(&AdminTokenInspectRequest{Token: issue("ops@example.com", secret, -time.Minute)})
+	if err != nil {
+		t.Fatalf("InspectToken() error = %v", err)
+	}
+	if expired.Valid || !expired.Expired || !expired.SignatureValid || !expired.IssuedByServer || len(expired.Problems) != 1 {
+		t.Errorf("InspectToken() expired token = %+v, want only the expiry problem", expired)
+	}
+
+	otherSecret := strings.Repeat("o", crypto.MinAdminJWTSecretLength)
+	foreign, err := service.InspectToken(&AdminTokenInspectRequest{Token: issue("former@example.com", otherSecret, time.Hour)})
+	if err != nil {
+		t.Fatalf("InspectToken() error = %v", err)
+	}
+	if foreign.Valid || foreign.SignatureValid || foreign.EmailAuthorized || len(foreign.Problems) != 2 {
+		t.Errorf("InspectToken() foreign token = %+v, want signature and allowlist problems", foreign)
+	}
+
+	if _, err := service.InspectToken(&AdminTokenInspectRequest{Token: "not-a-jwt"}); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Errorf("InspectToken() malformed error = %v, want invalid token", err)
+	}
+}
+
+// TestFormatEmailTime tests that email times use the configured timezone and fall back to UTC
+func TestFormatEmailTime(t *testing.T) {
+	expiresAt := time.Date(2025, 7, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timezone string
+		want     string
+	}{
+		{"default", "", "Tue, 01 Jul 2025 12:30 UTC (+00:00)"},
+		{"IANA zone", "Europe/Prague", "Tue, 01 Jul 2025 14:30 CEST (+02:00)"},
+		{"invalid zone falls back to UTC", "Mars/Olympus_Mons", "Tue, 01 Jul 2025 12:30 UTC (+00:00)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatEmailTime(expiresAt, loadEmailLocation(tt.timezone))
+			if got != tt.want {
+				t.Errorf("formatEmailTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadAdminConsoleURL tests that only absolute http(s) URLs without a fragment are used for sign-in links
+func TestLoadAdminConsoleURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", ""},
+		{"https://console.example.com/login", "https://console.example.com/login"},
+		{"http://localhost:3000", "http://localhost:3000"},
+		{"console.example.com", ""},
+		{"javascript:alert(1)", ""},
+		{"https://console.example.com/#/login", ""},
+	}
+
+	for _, tt := range tests {
+		if got := loadAdminConsoleURL(tt.raw); got != tt.want {
+			t.Errorf("loadAdminConsoleURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+// TestAdminAuthService_VerifyCode tests that login codes are single-use and lock after repeated wrong guesses
+func TestAdminAuthService_VerifyCode(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}, &models.AdminLoginCode{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	codeRepo := repositories.NewAdminLoginCodeRepository(db)
+	config := &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       strings.Repeat("s", crypto.MinAdminJWTSecretLength),
+		LoginMethod:     AdminLoginMethodCode,
+	}
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), repositories.NewAdminTokenRepository(db), codeRepo, nil, nil, nil, config)
+
+	storeCode := func(code string) {
+		t.Helper()
+		if err := codeRepo.Replace(&models.AdminLoginCode{
+			ID: uuid.New().String(), Email: "ops@example.com", CodeHash: crypto.HashToken(code), ExpiresAt: time.Now().Add(AdminLoginCodeTTL),
+		}); err != nil {
+			t.Fatalf("Replace() error = %v", err)
+		}
+	}
+	verify := func(code string) (*AdminCodeVerifyResponse, error) {
+		return service.VerifyCode(&AdminCodeVerifyRequest{Email: "Ops@Example.com", Code: code})
+	}
+
+	if _, err := verify("12ab56"); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("VerifyCode() malformed code error = %v, want validation error", err)
+	}
+	if _, err := verify("123456"); ErrorCodeOf(err) != ErrCodeUnauthorized {
+		t.Errorf("VerifyCode() without a code error = %v, want UNAUTHORIZED", err)
+	}
+
+	// A correct code yields a working admin token once
+	storeCode("042917")
+	response, err := verify("042917")
+	if err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+	if email, err := service.AuthenticateToken(response.Token); err != nil || email != "ops@example.com" {
+		t.Errorf("AuthenticateToken() = %q, %v, want ops@example.com", email, err)
+	}
+	if _, err := verify("042917"); ErrorCodeOf(err) != ErrCodeUnauthorized {
+		t.Errorf("VerifyCode() reused code error = %v, want UNAUTHORIZED", err)
+	}
+
+	// Wrong guesses lock the code, even against the right value
+	storeCode("314159")
+	for i := 0; i < AdminLoginCodeMaxAttempts; i++ {
+		if _, err := verify("000000"); ErrorCodeOf(err) != ErrCodeUnauthorized {
+			t.Fatalf("VerifyCode() wrong code error = %v, want UNAUTHORIZED", err)
+		}
+	}
+	if _, err := verify("314159"); ErrorCodeOf(err) != ErrCodeUnauthorized {
+		t.Errorf("VerifyCode() locked code error = %v, want UNAUTHORIZED", err)
+	}
+
+	// The token method does not accept codes
+	config.LoginMethod = AdminLoginMethodToken
+	tokenOnly := NewAdminAuthService(repositories.NewAdminUserRepository(db), repositories.NewAdminTokenRepository(db), codeRepo, nil, nil, nil, config)
+	if _, err := tokenOnly.VerifyCode(&AdminCodeVerifyRequest{Email: "ops@example.com", Code: "314159"}); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("VerifyCode() with token method error = %v, want not configured", err)
+	}
+}
+
+// TestAdminAuthService_TokenTTL tests that issued tokens and the request rate limit follow TokenTTL
+func TestAdminAuthService_TokenTTL(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}, &models.AdminLoginCode{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	secret := strings.Repeat("s", crypto.MinAdminJWTSecretLength)
+	codeRepo := repositories.NewAdminLoginCodeRepository(db)
+	service := NewAdminAuthService(repositories.NewAdminUserRepository(db), repositories.NewAdminTokenRepository(db), codeRepo, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+		JWTSecret:       secret,
+		LoginMethod:     AdminLoginMethodCode,
+		TokenTTL:        2 * time.Hour,
+	})
+
+	if err := codeRepo.Replace(&models.AdminLoginCode{
+		ID: uuid.New().String(), Email: "ops@example.com", CodeHash: crypto.HashToken("042917"), ExpiresAt: time.Now().Add(AdminLoginCodeTTL),
+	}); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	response, err := service.VerifyCode(&AdminCodeVerifyRequest{Email: "ops@example.com", Code: "042917"})
+	if err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+
+	claims, err := crypto.VerifyAdminJWT(response.Token, secret)
+	if err != nil {
+		t.Fatalf("VerifyAdminJWT() error = %v", err)
+	}
+	if lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time); lifetime != 2*time.Hour {
+		t.Errorf("token lifetime = %v, want 2h", lifetime)
+	}
+	if want := claims.ExpiresAt.UTC().Format(time.RFC3339); response.ExpiresAt != want {
+		t.Errorf("VerifyCode() expires_at = %s, want %s", response.ExpiresAt, want)
+	}
+
+	// The rate-limit window is the token lifetime: the token just issued blocks another for 2 hours
+	err = service.sendLoginToken("ops@example.com", "")
+	if ErrorCodeOf(err) != ErrCodeRateLimited || !strings.Contains(err.Error(), "last 2 hours") {
+		t.Fatalf("sendLoginToken() error = %v, want rate limit over 2 hours", err)
+	}
+	if wait, ok := RetryAfterOf(err); !ok || wait > 2*time.Hour || wait < 2*time.Hour-time.Minute {
+		t.Errorf("RetryAfterOf() = %v, %t, want about 2h", wait, ok)
+	}
+
+	// Without a configured lifetime the 24-hour default applies
+	if got := NewAdminAuthService(nil, nil, nil, nil, nil, nil, &AdminAuthConfig{}).tokenTTL; got != crypto.AdminJWTExpiration {
+		t.Errorf("default tokenTTL = %v, want %v", got, crypto.AdminJWTExpiration)
+	}
+}
+
+// TestGenerateLoginCode tests that codes are zero-padded digits of the configured length
+func TestGenerateLoginCode(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		code, err := generateLoginCode()
+		if err != nil {
+			t.Fatalf("generateLoginCode() error = %v", err)
+		}
+		if len(code) != AdminLoginCodeDigits || strings.Trim(code, "0123456789") != "" {
+			t.Fatalf("generateLoginCode() = %q, want %d digits", code, AdminLoginCodeDigits)
+		}
+	}
+}
+
+// failingEmailer is an Emailer whose sends always fail
+type failingEmailer struct{}
+
+func (failingEmailer) SendHTML(to, subject, htmlBody string) error {
+	return errors.New("smtp unavailable")
+}
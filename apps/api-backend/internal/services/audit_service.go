@@ -0,0 +1,206 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/validators"
+	"github.com/google/uuid"
+)
+
+// Audit log actions
+const (
+	AuditActionAdminSecretRotated = "admin.jwt_secret_rotated"
+	AuditActionNodeTokenRevoked   = "node_token.revoked"
+	AuditActionCleanupPaused      = "cleanup.paused"
+	AuditActionCleanupResumed     = "cleanup.resumed"
+	AuditActionNodeAutoDisabled   = "node.auto_disabled"
+	AuditActionNodeStatusChanged  = "node.status_changed"
+	AuditActionMACDenied          = "mac_denylist.added"
+	AuditActionMACAllowed         = "mac_denylist.removed"
+	AuditActionTokensRevokedAll   = "registration_token.revoked_all"
+	AuditActionTokensBulkDeleted  = "registration_token.bulk_deleted"
+)
+
+// Audit log page size limits
+const (
+	DefaultAuditLogPageSize = 50
+	MaxAuditLogPageSize     = 200
+)
+
+// AuditLogQuery filters and pages the audit log; all filters are optional
+type AuditLogQuery struct {
+	Action     string `form:"action" example:"node_token.revoked"`
+	AdminEmail string `form:"admin_email" example:"admin@example.com"`
+	TargetType string `form:"target_type" example:"node" enums:"admin,node,token,cleanup,mac"`
+	TargetID   string `form:"target_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	From       string `form:"from" example:"2025-11-10T00:00:00Z"`  // Inclusive UTC timestamp (RFC3339 format)
+	To         string `form:"to" example:"2025-11-11T00:00:00Z"`    // Inclusive UTC timestamp (RFC3339 format)
+	Page       int    `form:"page" example:"1"`                     // 1-based, defaults to 1
+	PageSize   int    `form:"page_size" example:"50"`               // Defaults to 50, at most 200
+	Sort       string `form:"sort" example:"desc" enums:"asc,desc"` // By time; defaults to desc (newest first)
+}
+
+// AuditLogResponse is one audit log entry
+type AuditLogResponse struct {
+	ID         string  `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Action     string  `json:"action" example:"node_token.revoked"`
+	AdminEmail *string `json:"admin_email,omitempty" example:"admin@example.com"`
+	TargetType string  `json:"target_type" example:"node"`
+	TargetID   *string `json:"target_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Details    *string `json:"details,omitempty" example:"revoked token d355e2e1-9bb2-4c7c-9e20-38a90c72f136"`
+	CreatedAt  string  `json:"created_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// AuditLogListResponse is one page of audit log entries
+type AuditLogListResponse struct {
+	Entries  []*AuditLogResponse `json:"entries"`
+	Page     int                 `json:"page" example:"1"`
+	PageSize int                 `json:"page_size" example:"50"`
+	// Total is the number of entries matching the filters across all pages
+	Total int64 `json:"total" example:"1"`
+}
+
+// AuditService records security-relevant admin actions and queries them
+type AuditService struct {
+	auditRepo *repositories.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(auditRepo *repositories.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record appends an action to the audit log
+// Failures are logged but never fail the action itself. Safe to call on a nil *AuditService,
+// which records nothing, so services can treat the audit log as optional.
+func (s *AuditService) Record(action, adminEmail, targetType, targetID, details string) {
+	if s == nil {
+		return
+	}
+
+	entry := &models.AuditLog{
+		ID:         uuid.New().String(),
+		Action:     action,
+		TargetType: targetType,
+	}
+	if email := strings.ToLower(strings.TrimSpace(adminEmail)); email != "" {
+		entry.AdminEmail = &email
+	}
+	if targetID != "" {
+		entry.TargetID = &targetID
+	}
+	if details != "" {
+		entry.Details = &details
+	}
+
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("Warning: failed to record audit log entry %s: %v", action, err)
+	}
+}
+
+// Query returns one page of audit log entries matching the filters
+func (s *AuditService) Query(query *AuditLogQuery) (*AuditLogListResponse, error) {
+	filter, page, pageSize, err := buildAuditLogFilter(query)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	entries, total, err := s.auditRepo.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AuditLogListResponse{
+		Entries:  make([]*AuditLogResponse, len(entries)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for i, entry := range entries {
+		response.Entries[i] = &AuditLogResponse{
+			ID:         entry.ID,
+			Action:     entry.Action,
+			AdminEmail: entry.AdminEmail,
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			Details:    entry.Details,
+			CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return response, nil
+}
+
+// buildAuditLogFilter validates the query and converts it to a repository filter
+func buildAuditLogFilter(query *AuditLogQuery) (filter repositories.AuditLogFilter, page, pageSize int, err error) {
+	page = query.Page
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return filter, 0, 0, fmt.Errorf("page must be at least 1")
+	}
+
+	pageSize = query.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultAuditLogPageSize
+	}
+	if pageSize < 1 || pageSize > MaxAuditLogPageSize {
+		return filter, 0, 0, fmt.Errorf("page_size must be between 1 and %d", MaxAuditLogPageSize)
+	}
+
+	switch query.Sort {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, 0, 0, fmt.Errorf("sort must be \"asc\" or \"desc\"")
+	}
+
+	switch query.TargetType {
+	case "", models.AuditTargetAdmin, models.AuditTargetNode, models.AuditTargetToken, models.AuditTargetCleanup, models.AuditTargetMAC:
+		filter.TargetType = query.TargetType
+	default:
+		return filter, 0, 0, fmt.Errorf("target_type must be one of admin, node, token, cleanup, mac")
+	}
+
+	if query.AdminEmail != "" {
+		if filter.AdminEmail, err = validators.NormalizeEmail(query.AdminEmail); err != nil {
+			return filter, 0, 0, err
+		}
+	}
+
+	for _, bound := range []struct {
+		name  string
+		value string
+		into  **time.Time
+	}{
+		{"from", query.From, &filter.From},
+		{"to", query.To, &filter.To},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		parsed, err := validators.ParseUTCTimestamp(bound.value)
+		if err != nil {
+			return filter, 0, 0, fmt.Errorf("%s must be a UTC timestamp (e.g. 2025-11-10T14:30:00Z)", bound.name)
+		}
+		*bound.into = &parsed
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return filter, 0, 0, fmt.Errorf("from must not be after to")
+	}
+
+	filter.Action = strings.TrimSpace(query.Action)
+	filter.TargetID = strings.TrimSpace(query.TargetID)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+	return filter, page, pageSize, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestAuditService_RecordAndQuery tests that recorded actions can be queried and invalid filters are rejected
+func TestAuditService_RecordAndQuery(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AuditLog{}, &models.Setting{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	service := NewAuditService(repositories.NewAuditLogRepository(db))
+
+	// Pausing and resuming cleanup is recorded
+	cleanup := NewCleanupService(nil, nil, nil, repositories.NewSettingRepository(db), service, nil, nil)
+	if _, err := cleanup.Pause("Admin@Example.com"); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if _, err := cleanup.Resume(""); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	page, err := service.Query(&AuditLogQuery{TargetType: models.AuditTargetCleanup, Sort: "asc"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if page.Total != 2 || len(page.Entries) != 2 || page.Page != 1 || page.PageSize != DefaultAuditLogPageSize {
+		t.Fatalf("Query() = %+v, want 2 entries on page 1", page)
+	}
+	if page.Entries[0].Action != AuditActionCleanupPaused || page.Entries[1].Action != AuditActionCleanupResumed {
+		t.Errorf("Query() actions = %s, %s, want paused then resumed", page.Entries[0].Action, page.Entries[1].Action)
+	}
+	if page.Entries[1].AdminEmail != nil {
+		t.Errorf("Query() anonymous entry AdminEmail = %q, want nil", *page.Entries[1].AdminEmail)
+	}
+
+	// Admin emails are matched case-insensitively, like logins
+	byAdmin, err := service.Query(&AuditLogQuery{AdminEmail: "ADMIN@example.com"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if byAdmin.Total != 1 || byAdmin.Entries[0].Action != AuditActionCleanupPaused {
+		t.Errorf("Query() by admin = %+v, want the pause entry only", byAdmin)
+	}
+
+	invalid := []*AuditLogQuery{
+		{Page: -1},
+		{PageSize: MaxAuditLogPageSize + 1},
+		{Sort: "newest"},
+		{TargetType: "user"},
+		{From: "yesterday"},
+		{From: "2025-11-11T00:00:00Z", To: "2025-11-10T00:00:00Z"},
+	}
+	for _, query := range invalid {
+		if _, err := service.Query(query); err == nil || !strings.HasPrefix(err.Error(), "validation") {
+			t.Errorf("Query(%+v) error = %v, want validation error", query, err)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/events"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// CleanupPausedSetting is the settings key holding whether automatic cleanup is paused
+const CleanupPausedSetting = "cleanup.paused"
+
+// MaxCleanupReportTokens bounds how many removed tokens the last-run report lists
+// The deleted count is always exact; only the per-token detail is truncated.
+const MaxCleanupReportTokens = 1000
+
+// CleanupConfig contains configuration for the periodic cleanup job
+type CleanupConfig struct {
+	// Interval between cleanup runs; zero or negative disables the job
+	Interval time.Duration
+	// BatchSize is the maximum number of expired tokens deleted per statement
+	// Zero or negative deletes all expired tokens in one statement.
+	BatchSize int
+	// DeletedTokenRetention is how long admin-deleted tokens are kept for undo before being purged
+	// Zero or negative purges every deleted token on the next run.
+	DeletedTokenRetention time.Duration
+	// InactiveNodeDisableAfter disables active nodes not seen for this long
+	// Zero or negative leaves inactive nodes alone (the default).
+	InactiveNodeDisableAfter time.Duration
+}
+
+// DefaultCleanupConfig returns the default cleanup configuration
+func DefaultCleanupConfig() *CleanupConfig {
+	return &CleanupConfig{
+		Interval:              1 * time.Hour,
+		BatchSize:             500,
+		DeletedTokenRetention: 24 * time.Hour,
+	}
+}
+
+// CleanedTokenResponse identifies a token removed by cleanup
+type CleanedTokenResponse struct {
+	ID        string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt string `json:"created_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	ExpiresAt string `json:"expires_at" example:"2025-11-11T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// CleanupRunResponse reports what one cleanup run removed
+type CleanupRunResponse struct {
+	StartedAt     string                  `json:"started_at" example:"2025-11-10T14:30:00Z"`  // UTC timestamp (RFC3339 format)
+	FinishedAt    string                  `json:"finished_at" example:"2025-11-10T14:30:01Z"` // UTC timestamp (RFC3339 format)
+	DeletedTokens int64                   `json:"deleted_tokens" example:"2"`
+	Tokens        []*CleanedTokenResponse `json:"tokens"`
+	// Truncated is true when more than MaxCleanupReportTokens tokens were removed and Tokens lists only the first ones
+	Truncated bool `json:"truncated" example:"false"`
+	// DeletedRevocations counts node JWT revocation entries removed because their tokens have expired
+	DeletedRevocations int64 `json:"deleted_revocations" example:"0"`
+	// PurgedDeletedTokens counts admin-deleted tokens removed for good after the undo window
+	PurgedDeletedTokens int64 `json:"purged_deleted_tokens" example:"0"`
+	// DisabledNodes counts active nodes disabled because they were not seen within InactiveNodeDisableAfter
+	DisabledNodes int64 `json:"disabled_nodes" example:"0"`
+	// Error is set when the run stopped early; tokens removed before the failure are still listed
+	Error *string `json:"error,omitempty" example:"failed to cleanup expired tokens: database is locked"`
+}
+
+// CleanupStateResponse reports whether automatic cleanup is paused
+type CleanupStateResponse struct {
+	Paused bool `json:"paused" example:"false"`
+	// UpdatedBy is the admin who last paused or resumed cleanup
+	UpdatedBy *string `json:"updated_by,omitempty" example:"admin@example.com"`
+	// UpdatedAt is when cleanup was last paused or resumed; null if it never was
+	UpdatedAt *string `json:"updated_at,omitempty" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// CleanupService periodically removes expired registration tokens and expired
+// node JWT revocation entries, optionally disables nodes that have gone dark,
+// and remembers what the most recent run did
+// Automatic runs can be paused; the flag is stored in the settings table so it survives restarts.
+type CleanupService struct {
+	tokenRepo    *repositories.RegistrationTokenRepository
+	revokedRepo  *repositories.RevokedNodeTokenRepository
+	nodeRepo     *repositories.NodeRepository
+	settingRepo  *repositories.SettingRepository
+	auditService *AuditService
+	broker       *events.Broker
+	config       *CleanupConfig
+
+	mu      sync.RWMutex
+	lastRun *CleanupRunResponse
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewCleanupService creates a new cleanup service
+// revokedRepo may be nil, in which case revocation entries are not cleaned up
+// nodeRepo may be nil, in which case inactive nodes are never disabled
+// settingRepo may be nil, in which case cleanup cannot be paused
+// auditService may be nil, in which case pausing and resuming are only logged
+// broker may be nil if live event streaming is not needed
+// If config is nil, uses DefaultCleanupConfig()
+func NewCleanupService(
+	tokenRepo *repositories.RegistrationTokenRepository,
+	revokedRepo *repositories.RevokedNodeTokenRepository,
+	nodeRepo *repositories.NodeRepository,
+	settingRepo *repositories.SettingRepository,
+	auditService *AuditService,
+	broker *events.Broker,
+	config *CleanupConfig,
+) *CleanupService {
+	if config == nil {
+		config = DefaultCleanupConfig()
+	}
+	return &CleanupService{
+		tokenRepo:    tokenRepo,
+		revokedRepo:  revokedRepo,
+		nodeRepo:     nodeRepo,
+		settingRepo:  settingRepo,
+		auditService: auditService,
+		broker:       broker,
+		config:       config,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start runs cleanup once per interval in a background goroutine until Stop is called
+// Does nothing when the interval is not positive.
+func (s *CleanupService) Start() {
+	if s.config.Interval <= 0 {
+		close(s.done)
+		return
+	}
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runScheduledCleanup()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the background goroutine to exit and waits for a running cleanup to finish
+// Must only be called after Start.
+func (s *CleanupService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// LastRun returns the report of the most recent cleanup run, or nil if none has run yet
+func (s *CleanupService) LastRun() *CleanupRunResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastRun
+}
+
+// Pause stops automatic cleanup runs until Resume is called, including across restarts
+// A run already in progress finishes.
+func (s *CleanupService) Pause(pausedBy string) (*CleanupStateResponse, error) {
+	return s.setPaused(true, pausedBy)
+}
+
+// Resume re-enables automatic cleanup runs from the next interval on
+func (s *CleanupService) Resume(resumedBy string) (*CleanupStateResponse, error) {
+	return s.setPaused(false, resumedBy)
+}
+
+// State returns whether automatic cleanup is paused
+func (s *CleanupService) State() (*CleanupStateResponse, error) {
+	if s.settingRepo == nil {
+		return &CleanupStateResponse{}, nil
+	}
+
+	setting, err := s.settingRepo.Get(CleanupPausedSetting)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return &CleanupStateResponse{}, nil
+		}
+		return nil, err
+	}
+
+	paused, err := strconv.ParseBool(setting.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s setting %q: %w", CleanupPausedSetting, setting.Value, err)
+	}
+	updatedAt := setting.UpdatedAt.UTC().Format(time.RFC3339)
+	return &CleanupStateResponse{
+		Paused:    paused,
+		UpdatedBy: setting.UpdatedBy,
+		UpdatedAt: &updatedAt,
+	}, nil
+}
+
+// setPaused persists the paused flag and logs the change for auditing
+func (s *CleanupService) setPaused(paused bool, changedBy string) (*CleanupStateResponse, error) {
+	if s.settingRepo == nil {
+		return nil, withCode(ErrCodeServiceUnavailable, fmt.Errorf("cleanup pause is not available"))
+	}
+
+	setting := &models.Setting{
+		Key:       CleanupPausedSetting,
+		Value:     strconv.FormatBool(paused),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if changedBy != "" {
+		setting.UpdatedBy = &changedBy
+	}
+	if err := s.settingRepo.Set(setting); err != nil {
+		return nil, err
+	}
+
+	action, auditAction := "resumed", AuditActionCleanupResumed
+	if paused {
+		action, auditAction = "paused", AuditActionCleanupPaused
+	}
+	log.Printf("AUDIT: automatic token cleanup %s by %s", action, auditActor(changedBy))
+	s.auditService.Record(auditAction, changedBy, models.AuditTargetCleanup, "", "")
+
+	return s.State()
+}
+
+// runScheduledCleanup runs cleanup unless it is paused
+// If the paused flag cannot be read the run is skipped, so a pause is never ignored.
+func (s *CleanupService) runScheduledCleanup() {
+	state, err := s.State()
+	if err != nil {
+		log.Printf("Token cleanup skipped: failed to read paused state: %v", err)
+		return
+	}
+	if state.Paused {
+		log.Println("Token cleanup skipped: paused by an admin")
+		return
+	}
+
+	s.runCleanup()
+}
+
+// runCleanup deletes expired tokens in batches, records the run and logs the outcome
+// Errors are logged, not returned; the next run retries from where this one stopped.
+func (s *CleanupService) runCleanup() {
+	started := time.Now().UTC()
+	run := &CleanupRunResponse{
+		StartedAt: started.Format(time.RFC3339),
+		Tokens:    []*CleanedTokenResponse{},
+	}
+
+	for {
+		deleted, err := s.tokenRepo.DeleteExpiredBatch(s.config.BatchSize, started)
+		if err != nil {
+			message := err.Error()
+			run.Error = &message
+			log.Printf("Token cleanup failed after deleting %d tokens: %v", run.DeletedTokens, err)
+			break
+		}
+
+		run.DeletedTokens += int64(len(deleted))
+		for _, token := range deleted {
+			if len(run.Tokens) >= MaxCleanupReportTokens {
+				run.Truncated = true
+				break
+			}
+			run.Tokens = append(run.Tokens, &CleanedTokenResponse{
+				ID:        token.ID,
+				CreatedAt: token.CreatedAt.UTC().Format(time.RFC3339),
+				ExpiresAt: token.ExpiresAt.UTC().Format(time.RFC3339),
+			})
+		}
+
+		if s.config.BatchSize <= 0 || len(deleted) < s.config.BatchSize {
+			break
+		}
+	}
+
+	retention := s.config.DeletedTokenRetention
+	if retention < 0 {
+		retention = 0
+	}
+	purged, err := s.tokenRepo.PurgeDeleted(started.Add(-retention))
+	if err != nil {
+		log.Printf("Deleted token purge failed: %v", err)
+	} else {
+		run.PurgedDeletedTokens = purged
+	}
+
+	if s.revokedRepo != nil {
+		deleted, err := s.revokedRepo.DeleteExpired(started)
+		if err != nil {
+			log.Printf("Revocation list cleanup failed: %v", err)
+		} else {
+			run.DeletedRevocations = deleted
+		}
+	}
+
+	if s.nodeRepo != nil && s.config.InactiveNodeDisableAfter > 0 {
+		disabled, err := s.disableInactiveNodes(started)
+		if err != nil {
+			log.Printf("Inactive node check failed after disabling %d nodes: %v", disabled, err)
+		}
+		run.DisabledNodes = disabled
+	}
+
+	run.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	s.mu.Lock()
+	s.lastRun = run
+	s.mu.Unlock()
+
+	if run.Error == nil && run.DeletedTokens > 0 {
+		log.Printf("Token cleanup deleted %d expired tokens", run.DeletedTokens)
+	}
+	if run.PurgedDeletedTokens > 0 {
+		log.Printf("Token cleanup purged %d deleted tokens past the undo window", run.PurgedDeletedTokens)
+	}
+	if run.DeletedRevocations > 0 {
+		log.Printf("Token cleanup deleted %d expired revocation entries", run.DeletedRevocations)
+	}
+	if run.DisabledNodes > 0 {
+		log.Printf("Cleanup disabled %d inactive nodes", run.DisabledNodes)
+	}
+}
+
+// disableInactiveNodes disables active nodes not seen within InactiveNodeDisableAfter
+// Activity is the latest of creation, last authentication and last registration, so a freshly
+// registered or re-registered node is not disabled before it had a chance to check in. Each
+// transition is recorded in the audit log and published to live subscribers; re-registering
+// or setting the status re-enables the node.
+func (s *CleanupService) disableInactiveNodes(now time.Time) (int64, error) {
+	threshold := s.config.InactiveNodeDisableAfter
+	cutoff := now.Add(-threshold)
+
+	nodes, err := s.nodeRepo.FindInactive(threshold)
+	if err != nil {
+		return 0, err
+	}
+
+	var disabled int64
+	for _, node := range nodes {
+		if !node.IsActive() {
+			continue
+		}
+
+		lastActivity := node.CreatedAt
+		if node.LastSeenAt != nil && node.LastSeenAt.After(lastActivity) {
+			lastActivity = *node.LastSeenAt
+		}
+		if node.LastRegisteredAt != nil && node.LastRegisteredAt.After(lastActivity) {
+			lastActivity = *node.LastRegisteredAt
+		}
+		if lastActivity.After(cutoff) {
+			continue
+		}
+
+		if err := s.nodeRepo.UpdateStatus(node.UUID, models.NodeStatusDisabled); err != nil {
+			return disabled, err
+		}
+		disabled++
+
+		details := fmt.Sprintf("not seen since %s (threshold %s)", lastActivity.UTC().Format(time.RFC3339), threshold)
+		log.Printf("AUDIT: node %s (%s) disabled automatically: %s", node.UUID, node.MacAddress, details)
+		s.auditService.Record(AuditActionNodeAutoDisabled, "", models.AuditTargetNode, node.UUID, details)
+		s.broker.Publish(events.Event{
+			Type:       events.TypeNodeStatusChanged,
+			NodeUUID:   node.UUID,
+			MacAddress: node.MacAddress,
+			Status:     models.NodeStatusDisabled,
+		})
+	}
+
+	return disabled, nil
+}
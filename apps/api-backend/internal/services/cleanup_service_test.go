@@ -0,0 +1,346 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/events"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestCleanupService_RunCleanup tests that a cleanup run removes the whole expired backlog in batches
+func TestCleanupService_RunCleanup(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	expiredAt := time.Now().UTC().Add(-1 * time.Hour)
+	validAt := time.Now().UTC().Add(24 * time.Hour)
+
+	for i := 0; i < 250; i++ {
+		token := &models.RegistrationToken{
+			ID:        fmt.Sprintf("expired-%d", i),
+			Token:     fmt.Sprintf("expired_token_%d", i),
+			ExpiresAt: &expiredAt,
+		}
+		if err := tokenRepo.CreateAllowPastExpiry(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "valid-1", Token: "valid_token_1", ExpiresAt: &validAt}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	service := NewCleanupService(tokenRepo, nil, nil, nil, nil, nil, &CleanupConfig{BatchSize: 40})
+	if service.LastRun() != nil {
+		t.Error("LastRun() before any run should be nil")
+	}
+	service.runCleanup()
+
+	count, err := tokenRepo.Count()
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Count() after cleanup = %d, want 1", count)
+	}
+
+	run := service.LastRun()
+	if run == nil {
+		t.Fatal("LastRun() after cleanup = nil")
+	}
+	if run.DeletedTokens != 250 || len(run.Tokens) != 250 || run.Truncated || run.Error != nil {
+		t.Errorf("LastRun() = %d deleted, %d listed, truncated %v, error %v; want 250, 250, false, nil",
+			run.DeletedTokens, len(run.Tokens), run.Truncated, run.Error)
+	}
+	seen := make(map[string]bool, len(run.Tokens))
+	for _, token := range run.Tokens {
+		if token.CreatedAt == "" || token.ExpiresAt == "" {
+			t.Errorf("LastRun() token %s missing dates", token.ID)
+		}
+		seen[token.ID] = true
+	}
+	if len(seen) != 250 || seen["valid-1"] {
+		t.Errorf("LastRun() listed %d distinct tokens (valid-1 listed: %v), want the 250 expired ones", len(seen), seen["valid-1"])
+	}
+
+	// A run with nothing to delete replaces the report
+	service.runCleanup()
+	if run := service.LastRun(); run.DeletedTokens != 0 || len(run.Tokens) != 0 {
+		t.Errorf("LastRun() after empty run = %d deleted, %d listed, want 0", run.DeletedTokens, len(run.Tokens))
+	}
+}
+
+// TestCleanupService_RemovesExpiredRevocations tests that revocation entries are dropped once their tokens have expired
+func TestCleanupService_RemovesExpiredRevocations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}, &models.RevokedNodeToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	revokedRepo := repositories.NewRevokedNodeTokenRepository(db)
+	now := time.Now().UTC()
+	for id, expiresAt := range map[string]time.Time{"jti-expired": now.Add(-time.Hour), "jti-current": now.Add(time.Hour)} {
+		if err := revokedRepo.Create(&models.RevokedNodeToken{ID: id, NodeUUID: "node-1", ExpiresAt: expiresAt}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	service := NewCleanupService(repositories.NewRegistrationTokenRepository(db), revokedRepo, nil, nil, nil, nil, nil)
+	service.runCleanup()
+
+	if run := service.LastRun(); run.DeletedRevocations != 1 {
+		t.Errorf("LastRun() DeletedRevocations = %d, want 1", run.DeletedRevocations)
+	}
+	if revoked, _ := revokedRepo.IsRevoked("jti-current"); !revoked {
+		t.Error("cleanup removed an unexpired revocation entry")
+	}
+}
+
+// TestCleanupService_PurgesDeletedTokens tests that deleted tokens are purged only after the undo window
+func TestCleanupService_PurgesDeletedTokens(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	for _, id := range []string{"old", "recent"} {
+		if err := tokenRepo.Create(&models.RegistrationToken{ID: id, Token: id + "_token"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := tokenRepo.Delete(id + "_token"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+	}
+	if err := db.Unscoped().Model(&models.RegistrationToken{}).Where("id = ?", "old").
+		Update("deleted_at", time.Now().UTC().Add(-48*time.Hour)).Error; err != nil {
+		t.Fatalf("failed to backdate deletion: %v", err)
+	}
+
+	service := NewCleanupService(tokenRepo, nil, nil, nil, nil, nil, &CleanupConfig{DeletedTokenRetention: 24 * time.Hour})
+	service.runCleanup()
+
+	if run := service.LastRun(); run.PurgedDeletedTokens != 1 {
+		t.Errorf("LastRun() PurgedDeletedTokens = %d, want 1", run.PurgedDeletedTokens)
+	}
+	if _, err := tokenRepo.FindDeleted("old"); err == nil {
+		t.Error("token deleted before the undo window was not purged")
+	}
+	if _, err := tokenRepo.FindDeleted("recent"); err != nil {
+		t.Errorf("token deleted within the undo window was purged: %v", err)
+	}
+}
+
+// TestCleanupService_DisablesInactiveNodes tests that only active nodes past the threshold are disabled and audited
+func TestCleanupService_DisablesInactiveNodes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}, &models.Node{}, &models.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	now := time.Now().UTC()
+	stale := now.Add(-72 * time.Hour)
+	recent := now.Add(-time.Hour)
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive, LastSeenAt: &stale},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Status: models.NodeStatusActive, LastSeenAt: &recent},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", Status: models.NodeStatusRevoked, LastSeenAt: &stale},
+		// Registered recently but never authenticated
+		{UUID: "550e8400-e29b-41d4-a716-446655440004", MacAddress: "AA:BB:CC:DD:EE:04", JWTSecret: "s4", Status: models.NodeStatusActive, LastRegisteredAt: &recent},
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	backdateNodesCreatedAt(t, db, now.Add(-96*time.Hour))
+
+	auditService := NewAuditService(repositories.NewAuditLogRepository(db))
+	broker := events.NewBroker()
+	sub := broker.Subscribe(4)
+	defer sub.Unsubscribe()
+	service := NewCleanupService(repositories.NewRegistrationTokenRepository(db), nil, nodeRepo, nil, auditService, broker,
+		&CleanupConfig{InactiveNodeDisableAfter: 24 * time.Hour})
+	service.runCleanup()
+
+	if run := service.LastRun(); run.DisabledNodes != 1 {
+		t.Errorf("LastRun() DisabledNodes = %d, want 1", run.DisabledNodes)
+	}
+
+	wantStatus := []string{models.NodeStatusDisabled, models.NodeStatusActive, models.NodeStatusRevoked, models.NodeStatusActive}
+	for i, n := range nodes {
+		found, err := nodeRepo.FindByUUID(n.UUID)
+		if err != nil {
+			t.Fatalf("FindByUUID() error = %v", err)
+		}
+		if found.Status != wantStatus[i] {
+			t.Errorf("node %d status = %s, want %s", i+1, found.Status, wantStatus[i])
+		}
+	}
+
+	logs, err := auditService.Query(&AuditLogQuery{Action: AuditActionNodeAutoDisabled})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if logs.Total != 1 || *logs.Entries[0].TargetID != nodes[0].UUID {
+		t.Errorf("audit log = %+v, want one entry for %s", logs.Entries, nodes[0].UUID)
+	}
+
+	select {
+	case event := <-sub.Events():
+		if event.Type != events.TypeNodeStatusChanged || event.NodeUUID != nodes[0].UUID || event.Status != models.NodeStatusDisabled {
+			t.Errorf("event = %+v, want %s for %s with status disabled", event, events.TypeNodeStatusChanged, nodes[0].UUID)
+		}
+	default:
+		t.Error("no event published for the automatic disable")
+	}
+}
+
+// TestCleanupService_ReRegisteredNodeStaysActive tests that a node re-registering after an automatic
+// disable is not disabled again by the next run while its last authentication is still old
+func TestCleanupService_ReRegisteredNodeStaysActive(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}, &models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	now := time.Now().UTC()
+	stale := now.Add(-72 * time.Hour)
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive, LastSeenAt: &stale, LastRegisteredAt: &stale}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	backdateNodesCreatedAt(t, db, now.Add(-96*time.Hour))
+
+	service := NewCleanupService(repositories.NewRegistrationTokenRepository(db), nil, nodeRepo, nil, nil, nil,
+		&CleanupConfig{InactiveNodeDisableAfter: 24 * time.Hour})
+	service.runCleanup()
+
+	found, err := nodeRepo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if found.Status != models.NodeStatusDisabled {
+		t.Fatalf("status after first run = %s, want disabled", found.Status)
+	}
+
+	// Re-registration reactivates the node and sets last_registered_at; last_seen_at keeps its old value
+	registeredAt := time.Now().UTC()
+	found.Status = models.NodeStatusActive
+	found.LastRegisteredAt = &registeredAt
+	if err := nodeRepo.Update(found); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	service.runCleanup()
+
+	if run := service.LastRun(); run.DisabledNodes != 0 {
+		t.Errorf("LastRun() DisabledNodes = %d, want 0", run.DisabledNodes)
+	}
+	found, err = nodeRepo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if found.Status != models.NodeStatusActive {
+		t.Errorf("status after re-registration and second run = %s, want active", found.Status)
+	}
+}
+
+// backdateNodesCreatedAt moves created_at of all nodes into the past
+// The create hook always stamps the current time, so fixtures are backdated afterwards.
+func backdateNodesCreatedAt(t *testing.T, db *gorm.DB, createdAt time.Time) {
+	t.Helper()
+
+	if err := db.Model(&models.Node{}).Where("1 = 1").UpdateColumn("created_at", createdAt).Error; err != nil {
+		t.Fatalf("failed to backdate nodes: %v", err)
+	}
+}
+
+// TestCleanupService_PauseSurvivesRestart tests that a paused cleanup skips scheduled runs, also in a new service instance
+func TestCleanupService_PauseSurvivesRestart(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}, &models.Setting{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	settingRepo := repositories.NewSettingRepository(db)
+	expiredAt := time.Now().UTC().Add(-time.Hour)
+	if err := tokenRepo.CreateAllowPastExpiry(&models.RegistrationToken{ID: "expired-1", Token: "expired_token_1", ExpiresAt: &expiredAt}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	service := NewCleanupService(tokenRepo, nil, nil, settingRepo, nil, nil, nil)
+	state, err := service.Pause("admin@example.com")
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if !state.Paused || state.UpdatedBy == nil || *state.UpdatedBy != "admin@example.com" {
+		t.Errorf("Pause() = %+v, want paused by admin@example.com", state)
+	}
+
+	// A restarted service reads the persisted flag
+	restarted := NewCleanupService(tokenRepo, nil, nil, settingRepo, nil, nil, nil)
+	restarted.runScheduledCleanup()
+	if restarted.LastRun() != nil {
+		t.Error("scheduled cleanup ran while paused")
+	}
+	if count, _ := tokenRepo.Count(); count != 1 {
+		t.Errorf("Count() while paused = %d, want 1", count)
+	}
+
+	if _, err := restarted.Resume(""); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	restarted.runScheduledCleanup()
+	if run := restarted.LastRun(); run == nil || run.DeletedTokens != 1 {
+		t.Errorf("LastRun() after resume = %+v, want 1 token deleted", run)
+	}
+}
+
+// TestCleanupService_StartStopDisabled tests that a disabled job starts and stops without blocking
+func TestCleanupService_StartStopDisabled(t *testing.T) {
+	service := NewCleanupService(nil, nil, nil, nil, nil, nil, &CleanupConfig{Interval: 0})
+	service.Start()
+	service.Stop()
+}
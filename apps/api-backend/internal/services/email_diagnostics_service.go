@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/templates"
+	"github.com/boomchecker/api-backend/internal/validators"
+)
+
+// EmailTestInterval is the minimum time between two test emails
+// Each test contacts the mail server, so the endpoint must not become a way to spam it.
+const EmailTestInterval = time.Minute
+
+// EmailTestRequest selects the recipient of a test email
+type EmailTestRequest struct {
+	// To must be an authorized admin email; defaults to the logged-in admin
+	To string `json:"to,omitempty" binding:"omitempty,email,max=254" example:"admin@example.com"`
+}
+
+// EmailTestResponse confirms that the mail server accepted the test email
+type EmailTestResponse struct {
+	Recipient string `json:"recipient" example:"admin@example.com"`
+	SentAt    string `json:"sent_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	Message   string `json:"message" example:"Test email accepted by the mail server"`
+}
+
+// EmailDiagnosticsService sends test emails so admins can verify the email setup
+// before relying on it for login
+type EmailDiagnosticsService struct {
+	emailService *EmailService
+	renderer     *templates.TemplateRenderer
+	authService  *AdminAuthService
+
+	mu       sync.Mutex
+	lastSent time.Time
+	now      func() time.Time
+}
+
+// NewEmailDiagnosticsService creates a new email diagnostics service
+// authService restricts recipients to authorized admins, so the endpoint cannot send mail to arbitrary addresses
+func NewEmailDiagnosticsService(
+	emailService *EmailService,
+	renderer *templates.TemplateRenderer,
+	authService *AdminAuthService,
+) *EmailDiagnosticsService {
+	return &EmailDiagnosticsService{
+		emailService: emailService,
+		renderer:     renderer,
+		authService:  authService,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SendTestEmail sends a short test email and returns the mail server's error, if any
+// requestedBy is the logged-in admin and the default recipient; it is empty when admin login is disabled.
+// At most one test email is sent per EmailTestInterval, whatever the outcome.
+func (s *EmailDiagnosticsService) SendTestEmail(req *EmailTestRequest, requestedBy string) (*EmailTestResponse, error) {
+	recipient := req.To
+	if recipient == "" {
+		recipient = requestedBy
+	}
+	if recipient == "" {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: to is required when no admin is logged in"))
+	}
+	recipient, err := validators.NormalizeEmail(recipient)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	authorized, err := s.authService.IsAuthorizedEmail(recipient)
+	if err != nil {
+		return nil, err
+	}
+	if !authorized {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %s is not an authorized admin email", recipient))
+	}
+
+	if s.emailService == nil || !s.emailService.IsConfigured() {
+		return nil, withCode(ErrCodeServiceUnavailable, fmt.Errorf("email is not configured: SMTP_HOST and EMAIL_FROM are required"))
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	if wait := s.lastSent.Add(EmailTestInterval).Sub(now); wait > 0 {
+		s.mu.Unlock()
+		return nil, withCode(ErrCodeRateLimited, withRetryAfter(wait, fmt.Errorf("rate limit exceeded: one test email may be sent per %d seconds", int(EmailTestInterval/time.Second))))
+	}
+	s.lastSent = now
+	s.mu.Unlock()
+
+	subject, body, err := s.renderer.RenderTestEmail(&templates.TestEmailData{
+		Email:       recipient,
+		RequestedBy: requestedBy,
+		SentAtUTC:   now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.emailService.SendHTML(recipient, subject, body); err != nil {
+		log.Printf("Test email to %s failed: %v", recipient, err)
+		return nil, fmt.Errorf("failed to send test email: %w", err)
+	}
+
+	log.Printf("Test email sent to %s by %s", recipient, auditActor(requestedBy))
+	return &EmailTestResponse{
+		Recipient: recipient,
+		SentAt:    now.Format(time.RFC3339),
+		Message:   "Test email accepted by the mail server",
+	}, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/templates"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestEmailDiagnosticsService_SendTestEmail tests recipient checks, the mail server error and the rate limit
+func TestEmailDiagnosticsService_SendTestEmail(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AdminUser{}, &models.AdminToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	authService := NewAdminAuthService(repositories.NewAdminUserRepository(db), repositories.NewAdminTokenRepository(db), nil, nil, nil, nil, &AdminAuthConfig{
+		BootstrapEmails: []string{"ops@example.com"},
+	})
+	renderer, err := templates.NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	unconfigured := NewEmailDiagnosticsService(NewEmailService(nil), renderer, authService)
+	if _, err := unconfigured.SendTestEmail(&EmailTestRequest{}, "ops@example.com"); ErrorCodeOf(err) != ErrCodeServiceUnavailable {
+		t.Errorf("SendTestEmail() without SMTP error = %v, want SERVICE_UNAVAILABLE", err)
+	}
+
+	// A port nothing listens on makes the mail server unreachable
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	service := NewEmailDiagnosticsService(NewEmailService(&EmailConfig{SMTPHost: "127.0.0.1", SMTPPort: port, From: "noreply@example.com"}), renderer, authService)
+
+	if _, err := service.SendTestEmail(&EmailTestRequest{}, ""); ErrorCodeOf(err) != ErrCodeValidationFailed {
+		t.Errorf("SendTestEmail() without recipient error = %v, want VALIDATION_FAILED", err)
+	}
+	if _, err := service.SendTestEmail(&EmailTestRequest{To: "someone@example.com"}, "ops@example.com"); ErrorCodeOf(err) != ErrCodeValidationFailed {
+		t.Errorf("SendTestEmail() to non-admin error = %v, want VALIDATION_FAILED", err)
+	}
+
+	if _, err := service.SendTestEmail(&EmailTestRequest{To: "Ops@Example.com"}, ""); err == nil || !strings.HasPrefix(err.Error(), "failed to send test email") {
+		t.Errorf("SendTestEmail() to unreachable server error = %v, want send failure", err)
+	}
+	_, err = service.SendTestEmail(&EmailTestRequest{}, "ops@example.com")
+	if ErrorCodeOf(err) != ErrCodeRateLimited {
+		t.Fatalf("SendTestEmail() within interval error = %v, want RATE_LIMITED", err)
+	}
+	if wait, ok := RetryAfterOf(err); !ok || wait <= 0 || wait > EmailTestInterval {
+		t.Errorf("RetryAfterOf() = %v, %v, want within %v", wait, ok, EmailTestInterval)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailConfig holds the SMTP settings used to send admin emails
+type EmailConfig struct {
+	// SMTPHost and SMTPPort locate the mail server
+	SMTPHost string
+	SMTPPort int
+
+	// Username and Password enable SMTP AUTH PLAIN when Username is set
+	Username string
+	Password string
+
+	// From is the sender, either a bare address or "Name <address>"
+	From string
+}
+
+// DefaultEmailConfig returns the default email settings (port 587, no server configured)
+func DefaultEmailConfig() *EmailConfig {
+	return &EmailConfig{
+		SMTPPort: 587,
+	}
+}
+
+// Emailer sends HTML emails
+// EmailService delivers them over SMTP; MemoryEmailer keeps them for tests.
+type Emailer interface {
+	SendHTML(to, subject, htmlBody string) error
+}
+
+// EmailService sends HTML emails through an SMTP server
+// The connection is upgraded with STARTTLS when the server supports it.
+type EmailService struct {
+	config *EmailConfig
+}
+
+// NewEmailService creates a new email service instance
+// If config is nil, DefaultEmailConfig is used
+func NewEmailService(config *EmailConfig) *EmailService {
+	if config == nil {
+		config = DefaultEmailConfig()
+	}
+
+	return &EmailService{config: config}
+}
+
+// IsConfigured reports whether an SMTP server and sender are set
+func (s *EmailService) IsConfigured() bool {
+	return s.config.SMTPHost != "" && s.config.From != ""
+}
+
+// SendHTML sends an HTML email to a single recipient
+func (s *EmailService) SendHTML(to, subject, htmlBody string) error {
+	if !s.IsConfigured() {
+		return fmt.Errorf("email is not configured: SMTP_HOST and EMAIL_FROM are required")
+	}
+	if to == "" {
+		return fmt.Errorf("recipient is required")
+	}
+
+	sender, err := mail.ParseAddress(s.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.config.From, err)
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.SMTPHost)
+	}
+
+	address := net.JoinHostPort(s.config.SMTPHost, strconv.Itoa(s.config.SMTPPort))
+	if err := smtp.SendMail(address, auth, sender.Address, []string{to}, buildHTMLMessage(sender.String(), to, subject, htmlBody)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// buildHTMLMessage assembles the RFC 5322 message for an HTML email
+func buildHTMLMessage(from, to, subject, htmlBody string) []byte {
+	var msg strings.Builder
+	msg.WriteString("From: " + from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(htmlBody)
+	return []byte(msg.String())
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// ErrorCode is a stable, machine-readable error identifier returned as "code" in API error responses
+// Clients (e.g. device firmware) should branch on the code, never on the English message.
+// Codes are only ever added, never renamed.
+type ErrorCode string
+
+const (
+	// Request errors
+	ErrCodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	ErrCodeMACInvalid           ErrorCode = "MAC_INVALID"
+	ErrCodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrCodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+
+	// Registration token errors
+	ErrCodeTokenNotFound     ErrorCode = "TOKEN_NOT_FOUND"
+	ErrCodeTokenExpired      ErrorCode = "TOKEN_EXPIRED"
+	ErrCodeTokenExhausted    ErrorCode = "TOKEN_EXHAUSTED"
+	ErrCodeTokenMACMismatch  ErrorCode = "TOKEN_MAC_MISMATCH"
+	ErrCodeTokenLimitReached ErrorCode = "TOKEN_LIMIT_REACHED"
+	ErrCodeTokenMACLimit     ErrorCode = "TOKEN_MAC_LIMIT"
+
+	// Node errors
+	ErrCodeNodeNotFound          ErrorCode = "NODE_NOT_FOUND"
+	ErrCodeNodeRevoked           ErrorCode = "NODE_REVOKED"
+	ErrCodeNodeDisabled          ErrorCode = "NODE_DISABLED"
+	ErrCodeNodeAlreadyRegistered ErrorCode = "NODE_ALREADY_REGISTERED"
+	ErrCodeMACDenied             ErrorCode = "MAC_DENIED"
+	ErrCodeOUINotAllowed         ErrorCode = "OUI_NOT_ALLOWED"
+	ErrCodeOwnerMismatch         ErrorCode = "OWNER_MISMATCH"
+	ErrCodeFirmwareDowngrade     ErrorCode = "FIRMWARE_DOWNGRADE"
+
+	// Authentication and limits
+	ErrCodeUnauthorized ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden    ErrorCode = "FORBIDDEN"
+	ErrCodeRateLimited  ErrorCode = "RATE_LIMITED"
+
+	// Server configuration errors
+	ErrCodeEncryptionKeyMismatch ErrorCode = "ENCRYPTION_KEY_MISMATCH"
+
+	// Idempotency errors
+	ErrCodeIdempotencyKeyReused ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+
+	// Generic codes used when the error carries no specific code
+	ErrCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrCodeConflict           ErrorCode = "CONFLICT"
+	ErrCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrCodeInternal           ErrorCode = "INTERNAL_ERROR"
+)
+
+// CodedError attaches an ErrorCode to an error
+// Error() returns the wrapped message unchanged, so message-based status mapping keeps working.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+// Error returns the wrapped error's message
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// withCode attaches code to err; nil errors and empty codes leave err unchanged
+func withCode(code ErrorCode, err error) error {
+	if err == nil || code == "" {
+		return err
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCodeOf returns the code attached anywhere in err's chain, or "" if there is none
+func ErrorCodeOf(err error) ErrorCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+// RetryAfterError marks an error that clears once RetryAfter has passed, e.g. a rate limit
+// Error() returns the wrapped message unchanged, like CodedError.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error returns the wrapped error's message
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// withRetryAfter attaches a retry delay to err; nil errors and non-positive delays leave err unchanged
+func withRetryAfter(retryAfter time.Duration, err error) error {
+	if err == nil || retryAfter <= 0 {
+		return err
+	}
+	return &RetryAfterError{RetryAfter: retryAfter, Err: err}
+}
+
+// RetryAfterOf returns the retry delay attached anywhere in err's chain
+func RetryAfterOf(err error) (time.Duration, bool) {
+	var retry *RetryAfterError
+	if errors.As(err, &retry) {
+		return retry.RetryAfter, true
+	}
+	return 0, false
+}
+
+// tokenErrorCode classifies a registration token repository error
+// Returns "" for errors that are not about the token itself (e.g. database failures)
+func tokenErrorCode(err error) ErrorCode {
+	if reason, ok := repositories.TokenRejectReasonOf(err); ok {
+		switch reason {
+		case repositories.TokenRejectNotFound:
+			return ErrCodeTokenNotFound
+		case repositories.TokenRejectExpired:
+			return ErrCodeTokenExpired
+		case repositories.TokenRejectExhausted:
+			return ErrCodeTokenExhausted
+		case repositories.TokenRejectMACMismatch:
+			return ErrCodeTokenMACMismatch
+		case repositories.TokenRejectMACLimit:
+			return ErrCodeTokenMACLimit
+		}
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "token not found"):
+		return ErrCodeTokenNotFound
+	case strings.Contains(msg, "token has expired"):
+		return ErrCodeTokenExpired
+	case strings.Contains(msg, "token has no remaining uses"):
+		return ErrCodeTokenExhausted
+	case strings.Contains(msg, "token cannot be used for MAC address"):
+		return ErrCodeTokenMACMismatch
+	}
+	return ""
+}
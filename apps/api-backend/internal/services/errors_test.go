@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestErrorCodeOf tests that codes survive wrapping and that uncoded errors have no code
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"coded", withCode(ErrCodeNodeRevoked, errors.New("node is revoked")), ErrCodeNodeRevoked},
+		{"wrapped", fmt.Errorf("validation failed: %w", withCode(ErrCodeMACInvalid, errors.New("bad mac"))), ErrCodeMACInvalid},
+		{"uncoded", errors.New("failed to query"), ""},
+		{"empty code", withCode("", errors.New("failed to query")), ""},
+		{"expired token", withCode(tokenErrorCode(errors.New("token has expired")), errors.New("x")), ErrCodeTokenExpired},
+		{"exhausted token", withCode(tokenErrorCode(errors.New("token has no remaining uses")), errors.New("x")), ErrCodeTokenExhausted},
+		{"token database error", withCode(tokenErrorCode(errors.New("failed to find token")), errors.New("x")), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeOf(tt.err); got != tt.want {
+				t.Errorf("ErrorCodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The message is unchanged so message-based status mapping keeps working
+	if got := withCode(ErrCodeNodeRevoked, errors.New("node is revoked")).Error(); got != "node is revoked" {
+		t.Errorf("Error() = %q, want unchanged message", got)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// Health check statuses
+const (
+	HealthStatusOK    = "ok"
+	HealthStatusError = "error"
+)
+
+// HealthCheckFunc checks one subsystem; a nil error means healthy
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthService runs the registered subsystem checks for the /health endpoint
+type HealthService struct {
+	mu     sync.RWMutex
+	checks map[string]HealthCheckFunc
+}
+
+// NewHealthService creates a new health service with no checks registered
+func NewHealthService() *HealthService {
+	return &HealthService{
+		checks: make(map[string]HealthCheckFunc),
+	}
+}
+
+// Register adds a named check, replacing any check with the same name
+func (s *HealthService) Register(name string, check HealthCheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// Check runs all registered checks and reports whether every check passed
+func (s *HealthService) Check(ctx context.Context) (*models.HealthResponse, bool) {
+	s.mu.RLock()
+	checks := make(map[string]HealthCheckFunc, len(s.checks))
+	names := make([]string, 0, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	response := &models.HealthResponse{
+		Status:    HealthStatusOK,
+		Timestamp: time.Now(),
+		Service:   "api-backend",
+		Checks:    make(map[string]*models.HealthCheckResult, len(names)),
+	}
+
+	healthy := true
+	for _, name := range names {
+		result := runHealthCheck(ctx, checks[name])
+		if result.Status != HealthStatusOK {
+			healthy = false
+			response.Status = HealthStatusError
+		}
+		response.Checks[name] = result
+	}
+
+	return response, healthy
+}
+
+// runHealthCheck converts a check outcome into its result DTO
+func runHealthCheck(ctx context.Context, check HealthCheckFunc) *models.HealthCheckResult {
+	result := &models.HealthCheckResult{
+		Status:    HealthStatusOK,
+		CheckedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := check(ctx); err != nil {
+		result.Status = HealthStatusError
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// CachedHealthCheck wraps a check so it runs at most once per ttl
+// Used for checks against external services that should not be hit on every health probe.
+func CachedHealthCheck(check HealthCheckFunc, ttl time.Duration) HealthCheckFunc {
+	var (
+		mu        sync.Mutex
+		lastErr   error
+		checkedAt time.Time
+	)
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+
+		lastErr = check(ctx)
+		checkedAt = time.Now()
+		return lastErr
+	}
+}
+
+// NewEncryptionKeyHealthCheck returns a check that decrypts the oldest node's JWT secret
+// It fails when JWT_ENCRYPTION_KEY was replaced after nodes registered, before any node
+// re-registers or authenticates. The oldest node is used because nodes registered after a
+// key change decrypt fine and would hide the problem. An empty fleet passes.
+func NewEncryptionKeyHealthCheck(nodeRepo *repositories.NodeRepository, keyProvider crypto.KeyProvider) HealthCheckFunc {
+	if keyProvider == nil {
+		keyProvider = crypto.EnvKeyProvider{}
+	}
+
+	return func(ctx context.Context) error {
+		node, err := nodeRepo.FindOldest()
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			return nil
+		}
+
+		_, err = decryptNodeSecret(keyProvider, node)
+		return err
+	}
+}
+
+// EmailHealthConfig holds settings for the email reachability check
+type EmailHealthConfig struct {
+	// Enabled turns the check on; it is off by default because it contacts an external server
+	Enabled bool
+
+	// SMTPHost and SMTPPort locate the mail server used for admin emails
+	SMTPHost string
+	SMTPPort int
+
+	// Timeout bounds connecting and the SMTP greeting
+	Timeout time.Duration
+
+	// CacheTTL is how long a result is reused before the server is contacted again
+	CacheTTL time.Duration
+}
+
+// DefaultEmailHealthConfig returns the default email check settings (disabled, port 587, 5s timeout, 60s cache)
+func DefaultEmailHealthConfig() *EmailHealthConfig {
+	return &EmailHealthConfig{
+		Enabled:  false,
+		SMTPPort: 587,
+		Timeout:  5 * time.Second,
+		CacheTTL: 60 * time.Second,
+	}
+}
+
+// NewEmailHealthCheck returns a cached check that connects to the SMTP server,
+// waits for its greeting, identifies itself and quits without sending mail
+func NewEmailHealthCheck(config *EmailHealthConfig) (HealthCheckFunc, error) {
+	if config == nil {
+		config = DefaultEmailHealthConfig()
+	}
+	if config.SMTPHost == "" {
+		return nil, fmt.Errorf("SMTP host is required for the email health check")
+	}
+
+	address := net.JoinHostPort(config.SMTPHost, strconv.Itoa(config.SMTPPort))
+	check := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to SMTP server %s: %w", address, err)
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		client, err := smtp.NewClient(conn, config.SMTPHost)
+		if err != nil {
+			return fmt.Errorf("SMTP server %s did not greet: %w", address, err)
+		}
+		if err := client.Quit(); err != nil {
+			return fmt.Errorf("SMTP server %s rejected session: %w", address, err)
+		}
+
+		return nil
+	}
+
+	return CachedHealthCheck(check, config.CacheTTL), nil
+}
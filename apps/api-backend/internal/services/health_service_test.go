@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestHealthService_Check tests that one failing check marks the service unhealthy
+func TestHealthService_Check(t *testing.T) {
+	service := NewHealthService()
+	service.Register("database", func(ctx context.Context) error { return nil })
+	service.Register("email", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	response, healthy := service.Check(context.Background())
+	if healthy || response.Status != HealthStatusError {
+		t.Errorf("Check() healthy = %v, status = %q, want unhealthy", healthy, response.Status)
+	}
+	if response.Checks["database"].Status != HealthStatusOK {
+		t.Errorf("database status = %q, want ok", response.Checks["database"].Status)
+	}
+	if email := response.Checks["email"]; email.Status != HealthStatusError || email.Message != "connection refused" {
+		t.Errorf("email result = %+v, want error with message", email)
+	}
+}
+
+// TestCachedHealthCheck tests that the wrapped check runs at most once per TTL
+func TestCachedHealthCheck(t *testing.T) {
+	calls := 0
+	check := CachedHealthCheck(func(ctx context.Context) error {
+		calls++
+		return nil
+	}, 50*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		check(context.Background())
+	}
+	if calls != 1 {
+		t.Errorf("calls within TTL = %d, want 1", calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	check(context.Background())
+	if calls != 2 {
+		t.Errorf("calls after TTL = %d, want 2", calls)
+	}
+}
+
+// TestEmailHealthCheck tests the SMTP check against a minimal fake server
+func TestEmailHealthCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				conn.Write([]byte("220 test ESMTP\r\n"))
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if strings.HasPrefix(line, "QUIT") {
+						conn.Write([]byte("221 bye\r\n"))
+						return
+					}
+					conn.Write([]byte("250 test\r\n"))
+				}
+			}(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	check, err := NewEmailHealthCheck(&EmailHealthConfig{Enabled: true, SMTPHost: host, SMTPPort: portNumber, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewEmailHealthCheck() error = %v", err)
+	}
+	if err := check(context.Background()); err != nil {
+		t.Errorf("check() against reachable server error = %v", err)
+	}
+
+	listener.Close()
+	unreachable, _ := NewEmailHealthCheck(&EmailHealthConfig{Enabled: true, SMTPHost: host, SMTPPort: portNumber, Timeout: time.Second})
+	if err := unreachable(context.Background()); err == nil {
+		t.Error("check() against closed port should return error")
+	}
+
+	if _, err := NewEmailHealthCheck(&EmailHealthConfig{Enabled: true}); err == nil {
+		t.Error("NewEmailHealthCheck() without host should return error")
+	}
+}
+
+// TestEncryptionKeyHealthCheck tests that the check fails once stored secrets no longer match the key
+func TestEncryptionKeyHealthCheck(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	oldKey := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	newKey := crypto.StaticKeyProvider(bytes.Repeat([]byte{8}, 32))
+
+	if err := NewEncryptionKeyHealthCheck(nodeRepo, newKey)(context.Background()); err != nil {
+		t.Errorf("check with no nodes error = %v, want nil", err)
+	}
+
+	_, encrypted, err := crypto.EncryptJWTSecret(oldKey)
+	if err != nil {
+		t.Fatalf("EncryptJWTSecret() error = %v", err)
+	}
+	if err := nodeRepo.Create(&models.Node{UUID: "550e8400-e29b-41d4-a716-446655440000", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: encrypted}); err != nil {
+		t.Fatalf("Create() node error = %v", err)
+	}
+
+	if err := NewEncryptionKeyHealthCheck(nodeRepo, oldKey)(context.Background()); err != nil {
+		t.Errorf("check with matching key error = %v, want nil", err)
+	}
+	if err := NewEncryptionKeyHealthCheck(nodeRepo, newKey)(context.Background()); ErrorCodeOf(err) != ErrCodeEncryptionKeyMismatch {
+		t.Errorf("check with replaced key error = %v, want ENCRYPTION_KEY_MISMATCH", err)
+	}
+}
+
+// TestReadinessService_Check tests that readiness requires every step and its live check
+func TestReadinessService_Check(t *testing.T) {
+	service := NewReadinessService(ReadinessStepCleanupService, ReadinessStepDatabase)
+
+	response, ready := service.Check(context.Background())
+	if ready || response.Checks[ReadinessStepDatabase].Message != "not initialized" {
+		t.Errorf("Check() before startup ready = %v, database = %+v, want not initialized", ready, response.Checks[ReadinessStepDatabase])
+	}
+
+	service.MarkReady(ReadinessStepCleanupService, nil)
+	if _, ready := service.Check(context.Background()); ready {
+		t.Error("Check() ready with the database step missing")
+	}
+
+	var dbErr error
+	service.MarkReady(ReadinessStepDatabase, func(ctx context.Context) error { return dbErr })
+	if response, ready := service.Check(context.Background()); !ready || response.Status != HealthStatusOK {
+		t.Errorf("Check() after all steps ready = %v, status = %q, want ready", ready, response.Status)
+	}
+
+	dbErr = errors.New("database is closed")
+	response, ready = service.Check(context.Background())
+	if ready || response.Checks[ReadinessStepDatabase].Message != "database is closed" {
+		t.Errorf("Check() with unreachable database ready = %v, database = %+v", ready, response.Checks[ReadinessStepDatabase])
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/validators"
+)
+
+// DenyMACRequest adds a MAC address to the denylist
+type DenyMACRequest struct {
+	MacAddress string  `json:"mac_address" binding:"required" example:"AA:BB:CC:DD:EE:FF"`
+	Reason     *string `json:"reason,omitempty" example:"Device reported stolen"`
+}
+
+// DeniedMACResponse describes a denylisted MAC address
+type DeniedMACResponse struct {
+	MacAddress string  `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
+	Reason     *string `json:"reason,omitempty" example:"Device reported stolen"`
+	CreatedBy  *string `json:"created_by,omitempty" example:"admin@example.com"`
+	CreatedAt  string  `json:"created_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// DeniedMACListResponse lists every denylisted MAC address, most recently banned first
+type DeniedMACListResponse struct {
+	Count   int                  `json:"count" example:"1"`
+	Entries []*DeniedMACResponse `json:"entries"`
+}
+
+// MACDenylistService manages MAC addresses that may never register
+// Unlike revoking a node, a denylist entry survives deletion of the node.
+type MACDenylistService struct {
+	deniedRepo   *repositories.DeniedMACRepository
+	auditService *AuditService
+}
+
+// NewMACDenylistService creates a new MAC denylist service instance
+// auditService may be nil, in which case changes are only logged
+func NewMACDenylistService(deniedRepo *repositories.DeniedMACRepository, auditService *AuditService) *MACDenylistService {
+	return &MACDenylistService{
+		deniedRepo:   deniedRepo,
+		auditService: auditService,
+	}
+}
+
+// DenyMAC adds a MAC address to the denylist
+// An existing node with the MAC keeps working until it re-registers; revoke it to cut it off at once.
+func (s *MACDenylistService) DenyMAC(req *DenyMACRequest, deniedBy string) (*DeniedMACResponse, error) {
+	mac, err := validators.NormalizeMACAddress(req.MacAddress)
+	if err != nil {
+		return nil, withCode(ErrCodeMACInvalid, fmt.Errorf("validation failed: %w", err))
+	}
+
+	reason := req.Reason
+	if reason != nil && *reason == "" {
+		reason = nil
+	}
+	if reason != nil {
+		if err := validators.ValidateDescription(*reason, "reason"); err != nil {
+			return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+		}
+	}
+
+	entry := &models.DeniedMAC{
+		MacAddress: mac,
+		Reason:     reason,
+	}
+	if deniedBy != "" {
+		entry.CreatedBy = &deniedBy
+	}
+	if err := s.deniedRepo.Create(entry); err != nil {
+		if strings.Contains(err.Error(), "already denylisted") {
+			return nil, withCode(ErrCodeConflict, err)
+		}
+		return nil, err
+	}
+
+	log.Printf("AUDIT: MAC %s denylisted by %s", mac, auditActor(deniedBy))
+	details := ""
+	if reason != nil {
+		details = *reason
+	}
+	s.auditService.Record(AuditActionMACDenied, deniedBy, models.AuditTargetMAC, mac, details)
+
+	return toDeniedMACResponse(entry), nil
+}
+
+// AllowMAC removes a MAC address from the denylist
+func (s *MACDenylistService) AllowMAC(macAddress string, allowedBy string) error {
+	mac, err := validators.NormalizeMACAddress(macAddress)
+	if err != nil {
+		return withCode(ErrCodeMACInvalid, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if err := s.deniedRepo.Delete(mac); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return withCode(ErrCodeNotFound, err)
+		}
+		return err
+	}
+
+	log.Printf("AUDIT: MAC %s removed from denylist by %s", mac, auditActor(allowedBy))
+	s.auditService.Record(AuditActionMACAllowed, allowedBy, models.AuditTargetMAC, mac, "")
+	return nil
+}
+
+// ListDeniedMACs returns every denylisted MAC address
+func (s *MACDenylistService) ListDeniedMACs() (*DeniedMACListResponse, error) {
+	entries, err := s.deniedRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*DeniedMACResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = toDeniedMACResponse(entry)
+	}
+
+	return &DeniedMACListResponse{
+		Count:   len(responses),
+		Entries: responses,
+	}, nil
+}
+
+// toDeniedMACResponse converts a denylist entry to its response DTO
+func toDeniedMACResponse(entry *models.DeniedMAC) *DeniedMACResponse {
+	return &DeniedMACResponse{
+		MacAddress: entry.MacAddress,
+		Reason:     entry.Reason,
+		CreatedBy:  entry.CreatedBy,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
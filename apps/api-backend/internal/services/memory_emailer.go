@@ -0,0 +1,38 @@
+package services
+
+import "sync"
+
+// SentEmail is one email recorded by MemoryEmailer
+type SentEmail struct {
+	To       string
+	Subject  string
+	HTMLBody string
+}
+
+// MemoryEmailer is an Emailer that records emails instead of delivering them
+// Meant for tests, so flows that email a token or code can be exercised end to end
+// without an SMTP server.
+type MemoryEmailer struct {
+	mu   sync.Mutex
+	sent []SentEmail
+}
+
+// NewMemoryEmailer creates an emailer that has sent nothing yet
+func NewMemoryEmailer() *MemoryEmailer {
+	return &MemoryEmailer{}
+}
+
+// SendHTML records the email; it never fails
+func (e *MemoryEmailer) SendHTML(to, subject, htmlBody string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sent = append(e.sent, SentEmail{To: to, Subject: subject, HTMLBody: htmlBody})
+	return nil
+}
+
+// Sent returns the recorded emails, oldest first
+func (e *MemoryEmailer) Sent() []SentEmail {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SentEmail(nil), e.sent...)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// NodeAuthConfig holds settings for node authentication
+type NodeAuthConfig struct {
+	// LastSeenInterval is the minimum time between LastSeenAt writes for one node
+	LastSeenInterval time.Duration
+}
+
+// DefaultNodeAuthConfig returns the default node authentication settings
+func DefaultNodeAuthConfig() *NodeAuthConfig {
+	return &NodeAuthConfig{
+		LastSeenInterval: 60 * time.Second,
+	}
+}
+
+// NodeAuthService authenticates nodes by their JWT and tracks their activity
+type NodeAuthService struct {
+	nodeRepo    *repositories.NodeRepository
+	revokedRepo *repositories.RevokedNodeTokenRepository
+	keyProvider crypto.KeyProvider
+	secretCache *NodeSecretCache
+	config      *NodeAuthConfig
+
+	// lastWrites caches when LastSeenAt was last written per node UUID
+	mu         sync.Mutex
+	lastWrites map[string]time.Time
+	now        func() time.Time
+}
+
+// NewNodeAuthService creates a new node authentication service instance
+// revokedRepo may be nil, in which case the token revocation list is not checked
+// If keyProvider is nil, the key is read from the environment
+// secretCache may be nil, in which case the node secret is decrypted on every request
+// If config is nil, DefaultNodeAuthConfig is used
+func NewNodeAuthService(
+	nodeRepo *repositories.NodeRepository,
+	revokedRepo *repositories.RevokedNodeTokenRepository,
+	keyProvider crypto.KeyProvider,
+	secretCache *NodeSecretCache,
+	config *NodeAuthConfig,
+) *NodeAuthService {
+	if keyProvider == nil {
+		keyProvider = crypto.EnvKeyProvider{}
+	}
+	if config == nil {
+		config = DefaultNodeAuthConfig()
+	}
+
+	return &NodeAuthService{
+		nodeRepo:    nodeRepo,
+		revokedRepo: revokedRepo,
+		keyProvider: keyProvider,
+		secretCache: secretCache,
+		config:      config,
+		lastWrites:  make(map[string]time.Time),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// HeartbeatResponse contains the data returned to a node on heartbeat
+type HeartbeatResponse struct {
+	UUID       string `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status     string `json:"status" example:"active"`
+	ServerTime string `json:"server_time" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+
+	// TargetFirmwareVersion is set when an admin scheduled a firmware newer than the node reports
+	TargetFirmwareVersion *string `json:"target_firmware_version,omitempty" example:"1.1.0"`
+}
+
+// Authenticate verifies a node JWT and returns the node it was issued to
+// The token is verified with the node's own decrypted JWT secret
+func (s *NodeAuthService) Authenticate(tokenString string) (*models.Node, error) {
+	if tokenString == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	// The UUID claim is only used to look up the node's secret; the signature is verified below
+	nodeUUID, err := crypto.GetNodeUUIDFromToken(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	node, err := s.nodeRepo.FindByUUID(nodeUUID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: node not found")
+	}
+
+	jwtSecret, err := decryptNodeSecretCached(s.keyProvider, s.secretCache, node)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := crypto.VerifyNodeJWT(tokenString, jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if claims.NodeUUID != node.UUID {
+		return nil, fmt.Errorf("invalid token: node UUID mismatch")
+	}
+
+	// Tokens issued before jti was added carry no ID and cannot be revoked individually
+	if s.revokedRepo != nil && claims.ID != "" {
+		revoked, err := s.revokedRepo.IsRevoked(claims.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			// Worded without "revoked" so the middleware answers 401 (re-register) rather than 403 (node revoked)
+			return nil, fmt.Errorf("invalid token: token ID is on the revocation list")
+		}
+	}
+
+	if node.IsRevoked() {
+		return nil, withCode(ErrCodeNodeRevoked, fmt.Errorf("node is revoked"))
+	}
+	if node.IsDisabled() {
+		return nil, withCode(ErrCodeNodeDisabled, fmt.Errorf("node is disabled"))
+	}
+
+	return node, nil
+}
+
+// TouchLastSeen updates the node's LastSeenAt timestamp
+// Writes are debounced to at most one per LastSeenInterval per node.
+// Returns true if the database was written.
+func (s *NodeAuthService) TouchLastSeen(nodeUUID string) (bool, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	last, ok := s.lastWrites[nodeUUID]
+	if ok && now.Sub(last) < s.config.LastSeenInterval {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.lastWrites[nodeUUID] = now
+	s.mu.Unlock()
+
+	if err := s.nodeRepo.UpdateLastSeen(nodeUUID); err != nil {
+		// Forget the write so the next request retries
+		s.mu.Lock()
+		if s.lastWrites[nodeUUID].Equal(now) {
+			delete(s.lastWrites, nodeUUID)
+		}
+		s.mu.Unlock()
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Heartbeat builds the heartbeat response for an authenticated node
+func (s *NodeAuthService) Heartbeat(node *models.Node) *HeartbeatResponse {
+	return &HeartbeatResponse{
+		UUID:                  node.UUID,
+		Status:                node.Status,
+		ServerTime:            s.now().Format(time.RFC3339),
+		TargetFirmwareVersion: node.PendingFirmwareUpdate(),
+	}
+}
+
+// decryptNodeSecret decrypts a node's stored JWT secret
+// A secret that fails authentication was encrypted under a different JWT_ENCRYPTION_KEY; that is
+// logged and returned as ENCRYPTION_KEY_MISMATCH so operators see the cause instead of a generic failure.
+func decryptNodeSecret(provider crypto.KeyProvider, node *models.Node) (string, error) {
+	jwtSecret, err := crypto.DecryptJWTSecret(provider, node.JWTSecret)
+	if err != nil {
+		if crypto.IsKeyMismatch(err) {
+			log.Printf("ERROR: JWT secret of node %s cannot be decrypted with the configured %s; the key has likely changed since the node registered", node.UUID, crypto.EnvKeyName)
+			return "", withCode(ErrCodeEncryptionKeyMismatch, fmt.Errorf("encryption key mismatch: node secret cannot be decrypted with the configured encryption key"))
+		}
+		return "", fmt.Errorf("failed to decrypt JWT secret: %w", err)
+	}
+
+	return jwtSecret, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestNodeAuthService_TouchLastSeenDebounce tests that LastSeenAt writes are limited to one per interval
+func TestNodeAuthService_TouchLastSeenDebounce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	// Count UPDATE statements hitting the database
+	writes := 0
+	if err := db.Callback().Update().After("gorm:update").Register("test:count_writes", func(tx *gorm.DB) {
+		writes++
+	}); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	node := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440000",
+		MacAddress: "AA:BB:CC:DD:EE:FF",
+		JWTSecret:  "secret",
+		Status:     models.NodeStatusActive,
+	}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	service := NewNodeAuthService(nodeRepo, nil, nil, nil, &NodeAuthConfig{LastSeenInterval: time.Minute})
+	now := time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	// First request writes
+	written, err := service.TouchLastSeen(node.UUID)
+	if err != nil {
+		t.Fatalf("TouchLastSeen() error = %v", err)
+	}
+	if !written {
+		t.Error("first TouchLastSeen() should write")
+	}
+
+	// Burst of requests within the interval is debounced
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Second)
+		written, err := service.TouchLastSeen(node.UUID)
+		if err != nil {
+			t.Fatalf("TouchLastSeen() error = %v", err)
+		}
+		if written {
+			t.Fatalf("TouchLastSeen() wrote after %d seconds, want debounced", i+1)
+		}
+	}
+	if writes != 1 {
+		t.Errorf("database writes = %d, want 1", writes)
+	}
+
+	// Once the interval has elapsed the next request writes again
+	now = now.Add(10 * time.Second)
+	written, err = service.TouchLastSeen(node.UUID)
+	if err != nil {
+		t.Fatalf("TouchLastSeen() error = %v", err)
+	}
+	if !written {
+		t.Error("TouchLastSeen() after interval should write")
+	}
+	if writes != 2 {
+		t.Errorf("database writes = %d, want 2", writes)
+	}
+
+	found, err := nodeRepo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if found.LastSeenAt == nil {
+		t.Error("LastSeenAt should be set after TouchLastSeen()")
+	}
+
+	// Failed writes are not cached, so the next request retries
+	written, err = service.TouchLastSeen("550e8400-e29b-41d4-a716-446655440099")
+	if err == nil || written {
+		t.Errorf("TouchLastSeen(unknown) = %v, %v, want false and error", written, err)
+	}
+	if _, cached := service.lastWrites["550e8400-e29b-41d4-a716-446655440099"]; cached {
+		t.Error("failed write should not be cached")
+	}
+}
+
+// TestNodeAuthService_RevokedTokenID tests that a revoked jti is rejected while other tokens of the node still work
+func TestNodeAuthService_RevokedTokenID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RevokedNodeToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	plainSecret, encryptedSecret, err := crypto.EncryptJWTSecret(keyProvider)
+	if err != nil {
+		t.Fatalf("EncryptJWTSecret() error = %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	node := &models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440000",
+		MacAddress: "AA:BB:CC:DD:EE:FF",
+		JWTSecret:  encryptedSecret,
+		Status:     models.NodeStatusActive,
+	}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	compromised, _, err := crypto.GenerateNodeJWT(node.UUID, plainSecret, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("GenerateNodeJWT() error = %v", err)
+	}
+	replacement, _, err := crypto.GenerateNodeJWT(node.UUID, plainSecret, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("GenerateNodeJWT() error = %v", err)
+	}
+	claims, err := crypto.VerifyNodeJWT(compromised, plainSecret)
+	if err != nil {
+		t.Fatalf("VerifyNodeJWT() error = %v", err)
+	}
+
+	revokedRepo := repositories.NewRevokedNodeTokenRepository(db)
+	revocation := NewNodeTokenRevocationService(nodeRepo, revokedRepo, nil)
+	if _, err := revocation.RevokeToken(node.UUID, &RevokeNodeTokenRequest{JTI: claims.ID}, "admin@example.com"); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+
+	service := NewNodeAuthService(nodeRepo, revokedRepo, keyProvider, nil, nil)
+	_, err = service.Authenticate(compromised)
+	if err == nil {
+		t.Fatal("Authenticate() with revoked jti should fail")
+	}
+	// Must not read as a revoked node, which the middleware answers with 403 NODE_REVOKED
+	if strings.Contains(err.Error(), "revoked") {
+		t.Errorf("Authenticate() error = %q, should not mention \"revoked\"", err)
+	}
+
+	if _, err := service.Authenticate(replacement); err != nil {
+		t.Errorf("Authenticate() with other token error = %v", err)
+	}
+
+	if _, err := revocation.RevokeToken("550e8400-e29b-41d4-a716-446655440099", &RevokeNodeTokenRequest{JTI: claims.ID}, ""); ErrorCodeOf(err) != ErrCodeNodeNotFound {
+		t.Errorf("RevokeToken(unknown node) error = %v, want NODE_NOT_FOUND", err)
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// Node metadata limits
+const (
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 1024
+)
+
+// metadataKeyPattern allows keys such as site_code, install.date or installer-id
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// NodeMetadataConfig holds limits for admin-assigned node metadata
+type NodeMetadataConfig struct {
+	// MaxKeys is the maximum number of metadata keys per node
+	MaxKeys int
+}
+
+// DefaultNodeMetadataConfig returns the default metadata limits
+func DefaultNodeMetadataConfig() *NodeMetadataConfig {
+	return &NodeMetadataConfig{
+		MaxKeys: 32,
+	}
+}
+
+// NodeMetadataService manages free-form key/value metadata attached to nodes
+type NodeMetadataService struct {
+	nodeRepo     *repositories.NodeRepository
+	metadataRepo *repositories.NodeMetadataRepository
+	config       *NodeMetadataConfig
+}
+
+// NewNodeMetadataService creates a new node metadata service instance
+// If config is nil, DefaultNodeMetadataConfig is used
+func NewNodeMetadataService(
+	nodeRepo *repositories.NodeRepository,
+	metadataRepo *repositories.NodeMetadataRepository,
+	config *NodeMetadataConfig,
+) *NodeMetadataService {
+	if config == nil {
+		config = DefaultNodeMetadataConfig()
+	}
+
+	return &NodeMetadataService{
+		nodeRepo:     nodeRepo,
+		metadataRepo: metadataRepo,
+		config:       config,
+	}
+}
+
+// SetNodeMetadataRequest sets the value of one metadata key
+type SetNodeMetadataRequest struct {
+	Value string `json:"value" binding:"required" example:"PRG-01"`
+}
+
+// NodeMetadataResponse contains all metadata of a node
+type NodeMetadataResponse struct {
+	NodeUUID string            `json:"node_uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// GetMetadata returns all metadata of a node
+func (s *NodeMetadataService) GetMetadata(uuid string) (*NodeMetadataResponse, error) {
+	if err := s.requireNode(uuid); err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.GetMetadataMap(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeMetadataResponse{NodeUUID: uuid, Metadata: metadata}, nil
+}
+
+// GetMetadataMap returns a node's metadata as a key/value map without checking that the node exists
+// Used to embed metadata in other node responses
+func (s *NodeMetadataService) GetMetadataMap(uuid string) (map[string]string, error) {
+	entries, err := s.metadataRepo.FindByNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		metadata[entry.Key] = entry.Value
+	}
+	return metadata, nil
+}
+
+// SetMetadata creates or updates one metadata key of a node and returns all of its metadata
+// New keys are rejected once the node has MaxKeys keys
+func (s *NodeMetadataService) SetMetadata(uuid, key string, req *SetNodeMetadataRequest) (*NodeMetadataResponse, error) {
+	if err := validateMetadataEntry(key, req.Value); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.requireNode(uuid); err != nil {
+		return nil, err
+	}
+
+	entry := &models.NodeMetadata{
+		NodeUUID: uuid,
+		Key:      key,
+		Value:    req.Value,
+	}
+	if err := s.metadataRepo.Set(entry, s.config.MaxKeys); err != nil {
+		if strings.Contains(err.Error(), "metadata key limit reached") {
+			return nil, withCode(ErrCodeConflict, fmt.Errorf("%w (maximum %d)", err, s.config.MaxKeys))
+		}
+		return nil, err
+	}
+
+	return s.GetMetadata(uuid)
+}
+
+// DeleteMetadata removes one metadata key of a node
+func (s *NodeMetadataService) DeleteMetadata(uuid, key string) error {
+	if err := s.requireNode(uuid); err != nil {
+		return err
+	}
+
+	if err := s.metadataRepo.Delete(uuid, key); err != nil {
+		if strings.Contains(err.Error(), "metadata key not found") {
+			return withCode(ErrCodeNotFound, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// requireNode returns a NODE_NOT_FOUND error if the node does not exist
+func (s *NodeMetadataService) requireNode(uuid string) error {
+	exists, err := s.nodeRepo.Exists(uuid)
+	if err != nil {
+		return fmt.Errorf("failed to find node: %w", err)
+	}
+	if !exists {
+		return withCode(ErrCodeNodeNotFound, fmt.Errorf("node not found: %s", uuid))
+	}
+	return nil
+}
+
+// validateMetadataEntry checks the key format and the key and value lengths
+func validateMetadataEntry(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if len(key) > MaxMetadataKeyLength {
+		return fmt.Errorf("key must be at most %d characters", MaxMetadataKeyLength)
+	}
+	if !metadataKeyPattern.MatchString(key) {
+		return fmt.Errorf("key may only contain letters, digits, '_', '.' and '-'")
+	}
+	if len(value) > MaxMetadataValueLength {
+		return fmt.Errorf("value must be at most %d characters", MaxMetadataValueLength)
+	}
+	return nil
+}
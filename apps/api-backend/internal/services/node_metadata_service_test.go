@@ -0,0 +1,35 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateMetadataEntry tests key format and length limits
+func TestValidateMetadataEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"simple key", "site_code", "PRG-01", false},
+		{"dotted key", "install.date", "2025-11-10", false},
+		{"empty value", "note", "", false},
+		{"empty key", "", "x", true},
+		{"key with space", "site code", "x", true},
+		{"key with slash", "site/code", "x", true},
+		{"key at limit", strings.Repeat("k", MaxMetadataKeyLength), "x", false},
+		{"key too long", strings.Repeat("k", MaxMetadataKeyLength+1), "x", true},
+		{"value at limit", "note", strings.Repeat("v", MaxMetadataValueLength), false},
+		{"value too long", "note", strings.Repeat("v", MaxMetadataValueLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMetadataEntry(tt.key, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validateMetadataEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,29 +2,141 @@ package services
 
 import (
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/events"
 	"github.com/boomchecker/api-backend/internal/models"
 	"github.com/boomchecker/api-backend/internal/repositories"
 	"github.com/boomchecker/api-backend/internal/validators"
 	"github.com/google/uuid"
 )
 
+// NodeJWTExpiration is the lifetime of a node JWT issued at registration
+const NodeJWTExpiration = 30 * 24 * time.Hour
+
+// NodeRegistrationConfig holds settings for node registration
+type NodeRegistrationConfig struct {
+	// ReRegistrationCooldown is the minimum time between registrations of one node
+	// Re-registering sooner is rejected without consuming a token use; 0 disables the check
+	ReRegistrationCooldown time.Duration
+
+	// AllowedOUIs restricts registration to MACs with these manufacturer prefixes (ALLOWED_OUI)
+	// Any notation accepted by validators.NormalizeOUI works; empty allows every manufacturer
+	AllowedOUIs []string
+
+	// OneTimeJWT makes a node's JWT retrievable only at its first registration (NODE_JWT_ONE_TIME)
+	// Re-registrations then update the node but return no JWT unless the request sets force_reissue.
+	OneTimeJWT bool
+
+	// FirmwareDowngradePolicy decides what happens when a re-registering node reports an older
+	// firmware version than the stored one (FIRMWARE_DOWNGRADE_POLICY); empty means FirmwareDowngradeWarn
+	FirmwareDowngradePolicy string
+
+	// FreePreAuthorizedReRegistration lets a node re-register with a token pre-authorized for its own
+	// MAC without consuming a use, even once the token is exhausted (PREAUTHORIZED_REREGISTRATION_FREE)
+	// The token is bound to that device, so a repeat registration is not a new provisioning.
+	FreePreAuthorizedReRegistration bool
+}
+
+// Firmware downgrade policies for re-registration
+// A downgrade may be a legitimate rollback or a rollback attack to a vulnerable version.
+const (
+	// FirmwareDowngradeWarn stores the older version and logs a warning
+	FirmwareDowngradeWarn = "warn"
+	// FirmwareDowngradeKeep re-registers the node but keeps the stored (newer) version, logging a warning
+	FirmwareDowngradeKeep = "keep"
+	// FirmwareDowngradeReject refuses the re-registration without consuming a token use
+	FirmwareDowngradeReject = "reject"
+)
+
+// DefaultNodeRegistrationConfig returns the default node registration settings
+// (no re-registration cooldown, every manufacturer allowed, JWT returned on every registration,
+// firmware downgrades stored with a warning)
+func DefaultNodeRegistrationConfig() *NodeRegistrationConfig {
+	return &NodeRegistrationConfig{
+		FirmwareDowngradePolicy: FirmwareDowngradeWarn,
+	}
+}
+
+// Validate checks that every allowed OUI is a valid three-octet prefix and the downgrade policy is known
+// An invalid entry is an error rather than being skipped, since dropping all
+// entries would silently allow every manufacturer.
+func (c *NodeRegistrationConfig) Validate() error {
+	for _, oui := range c.AllowedOUIs {
+		if _, err := validators.NormalizeOUI(oui); err != nil {
+			return err
+		}
+	}
+	switch c.FirmwareDowngradePolicy {
+	case "", FirmwareDowngradeWarn, FirmwareDowngradeKeep, FirmwareDowngradeReject:
+	default:
+		return fmt.Errorf("firmware downgrade policy must be %q, %q or %q (got %q)",
+			FirmwareDowngradeWarn, FirmwareDowngradeKeep, FirmwareDowngradeReject, c.FirmwareDowngradePolicy)
+	}
+	return nil
+}
+
 // NodeRegistrationService handles the business logic for node registration
 type NodeRegistrationService struct {
-	nodeRepo  *repositories.NodeRepository
-	tokenRepo *repositories.RegistrationTokenRepository
+	nodeRepo    *repositories.NodeRepository
+	tokenRepo   *repositories.RegistrationTokenRepository
+	eventRepo   *repositories.RegistrationEventRepository
+	deniedRepo  *repositories.DeniedMACRepository
+	broker      *events.Broker
+	keyProvider crypto.KeyProvider
+	metrics     *TokenValidationMetrics
+	secretCache *NodeSecretCache
+	config      *NodeRegistrationConfig
+	allowedOUIs map[string]bool
 }
 
 // NewNodeRegistrationService creates a new node registration service instance
+// deniedRepo may be nil, in which case no MAC is denylisted
+// broker may be nil if live event streaming is not needed
+// If keyProvider is nil, the key is read from the environment
+// metrics may be nil, in which case token validation outcomes are not counted
+// secretCache may be nil, in which case node secrets are decrypted on every re-registration
+// If config is nil, DefaultNodeRegistrationConfig is used; invalid allowed OUIs are skipped,
+// so call config.Validate first
 func NewNodeRegistrationService(
 	nodeRepo *repositories.NodeRepository,
 	tokenRepo *repositories.RegistrationTokenRepository,
+	eventRepo *repositories.RegistrationEventRepository,
+	deniedRepo *repositories.DeniedMACRepository,
+	broker *events.Broker,
+	keyProvider crypto.KeyProvider,
+	metrics *TokenValidationMetrics,
+	secretCache *NodeSecretCache,
+	config *NodeRegistrationConfig,
 ) *NodeRegistrationService {
+	if keyProvider == nil {
+		keyProvider = crypto.EnvKeyProvider{}
+	}
+	if config == nil {
+		config = DefaultNodeRegistrationConfig()
+	}
+
+	allowedOUIs := make(map[string]bool, len(config.AllowedOUIs))
+	for _, oui := range config.AllowedOUIs {
+		if normalized, err := validators.NormalizeOUI(oui); err == nil {
+			allowedOUIs[normalized] = true
+		}
+	}
+
 	return &NodeRegistrationService{
-		nodeRepo:  nodeRepo,
-		tokenRepo: tokenRepo,
+		nodeRepo:    nodeRepo,
+		tokenRepo:   tokenRepo,
+		eventRepo:   eventRepo,
+		deniedRepo:  deniedRepo,
+		broker:      broker,
+		keyProvider: keyProvider,
+		metrics:     metrics,
+		secretCache: secretCache,
+		config:      config,
+		allowedOUIs: allowedOUIs,
 	}
 }
 
@@ -35,13 +147,19 @@ type RegistrationRequest struct {
 	FirmwareVersion   *string  `json:"firmware_version,omitempty" example:"1.0.0"`
 	Latitude          *float64 `json:"latitude,omitempty" example:"50.0755"`
 	Longitude         *float64 `json:"longitude,omitempty" example:"14.4378"`
+	ForceReissue      bool     `json:"force_reissue,omitempty" example:"false"` // Return a JWT on re-registration even when NODE_JWT_ONE_TIME is set
+
+	// ClientIP is the caller's address, set by the handler from the connection (never from the body)
+	ClientIP string `json:"-"`
 }
 
 // RegistrationResponse contains the data returned after successful registration
+// JWTToken and ExpiresAt are omitted when a re-registration issued no JWT (see NodeRegistrationConfig.OneTimeJWT)
 type RegistrationResponse struct {
 	UUID       string `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
-	JWTToken   string `json:"jwt_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	ExpiresAt  string `json:"expires_at" example:"2025-12-10T14:30:00Z"` // UTC timestamp when JWT expires (RFC3339 format)
+	JWTIssued  bool   `json:"jwt_issued" example:"true"`
+	JWTToken   string `json:"jwt_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt  string `json:"expires_at,omitempty" example:"2025-12-10T14:30:00Z"` // UTC timestamp when JWT expires (RFC3339 format)
 	IsNewNode  bool   `json:"is_new_node" example:"true"`
 	MacAddress string `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
 }
@@ -50,11 +168,12 @@ type RegistrationResponse struct {
 // This includes:
 // 1. Validating the registration token
 // 2. Validating input data (MAC address, GPS coordinates, firmware version)
-// 3. Checking if node already exists (re-registration case)
-// 4. Generating UUID and JWT secret for new nodes
-// 5. Creating/updating node in database
-// 6. Incrementing token usage count
-// 7. Generating JWT token for the node
+// 3. Refusing denylisted MAC addresses and manufacturers not on the OUI allowlist
+// 4. Checking if node already exists (re-registration case)
+// 5. Generating UUID and JWT secret for new nodes
+// 6. Creating/updating node in database
+// 7. Atomically consuming one token use (given back if the node write fails)
+// 8. Generating JWT token for the node
 func (s *NodeRegistrationService) RegisterNode(req *RegistrationRequest) (*RegistrationResponse, error) {
 	// Step 1: Validate input data
 	if err := s.validateRegistrationRequest(req); err != nil {
@@ -64,24 +183,57 @@ func (s *NodeRegistrationService) RegisterNode(req *RegistrationRequest) (*Regis
 	// Step 2: Normalize MAC address
 	normalizedMAC, err := validators.NormalizeMACAddress(req.MacAddress)
 	if err != nil {
-		return nil, fmt.Errorf("invalid MAC address: %w", err)
+		return nil, withCode(ErrCodeMACInvalid, fmt.Errorf("invalid MAC address: %w", err))
 	}
 	req.MacAddress = normalizedMAC
 
 	// Step 3: Validate registration token
 	token, err := s.tokenRepo.ValidateToken(req.RegistrationToken, &req.MacAddress)
+	if err != nil && s.config.FreePreAuthorizedReRegistration {
+		token, err = s.acceptExhaustedForOwnMAC(req, err)
+	}
+	s.metrics.Record(TokenValidationEndpointRegister, err, req.RegistrationToken, req.MacAddress)
 	if err != nil {
-		return nil, fmt.Errorf("invalid registration token: %w", err)
+		return nil, withCode(tokenErrorCode(err), fmt.Errorf("invalid registration token: %w", err))
 	}
 
-	// Step 4: Check if node already exists (re-registration case)
+	// Step 4: Refuse denylisted MACs and disallowed manufacturers, whether or not a node with the MAC still exists
+	if s.deniedRepo != nil {
+		denied, err := s.deniedRepo.IsDenied(req.MacAddress)
+		if err != nil {
+			return nil, err
+		}
+		if denied {
+			// The caller is unauthenticated, so the MAC is only logged, never echoed back
+			log.Printf("Warning: refused registration of denylisted MAC %s", req.MacAddress)
+			return nil, withCode(ErrCodeMACDenied, fmt.Errorf("mac address is denylisted"))
+		}
+	}
+	if len(s.allowedOUIs) > 0 {
+		oui, err := validators.MACAddressOUI(req.MacAddress)
+		if err != nil {
+			return nil, withCode(ErrCodeMACInvalid, fmt.Errorf("invalid MAC address: %w", err))
+		}
+		if !s.allowedOUIs[oui] {
+			return nil, withCode(ErrCodeOUINotAllowed, fmt.Errorf("mac address manufacturer is not allowed: OUI %s is not on the allowlist", oui))
+		}
+	}
+
+	// Step 5: Check if node already exists (re-registration case)
 	existingNode, err := s.nodeRepo.FindByMAC(req.MacAddress)
 	if err == nil {
-		// Node exists - handle re-registration
+		// Node exists - handle re-registration unless the token forbids it
+		if !token.AllowsReRegistration() {
+			return nil, withCode(ErrCodeNodeAlreadyRegistered, fmt.Errorf("node already registered: token does not allow re-registration"))
+		}
+		// A tenant's token must not take over another tenant's (or a tenant-less) node
+		if token.OwnerID != nil && (existingNode.OwnerID == nil || *existingNode.OwnerID != *token.OwnerID) {
+			return nil, withCode(ErrCodeOwnerMismatch, fmt.Errorf("node belongs to another owner: token provisions nodes for owner %s", *token.OwnerID))
+		}
 		return s.handleReRegistration(existingNode, req, token)
 	}
 
-	// Step 5: Node doesn't exist - create new node
+	// Step 6: Node doesn't exist - create new node
 	return s.handleNewRegistration(req, token)
 }
 
@@ -94,43 +246,55 @@ func (s *NodeRegistrationService) handleNewRegistration(
 	nodeUUID := uuid.New().String()
 
 	// Generate and encrypt JWT secret
-	jwtSecret, encryptedSecret, err := crypto.EncryptJWTSecret()
+	jwtSecret, encryptedSecret, err := crypto.EncryptJWTSecret(s.keyProvider)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate and encrypt JWT secret: %w", err)
 	}
 
 	// Create node model
+	now := time.Now().UTC()
 	node := &models.Node{
-		UUID:            nodeUUID,
-		MacAddress:      req.MacAddress,
-		JWTSecret:       encryptedSecret,
-		Status:          models.NodeStatusActive,
-		FirmwareVersion: req.FirmwareVersion,
-		Latitude:        req.Latitude,
-		Longitude:       req.Longitude,
-		LastSeenAt:      timePtr(time.Now().UTC()),
+		UUID:             nodeUUID,
+		MacAddress:       req.MacAddress,
+		JWTSecret:        encryptedSecret,
+		Status:           models.NodeStatusActive,
+		FirmwareVersion:  req.FirmwareVersion,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
+		OwnerID:          token.OwnerID,
+		LastIP:           normalizeClientIP(req.ClientIP),
+		LastSeenAt:       &now,
+		LastRegisteredAt: &now,
+	}
+
+	// Take one token use; this is the authoritative usage-limit check
+	if err := s.consumeTokenUse(req.RegistrationToken); err != nil {
+		return nil, err
 	}
 
 	// Save node to database
 	if err := s.nodeRepo.Create(node); err != nil {
+		s.releaseTokenUse(req.RegistrationToken, token)
 		return nil, fmt.Errorf("failed to create node: %w", err)
 	}
 
-	// Increment token usage count
-	if err := s.tokenRepo.IncrementUsedCount(req.RegistrationToken); err != nil {
-		// Log error but don't fail the registration
-		// The node is already created at this point
-		fmt.Printf("Warning: failed to increment token usage: %v\n", err)
-	}
+	s.recordEvent(nodeUUID, req.MacAddress, token, models.RegistrationEventRegistered)
+	s.broker.Publish(events.Event{
+		Type:       events.TypeNodeRegistered,
+		NodeUUID:   nodeUUID,
+		MacAddress: req.MacAddress,
+		Status:     node.Status,
+	})
 
 	// Generate JWT token for the node
-	jwtToken, expiresAt, err := s.generateNodeJWT(nodeUUID, jwtSecret)
+	jwtToken, expiresAt, err := s.generateNodeJWT(nodeUUID, jwtSecret, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
 	return &RegistrationResponse{
 		UUID:       nodeUUID,
+		JWTIssued:  true,
 		JWTToken:   jwtToken,
 		ExpiresAt:  expiresAt,
 		IsNewNode:  true,
@@ -146,11 +310,38 @@ func (s *NodeRegistrationService) handleReRegistration(
 ) (*RegistrationResponse, error) {
 	// Check if node is revoked
 	if existingNode.IsRevoked() {
-		return nil, fmt.Errorf("node is revoked and cannot be re-registered")
+		return nil, withCode(ErrCodeNodeRevoked, fmt.Errorf("node is revoked and cannot be re-registered"))
+	}
+
+	// Reject a flapping device before it consumes a token use or mints another JWT
+	now := time.Now().UTC()
+	if s.config.ReRegistrationCooldown > 0 && existingNode.LastRegisteredAt != nil {
+		if wait := existingNode.LastRegisteredAt.Add(s.config.ReRegistrationCooldown).Sub(now); wait > 0 {
+			return nil, withCode(ErrCodeRateLimited, withRetryAfter(wait, fmt.Errorf("rate limit exceeded: node re-registered too recently, retry in %d seconds", int(wait.Round(time.Second)/time.Second))))
+		}
+	}
+
+	// Apply the downgrade policy before any side effect, so a rejected downgrade uses no token
+	keepFirmware, err := s.checkFirmwareDowngrade(existingNode, req.FirmwareVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// In one-time mode the node already received its JWT; only an explicit force_reissue mints another
+	issueJWT := !s.config.OneTimeJWT || req.ForceReissue
+
+	// Decrypt the existing JWT secret before any side effect, so a key mismatch leaves the node and token untouched
+	var jwtSecret string
+	if issueJWT {
+		secret, err := decryptNodeSecretCached(s.keyProvider, s.secretCache, existingNode)
+		if err != nil {
+			return nil, err
+		}
+		jwtSecret = secret
 	}
 
 	// Update node information
-	if req.FirmwareVersion != nil {
+	if req.FirmwareVersion != nil && !keepFirmware {
 		existingNode.FirmwareVersion = req.FirmwareVersion
 	}
 	if req.Latitude != nil && req.Longitude != nil {
@@ -159,43 +350,137 @@ func (s *NodeRegistrationService) handleReRegistration(
 	}
 
 	// Set status to active if it was disabled
+	reactivated := false
 	if existingNode.IsDisabled() {
 		existingNode.Status = models.NodeStatusActive
+		reactivated = true
 	}
 
-	// Update last seen timestamp
-	now := time.Now().UTC()
-	existingNode.LastSeenAt = &now
+	// Re-registration is provisioning activity; LastSeenAt tracks authenticated requests only
+	existingNode.LastRegisteredAt = &now
+	if ip := normalizeClientIP(req.ClientIP); ip != nil {
+		existingNode.LastIP = ip
+	}
+
+	// Take one token use; this is the authoritative usage-limit check
+	// A token pre-authorized for this very MAC may re-register it for free
+	consumeUse := !s.isFreeReRegistration(token, req.MacAddress)
+	if consumeUse {
+		if err := s.consumeTokenUse(req.RegistrationToken); err != nil {
+			return nil, err
+		}
+	}
 
 	// Save updates
 	if err := s.nodeRepo.Update(existingNode); err != nil {
+		if consumeUse {
+			s.releaseTokenUse(req.RegistrationToken, token)
+		}
 		return nil, fmt.Errorf("failed to update node: %w", err)
 	}
 
-	// Increment token usage count
-	if err := s.tokenRepo.IncrementUsedCount(req.RegistrationToken); err != nil {
-		fmt.Printf("Warning: failed to increment token usage: %v\n", err)
+	s.recordEvent(existingNode.UUID, req.MacAddress, token, models.RegistrationEventReRegistered)
+	s.broker.Publish(events.Event{
+		Type:       events.TypeNodeReRegistered,
+		NodeUUID:   existingNode.UUID,
+		MacAddress: req.MacAddress,
+		Status:     existingNode.Status,
+	})
+	if reactivated {
+		s.broker.Publish(events.Event{
+			Type:       events.TypeNodeStatusChanged,
+			NodeUUID:   existingNode.UUID,
+			MacAddress: req.MacAddress,
+			Status:     existingNode.Status,
+		})
 	}
 
-	// Decrypt existing JWT secret
-	jwtSecret, err := crypto.DecryptJWTSecret(existingNode.JWTSecret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt JWT secret: %w", err)
+	response := &RegistrationResponse{
+		UUID:       existingNode.UUID,
+		IsNewNode:  false,
+		MacAddress: req.MacAddress,
+	}
+	if !issueJWT {
+		return response, nil
 	}
 
 	// Generate new JWT token with existing secret
-	jwtToken, expiresAt, err := s.generateNodeJWT(existingNode.UUID, jwtSecret)
+	jwtToken, expiresAt, err := s.generateNodeJWT(existingNode.UUID, jwtSecret, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
+	response.JWTIssued = true
+	response.JWTToken = jwtToken
+	response.ExpiresAt = expiresAt
 
-	return &RegistrationResponse{
-		UUID:       existingNode.UUID,
-		JWTToken:   jwtToken,
-		ExpiresAt:  expiresAt,
-		IsNewNode:  false,
-		MacAddress: req.MacAddress,
-	}, nil
+	return response, nil
+}
+
+// MACStatusResponse tells a provisioning tool whether a MAC is already registered
+// The node UUID is deliberately omitted so the endpoint cannot be used to map the fleet
+type MACStatusResponse struct {
+	MacAddress string  `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
+	Registered bool    `json:"registered" example:"true"`
+	Status     *string `json:"status,omitempty" example:"active"` // Only set when registered
+}
+
+// checkFirmwareDowngrade compares the reported firmware with the node's stored version
+// Returns true if the stored version must be kept, or an error if the policy rejects the downgrade.
+// Versions that do not parse as semantic versions are never treated as downgrades.
+func (s *NodeRegistrationService) checkFirmwareDowngrade(node *models.Node, reported *string) (bool, error) {
+	if reported == nil || node.FirmwareVersion == nil || *node.FirmwareVersion == "" {
+		return false, nil
+	}
+
+	cmp, err := validators.CompareSemanticVersions(*reported, *node.FirmwareVersion)
+	if err != nil || cmp >= 0 {
+		return false, nil
+	}
+
+	switch s.config.FirmwareDowngradePolicy {
+	case FirmwareDowngradeReject:
+		log.Printf("Warning: rejected re-registration of node %s: firmware downgrade from %s to %s", node.UUID, *node.FirmwareVersion, *reported)
+		return false, withCode(ErrCodeFirmwareDowngrade, fmt.Errorf("firmware downgrade rejected: reported version %s is older than %s", *reported, *node.FirmwareVersion))
+	case FirmwareDowngradeKeep:
+		log.Printf("Warning: node %s reported firmware downgrade from %s to %s; keeping %s", node.UUID, *node.FirmwareVersion, *reported, *node.FirmwareVersion)
+		return true, nil
+	default:
+		log.Printf("Warning: node %s reported firmware downgrade from %s to %s", node.UUID, *node.FirmwareVersion, *reported)
+		return false, nil
+	}
+}
+
+// CheckMACStatus reports whether a MAC is registered and its status
+// The registration token must be valid and, if pre-authorized, bound to this MAC,
+// so only holders of a usable token can query. No token use is consumed.
+func (s *NodeRegistrationService) CheckMACStatus(tokenValue, macAddress string) (*MACStatusResponse, error) {
+	if tokenValue == "" {
+		return nil, withCode(ErrCodeUnauthorized, fmt.Errorf("invalid registration token: token value is required"))
+	}
+
+	normalizedMAC, err := validators.NormalizeMACAddress(macAddress)
+	if err != nil {
+		return nil, withCode(ErrCodeMACInvalid, fmt.Errorf("invalid MAC address: %w", err))
+	}
+
+	_, err = s.tokenRepo.ValidateToken(tokenValue, &normalizedMAC)
+	s.metrics.Record(TokenValidationEndpointMACStatus, err, tokenValue, normalizedMAC)
+	if err != nil {
+		return nil, withCode(tokenErrorCode(err), fmt.Errorf("invalid registration token: %w", err))
+	}
+
+	response := &MACStatusResponse{MacAddress: normalizedMAC}
+	node, err := s.nodeRepo.FindByMAC(normalizedMAC)
+	if err != nil {
+		if strings.Contains(err.Error(), "node not found") {
+			return response, nil
+		}
+		return nil, err
+	}
+
+	response.Registered = true
+	response.Status = &node.Status
+	return response, nil
 }
 
 // validateRegistrationRequest validates all input data
@@ -207,11 +492,14 @@ func (s *NodeRegistrationService) validateRegistrationRequest(req *RegistrationR
 
 	// Validate MAC address
 	if err := validators.ValidateMACAddress(req.MacAddress, "mac_address"); err != nil {
-		return err
+		return withCode(ErrCodeMACInvalid, err)
 	}
 
 	// Validate firmware version if provided
 	if req.FirmwareVersion != nil && *req.FirmwareVersion != "" {
+		if err := validators.ValidateStringLength(*req.FirmwareVersion, "firmware_version", 0, validators.MaxFirmwareVersionLength); err != nil {
+			return err
+		}
 		if !validators.IsValidSemanticVersion(*req.FirmwareVersion) {
 			return fmt.Errorf("invalid firmware version format: %s", *req.FirmwareVersion)
 		}
@@ -231,12 +519,15 @@ func (s *NodeRegistrationService) validateRegistrationRequest(req *RegistrationR
 }
 
 // generateNodeJWT creates a JWT token for a node
+// The custom claims of the registration token used are signed into the JWT.
 // Returns the token string, expiration time as UTC string (RFC3339), and any error
-func (s *NodeRegistrationService) generateNodeJWT(nodeUUID string, jwtSecret string) (string, string, error) {
-	// JWT expires in 30 days
-	expiresIn := int64(30 * 24 * 60 * 60) // 30 days in seconds
+func (s *NodeRegistrationService) generateNodeJWT(nodeUUID string, jwtSecret string, regToken *models.RegistrationToken) (string, string, error) {
+	custom, err := regToken.ParsedCustomClaims()
+	if err != nil {
+		return "", "", err
+	}
 
-	token, expiresAtUnix, err := crypto.GenerateNodeJWT(nodeUUID, jwtSecret, time.Duration(expiresIn)*time.Second)
+	token, expiresAtUnix, err := crypto.GenerateNodeJWT(nodeUUID, jwtSecret, NodeJWTExpiration, custom)
 	if err != nil {
 		return "", "", err
 	}
@@ -247,7 +538,81 @@ func (s *NodeRegistrationService) generateNodeJWT(nodeUUID string, jwtSecret str
 	return token, expiresAt, nil
 }
 
-// Helper function to create a pointer to a time value
-func timePtr(t time.Time) *time.Time {
-	return &t
+// recordEvent stores a registration event for history and anomaly reports
+// Failures are logged but never fail the registration itself
+func (s *NodeRegistrationService) recordEvent(nodeUUID, macAddress string, token *models.RegistrationToken, eventType string) {
+	event := &models.RegistrationEvent{
+		ID:         uuid.New().String(),
+		NodeUUID:   nodeUUID,
+		MacAddress: macAddress,
+		TokenID:    token.ID,
+		EventType:  eventType,
+	}
+
+	if err := s.eventRepo.Create(event); err != nil {
+		fmt.Printf("Warning: failed to record %s event for node %s: %v\n", eventType, nodeUUID, err)
+	}
+}
+
+// normalizeClientIP returns the normalized client IP to store on a node, or nil if unknown
+// An unparsable address is logged and not stored rather than failing the registration.
+func normalizeClientIP(ip string) *string {
+	if ip == "" {
+		return nil
+	}
+
+	normalized, err := validators.NormalizeIP(ip)
+	if err != nil {
+		log.Printf("Warning: not recording invalid client IP %q: %v", ip, err)
+		return nil
+	}
+	return &normalized
+}
+
+// isFreeReRegistration reports whether re-registering macAddress with token consumes no use
+// See NodeRegistrationConfig.FreePreAuthorizedReRegistration
+func (s *NodeRegistrationService) isFreeReRegistration(token *models.RegistrationToken, macAddress string) bool {
+	return s.config.FreePreAuthorizedReRegistration &&
+		token.PreAuthorizedMacAddress != nil &&
+		token.CanBeUsedForMac(macAddress)
+}
+
+// acceptExhaustedForOwnMAC lets an exhausted token through when it is pre-authorized for the
+// request's MAC and that node is already registered, so the re-registration costs no use
+// Any other case returns validateErr unchanged.
+func (s *NodeRegistrationService) acceptExhaustedForOwnMAC(req *RegistrationRequest, validateErr error) (*models.RegistrationToken, error) {
+	if reason, ok := repositories.TokenRejectReasonOf(validateErr); !ok || reason != repositories.TokenRejectExhausted {
+		return nil, validateErr
+	}
+
+	token, err := s.tokenRepo.FindByToken(req.RegistrationToken)
+	if err != nil || !s.isFreeReRegistration(token, req.MacAddress) {
+		return nil, validateErr
+	}
+	if _, err := s.nodeRepo.FindByMAC(req.MacAddress); err != nil {
+		return nil, validateErr
+	}
+
+	return token, nil
+}
+
+// consumeTokenUse atomically takes one use of the registration token
+// ValidateToken only pre-checks the limit; this conditional update is what stops
+// concurrent registrations from exceeding it.
+func (s *NodeRegistrationService) consumeTokenUse(tokenValue string) error {
+	if err := s.tokenRepo.ConsumeUse(tokenValue); err != nil {
+		if strings.HasPrefix(err.Error(), "failed to") {
+			return err
+		}
+		return withCode(tokenErrorCode(err), fmt.Errorf("invalid registration token: %w", err))
+	}
+	return nil
+}
+
+// releaseTokenUse gives back a token use after the registration failed
+// Failures are logged; the use then stays consumed
+func (s *NodeRegistrationService) releaseTokenUse(tokenValue string, token *models.RegistrationToken) {
+	if err := s.tokenRepo.ReleaseUse(tokenValue); err != nil {
+		fmt.Printf("Warning: failed to release use of token %s: %v\n", token.ID, err)
+	}
 }
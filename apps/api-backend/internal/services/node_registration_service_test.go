@@ -0,0 +1,992 @@
+package services
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestNodeRegistrationService_CheckMACStatus tests that MAC status requires a usable token
+func TestNodeRegistrationService_CheckMACStatus(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, nil, nil, nil, nil)
+
+	if err := nodeRepo.Create(&models.Node{
+		UUID:       "550e8400-e29b-41d4-a716-446655440000",
+		MacAddress: "AA:BB:CC:DD:EE:01",
+		JWTSecret:  "secret",
+		Status:     models.NodeStatusDisabled,
+	}); err != nil {
+		t.Fatalf("Create() node error = %v", err)
+	}
+
+	boundMAC := "AA:BB:CC:DD:EE:02"
+	pastExpiry := time.Now().UTC().Add(-time.Hour)
+	tokens := []*models.RegistrationToken{
+		{ID: "open", Token: "open_token"},
+		{ID: "bound", Token: "bound_token", PreAuthorizedMacAddress: &boundMAC},
+	}
+	for _, token := range tokens {
+		if err := tokenRepo.Create(token); err != nil {
+			t.Fatalf("Create() token error = %v", err)
+		}
+	}
+	if err := tokenRepo.CreateAllowPastExpiry(&models.RegistrationToken{ID: "expired", Token: "expired_token", ExpiresAt: &pastExpiry}); err != nil {
+		t.Fatalf("CreateAllowPastExpiry() error = %v", err)
+	}
+
+	// Registered MAC, given in lowercase
+	status, err := service.CheckMACStatus("open_token", "aa:bb:cc:dd:ee:01")
+	if err != nil {
+		t.Fatalf("CheckMACStatus() error = %v", err)
+	}
+	if !status.Registered || status.Status == nil || *status.Status != models.NodeStatusDisabled || status.MacAddress != "AA:BB:CC:DD:EE:01" {
+		t.Errorf("CheckMACStatus() = %+v, want registered disabled AA:BB:CC:DD:EE:01", status)
+	}
+
+	// Unknown MAC
+	status, err = service.CheckMACStatus("open_token", "AA:BB:CC:DD:EE:99")
+	if err != nil {
+		t.Fatalf("CheckMACStatus() error = %v", err)
+	}
+	if status.Registered || status.Status != nil {
+		t.Errorf("CheckMACStatus() unknown MAC = %+v, want not registered", status)
+	}
+
+	// A pre-authorized token only answers for its own MAC
+	if _, err := service.CheckMACStatus("bound_token", boundMAC); err != nil {
+		t.Errorf("CheckMACStatus() own MAC error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		mac   string
+		code  ErrorCode
+	}{
+		{"missing token", "", "AA:BB:CC:DD:EE:01", ErrCodeUnauthorized},
+		{"unknown token", "nope", "AA:BB:CC:DD:EE:01", ErrCodeTokenNotFound},
+		{"expired token", "expired_token", "AA:BB:CC:DD:EE:01", ErrCodeTokenExpired},
+		{"other MAC", "bound_token", "AA:BB:CC:DD:EE:01", ErrCodeTokenMACMismatch},
+		{"invalid MAC", "open_token", "not-a-mac", ErrCodeMACInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CheckMACStatus(tt.token, tt.mac); ErrorCodeOf(err) != tt.code {
+				t.Errorf("CheckMACStatus() error = %v, want code %s", err, tt.code)
+			}
+		})
+	}
+
+	// Checking status never consumes a use
+	token, err := tokenRepo.FindByToken("open_token")
+	if err != nil {
+		t.Fatalf("FindByToken() error = %v", err)
+	}
+	if token.UsedCount != 0 {
+		t.Errorf("UsedCount = %d, want 0", token.UsedCount)
+	}
+}
+
+// TestNodeRegistrationService_ReRegistrationCooldown tests that rapid re-registrations are rejected without using the token
+func TestNodeRegistrationService_ReRegistrationCooldown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{
+		ReRegistrationCooldown: time.Minute,
+	})
+
+	maxUses := 10
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	register := func() (*RegistrationResponse, error) {
+		return service.RegisterNode(&RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01"})
+	}
+
+	first, err := register()
+	if err != nil || !first.IsNewNode {
+		t.Fatalf("RegisterNode() = %+v, %v, want new node", first, err)
+	}
+
+	// Rapid successive re-registrations are rate limited and leave the token untouched
+	for i := 0; i < 3; i++ {
+		_, err := register()
+		if err == nil || ErrorCodeOf(err) != ErrCodeRateLimited {
+			t.Fatalf("RegisterNode() within cooldown error = %v, want RATE_LIMITED", err)
+		}
+		if wait, ok := RetryAfterOf(err); !ok || wait <= 0 || wait > time.Minute {
+			t.Errorf("RetryAfterOf() = %s, %v, want a delay within the cooldown", wait, ok)
+		}
+	}
+	token, err := tokenRepo.FindByID("batch")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1", token.UsedCount)
+	}
+
+	// Once the cooldown has passed the node may re-register
+	past := time.Now().UTC().Add(-2 * time.Minute)
+	if err := db.Model(&models.Node{}).Where("uuid = ?", first.UUID).UpdateColumn("last_registered_at", past).Error; err != nil {
+		t.Fatalf("failed to backdate registration: %v", err)
+	}
+	second, err := register()
+	if err != nil || second.IsNewNode || second.UUID != first.UUID {
+		t.Fatalf("RegisterNode() after cooldown = %+v, %v, want re-registration of %s", second, err, first.UUID)
+	}
+
+	// Nodes registered before the timestamp was tracked are not held back
+	if err := db.Model(&models.Node{}).Where("uuid = ?", first.UUID).UpdateColumn("last_registered_at", nil).Error; err != nil {
+		t.Fatalf("failed to clear registration time: %v", err)
+	}
+	if _, err := register(); err != nil {
+		t.Errorf("RegisterNode() without last_registered_at error = %v", err)
+	}
+}
+
+// TestNodeRegistrationService_ReRegistrationTimestamps tests that re-registration moves LastRegisteredAt but not LastSeenAt
+func TestNodeRegistrationService_ReRegistrationTimestamps(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+
+	maxUses := 2
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	req := func() *RegistrationRequest {
+		return &RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01"}
+	}
+
+	created, err := service.RegisterNode(req())
+	if err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+
+	lastSeen := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	if err := db.Model(&models.Node{}).Where("uuid = ?", created.UUID).UpdateColumns(map[string]interface{}{
+		"last_seen_at":       lastSeen,
+		"last_registered_at": lastSeen,
+	}).Error; err != nil {
+		t.Fatalf("failed to backdate node: %v", err)
+	}
+
+	if _, err := service.RegisterNode(req()); err != nil {
+		t.Fatalf("RegisterNode() re-registration error = %v", err)
+	}
+	node, err := nodeRepo.FindByUUID(created.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if node.LastSeenAt == nil || !node.LastSeenAt.Equal(lastSeen) {
+		t.Errorf("LastSeenAt = %v, want unchanged %v", node.LastSeenAt, lastSeen)
+	}
+	if node.LastRegisteredAt == nil || time.Since(*node.LastRegisteredAt) > time.Minute {
+		t.Errorf("LastRegisteredAt = %v, want just now", node.LastRegisteredAt)
+	}
+	if got := toNodeResponse(node).LastRegisteredAt; got == nil || *got != node.LastRegisteredAt.UTC().Format(time.RFC3339) {
+		t.Errorf("NodeResponse.LastRegisteredAt = %v, want %s", got, node.LastRegisteredAt.UTC().Format(time.RFC3339))
+	}
+}
+
+// TestNodeRegistrationService_ReRegistrationNotAllowed tests that a token with allow_re_registration=false
+// rejects a MAC that is already registered without using the token
+func TestNodeRegistrationService_ReRegistrationNotAllowed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+
+	maxUses := 5
+	allow := false
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "strict", Token: "strict_token", UsageLimit: &maxUses, AllowReRegistration: &allow}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+
+	// The strict token still registers new nodes
+	if _, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: "strict_token", MacAddress: "AA:BB:CC:DD:EE:02"}); err != nil {
+		t.Fatalf("RegisterNode() new node error = %v", err)
+	}
+
+	existing, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01"})
+	if err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+	registeredAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	if err := db.Model(&models.Node{}).Where("uuid = ?", existing.UUID).UpdateColumn("last_registered_at", registeredAt).Error; err != nil {
+		t.Fatalf("failed to backdate registration: %v", err)
+	}
+
+	_, err = service.RegisterNode(&RegistrationRequest{RegistrationToken: "strict_token", MacAddress: "AA:BB:CC:DD:EE:01"})
+	if ErrorCodeOf(err) != ErrCodeNodeAlreadyRegistered {
+		t.Fatalf("RegisterNode() existing MAC error = %v, want NODE_ALREADY_REGISTERED", err)
+	}
+
+	token, err := tokenRepo.FindByID("strict")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1", token.UsedCount)
+	}
+	node, err := nodeRepo.FindByUUID(existing.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if node.LastRegisteredAt == nil || !node.LastRegisteredAt.Equal(registeredAt) {
+		t.Errorf("LastRegisteredAt = %v, want unchanged %v", node.LastRegisteredAt, registeredAt)
+	}
+}
+
+// TestNodeRegistrationService_EncryptionKeyMismatch tests that re-registration under a replaced
+// encryption key fails with a key mismatch and leaves the token use unconsumed
+func TestNodeRegistrationService_EncryptionKeyMismatch(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	eventRepo := repositories.NewRegistrationEventRepository(db)
+	oldKey := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	newKey := crypto.StaticKeyProvider(bytes.Repeat([]byte{8}, 32))
+
+	maxUses := 2
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	req := &RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01"}
+
+	if _, err := NewNodeRegistrationService(nodeRepo, tokenRepo, eventRepo, nil, nil, oldKey, nil, nil, &NodeRegistrationConfig{}).RegisterNode(req); err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+
+	_, err = NewNodeRegistrationService(nodeRepo, tokenRepo, eventRepo, nil, nil, newKey, nil, nil, &NodeRegistrationConfig{}).RegisterNode(req)
+	if ErrorCodeOf(err) != ErrCodeEncryptionKeyMismatch {
+		t.Fatalf("RegisterNode() with replaced key error = %v, want ENCRYPTION_KEY_MISMATCH", err)
+	}
+
+	token, err := tokenRepo.FindByID("batch")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1 (failed re-registration must not consume a use)", token.UsedCount)
+	}
+}
+
+// TestNodeRegistrationService_DeniedMAC tests that a denylisted MAC cannot register after its node
+// is deleted, without consuming a token use, and can again once removed from the denylist
+func TestNodeRegistrationService_DeniedMAC(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}, &models.DeniedMAC{}, &models.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	deniedRepo := repositories.NewDeniedMACRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), deniedRepo, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+	denylist := NewMACDenylistService(deniedRepo, NewAuditService(repositories.NewAuditLogRepository(db)))
+
+	maxUses := 5
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	req := func() *RegistrationRequest {
+		return &RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01"}
+	}
+
+	created, err := service.RegisterNode(req())
+	if err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+
+	// Revoke the node, ban its MAC and delete the node for good
+	if err := nodeRepo.UpdateStatus(created.UUID, models.NodeStatusRevoked); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if _, err := denylist.DenyMAC(&DenyMACRequest{MacAddress: "AA:BB:CC:DD:EE:01"}, "admin@example.com"); err != nil {
+		t.Fatalf("DenyMAC() error = %v", err)
+	}
+	if _, err := denylist.DenyMAC(&DenyMACRequest{MacAddress: "aa:bb:cc:dd:ee:01"}, "admin@example.com"); ErrorCodeOf(err) != ErrCodeConflict {
+		t.Errorf("DenyMAC() again error = %v, want CONFLICT", err)
+	}
+	if err := nodeRepo.HardDelete(created.UUID); err != nil {
+		t.Fatalf("HardDelete() error = %v", err)
+	}
+
+	_, err = service.RegisterNode(req())
+	if err == nil || ErrorCodeOf(err) != ErrCodeMACDenied {
+		t.Fatalf("RegisterNode() of denylisted MAC error = %v, want MAC_DENIED", err)
+	}
+	if strings.Contains(err.Error(), req().MacAddress) {
+		t.Errorf("RegisterNode() error %q echoes the MAC address", err.Error())
+	}
+	token, err := tokenRepo.FindByID("batch")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1 (refused registration must not consume a use)", token.UsedCount)
+	}
+
+	if err := denylist.AllowMAC("AA:BB:CC:DD:EE:01", "admin@example.com"); err != nil {
+		t.Fatalf("AllowMAC() error = %v", err)
+	}
+	if err := denylist.AllowMAC("AA:BB:CC:DD:EE:01", "admin@example.com"); ErrorCodeOf(err) != ErrCodeNotFound {
+		t.Errorf("AllowMAC() again error = %v, want NOT_FOUND", err)
+	}
+	if _, err := service.RegisterNode(req()); err != nil {
+		t.Errorf("RegisterNode() after removal from denylist error = %v", err)
+	}
+}
+
+// TestNodeRegistrationService_TokenValidationMetrics tests that token rejections are counted per reason and logged
+func TestNodeRegistrationService_TokenValidationMetrics(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	var logs bytes.Buffer
+	metrics := NewTokenValidationMetrics(slog.New(slog.NewTextHandler(&logs, nil)))
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(repositories.NewNodeRepository(db), tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, metrics, nil, &NodeRegistrationConfig{})
+
+	boundMAC := "AA:BB:CC:DD:EE:02"
+	maxUses := 1
+	pastExpiry := time.Now().UTC().Add(-time.Hour)
+	for _, token := range []*models.RegistrationToken{
+		{ID: "open", Token: "open_token", UsageLimit: &maxUses},
+		{ID: "bound", Token: "bound_token", PreAuthorizedMacAddress: &boundMAC},
+	} {
+		if err := tokenRepo.Create(token); err != nil {
+			t.Fatalf("Create() token error = %v", err)
+		}
+	}
+	if err := tokenRepo.CreateAllowPastExpiry(&models.RegistrationToken{ID: "expired", Token: "expired_token", ExpiresAt: &pastExpiry}); err != nil {
+		t.Fatalf("CreateAllowPastExpiry() error = %v", err)
+	}
+
+	register := func(token string) error {
+		_, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: token, MacAddress: "AA:BB:CC:DD:EE:01"})
+		return err
+	}
+	if err := register("open_token"); err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+	if err := register("open_token"); ErrorCodeOf(err) != ErrCodeTokenExhausted {
+		t.Errorf("RegisterNode() with used-up token error = %v, want TOKEN_EXHAUSTED", err)
+	}
+	if err := register("expired_token"); ErrorCodeOf(err) != ErrCodeTokenExpired {
+		t.Errorf("RegisterNode() with expired token error = %v, want TOKEN_EXPIRED", err)
+	}
+	if err := register("bound_token"); ErrorCodeOf(err) != ErrCodeTokenMACMismatch {
+		t.Errorf("RegisterNode() with token for another MAC error = %v, want TOKEN_MAC_MISMATCH", err)
+	}
+	if _, err := service.CheckMACStatus("guessed_token", "AA:BB:CC:DD:EE:01"); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("CheckMACStatus() with unknown token error = %v, want TOKEN_NOT_FOUND", err)
+	}
+
+	want := []TokenValidationCount{
+		{Endpoint: TokenValidationEndpointMACStatus, Outcome: "not_found", Count: 1},
+		{Endpoint: TokenValidationEndpointRegister, Outcome: "exhausted", Count: 1},
+		{Endpoint: TokenValidationEndpointRegister, Outcome: "expired", Count: 1},
+		{Endpoint: TokenValidationEndpointRegister, Outcome: "mac_mismatch", Count: 1},
+		{Endpoint: TokenValidationEndpointRegister, Outcome: TokenValidationOutcomeValid, Count: 1},
+	}
+	got := metrics.Counts()
+	if len(got) != len(want) {
+		t.Fatalf("Counts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Counts()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Rejections are logged at WARN without the raw token
+	if n := strings.Count(logs.String(), "level=WARN"); n != 4 {
+		t.Errorf("logged %d warnings, want 4:\n%s", n, logs.String())
+	}
+	if !strings.Contains(logs.String(), "reason=mac_mismatch") {
+		t.Errorf("logs lack the mac_mismatch reason:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), "guessed_token") {
+		t.Error("logs contain a raw token value")
+	}
+}
+
+// TestNodeRegistrationService_AllowedOUIs tests that only allowlisted manufacturers can register
+func TestNodeRegistrationService_AllowedOUIs(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	config := &NodeRegistrationConfig{AllowedOUIs: []string{"a4-cf-12", "240AC4"}}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := (&NodeRegistrationConfig{AllowedOUIs: []string{"A4:CF"}}).Validate(); err == nil {
+		t.Error("Validate() accepted a two-octet OUI")
+	}
+
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(repositories.NewNodeRepository(db), tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, config)
+
+	maxUses := 5
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	register := func(mac string) error {
+		_, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: "batch_token", MacAddress: mac})
+		return err
+	}
+
+	if err := register("A4:CF:12:00:00:01"); err != nil {
+		t.Errorf("RegisterNode() with allowed OUI error = %v", err)
+	}
+	if err := register("24:0A:C4:00:00:01"); err != nil {
+		t.Errorf("RegisterNode() with second allowed OUI error = %v", err)
+	}
+
+	err = register("AA:BB:CC:00:00:01")
+	if ErrorCodeOf(err) != ErrCodeOUINotAllowed {
+		t.Fatalf("RegisterNode() with other OUI error = %v, want OUI_NOT_ALLOWED", err)
+	}
+	token, err := tokenRepo.FindByID("batch")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 2 {
+		t.Errorf("UsedCount = %d, want 2 (refused registration must not consume a use)", token.UsedCount)
+	}
+}
+
+// TestNodeRegistrationService_OneTimeJWT tests that re-registration only returns a JWT when asked to in one-time mode
+func TestNodeRegistrationService_OneTimeJWT(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	config := &NodeRegistrationConfig{}
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, config)
+
+	maxUses := 10
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	firmware := "2.0.0"
+	register := func(forceReissue bool) *RegistrationResponse {
+		t.Helper()
+		response, err := service.RegisterNode(&RegistrationRequest{
+			RegistrationToken: "batch_token",
+			MacAddress:        "AA:BB:CC:DD:EE:01",
+			FirmwareVersion:   &firmware,
+			ForceReissue:      forceReissue,
+		})
+		if err != nil {
+			t.Fatalf("RegisterNode() error = %v", err)
+		}
+		return response
+	}
+
+	// By default every registration returns a JWT
+	first := register(false)
+	if !first.IsNewNode || !first.JWTIssued || first.JWTToken == "" {
+		t.Fatalf("RegisterNode() = %+v, want new node with JWT", first)
+	}
+	if again := register(false); !again.JWTIssued || again.JWTToken == "" {
+		t.Errorf("RegisterNode() re-registration = %+v, want JWT", again)
+	}
+
+	// In one-time mode a re-registration updates the node but returns no JWT
+	config.OneTimeJWT = true
+	firmware = "2.1.0"
+	quiet := register(false)
+	if quiet.JWTIssued || quiet.JWTToken != "" || quiet.ExpiresAt != "" {
+		t.Errorf("RegisterNode() one-time re-registration = %+v, want no JWT", quiet)
+	}
+	if quiet.UUID != first.UUID {
+		t.Errorf("RegisterNode() UUID = %s, want %s", quiet.UUID, first.UUID)
+	}
+	node, err := nodeRepo.FindByUUID(first.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if node.FirmwareVersion == nil || *node.FirmwareVersion != "2.1.0" {
+		t.Errorf("FirmwareVersion = %v, want 2.1.0", node.FirmwareVersion)
+	}
+
+	// force_reissue still returns a JWT
+	forced := register(true)
+	if !forced.JWTIssued || forced.JWTToken == "" || forced.ExpiresAt == "" {
+		t.Errorf("RegisterNode() with force_reissue = %+v, want JWT", forced)
+	}
+}
+
+// TestNodeRegistrationService_Owner tests that nodes inherit the token's owner and stay with it
+func TestNodeRegistrationService_Owner(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+
+	acme, globex := "acme", "globex"
+	for _, token := range []*models.RegistrationToken{
+		{ID: "acme", Token: "acme_token", OwnerID: &acme},
+		{ID: "globex", Token: "globex_token", OwnerID: &globex},
+		{ID: "global", Token: "global_token"},
+	} {
+		if err := tokenRepo.Create(token); err != nil {
+			t.Fatalf("Create() token error = %v", err)
+		}
+	}
+	register := func(tokenValue, mac string) (*RegistrationResponse, error) {
+		return service.RegisterNode(&RegistrationRequest{RegistrationToken: tokenValue, MacAddress: mac})
+	}
+
+	resp, err := register("acme_token", "AA:BB:CC:00:00:01")
+	if err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+	node, err := nodeRepo.FindByUUID(resp.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if node.OwnerID == nil || *node.OwnerID != acme {
+		t.Errorf("OwnerID = %v, want acme", node.OwnerID)
+	}
+
+	// Another tenant's token cannot take the node over
+	if _, err := register("globex_token", "AA:BB:CC:00:00:01"); ErrorCodeOf(err) != ErrCodeOwnerMismatch {
+		t.Errorf("RegisterNode() with other owner's token error = %v, want OWNER_MISMATCH", err)
+	}
+	// The owner's own token and a tenant-less token re-register it without changing the owner
+	for _, tokenValue := range []string{"acme_token", "global_token"} {
+		if _, err := register(tokenValue, "AA:BB:CC:00:00:01"); err != nil {
+			t.Errorf("RegisterNode() re-registration with %s error = %v", tokenValue, err)
+		}
+	}
+	// A tenant-less node cannot be claimed by a tenant's token
+	if _, err := register("global_token", "AA:BB:CC:00:00:02"); err != nil {
+		t.Fatalf("RegisterNode() error = %v", err)
+	}
+	if _, err := register("acme_token", "AA:BB:CC:00:00:02"); ErrorCodeOf(err) != ErrCodeOwnerMismatch {
+		t.Errorf("RegisterNode() claiming tenant-less node error = %v, want OWNER_MISMATCH", err)
+	}
+
+	nodes, total, err := nodeRepo.List("", acme, 0, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 1 || len(nodes) != 1 || nodes[0].UUID != resp.UUID {
+		t.Errorf("List(owner acme) = %d nodes (total %d), want only the acme node", len(nodes), total)
+	}
+}
+
+// TestNodeRegistrationService_FirmwareDowngrade tests upgrades, same versions and downgrades under each policy
+func TestNodeRegistrationService_FirmwareDowngrade(t *testing.T) {
+	if err := (&NodeRegistrationConfig{FirmwareDowngradePolicy: "block"}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown downgrade policy")
+	}
+
+	tests := []struct {
+		policy      string
+		reported    string
+		wantStored  string
+		wantErrCode ErrorCode
+	}{
+		{FirmwareDowngradeWarn, "1.3.0", "1.3.0", ""},
+		{FirmwareDowngradeWarn, "1.2.0", "1.2.0", ""},
+		{FirmwareDowngradeWarn, "1.1.0", "1.1.0", ""},
+		{FirmwareDowngradeKeep, "1.3.0", "1.3.0", ""},
+		{FirmwareDowngradeKeep, "1.2.0", "1.2.0", ""},
+		{FirmwareDowngradeKeep, "1.1.0", "1.2.0", ""},
+		{FirmwareDowngradeReject, "1.3.0", "1.3.0", ""},
+		{FirmwareDowngradeReject, "1.2.0", "1.2.0", ""},
+		{FirmwareDowngradeReject, "1.2.0-rc.1", "1.2.0", ErrCodeFirmwareDowngrade},
+		{FirmwareDowngradeReject, "1.1.0", "1.2.0", ErrCodeFirmwareDowngrade},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+" "+tt.reported, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to connect to test database: %v", err)
+			}
+			if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+				t.Fatalf("failed to migrate database: %v", err)
+			}
+
+			nodeRepo := repositories.NewNodeRepository(db)
+			tokenRepo := repositories.NewRegistrationTokenRepository(db)
+			keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+			service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil,
+				&NodeRegistrationConfig{FirmwareDowngradePolicy: tt.policy})
+
+			maxUses := 5
+			if err := tokenRepo.Create(&models.RegistrationToken{ID: "batch", Token: "batch_token", UsageLimit: &maxUses}); err != nil {
+				t.Fatalf("Create() token error = %v", err)
+			}
+			register := func(version string) (*RegistrationResponse, error) {
+				return service.RegisterNode(&RegistrationRequest{RegistrationToken: "batch_token", MacAddress: "AA:BB:CC:DD:EE:01", FirmwareVersion: &version})
+			}
+
+			first, err := register("1.2.0")
+			if err != nil {
+				t.Fatalf("RegisterNode() error = %v", err)
+			}
+			_, err = register(tt.reported)
+			if ErrorCodeOf(err) != tt.wantErrCode {
+				t.Fatalf("RegisterNode(%s) error = %v, want code %q", tt.reported, err, tt.wantErrCode)
+			}
+
+			node, err := nodeRepo.FindByUUID(first.UUID)
+			if err != nil {
+				t.Fatalf("FindByUUID() error = %v", err)
+			}
+			if node.FirmwareVersion == nil || *node.FirmwareVersion != tt.wantStored {
+				t.Errorf("stored firmware = %v, want %s", node.FirmwareVersion, tt.wantStored)
+			}
+
+			token, err := tokenRepo.FindByID("batch")
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			wantUses := 2
+			if tt.wantErrCode != "" {
+				wantUses = 1
+			}
+			if token.UsedCount != wantUses {
+				t.Errorf("UsedCount = %d, want %d", token.UsedCount, wantUses)
+			}
+		})
+	}
+}
+
+// TestNodeRegistrationService_FreePreAuthorizedReRegistration tests that a single-use token
+// pre-authorized for a MAC keeps re-registering that node without consuming uses when enabled
+func TestNodeRegistrationService_FreePreAuthorizedReRegistration(t *testing.T) {
+	for name, enabled := range map[string]bool{"disabled": false, "enabled": true} {
+		t.Run(name, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to connect to test database: %v", err)
+			}
+			if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+				t.Fatalf("failed to migrate database: %v", err)
+			}
+
+			nodeRepo := repositories.NewNodeRepository(db)
+			tokenRepo := repositories.NewRegistrationTokenRepository(db)
+			keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+			service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil,
+				&NodeRegistrationConfig{FreePreAuthorizedReRegistration: enabled})
+
+			singleUse := 1
+			mac := "AA:BB:CC:DD:EE:01"
+			if err := tokenRepo.Create(&models.RegistrationToken{ID: "bound", Token: "bound_token", UsageLimit: &singleUse, PreAuthorizedMacAddress: &mac}); err != nil {
+				t.Fatalf("Create() token error = %v", err)
+			}
+			register := func() (*RegistrationResponse, error) {
+				return service.RegisterNode(&RegistrationRequest{RegistrationToken: "bound_token", MacAddress: mac})
+			}
+
+			// The first registration always takes the only use
+			if _, err := register(); err != nil {
+				t.Fatalf("RegisterNode() error = %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				response, err := register()
+				if !enabled {
+					if ErrorCodeOf(err) != ErrCodeTokenExhausted {
+						t.Fatalf("RegisterNode() re-registration error = %v, want TOKEN_EXHAUSTED", err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("RegisterNode() re-registration #%d error = %v", i+1, err)
+				}
+				if response.IsNewNode || !response.JWTIssued {
+					t.Errorf("RegisterNode() re-registration = %+v, want existing node with JWT", response)
+				}
+			}
+
+			token, err := tokenRepo.FindByToken("bound_token")
+			if err != nil {
+				t.Fatalf("FindByToken() error = %v", err)
+			}
+			if token.UsedCount != 1 {
+				t.Errorf("UsedCount = %d, want 1", token.UsedCount)
+			}
+		})
+	}
+}
+
+// TestNodeRegistrationService_LastIP tests that registrations record the normalized client IP
+func TestNodeRegistrationService_LastIP(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "unlimited", Token: "unlimited_token"}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+
+	register := func(clientIP string) *models.Node {
+		t.Helper()
+		response, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: "unlimited_token", MacAddress: "AA:BB:CC:DD:EE:01", ClientIP: clientIP})
+		if err != nil {
+			t.Fatalf("RegisterNode(%q) error = %v", clientIP, err)
+		}
+		node, err := nodeRepo.FindByUUID(response.UUID)
+		if err != nil {
+			t.Fatalf("FindByUUID() error = %v", err)
+		}
+		return node
+	}
+
+	tests := []struct {
+		clientIP string
+		want     string
+	}{
+		{"::ffff:203.0.113.7", "203.0.113.7"}, // first registration
+		{"2001:0DB8::0001", "2001:db8::1"},    // re-registration from a new network
+		{"not-an-ip", "2001:db8::1"},          // unparsable: previous value kept
+		{"", "2001:db8::1"},                   // unknown: previous value kept
+	}
+	for _, tt := range tests {
+		node := register(tt.clientIP)
+		if node.LastIP == nil || *node.LastIP != tt.want {
+			t.Errorf("after RegisterNode(%q) LastIP = %v, want %q", tt.clientIP, node.LastIP, tt.want)
+		}
+		if got := toNodeResponse(node).LastIP; got == nil || *got != tt.want {
+			t.Errorf("NodeResponse.LastIP = %v, want %q", got, tt.want)
+		}
+	}
+}
+
+// TestNodeRegistrationService_CustomClaims tests that node JWTs carry the custom claims of the token used
+func TestNodeRegistrationService_CustomClaims(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(nodeRepo, tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+
+	siteClaims := `{"site_id":"berlin-1","role":"sensor"}`
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "site", Token: "site_token", CustomClaims: &siteClaims}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "plain", Token: "plain_token"}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+
+	register := func(registrationToken string) *crypto.NodeClaims {
+		t.Helper()
+		response, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: registrationToken, MacAddress: "AA:BB:CC:DD:EE:01"})
+		if err != nil {
+			t.Fatalf("RegisterNode(%s) error = %v", registrationToken, err)
+		}
+		node, err := nodeRepo.FindByUUID(response.UUID)
+		if err != nil {
+			t.Fatalf("FindByUUID() error = %v", err)
+		}
+		secret, err := crypto.DecryptJWTSecret(keyProvider, node.JWTSecret)
+		if err != nil {
+			t.Fatalf("DecryptJWTSecret() error = %v", err)
+		}
+		claims, err := crypto.VerifyNodeJWT(response.JWTToken, secret)
+		if err != nil {
+			t.Fatalf("VerifyNodeJWT() error = %v", err)
+		}
+		return claims
+	}
+
+	claims := register("site_token")
+	if claims.Custom["site_id"] != "berlin-1" || claims.Custom["role"] != "sensor" || claims.NodeUUID == "" {
+		t.Errorf("claims with custom claims = %+v, want site_id and role under ext", claims)
+	}
+
+	// Re-registering with another token issues a JWT with that token's claims
+	if claims := register("plain_token"); claims.Custom != nil {
+		t.Errorf("Custom after re-registration with plain token = %v, want none", claims.Custom)
+	}
+}
+
+// TestNodeRegistrationService_DistinctMacLimit tests that a token stops accepting new MACs at its distinct MAC limit
+func TestNodeRegistrationService_DistinctMacLimit(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	service := NewNodeRegistrationService(repositories.NewNodeRepository(db), tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+	limit := 2
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "capped", Token: "capped_token", DistinctMacLimit: &limit}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		mac      string
+		wantCode ErrorCode
+	}{
+		{"first device", "AA:BB:CC:DD:EE:01", ""},
+		{"second device", "AA:BB:CC:DD:EE:02", ""},
+		{"third device over the limit", "AA:BB:CC:DD:EE:03", ErrCodeTokenMACLimit},
+		{"known device re-registers", "AA:BB:CC:DD:EE:01", ""},
+		{"third device still refused", "AA:BB:CC:DD:EE:03", ErrCodeTokenMACLimit},
+	}
+	for _, tt := range tests {
+		_, err := service.RegisterNode(&RegistrationRequest{RegistrationToken: "capped_token", MacAddress: tt.mac})
+		if tt.wantCode == "" && err != nil {
+			t.Errorf("%s: RegisterNode() error = %v", tt.name, err)
+		}
+		if tt.wantCode != "" && ErrorCodeOf(err) != tt.wantCode {
+			t.Errorf("%s: RegisterNode() error = %v, want %s", tt.name, err, tt.wantCode)
+		}
+	}
+
+	token, err := tokenRepo.FindByID("capped")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 3 {
+		t.Errorf("UsedCount = %d, want 3 (refused registrations use nothing)", token.UsedCount)
+	}
+}
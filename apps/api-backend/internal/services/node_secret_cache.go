@@ -0,0 +1,167 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// NodeSecretCacheConfig holds settings for the decrypted node secret cache
+type NodeSecretCacheConfig struct {
+	// MaxEntries caps how many decrypted secrets are held; zero or negative disables the cache
+	MaxEntries int
+
+	// TTL is how long a decrypted secret is reused before it is decrypted again
+	TTL time.Duration
+}
+
+// DefaultNodeSecretCacheConfig returns the default cache settings
+// (disabled, since the cache holds plaintext secrets in memory; 5-minute TTL when enabled)
+func DefaultNodeSecretCacheConfig() *NodeSecretCacheConfig {
+	return &NodeSecretCacheConfig{
+		MaxEntries: 0,
+		TTL:        5 * time.Minute,
+	}
+}
+
+// NodeSecretCache is a bounded LRU cache of decrypted node JWT secrets keyed by node UUID
+// It spares the AES-GCM decryption on every node request. Each entry remembers the
+// ciphertext it was decrypted from, so a node whose stored secret changed (rotation,
+// re-import) misses and is decrypted afresh. Safe for concurrent use.
+type NodeSecretCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+// nodeSecretEntry is one cached secret
+type nodeSecretEntry struct {
+	uuid       string
+	ciphertext string
+	secret     string
+	expiresAt  time.Time
+}
+
+// NewNodeSecretCache creates a cache, or returns nil when config disables it
+// A nil cache is valid and never holds anything. If config is nil, DefaultNodeSecretCacheConfig is used.
+func NewNodeSecretCache(config *NodeSecretCacheConfig) *NodeSecretCache {
+	if config == nil {
+		config = DefaultNodeSecretCacheConfig()
+	}
+	if config.MaxEntries <= 0 || config.TTL <= 0 {
+		return nil
+	}
+
+	return &NodeSecretCache{
+		maxEntries: config.MaxEntries,
+		ttl:        config.TTL,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached secret of a node if it was decrypted from the same ciphertext and has not expired
+func (c *NodeSecretCache) Get(nodeUUID, ciphertext string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[nodeUUID]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*nodeSecretEntry)
+	if entry.ciphertext != ciphertext || !c.now().Before(entry.expiresAt) {
+		c.removeElement(element)
+		return "", false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.secret, true
+}
+
+// Put stores a decrypted secret, evicting the least recently used entry when full
+func (c *NodeSecretCache) Put(nodeUUID, ciphertext, secret string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[nodeUUID]; ok {
+		entry := element.Value.(*nodeSecretEntry)
+		entry.ciphertext = ciphertext
+		entry.secret = secret
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[nodeUUID] = c.order.PushFront(&nodeSecretEntry{
+		uuid:       nodeUUID,
+		ciphertext: ciphertext,
+		secret:     secret,
+		expiresAt:  expiresAt,
+	})
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Invalidate drops the cached secret of a node
+func (c *NodeSecretCache) Invalidate(nodeUUID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[nodeUUID]; ok {
+		c.removeElement(element)
+	}
+}
+
+// Len returns the number of cached secrets
+func (c *NodeSecretCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// removeElement deletes an entry; the caller holds mu
+func (c *NodeSecretCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*nodeSecretEntry).uuid)
+}
+
+// decryptNodeSecretCached returns a node's JWT secret from cache, decrypting and caching it on a miss
+func decryptNodeSecretCached(provider crypto.KeyProvider, cache *NodeSecretCache, node *models.Node) (string, error) {
+	if secret, ok := cache.Get(node.UUID, node.JWTSecret); ok {
+		return secret, nil
+	}
+
+	secret, err := decryptNodeSecret(provider, node)
+	if err != nil {
+		return "", err
+	}
+	cache.Put(node.UUID, node.JWTSecret, secret)
+	return secret, nil
+}
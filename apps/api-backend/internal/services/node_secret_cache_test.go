@@ -0,0 +1,162 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestNodeSecretCache tests LRU eviction, expiry and invalidation by changed ciphertext
+func TestNodeSecretCache(t *testing.T) {
+	if NewNodeSecretCache(nil) != nil {
+		t.Fatal("NewNodeSecretCache() with default config should be disabled")
+	}
+	var disabled *NodeSecretCache
+	disabled.Put("a", "ct-a", "secret-a")
+	if _, ok := disabled.Get("a", "ct-a"); ok {
+		t.Error("disabled cache returned a secret")
+	}
+
+	cache := NewNodeSecretCache(&NodeSecretCacheConfig{MaxEntries: 2, TTL: time.Minute})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Put("a", "ct-a", "secret-a")
+	cache.Put("b", "ct-b", "secret-b")
+	if secret, ok := cache.Get("a", "ct-a"); !ok || secret != "secret-a" {
+		t.Errorf("Get(a) = %q, %t, want secret-a", secret, ok)
+	}
+
+	// b is now least recently used and is evicted by c
+	cache.Put("c", "ct-c", "secret-c")
+	if _, ok := cache.Get("b", "ct-b"); ok {
+		t.Error("Get(b) hit after eviction")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+
+	// A rotated secret has new ciphertext and misses
+	if _, ok := cache.Get("a", "ct-a-rotated"); ok {
+		t.Error("Get(a) hit with changed ciphertext")
+	}
+	if _, ok := cache.Get("a", "ct-a"); ok {
+		t.Error("Get(a) hit after the stale entry was dropped")
+	}
+
+	cache.Invalidate("c")
+	if _, ok := cache.Get("c", "ct-c"); ok {
+		t.Error("Get(c) hit after Invalidate")
+	}
+
+	cache.Put("d", "ct-d", "secret-d")
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("d", "ct-d"); ok {
+		t.Error("Get(d) hit after TTL")
+	}
+}
+
+// TestNodeAuthService_SecretCache tests that cached authentication still follows a rotated secret
+func TestNodeAuthService_SecretCache(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	nodeRepo := repositories.NewNodeRepository(db)
+	cache := NewNodeSecretCache(&NodeSecretCacheConfig{MaxEntries: 10, TTL: time.Minute})
+	service := NewNodeAuthService(nodeRepo, nil, keyProvider, cache, nil)
+
+	nodeUUID := "550e8400-e29b-41d4-a716-446655440000"
+	issue := func() (string, string) {
+		t.Helper()
+		secret, encrypted, err := crypto.EncryptJWTSecret(keyProvider)
+		if err != nil {
+			t.Fatalf("EncryptJWTSecret() error = %v", err)
+		}
+		token, _, err := crypto.GenerateNodeJWT(nodeUUID, secret, time.Hour, nil)
+		if err != nil {
+			t.Fatalf("GenerateNodeJWT() error = %v", err)
+		}
+		return encrypted, token
+	}
+
+	encrypted, oldToken := issue()
+	if err := nodeRepo.Create(&models.Node{UUID: nodeUUID, MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: encrypted, Status: models.NodeStatusActive}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.Authenticate(oldToken); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("cache Len() = %d, want 1", cache.Len())
+	}
+
+	// Rotate the stored secret: the cached one must no longer verify tokens
+	rotated, newToken := issue()
+	if err := db.Model(&models.Node{}).Where("uuid = ?", nodeUUID).Update("jwt_secret", rotated).Error; err != nil {
+		t.Fatalf("failed to rotate secret: %v", err)
+	}
+	if _, err := service.Authenticate(oldToken); err == nil {
+		t.Error("Authenticate() accepted a token signed with the rotated-out secret")
+	}
+	if _, err := service.Authenticate(newToken); err != nil {
+		t.Errorf("Authenticate() with new secret error = %v", err)
+	}
+}
+
+// BenchmarkNodeRegistrationService_ReRegister compares re-registration with and without the secret cache
+func BenchmarkNodeRegistrationService_ReRegister(b *testing.B) {
+	for _, bench := range []struct {
+		name  string
+		cache *NodeSecretCache
+	}{
+		{"uncached", nil},
+		{"cached", NewNodeSecretCache(&NodeSecretCacheConfig{MaxEntries: 100, TTL: time.Minute})},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				b.Fatalf("failed to connect to test database: %v", err)
+			}
+			if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}); err != nil {
+				b.Fatalf("failed to migrate database: %v", err)
+			}
+
+			tokenRepo := repositories.NewRegistrationTokenRepository(db)
+			keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+			service := NewNodeRegistrationService(repositories.NewNodeRepository(db), tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, bench.cache, &NodeRegistrationConfig{})
+			if err := tokenRepo.Create(&models.RegistrationToken{ID: "unlimited", Token: "unlimited_token"}); err != nil {
+				b.Fatalf("Create() token error = %v", err)
+			}
+			req := func() *RegistrationRequest {
+				return &RegistrationRequest{RegistrationToken: "unlimited_token", MacAddress: "AA:BB:CC:DD:EE:01"}
+			}
+			if _, err := service.RegisterNode(req()); err != nil {
+				b.Fatalf("RegisterNode() error = %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := service.RegisterNode(req()); err != nil {
+					b.Fatalf("RegisterNode() error = %v", err)
+				}
+			}
+		})
+	}
+}
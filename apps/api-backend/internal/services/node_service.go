@@ -0,0 +1,926 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/events"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/validators"
+	"github.com/google/uuid"
+)
+
+// NodeConfig holds policy applied when admins change nodes
+type NodeConfig struct {
+	// UniqueNodeNames rejects a name already used by another active node
+	UniqueNodeNames bool
+}
+
+// DefaultNodeConfig returns the default policy (names are not unique)
+func DefaultNodeConfig() *NodeConfig {
+	return &NodeConfig{
+		UniqueNodeNames: false,
+	}
+}
+
+// Node list page size limits
+const (
+	DefaultNodeListPageSize = 50
+	MaxNodeListPageSize     = 200
+)
+
+// NodeService handles the business logic for admin node operations
+type NodeService struct {
+	nodeRepo     *repositories.NodeRepository
+	eventRepo    *repositories.RegistrationEventRepository
+	broker       *events.Broker
+	auditService *AuditService
+	keyProvider  crypto.KeyProvider
+	secretCache  *NodeSecretCache
+	config       *NodeConfig
+}
+
+// NewNodeService creates a new node service instance
+// broker may be nil if live event streaming is not needed
+// auditService may be nil, in which case status changes are only logged
+// If keyProvider is nil, the key is read from the environment
+// secretCache may be nil; when set, revoked and deleted nodes are evicted from it
+// If config is nil, DefaultNodeConfig is used
+func NewNodeService(
+	nodeRepo *repositories.NodeRepository,
+	eventRepo *repositories.RegistrationEventRepository,
+	broker *events.Broker,
+	auditService *AuditService,
+	keyProvider crypto.KeyProvider,
+	secretCache *NodeSecretCache,
+	config *NodeConfig,
+) *NodeService {
+	if keyProvider == nil {
+		keyProvider = crypto.EnvKeyProvider{}
+	}
+	if config == nil {
+		config = DefaultNodeConfig()
+	}
+
+	return &NodeService{
+		nodeRepo:     nodeRepo,
+		eventRepo:    eventRepo,
+		broker:       broker,
+		auditService: auditService,
+		keyProvider:  keyProvider,
+		secretCache:  secretCache,
+		config:       config,
+	}
+}
+
+// NodeListQuery filters and pages the node list
+type NodeListQuery struct {
+	Status   string `form:"status" example:"active"` // Optional: active, disabled or revoked
+	OwnerID  string `form:"owner_id" example:"acme"` // Optional: only nodes of this tenant
+	Page     int    `form:"page" example:"1"`        // 1-based, defaults to 1
+	PageSize int    `form:"page_size" example:"50"`  // Defaults to 50, at most 200
+}
+
+// NodeListResponse is one page of nodes, newest first
+type NodeListResponse struct {
+	Nodes    []*NodeResponse `json:"nodes"`
+	Page     int             `json:"page" example:"1"`
+	PageSize int             `json:"page_size" example:"50"`
+	// Total is the number of matching nodes across all pages
+	Total int64 `json:"total" example:"1"`
+}
+
+// UpdateNodeStatusRequest changes the status of a node
+type UpdateNodeStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"disabled"` // active, disabled or revoked
+}
+
+// UpdateNodeLocationRequest sets the GPS coordinates of a node
+type UpdateNodeLocationRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required" example:"50.0755"`
+	Longitude *float64 `json:"longitude" binding:"required" example:"14.4378"`
+}
+
+// ListNodes returns one page of nodes, optionally filtered by status and owner
+func (s *NodeService) ListNodes(query *NodeListQuery) (*NodeListResponse, error) {
+	page, pageSize, err := resolveNodePage(query.Page, query.PageSize)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+	if query.Status != "" {
+		if err := validators.ValidateNodeStatus(query.Status, "status"); err != nil {
+			return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+		}
+	}
+
+	if query.OwnerID != "" {
+		if err := validators.ValidateOwnerID(query.OwnerID, "owner_id"); err != nil {
+			return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+		}
+	}
+
+	nodes, total, err := s.nodeRepo.List(query.Status, query.OwnerID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*NodeResponse, len(nodes))
+	for i, node := range nodes {
+		responses[i] = toNodeResponse(node)
+	}
+
+	return &NodeListResponse{
+		Nodes:    responses,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+// resolveNodePage applies the page defaults and limits of the node list
+func resolveNodePage(page, pageSize int) (int, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return 0, 0, fmt.Errorf("page must be at least 1")
+	}
+
+	if pageSize == 0 {
+		pageSize = DefaultNodeListPageSize
+	}
+	if pageSize < 1 || pageSize > MaxNodeListPageSize {
+		return 0, 0, fmt.Errorf("page_size must be between 1 and %d", MaxNodeListPageSize)
+	}
+
+	return page, pageSize, nil
+}
+
+// GetNode looks up a node by UUID
+func (s *NodeService) GetNode(uuid string) (*NodeResponse, error) {
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return toNodeResponse(node), nil
+}
+
+// UpdateStatus changes the status of a node
+// Revocation is permanent: a revoked node cannot be set back to active or disabled.
+func (s *NodeService) UpdateStatus(uuid string, req *UpdateNodeStatusRequest, changedBy string) (*NodeResponse, error) {
+	if err := validators.ValidateNodeStatus(req.Status, "status"); err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.IsRevoked() && req.Status != models.NodeStatusRevoked {
+		return nil, withCode(ErrCodeNodeRevoked, fmt.Errorf("node is revoked and cannot be set to %s", req.Status))
+	}
+
+	if err := s.nodeRepo.UpdateStatus(node.UUID, req.Status); err != nil {
+		return nil, err
+	}
+	if node.Status != req.Status {
+		s.statusChanged(node, node.Status, req.Status, changedBy)
+	}
+
+	return s.GetNode(node.UUID)
+}
+
+// UpdateLocation sets the GPS coordinates of a node
+func (s *NodeService) UpdateLocation(uuid string, req *UpdateNodeLocationRequest) (*NodeResponse, error) {
+	if req.Latitude == nil || req.Longitude == nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: latitude and longitude are required"))
+	}
+	if err := validators.ValidateGPSCoordinates(*req.Latitude, *req.Longitude); err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.nodeRepo.UpdateLocation(node.UUID, *req.Latitude, *req.Longitude); err != nil {
+		return nil, err
+	}
+
+	return s.GetNode(node.UUID)
+}
+
+// DefaultReRegistrationThreshold is the re-registration count at which a MAC is reported as anomalous
+const DefaultReRegistrationThreshold = 5
+
+// NodeResponse contains node information returned to admins
+// The encrypted JWT secret is never included
+type NodeResponse struct {
+	UUID                  string   `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MacAddress            string   `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
+	Name                  *string  `json:"name,omitempty" example:"Living Room Sensor"`
+	FirmwareVersion       *string  `json:"firmware_version,omitempty" example:"1.0.0"`
+	TargetFirmwareVersion *string  `json:"target_firmware_version,omitempty" example:"1.1.0"`
+	Latitude              *float64 `json:"latitude,omitempty" example:"50.0755"`
+	Longitude             *float64 `json:"longitude,omitempty" example:"14.4378"`
+	OwnerID               *string  `json:"owner_id,omitempty" example:"acme"`
+	LastIP                *string  `json:"last_ip,omitempty" example:"203.0.113.7"` // Client IP of the last registration
+	Status                string   `json:"status" example:"active"`
+	LastSeenAt            *string  `json:"last_seen_at,omitempty" example:"2025-11-10T14:30:00Z"`       // Last authenticated request
+	LastRegisteredAt      *string  `json:"last_registered_at,omitempty" example:"2025-11-10T14:30:00Z"` // Last registration or re-registration
+	CreatedAt             string   `json:"created_at" example:"2025-11-10T14:30:00Z"`
+	UpdatedAt             string   `json:"updated_at" example:"2025-11-10T14:30:00Z"`
+
+	// Metadata is only included when explicitly requested
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NodeGroup is a set of nodes sharing the same value for a reported attribute
+type NodeGroup struct {
+	Key   string          `json:"key" example:"50.075500,14.437800"`
+	Count int             `json:"count" example:"2"`
+	Nodes []*NodeResponse `json:"nodes"`
+}
+
+// DuplicateReportResponse lists nodes that look like duplicates or misbehaving devices
+type DuplicateReportResponse struct {
+	SharedCoordinatesCount       int                           `json:"shared_coordinates_count" example:"2"`
+	SharedCoordinates            []*NodeGroup                  `json:"shared_coordinates"`
+	DuplicateNamesCount          int                           `json:"duplicate_names_count" example:"0"`
+	DuplicateNames               []*NodeGroup                  `json:"duplicate_names"`
+	FrequentReRegistrationsCount int                           `json:"frequent_re_registrations_count" example:"1"`
+	FrequentReRegistrations      []*repositories.MACEventCount `json:"frequent_re_registrations"`
+	ReRegistrationThreshold      int                           `json:"re_registration_threshold" example:"5"`
+}
+
+// GetDuplicateReport collects nodes sharing coordinates, nodes sharing a name,
+// and MAC addresses that re-registered at least reRegistrationThreshold times
+func (s *NodeService) GetDuplicateReport(reRegistrationThreshold int) (*DuplicateReportResponse, error) {
+	if reRegistrationThreshold < 1 {
+		return nil, fmt.Errorf("validation failed: re_registration_threshold must be at least 1")
+	}
+
+	sharedCoordinates, err := s.nodeRepo.FindSharedCoordinates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shared coordinates: %w", err)
+	}
+
+	duplicateNames, err := s.nodeRepo.FindDuplicateNames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate names: %w", err)
+	}
+
+	frequent, err := s.eventRepo.FindFrequentReRegistrations(reRegistrationThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find frequent re-registrations: %w", err)
+	}
+	if frequent == nil {
+		frequent = []*repositories.MACEventCount{}
+	}
+
+	return &DuplicateReportResponse{
+		SharedCoordinatesCount: len(sharedCoordinates),
+		SharedCoordinates: groupNodes(sharedCoordinates, func(n *models.Node) string {
+			return fmt.Sprintf("%f,%f", *n.Latitude, *n.Longitude)
+		}),
+		DuplicateNamesCount: len(duplicateNames),
+		DuplicateNames: groupNodes(duplicateNames, func(n *models.Node) string {
+			return *n.Name
+		}),
+		FrequentReRegistrationsCount: len(frequent),
+		FrequentReRegistrations:      frequent,
+		ReRegistrationThreshold:      reRegistrationThreshold,
+	}, nil
+}
+
+// groupNodes groups consecutive nodes with the same key
+// Input must already be ordered by the grouping attribute
+func groupNodes(nodes []*models.Node, key func(*models.Node) string) []*NodeGroup {
+	groups := []*NodeGroup{}
+	var current *NodeGroup
+	for _, node := range nodes {
+		k := key(node)
+		if current == nil || current.Key != k {
+			current = &NodeGroup{Key: k}
+			groups = append(groups, current)
+		}
+		current.Nodes = append(current.Nodes, toNodeResponse(node))
+		current.Count++
+	}
+	return groups
+}
+
+// toNodeResponse converts a node model to its admin response format
+func toNodeResponse(node *models.Node) *NodeResponse {
+	var lastSeenAt *string
+	if node.LastSeenAt != nil {
+		formatted := node.LastSeenAt.UTC().Format(time.RFC3339)
+		lastSeenAt = &formatted
+	}
+	var lastRegisteredAt *string
+	if node.LastRegisteredAt != nil {
+		formatted := node.LastRegisteredAt.UTC().Format(time.RFC3339)
+		lastRegisteredAt = &formatted
+	}
+
+	return &NodeResponse{
+		UUID:                  node.UUID,
+		MacAddress:            node.MacAddress,
+		Name:                  node.Name,
+		FirmwareVersion:       node.FirmwareVersion,
+		TargetFirmwareVersion: node.TargetFirmwareVersion,
+		Latitude:              node.Latitude,
+		Longitude:             node.Longitude,
+		OwnerID:               node.OwnerID,
+		LastIP:                node.LastIP,
+		Status:                node.Status,
+		LastSeenAt:            lastSeenAt,
+		LastRegisteredAt:      lastRegisteredAt,
+		CreatedAt:             node.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:             node.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// UnknownFirmwareVersion is the firmware distribution bucket for nodes that never reported a version
+const UnknownFirmwareVersion = "unknown"
+
+// FirmwareDistributionResponse maps firmware versions to node counts
+type FirmwareDistributionResponse struct {
+	TotalNodes int64            `json:"total_nodes" example:"120"`
+	Versions   map[string]int64 `json:"versions"` // firmware version -> node count; "unknown" for nodes without a version
+}
+
+// GetFirmwareDistribution returns how many nodes run each firmware version
+func (s *NodeService) GetFirmwareDistribution() (*FirmwareDistributionResponse, error) {
+	counts, err := s.nodeRepo.CountByFirmwareVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firmware distribution: %w", err)
+	}
+
+	response := &FirmwareDistributionResponse{
+		Versions: make(map[string]int64, len(counts)),
+	}
+	for _, c := range counts {
+		version := c.FirmwareVersion
+		if version == "" {
+			version = UnknownFirmwareVersion
+		}
+		response.Versions[version] += c.Count
+		response.TotalNodes += c.Count
+	}
+
+	return response, nil
+}
+
+// RegistrationDay is the number of nodes first registered on one UTC day
+type RegistrationDay struct {
+	Date  string `json:"date" example:"2025-11-10"` // UTC day (YYYY-MM-DD)
+	Count int64  `json:"count" example:"4"`
+}
+
+// RegistrationsByDayResponse contains daily node registrations, oldest day first
+type RegistrationsByDayResponse struct {
+	Days       int                `json:"days" example:"30"`
+	TotalNodes int64              `json:"total_nodes" example:"57"` // Nodes registered in the period
+	Series     []*RegistrationDay `json:"series"`
+}
+
+// GetRegistrationsByDay returns how many nodes were first registered on each of the last N days
+// The current day is included; days without registrations are reported with a zero count
+func (s *NodeService) GetRegistrationsByDay(days int) (*RegistrationsByDayResponse, error) {
+	if days < 1 || days > MaxTimelineDays {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: days must be between 1 and %d", MaxTimelineDays))
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	counts, err := s.nodeRepo.CountByDay(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registrations by day: %w", err)
+	}
+
+	response := &RegistrationsByDayResponse{
+		Days:   days,
+		Series: make([]*RegistrationDay, 0, days),
+	}
+
+	byDate := make(map[string]*RegistrationDay, days)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		entry := &RegistrationDay{Date: day.Format("2006-01-02")}
+		byDate[entry.Date] = entry
+		response.Series = append(response.Series, entry)
+	}
+
+	for _, c := range counts {
+		if entry, ok := byDate[c.Day]; ok {
+			entry.Count = c.Count
+			response.TotalNodes += c.Count
+		}
+	}
+
+	return response, nil
+}
+
+// SetTargetFirmwareRequest sets the firmware version a node should update to
+type SetTargetFirmwareRequest struct {
+	// TargetFirmwareVersion must be newer than the node's reported version; null clears the target
+	TargetFirmwareVersion *string `json:"target_firmware_version" example:"1.1.0"`
+}
+
+// SetTargetFirmware sets or clears the target firmware version of a node
+// The target must be a semantic version greater than the node's current firmware version
+func (s *NodeService) SetTargetFirmware(uuid string, req *SetTargetFirmwareRequest) (*NodeResponse, error) {
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	target := req.TargetFirmwareVersion
+	if target != nil && *target == "" {
+		target = nil
+	}
+
+	if target != nil {
+		if err := validateTargetFirmware(node, *target); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.nodeRepo.UpdateTargetFirmware(node.UUID, target); err != nil {
+		return nil, fmt.Errorf("failed to update target firmware: %w", err)
+	}
+
+	node.TargetFirmwareVersion = target
+	return toNodeResponse(node), nil
+}
+
+// validateTargetFirmware checks that target is a semantic version newer than the node's firmware
+func validateTargetFirmware(node *models.Node, target string) error {
+	if err := validators.ValidateFirmwareVersion(target, "target_firmware_version"); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if node.FirmwareVersion != nil && validators.IsValidSemanticVersion(*node.FirmwareVersion) {
+		cmp, err := validators.CompareSemanticVersions(target, *node.FirmwareVersion)
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if cmp <= 0 {
+			return fmt.Errorf("validation failed: target_firmware_version must be greater than the current firmware version %s", *node.FirmwareVersion)
+		}
+	}
+	return nil
+}
+
+// RenameNodeRequest sets the display name of a node
+type RenameNodeRequest struct {
+	// Name is the new display name; null or empty clears it
+	Name *string `json:"name" example:"Living Room Sensor"`
+}
+
+// RenameNode sets or clears the display name of a node
+// With UniqueNodeNames enabled, a name used by another active node is rejected;
+// keeping the node's current name is always allowed.
+func (s *NodeService) RenameNode(uuid string, req *RenameNodeRequest) (*NodeResponse, error) {
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name != nil && *name == "" {
+		name = nil
+	}
+
+	if name != nil {
+		if err := validators.ValidateNodeName(*name, "name"); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		if err := s.checkNameAvailable(s.nodeRepo, *name, node.UUID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.nodeRepo.UpdateName(node.UUID, name); err != nil {
+		return nil, fmt.Errorf("failed to rename node: %w", err)
+	}
+
+	node.Name = name
+	return toNodeResponse(node), nil
+}
+
+// PatchNodeRequest updates any subset of a node's admin-editable fields
+// An omitted (or null) field is left unchanged; an empty string clears name or target firmware.
+type PatchNodeRequest struct {
+	Name                  *string  `json:"name,omitempty" example:"Living Room Sensor"`
+	TargetFirmwareVersion *string  `json:"target_firmware_version,omitempty" example:"1.1.0"`
+	Latitude              *float64 `json:"latitude,omitempty" example:"50.0755"` // Must be sent together with longitude
+	Longitude             *float64 `json:"longitude,omitempty" example:"14.4378"`
+	Status                *string  `json:"status,omitempty" example:"disabled"` // active, disabled or revoked
+}
+
+// PatchNode validates every field present in req and applies them in a single update
+// Nothing is written when any field is invalid. The rules match the single-purpose endpoints:
+// unique names, a newer target firmware, valid coordinates, and no way back from revoked.
+// A status change is published and audited like UpdateStatus.
+func (s *NodeService) PatchNode(uuid string, req *PatchNodeRequest, changedBy string) (*NodeResponse, error) {
+	if req.Name == nil && req.TargetFirmwareVersion == nil && req.Latitude == nil && req.Longitude == nil && req.Status == nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: at least one field is required"))
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: latitude and longitude must be set together"))
+	}
+
+	node, err := s.findNode(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := node.Status
+	fields := make(map[string]interface{})
+
+	if req.Name != nil {
+		var name *string
+		if *req.Name != "" {
+			if err := validators.ValidateNodeName(*req.Name, "name"); err != nil {
+				return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+			}
+			if err := s.checkNameAvailable(s.nodeRepo, *req.Name, node.UUID); err != nil {
+				return nil, err
+			}
+			name = req.Name
+		}
+		fields["name"] = name
+		node.Name = name
+	}
+
+	if req.TargetFirmwareVersion != nil {
+		var target *string
+		if *req.TargetFirmwareVersion != "" {
+			if err := validateTargetFirmware(node, *req.TargetFirmwareVersion); err != nil {
+				return nil, withCode(ErrCodeValidationFailed, err)
+			}
+			target = req.TargetFirmwareVersion
+		}
+		fields["target_firmware_version"] = target
+		node.TargetFirmwareVersion = target
+	}
+
+	if req.Latitude != nil {
+		if err := validators.ValidateGPSCoordinates(*req.Latitude, *req.Longitude); err != nil {
+			return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+		}
+		fields["latitude"] = *req.Latitude
+		fields["longitude"] = *req.Longitude
+		node.Latitude = req.Latitude
+		node.Longitude = req.Longitude
+	}
+
+	if req.Status != nil {
+		if err := validators.ValidateNodeStatus(*req.Status, "status"); err != nil {
+			return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+		}
+		if node.IsRevoked() && *req.Status != models.NodeStatusRevoked {
+			return nil, withCode(ErrCodeNodeRevoked, fmt.Errorf("node is revoked and cannot be set to %s", *req.Status))
+		}
+		fields["status"] = *req.Status
+		node.Status = *req.Status
+	}
+
+	if err := s.nodeRepo.UpdateFields(node.UUID, fields); err != nil {
+		return nil, err
+	}
+	if node.Status != previousStatus {
+		s.statusChanged(node, previousStatus, node.Status, changedBy)
+	}
+
+	return toNodeResponse(node), nil
+}
+
+// statusChanged publishes an admin status change to live subscribers and records it in the audit log
+// A revoked node's cached secret is dropped so it is not kept in memory any longer
+func (s *NodeService) statusChanged(node *models.Node, from, to, changedBy string) {
+	if to == models.NodeStatusRevoked {
+		s.secretCache.Invalidate(node.UUID)
+	}
+
+	s.broker.Publish(events.Event{
+		Type:       events.TypeNodeStatusChanged,
+		NodeUUID:   node.UUID,
+		MacAddress: node.MacAddress,
+		Status:     to,
+	})
+
+	details := fmt.Sprintf("status changed from %s to %s", from, to)
+	log.Printf("AUDIT: node %s (%s) %s by %s", node.UUID, node.MacAddress, details, auditActor(changedBy))
+	s.auditService.Record(AuditActionNodeStatusChanged, changedBy, models.AuditTargetNode, node.UUID, details)
+}
+
+// checkNameAvailable rejects a name used by another active node when UniqueNodeNames is enabled
+// repo is passed so imports can check against their transaction
+func (s *NodeService) checkNameAvailable(repo *repositories.NodeRepository, name string, excludeUUID string) error {
+	if !s.config.UniqueNodeNames || name == "" {
+		return nil
+	}
+
+	taken, err := repo.IsNameTaken(name, excludeUUID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("node name already in use: %s", name)
+	}
+	return nil
+}
+
+// GetNodeByMAC looks up a node by MAC address
+// Accepts any notation NormalizeMACAddress understands (e.g. aa-bb-cc-dd-ee-ff from a device label)
+func (s *NodeService) GetNodeByMAC(macAddress string) (*NodeResponse, error) {
+	normalizedMAC, err := validators.NormalizeMACAddress(macAddress)
+	if err != nil {
+		return nil, withCode(ErrCodeMACInvalid, fmt.Errorf("validation failed: %w", err))
+	}
+
+	node, err := s.nodeRepo.FindByMAC(normalizedMAC)
+	if err != nil {
+		if strings.Contains(err.Error(), "node not found") {
+			return nil, withCode(ErrCodeNodeNotFound, fmt.Errorf("node not found: %s", normalizedMAC))
+		}
+		return nil, err
+	}
+
+	return toNodeResponse(node), nil
+}
+
+// ForceDeleteNodeResponse lists everything removed together with a node
+type ForceDeleteNodeResponse struct {
+	UUID            string   `json:"uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	MacAddress      string   `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
+	DeletedTokenIDs []string `json:"deleted_token_ids"` // Tokens pre-authorized for the node's MAC
+	DeletedEvents   int64    `json:"deleted_events" example:"3"`
+}
+
+// ForceDeleteNode permanently removes a node along with tokens pre-authorized for its MAC
+// and its registration events, in one transaction. Each removed record is logged.
+func (s *NodeService) ForceDeleteNode(uuid string) (*ForceDeleteNodeResponse, error) {
+	result, err := s.nodeRepo.ForceDelete(uuid)
+	if err != nil {
+		if strings.Contains(err.Error(), "node not found") {
+			return nil, withCode(ErrCodeNodeNotFound, fmt.Errorf("failed to force delete node: %w", err))
+		}
+		return nil, fmt.Errorf("failed to force delete node: %w", err)
+	}
+	s.secretCache.Invalidate(result.Node.UUID)
+
+	log.Printf("Force deleted node %s (%s)", result.Node.UUID, result.Node.MacAddress)
+	for _, tokenID := range result.DeletedTokenIDs {
+		log.Printf("Force delete of node %s removed pre-authorized token %s", result.Node.UUID, tokenID)
+	}
+	if result.DeletedEvents > 0 {
+		log.Printf("Force delete of node %s removed %d registration event(s)", result.Node.UUID, result.DeletedEvents)
+	}
+
+	return &ForceDeleteNodeResponse{
+		UUID:            result.Node.UUID,
+		MacAddress:      result.Node.MacAddress,
+		DeletedTokenIDs: result.DeletedTokenIDs,
+		DeletedEvents:   result.DeletedEvents,
+	}, nil
+}
+
+// MaxImportBatchSize is the maximum number of nodes accepted by a single import request
+const MaxImportBatchSize = 1000
+
+// DefaultMaxImportBodyBytes is the default request body limit for imports (1 MiB),
+// enough for MaxImportBatchSize records
+const DefaultMaxImportBodyBytes int64 = 1 << 20
+
+// Import result statuses
+const (
+	ImportStatusImported   = "imported"
+	ImportStatusSkipped    = "skipped"
+	ImportStatusFailed     = "failed"
+	ImportStatusRolledBack = "rolled_back"
+)
+
+// ImportNodeRecord is a single node to import from another system
+type ImportNodeRecord struct {
+	MacAddress      string   `json:"mac_address" binding:"required" example:"AA:BB:CC:DD:EE:FF"`
+	Name            *string  `json:"name,omitempty" example:"Living Room Sensor"`
+	FirmwareVersion *string  `json:"firmware_version,omitempty" example:"1.0.0"`
+	Latitude        *float64 `json:"latitude,omitempty" example:"50.0755"`
+	Longitude       *float64 `json:"longitude,omitempty" example:"14.4378"`
+}
+
+// ImportNodesRequest contains the nodes to import in bulk
+type ImportNodesRequest struct {
+	Nodes []ImportNodeRecord `json:"nodes" binding:"required,min=1,dive"`
+	// SkipDuplicates skips records whose MAC already exists instead of failing the import
+	SkipDuplicates bool `json:"skip_duplicates" example:"false"`
+}
+
+// ImportNodeResult reports the outcome of importing one record
+type ImportNodeResult struct {
+	Index      int    `json:"index" example:"0"`
+	MacAddress string `json:"mac_address" example:"AA:BB:CC:DD:EE:FF"`
+	Status     string `json:"status" example:"imported"` // imported, skipped, failed, rolled_back
+	UUID       string `json:"uuid,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Error      string `json:"error,omitempty" example:"invalid MAC address format"`
+}
+
+// ImportNodesResponse contains the per-record report of a bulk import
+// If any record failed, nothing is committed and Committed is false
+type ImportNodesResponse struct {
+	Committed bool                `json:"committed" example:"true"`
+	Imported  int                 `json:"imported" example:"2"`
+	Skipped   int                 `json:"skipped" example:"0"`
+	Failed    int                 `json:"failed" example:"0"`
+	Results   []*ImportNodeResult `json:"results"`
+}
+
+// errImportFailed signals that at least one record failed and the transaction must roll back
+var errImportFailed = errors.New("import failed")
+
+// ImportNodes validates and inserts nodes in a single transaction
+// Each imported node gets a new UUID and encrypted JWT secret; no registration token is consumed.
+// Duplicate MACs (existing or repeated within the batch) are skipped when SkipDuplicates is set,
+// otherwise they fail the import. With UniqueNodeNames enabled, a name already used by an active node
+// (or an earlier record of the batch) fails the record. Any failed record rolls back the whole batch.
+func (s *NodeService) ImportNodes(req *ImportNodesRequest) (*ImportNodesResponse, error) {
+	if len(req.Nodes) == 0 {
+		return nil, fmt.Errorf("validation failed: nodes must not be empty")
+	}
+	if len(req.Nodes) > MaxImportBatchSize {
+		return nil, fmt.Errorf("validation failed: at most %d nodes can be imported at once", MaxImportBatchSize)
+	}
+
+	response := &ImportNodesResponse{
+		Results: make([]*ImportNodeResult, len(req.Nodes)),
+	}
+
+	err := s.nodeRepo.Transaction(func(txRepo *repositories.NodeRepository) error {
+		seen := make(map[string]bool, len(req.Nodes))
+
+		for i := range req.Nodes {
+			result := s.importNode(txRepo, &req.Nodes[i], req.SkipDuplicates, seen)
+			result.Index = i
+			response.Results[i] = result
+
+			switch result.Status {
+			case ImportStatusImported:
+				response.Imported++
+			case ImportStatusSkipped:
+				response.Skipped++
+			case ImportStatusFailed:
+				response.Failed++
+			}
+		}
+
+		if response.Failed > 0 {
+			return errImportFailed
+		}
+		return nil
+	})
+
+	if errors.Is(err, errImportFailed) {
+		for _, result := range response.Results {
+			if result.Status == ImportStatusImported {
+				result.Status = ImportStatusRolledBack
+				result.UUID = ""
+			}
+		}
+		response.Imported = 0
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to import nodes: %w", err)
+	}
+
+	response.Committed = true
+	return response, nil
+}
+
+// importNode validates and inserts a single import record using the transaction-bound repository
+func (s *NodeService) importNode(
+	txRepo *repositories.NodeRepository,
+	record *ImportNodeRecord,
+	skipDuplicates bool,
+	seen map[string]bool,
+) *ImportNodeResult {
+	result := &ImportNodeResult{MacAddress: record.MacAddress}
+
+	fail := func(err error) *ImportNodeResult {
+		result.Status = ImportStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	// Other systems may use different MAC notations, so normalize before validating
+	normalizedMAC, err := validators.NormalizeMACAddress(record.MacAddress)
+	if err != nil {
+		return fail(err)
+	}
+	result.MacAddress = normalizedMAC
+
+	if err := validateImportRecord(record); err != nil {
+		return fail(err)
+	}
+
+	duplicate := seen[normalizedMAC]
+	if !duplicate {
+		if _, err := txRepo.FindByMAC(normalizedMAC); err == nil {
+			duplicate = true
+		}
+	}
+	seen[normalizedMAC] = true
+
+	if duplicate {
+		if skipDuplicates {
+			result.Status = ImportStatusSkipped
+			return result
+		}
+		return fail(fmt.Errorf("node with this MAC address already exists"))
+	}
+
+	// Earlier records of the batch are already inserted, so this also catches names repeated in the batch
+	if record.Name != nil {
+		if err := s.checkNameAvailable(txRepo, *record.Name, ""); err != nil {
+			return fail(err)
+		}
+	}
+
+	_, encryptedSecret, err := crypto.EncryptJWTSecret(s.keyProvider)
+	if err != nil {
+		return fail(fmt.Errorf("failed to generate and encrypt JWT secret: %w", err))
+	}
+
+	node := &models.Node{
+		UUID:            uuid.New().String(),
+		MacAddress:      normalizedMAC,
+		Name:            record.Name,
+		JWTSecret:       encryptedSecret,
+		Status:          models.NodeStatusActive,
+		FirmwareVersion: record.FirmwareVersion,
+		Latitude:        record.Latitude,
+		Longitude:       record.Longitude,
+	}
+
+	if err := txRepo.Create(node); err != nil {
+		return fail(err)
+	}
+
+	result.Status = ImportStatusImported
+	result.UUID = node.UUID
+	return result
+}
+
+// validateImportRecord validates the optional fields of an import record
+func validateImportRecord(record *ImportNodeRecord) error {
+	if record.Name != nil {
+		if err := validators.ValidateNodeName(*record.Name, "name"); err != nil {
+			return err
+		}
+	}
+
+	if record.FirmwareVersion != nil && *record.FirmwareVersion != "" {
+		if err := validators.ValidateStringLength(*record.FirmwareVersion, "firmware_version", 0, validators.MaxFirmwareVersionLength); err != nil {
+			return err
+		}
+		if !validators.IsValidSemanticVersion(*record.FirmwareVersion) {
+			return fmt.Errorf("invalid firmware version format: %s", *record.FirmwareVersion)
+		}
+	}
+
+	if record.Latitude != nil || record.Longitude != nil {
+		if record.Latitude == nil || record.Longitude == nil {
+			return fmt.Errorf("both latitude and longitude must be provided")
+		}
+		if err := validators.ValidateGPSCoordinates(*record.Latitude, *record.Longitude); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findNode loads a node, mapping a missing node to NODE_NOT_FOUND
+func (s *NodeService) findNode(uuid string) (*models.Node, error) {
+	node, err := s.nodeRepo.FindByUUID(uuid)
+	if err != nil {
+		if strings.Contains(err.Error(), "node not found") {
+			return nil, withCode(ErrCodeNodeNotFound, fmt.Errorf("node not found: %s", uuid))
+		}
+		return nil, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,570 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/events"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestNodeService creates a node service backed by an in-memory database
+// config and broker may be nil
+func newTestNodeService(t *testing.T, config *NodeConfig, broker *events.Broker) (*NodeService, *repositories.NodeRepository) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	service := NewNodeService(nodeRepo, repositories.NewRegistrationEventRepository(db), broker, nil, nil, nil, config)
+	return service, nodeRepo
+}
+
+// TestNodeService_ListNodes tests paging and the status filter
+func TestNodeService_ListNodes(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", Status: models.NodeStatusDisabled},
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	all, err := service.ListNodes(&NodeListQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListNodes() error = %v", err)
+	}
+	if all.Total != 3 || len(all.Nodes) != 2 || all.Page != 1 {
+		t.Errorf("ListNodes() total = %d, len = %d, page = %d, want 3, 2, 1", all.Total, len(all.Nodes), all.Page)
+	}
+
+	second, err := service.ListNodes(&NodeListQuery{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListNodes(page 2) error = %v", err)
+	}
+	if len(second.Nodes) != 1 {
+		t.Errorf("ListNodes(page 2) len = %d, want 1", len(second.Nodes))
+	}
+
+	disabled, err := service.ListNodes(&NodeListQuery{Status: models.NodeStatusDisabled})
+	if err != nil {
+		t.Fatalf("ListNodes(disabled) error = %v", err)
+	}
+	if disabled.Total != 1 || disabled.Nodes[0].UUID != nodes[2].UUID {
+		t.Errorf("ListNodes(disabled) = %+v, want only %s", disabled.Nodes, nodes[2].UUID)
+	}
+	if disabled.PageSize != DefaultTokenSearchPageSize {
+		t.Errorf("ListNodes() page size = %d, want default %d", disabled.PageSize, DefaultTokenSearchPageSize)
+	}
+
+	if _, err := service.ListNodes(&NodeListQuery{Status: "sleeping"}); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("ListNodes(invalid status) error = %v, want validation error", err)
+	}
+	if _, err := service.ListNodes(&NodeListQuery{PageSize: MaxTokenSearchPageSize + 1}); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("ListNodes(page size too large) error = %v, want validation error", err)
+	}
+}
+
+// TestNodeService_GetNode tests lookup by UUID
+func TestNodeService_GetNode(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := service.GetNode(node.UUID)
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	if found.MacAddress != node.MacAddress {
+		t.Errorf("GetNode() mac = %s, want %s", found.MacAddress, node.MacAddress)
+	}
+
+	_, err = service.GetNode("550e8400-e29b-41d4-a716-446655449999")
+	if err == nil || ErrorCodeOf(err) != ErrCodeNodeNotFound {
+		t.Errorf("GetNode(unknown) error = %v, want NODE_NOT_FOUND", err)
+	}
+}
+
+// TestNodeService_GetNodeByMAC tests lookup by MAC address in any accepted notation
+func TestNodeService_GetNodeByMAC(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	found, err := service.GetNodeByMAC("aa-bb-cc-dd-ee-01")
+	if err != nil {
+		t.Fatalf("GetNodeByMAC() error = %v", err)
+	}
+	if found.UUID != node.UUID {
+		t.Errorf("GetNodeByMAC() uuid = %s, want %s", found.UUID, node.UUID)
+	}
+
+	if _, err := service.GetNodeByMAC("AA:BB:CC:DD:EE:99"); ErrorCodeOf(err) != ErrCodeNodeNotFound {
+		t.Errorf("GetNodeByMAC(unknown) error = %v, want NODE_NOT_FOUND", err)
+	}
+	if _, err := service.GetNodeByMAC("not-a-mac"); ErrorCodeOf(err) != ErrCodeMACInvalid {
+		t.Errorf("GetNodeByMAC(invalid) error = %v, want MAC_INVALID", err)
+	}
+}
+
+// TestNodeService_UpdateStatus tests status changes and that revocation is permanent
+func TestNodeService_UpdateStatus(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	updated, err := service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusDisabled}, "")
+	if err != nil {
+		t.Fatalf("UpdateStatus(disabled) error = %v", err)
+	}
+	if updated.Status != models.NodeStatusDisabled {
+		t.Errorf("UpdateStatus() status = %s, want disabled", updated.Status)
+	}
+
+	if _, err := service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: "sleeping"}, ""); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("UpdateStatus(invalid) error = %v, want validation error", err)
+	}
+
+	if _, err := service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusRevoked}, ""); err != nil {
+		t.Fatalf("UpdateStatus(revoked) error = %v", err)
+	}
+	_, err = service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusActive}, "")
+	if err == nil || ErrorCodeOf(err) != ErrCodeNodeRevoked {
+		t.Errorf("UpdateStatus(revoked -> active) error = %v, want NODE_REVOKED", err)
+	}
+
+	_, err = service.UpdateStatus("550e8400-e29b-41d4-a716-446655449999", &UpdateNodeStatusRequest{Status: models.NodeStatusActive}, "")
+	if err == nil || ErrorCodeOf(err) != ErrCodeNodeNotFound {
+		t.Errorf("UpdateStatus(unknown) error = %v, want NODE_NOT_FOUND", err)
+	}
+}
+
+// TestNodeService_UpdateStatusPublishesEvent tests that admin status changes reach live subscribers
+func TestNodeService_UpdateStatusPublishesEvent(t *testing.T) {
+	broker := events.NewBroker()
+	sub := broker.Subscribe(4)
+	defer sub.Unsubscribe()
+	service, nodeRepo := newTestNodeService(t, nil, broker)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusDisabled}, ""); err != nil {
+		t.Fatalf("UpdateStatus(disabled) error = %v", err)
+	}
+
+	select {
+	case event := <-sub.Events():
+		if event.Type != events.TypeNodeStatusChanged || event.NodeUUID != node.UUID || event.Status != models.NodeStatusDisabled {
+			t.Errorf("event = %+v, want %s for %s with status disabled", event, events.TypeNodeStatusChanged, node.UUID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event published for the status change")
+	}
+
+	// Setting the current status again is not a change
+	if _, err := service.UpdateStatus(node.UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusDisabled}, ""); err != nil {
+		t.Fatalf("UpdateStatus(disabled again) error = %v", err)
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("unexpected event for unchanged status: %+v", event)
+	default:
+	}
+}
+
+// TestNodeService_EvictsCachedSecrets tests that revoking or deleting a node drops its cached secret
+func TestNodeService_EvictsCachedSecrets(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}, &models.RegistrationEvent{}, &models.NodeTelemetry{}, &models.NodeMetadata{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	cache := NewNodeSecretCache(&NodeSecretCacheConfig{MaxEntries: 10, TTL: time.Minute})
+	service := NewNodeService(nodeRepo, repositories.NewRegistrationEventRepository(db), nil, nil, nil, cache, nil)
+
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440003", MacAddress: "AA:BB:CC:DD:EE:03", JWTSecret: "s3", Status: models.NodeStatusActive},
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		cache.Put(n.UUID, n.JWTSecret, "secret-"+n.UUID)
+	}
+
+	// Disabling keeps the secret; the node may be enabled again
+	if _, err := service.UpdateStatus(nodes[0].UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusDisabled}, ""); err != nil {
+		t.Fatalf("UpdateStatus(disabled) error = %v", err)
+	}
+	if _, ok := cache.Get(nodes[0].UUID, nodes[0].JWTSecret); !ok {
+		t.Error("Get() after disable missed, want the secret kept")
+	}
+
+	if _, err := service.UpdateStatus(nodes[0].UUID, &UpdateNodeStatusRequest{Status: models.NodeStatusRevoked}, ""); err != nil {
+		t.Fatalf("UpdateStatus(revoked) error = %v", err)
+	}
+	if _, ok := cache.Get(nodes[0].UUID, nodes[0].JWTSecret); ok {
+		t.Error("Get() after revoke hit, want the secret evicted")
+	}
+
+	revoked := models.NodeStatusRevoked
+	if _, err := service.PatchNode(nodes[1].UUID, &PatchNodeRequest{Status: &revoked}, ""); err != nil {
+		t.Fatalf("PatchNode(revoked) error = %v", err)
+	}
+	if _, ok := cache.Get(nodes[1].UUID, nodes[1].JWTSecret); ok {
+		t.Error("Get() after patch to revoked hit, want the secret evicted")
+	}
+
+	if _, err := service.ForceDeleteNode(nodes[2].UUID); err != nil {
+		t.Fatalf("ForceDeleteNode() error = %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("cache Len() after delete = %d, want 0", cache.Len())
+	}
+}
+
+// TestNodeService_UpdateLocation tests coordinate validation and storage
+func TestNodeService_UpdateLocation(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	lat, lng := 50.0755, 14.4378
+	updated, err := service.UpdateLocation(node.UUID, &UpdateNodeLocationRequest{Latitude: &lat, Longitude: &lng})
+	if err != nil {
+		t.Fatalf("UpdateLocation() error = %v", err)
+	}
+	if updated.Latitude == nil || *updated.Latitude != lat || updated.Longitude == nil || *updated.Longitude != lng {
+		t.Errorf("UpdateLocation() = (%v, %v), want (%v, %v)", updated.Latitude, updated.Longitude, lat, lng)
+	}
+
+	badLat := 91.0
+	if _, err := service.UpdateLocation(node.UUID, &UpdateNodeLocationRequest{Latitude: &badLat, Longitude: &lng}); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("UpdateLocation(latitude 91) error = %v, want validation error", err)
+	}
+	if _, err := service.UpdateLocation(node.UUID, &UpdateNodeLocationRequest{Latitude: &lat}); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("UpdateLocation(missing longitude) error = %v, want validation error", err)
+	}
+
+	_, err = service.UpdateLocation("550e8400-e29b-41d4-a716-446655449999", &UpdateNodeLocationRequest{Latitude: &lat, Longitude: &lng})
+	if err == nil || ErrorCodeOf(err) != ErrCodeNodeNotFound {
+		t.Errorf("UpdateLocation(unknown) error = %v, want NODE_NOT_FOUND", err)
+	}
+}
+
+// TestNodeService_RenameNode_UniqueNames tests the optional unique name check
+func TestNodeService_RenameNode_UniqueNames(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, &NodeConfig{UniqueNodeNames: true}, nil)
+
+	garden := "Garden"
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Name: &garden, Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Status: models.NodeStatusActive},
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	// Another active node already uses the name
+	_, err := service.RenameNode(nodes[1].UUID, &RenameNodeRequest{Name: &garden})
+	if err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Fatalf("RenameNode() error = %v, want name already in use", err)
+	}
+
+	// Keeping the node's own name is allowed
+	if _, err := service.RenameNode(nodes[0].UUID, &RenameNodeRequest{Name: &garden}); err != nil {
+		t.Fatalf("RenameNode() with own name error = %v", err)
+	}
+
+	// Clearing the name frees it for other nodes
+	empty := ""
+	response, err := service.RenameNode(nodes[0].UUID, &RenameNodeRequest{Name: &empty})
+	if err != nil {
+		t.Fatalf("RenameNode() clear error = %v", err)
+	}
+	if response.Name != nil {
+		t.Errorf("RenameNode() clear name = %q, want nil", *response.Name)
+	}
+
+	response, err = service.RenameNode(nodes[1].UUID, &RenameNodeRequest{Name: &garden})
+	if err != nil {
+		t.Fatalf("RenameNode() after clear error = %v", err)
+	}
+	if response.Name == nil || *response.Name != garden {
+		t.Errorf("RenameNode() name = %v, want %q", response.Name, garden)
+	}
+}
+
+// TestNodeService_RenameNode_NonUniqueByDefault tests that names may repeat by default
+func TestNodeService_RenameNode_NonUniqueByDefault(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	garden := "Garden"
+	nodes := []*models.Node{
+		{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Name: &garden, Status: models.NodeStatusActive},
+		{UUID: "550e8400-e29b-41d4-a716-446655440002", MacAddress: "AA:BB:CC:DD:EE:02", JWTSecret: "s2", Status: models.NodeStatusActive},
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(n); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if _, err := service.RenameNode(nodes[1].UUID, &RenameNodeRequest{Name: &garden}); err != nil {
+		t.Errorf("RenameNode() error = %v, want duplicate name allowed", err)
+	}
+
+	if _, err := service.RenameNode("550e8400-e29b-41d4-a716-446655440099", &RenameNodeRequest{Name: &garden}); err == nil || !strings.Contains(err.Error(), "node not found") {
+		t.Errorf("RenameNode() unknown node error = %v, want node not found", err)
+	}
+}
+
+// TestNodeService_PatchNode tests partial updates and that an invalid field writes nothing
+func TestNodeService_PatchNode(t *testing.T) {
+	service, nodeRepo := newTestNodeService(t, nil, nil)
+
+	garden := "Garden"
+	firmware := "1.0.0"
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Name: &garden, FirmwareVersion: &firmware, Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Only the fields present are changed
+	target := "1.1.0"
+	lat, lon := 50.0755, 14.4378
+	disabled := models.NodeStatusDisabled
+	response, err := service.PatchNode(node.UUID, &PatchNodeRequest{TargetFirmwareVersion: &target, Latitude: &lat, Longitude: &lon, Status: &disabled}, "")
+	if err != nil {
+		t.Fatalf("PatchNode() error = %v", err)
+	}
+	stored, err := nodeRepo.FindByUUID(node.UUID)
+	if err != nil {
+		t.Fatalf("FindByUUID() error = %v", err)
+	}
+	if stored.Name == nil || *stored.Name != garden {
+		t.Errorf("name = %v, want unchanged %q", stored.Name, garden)
+	}
+	if stored.TargetFirmwareVersion == nil || *stored.TargetFirmwareVersion != target {
+		t.Errorf("target_firmware_version = %v, want %q", stored.TargetFirmwareVersion, target)
+	}
+	if stored.Latitude == nil || *stored.Latitude != lat || stored.Longitude == nil || *stored.Longitude != lon {
+		t.Errorf("location = %v, %v, want %v, %v", stored.Latitude, stored.Longitude, lat, lon)
+	}
+	if stored.Status != disabled || response.Status != disabled {
+		t.Errorf("status = %q (response %q), want %q", stored.Status, response.Status, disabled)
+	}
+
+	// An empty string clears, and one invalid field rejects the whole request
+	empty := ""
+	badLat := 91.0
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Name: &empty, Latitude: &badLat, Longitude: &lon}, ""); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Fatalf("PatchNode() error = %v, want validation failed", err)
+	}
+	if stored, _ := nodeRepo.FindByUUID(node.UUID); stored.Name == nil {
+		t.Error("name was cleared by a rejected request")
+	}
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Name: &empty}, ""); err != nil {
+		t.Fatalf("PatchNode() clear name error = %v", err)
+	}
+	if stored, _ := nodeRepo.FindByUUID(node.UUID); stored.Name != nil {
+		t.Errorf("name = %q, want cleared", *stored.Name)
+	}
+
+	paused := "paused"
+	tests := []struct {
+		name    string
+		req     *PatchNodeRequest
+		wantErr string
+	}{
+		{"no fields", &PatchNodeRequest{}, "at least one field"},
+		{"latitude without longitude", &PatchNodeRequest{Latitude: &lat}, "set together"},
+		{"older target firmware", &PatchNodeRequest{TargetFirmwareVersion: &firmware}, "must be greater"},
+		{"unknown status", &PatchNodeRequest{Status: &paused}, "validation failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.PatchNode(node.UUID, tt.req, ""); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("PatchNode() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	revoked := models.NodeStatusRevoked
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Status: &revoked}, ""); err != nil {
+		t.Fatalf("PatchNode() revoke error = %v", err)
+	}
+	active := models.NodeStatusActive
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Status: &active}, ""); err == nil || !strings.Contains(err.Error(), "node is revoked") {
+		t.Errorf("PatchNode() reactivate error = %v, want node is revoked", err)
+	}
+}
+
+// TestNodeService_PatchNodeStatusChange tests that a PATCH changing status is published and audited
+// while a PATCH leaving the status alone is not
+func TestNodeService_PatchNodeStatusChange(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationEvent{}, &models.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	broker := events.NewBroker()
+	sub := broker.Subscribe(4)
+	defer sub.Unsubscribe()
+	nodeRepo := repositories.NewNodeRepository(db)
+	auditService := NewAuditService(repositories.NewAuditLogRepository(db))
+	service := NewNodeService(nodeRepo, repositories.NewRegistrationEventRepository(db), broker, auditService, nil, nil, nil)
+
+	node := &models.Node{UUID: "550e8400-e29b-41d4-a716-446655440001", MacAddress: "AA:BB:CC:DD:EE:01", JWTSecret: "s1", Status: models.NodeStatusActive}
+	if err := nodeRepo.Create(node); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	name := "Garage"
+	active := models.NodeStatusActive
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Name: &name, Status: &active}, "admin@example.com"); err != nil {
+		t.Fatalf("PatchNode(unchanged status) error = %v", err)
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("unexpected event for unchanged status: %+v", event)
+	default:
+	}
+
+	disabled := models.NodeStatusDisabled
+	if _, err := service.PatchNode(node.UUID, &PatchNodeRequest{Status: &disabled}, "admin@example.com"); err != nil {
+		t.Fatalf("PatchNode(disabled) error = %v", err)
+	}
+	select {
+	case event := <-sub.Events():
+		if event.Type != events.TypeNodeStatusChanged || event.NodeUUID != node.UUID || event.Status != models.NodeStatusDisabled {
+			t.Errorf("event = %+v, want %s for %s with status disabled", event, events.TypeNodeStatusChanged, node.UUID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event published for the status change")
+	}
+
+	logs, err := auditService.Query(&AuditLogQuery{Action: AuditActionNodeStatusChanged})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if logs.Total != 1 {
+		t.Fatalf("audit entries = %d, want 1", logs.Total)
+	}
+	entry := logs.Entries[0]
+	if entry.TargetID == nil || *entry.TargetID != node.UUID {
+		t.Errorf("audit target = %v, want %s", entry.TargetID, node.UUID)
+	}
+	if entry.AdminEmail == nil || *entry.AdminEmail != "admin@example.com" {
+		t.Errorf("audit admin = %v, want admin@example.com", entry.AdminEmail)
+	}
+	if entry.Details == nil || *entry.Details != "status changed from active to disabled" {
+		t.Errorf("audit details = %v, want status changed from active to disabled", entry.Details)
+	}
+}
+
+// TestNodeService_GetRegistrationsByDay tests the zero-filled daily registration series
+func TestNodeService_GetRegistrationsByDay(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationEvent{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	nodeRepo := repositories.NewNodeRepository(db)
+	service := NewNodeService(nodeRepo, repositories.NewRegistrationEventRepository(db), nil, nil, nil, nil, nil)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	nodes := []struct {
+		uuid, mac string
+		createdAt time.Time
+	}{
+		{"550e8400-e29b-41d4-a716-446655440001", "AA:BB:CC:DD:EE:01", today.Add(time.Hour)},
+		{"550e8400-e29b-41d4-a716-446655440002", "AA:BB:CC:DD:EE:02", today.AddDate(0, 0, -2).Add(time.Hour)},
+		{"550e8400-e29b-41d4-a716-446655440003", "AA:BB:CC:DD:EE:03", today.AddDate(0, 0, -2).Add(2 * time.Hour)},
+		{"550e8400-e29b-41d4-a716-446655440004", "AA:BB:CC:DD:EE:04", today.AddDate(0, 0, -10)}, // outside the period
+	}
+	for _, n := range nodes {
+		if err := nodeRepo.Create(&models.Node{UUID: n.uuid, MacAddress: n.mac, JWTSecret: "s", Status: models.NodeStatusActive}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := db.Model(&models.Node{}).Where("uuid = ?", n.uuid).UpdateColumn("created_at", n.createdAt).Error; err != nil {
+			t.Fatalf("failed to backdate node: %v", err)
+		}
+	}
+
+	response, err := service.GetRegistrationsByDay(3)
+	if err != nil {
+		t.Fatalf("GetRegistrationsByDay() error = %v", err)
+	}
+	want := []int64{2, 0, 1}
+	if len(response.Series) != len(want) {
+		t.Fatalf("series length = %d, want %d", len(response.Series), len(want))
+	}
+	for i, count := range want {
+		day := response.Series[i]
+		if wantDate := today.AddDate(0, 0, i-2).Format("2006-01-02"); day.Date != wantDate || day.Count != count {
+			t.Errorf("series[%d] = %s: %d, want %s: %d", i, day.Date, day.Count, wantDate, count)
+		}
+	}
+	if response.TotalNodes != 3 {
+		t.Errorf("TotalNodes = %d, want 3", response.TotalNodes)
+	}
+
+	for _, days := range []int{0, MaxTimelineDays + 1} {
+		if _, err := service.GetRegistrationsByDay(days); ErrorCodeOf(err) != ErrCodeValidationFailed {
+			t.Errorf("GetRegistrationsByDay(%d) error = %v, want VALIDATION_FAILED", days, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// TelemetrySchemaVersion is the telemetry payload version this server understands
+// Bump it when fields change meaning; older versions are rejected rather than misread
+const TelemetrySchemaVersion = 1
+
+// NodeTelemetryService handles telemetry reported by authenticated nodes
+type NodeTelemetryService struct {
+	telemetryRepo *repositories.NodeTelemetryRepository
+}
+
+// NewNodeTelemetryService creates a new node telemetry service instance
+func NewNodeTelemetryService(telemetryRepo *repositories.NodeTelemetryRepository) *NodeTelemetryService {
+	return &NodeTelemetryService{
+		telemetryRepo: telemetryRepo,
+	}
+}
+
+// TelemetryRequest contains a telemetry reading sent by a node
+// At least one metric must be present
+type TelemetryRequest struct {
+	SchemaVersion  int    `json:"schema_version" binding:"required" example:"1"`
+	BatteryPercent *int   `json:"battery_percent,omitempty" example:"87" minimum:"0" maximum:"100"`
+	RSSI           *int   `json:"rssi,omitempty" example:"-67" minimum:"-150" maximum:"0"` // Signal strength in dBm
+	UptimeSeconds  *int64 `json:"uptime_seconds,omitempty" example:"86400" minimum:"0"`
+}
+
+// TelemetryResponse contains the stored telemetry reading
+type TelemetryResponse struct {
+	NodeUUID       string `json:"node_uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SchemaVersion  int    `json:"schema_version" example:"1"`
+	BatteryPercent *int   `json:"battery_percent,omitempty" example:"87"`
+	RSSI           *int   `json:"rssi,omitempty" example:"-67"`
+	UptimeSeconds  *int64 `json:"uptime_seconds,omitempty" example:"86400"`
+	ReportedAt     string `json:"reported_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// RecordTelemetry validates a reading and stores it as the node's latest telemetry
+func (s *NodeTelemetryService) RecordTelemetry(node *models.Node, req *TelemetryRequest) (*TelemetryResponse, error) {
+	if node == nil {
+		return nil, fmt.Errorf("node is required")
+	}
+	if err := validateTelemetryRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	telemetry := &models.NodeTelemetry{
+		NodeUUID:       node.UUID,
+		SchemaVersion:  req.SchemaVersion,
+		BatteryPercent: req.BatteryPercent,
+		RSSI:           req.RSSI,
+		UptimeSeconds:  req.UptimeSeconds,
+		ReportedAt:     time.Now().UTC(),
+	}
+	if err := s.telemetryRepo.Upsert(telemetry); err != nil {
+		return nil, err
+	}
+
+	return &TelemetryResponse{
+		NodeUUID:       telemetry.NodeUUID,
+		SchemaVersion:  telemetry.SchemaVersion,
+		BatteryPercent: telemetry.BatteryPercent,
+		RSSI:           telemetry.RSSI,
+		UptimeSeconds:  telemetry.UptimeSeconds,
+		ReportedAt:     telemetry.ReportedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// validateTelemetryRequest checks the schema version and metric ranges
+func validateTelemetryRequest(req *TelemetryRequest) error {
+	if req == nil {
+		return fmt.Errorf("request cannot be nil")
+	}
+	if req.SchemaVersion != TelemetrySchemaVersion {
+		return fmt.Errorf("schema_version: unsupported version %d (supported: %d)", req.SchemaVersion, TelemetrySchemaVersion)
+	}
+	if req.BatteryPercent == nil && req.RSSI == nil && req.UptimeSeconds == nil {
+		return fmt.Errorf("at least one of battery_percent, rssi, uptime_seconds is required")
+	}
+	if req.BatteryPercent != nil && (*req.BatteryPercent < 0 || *req.BatteryPercent > 100) {
+		return fmt.Errorf("battery_percent: must be between 0 and 100 (got: %d)", *req.BatteryPercent)
+	}
+	if req.RSSI != nil && (*req.RSSI < -150 || *req.RSSI > 0) {
+		return fmt.Errorf("rssi: must be between -150 and 0 dBm (got: %d)", *req.RSSI)
+	}
+	if req.UptimeSeconds != nil && *req.UptimeSeconds < 0 {
+		return fmt.Errorf("uptime_seconds: must not be negative (got: %d)", *req.UptimeSeconds)
+	}
+	return nil
+}
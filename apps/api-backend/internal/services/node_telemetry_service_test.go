@@ -0,0 +1,33 @@
+package services
+
+import "testing"
+
+// TestValidateTelemetryRequest tests schema version and metric range checks
+func TestValidateTelemetryRequest(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name    string
+		req     *TelemetryRequest
+		wantErr bool
+	}{
+		{"all metrics", &TelemetryRequest{SchemaVersion: 1, BatteryPercent: intPtr(87), RSSI: intPtr(-67), UptimeSeconds: int64Ptr(86400)}, false},
+		{"single metric", &TelemetryRequest{SchemaVersion: 1, RSSI: intPtr(0)}, false},
+		{"unsupported version", &TelemetryRequest{SchemaVersion: 2, BatteryPercent: intPtr(50)}, true},
+		{"no metrics", &TelemetryRequest{SchemaVersion: 1}, true},
+		{"battery over 100", &TelemetryRequest{SchemaVersion: 1, BatteryPercent: intPtr(101)}, true},
+		{"positive RSSI", &TelemetryRequest{SchemaVersion: 1, RSSI: intPtr(5)}, true},
+		{"negative uptime", &TelemetryRequest{SchemaVersion: 1, UptimeSeconds: int64Ptr(-1)}, true},
+		{"nil request", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTelemetryRequest(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTelemetryRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
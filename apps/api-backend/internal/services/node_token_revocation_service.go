@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/validators"
+)
+
+// RevokeNodeTokenRequest identifies the node JWT to revoke
+type RevokeNodeTokenRequest struct {
+	// JTI is the jti claim of the token (readable from the token's payload)
+	JTI string `json:"jti" binding:"required" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
+
+// RevokedNodeTokenResponse describes a revoked node JWT
+type RevokedNodeTokenResponse struct {
+	JTI       string  `json:"jti" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	NodeUUID  string  `json:"node_uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	RevokedBy *string `json:"revoked_by,omitempty" example:"admin@example.com"`
+	RevokedAt string  `json:"revoked_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	// ExpiresAt is when the entry is removed from the revocation list; the token has expired by then
+	ExpiresAt string `json:"expires_at" example:"2025-12-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// NodeTokenRevocationService revokes individual node JWTs by their jti claim
+type NodeTokenRevocationService struct {
+	nodeRepo     *repositories.NodeRepository
+	revokedRepo  *repositories.RevokedNodeTokenRepository
+	auditService *AuditService
+}
+
+// NewNodeTokenRevocationService creates a new node token revocation service instance
+// auditService may be nil, in which case revocations are only logged
+func NewNodeTokenRevocationService(
+	nodeRepo *repositories.NodeRepository,
+	revokedRepo *repositories.RevokedNodeTokenRepository,
+	auditService *AuditService,
+) *NodeTokenRevocationService {
+	return &NodeTokenRevocationService{
+		nodeRepo:     nodeRepo,
+		revokedRepo:  revokedRepo,
+		auditService: auditService,
+	}
+}
+
+// RevokeToken adds a node JWT to the revocation list
+// The entry is kept for NodeJWTExpiration, by which time any token with that jti has expired.
+// Revoking the same jti again returns the original entry.
+func (s *NodeTokenRevocationService) RevokeToken(nodeUUID string, req *RevokeNodeTokenRequest, revokedBy string) (*RevokedNodeTokenResponse, error) {
+	jti := strings.TrimSpace(req.JTI)
+	if !validators.IsValidUUID(jti) {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("invalid jti: must be a UUID"))
+	}
+
+	if _, err := s.nodeRepo.FindByUUID(nodeUUID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, withCode(ErrCodeNodeNotFound, fmt.Errorf("node not found"))
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	entry := &models.RevokedNodeToken{
+		ID:        jti,
+		NodeUUID:  nodeUUID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(NodeJWTExpiration),
+	}
+	if revokedBy != "" {
+		entry.RevokedBy = &revokedBy
+	}
+	if err := s.revokedRepo.Create(entry); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.revokedRepo.FindByID(jti)
+	if err != nil {
+		return nil, err
+	}
+	if stored.NodeUUID != nodeUUID {
+		return nil, withCode(ErrCodeConflict, fmt.Errorf("token %s is already revoked for another node", jti))
+	}
+
+	log.Printf("AUDIT: node token %s of node %s revoked by %s", jti, nodeUUID, auditActor(revokedBy))
+	s.auditService.Record(AuditActionNodeTokenRevoked, revokedBy, models.AuditTargetNode, nodeUUID,
+		fmt.Sprintf("revoked token %s", jti))
+
+	return &RevokedNodeTokenResponse{
+		JTI:       stored.ID,
+		NodeUUID:  stored.NodeUUID,
+		RevokedBy: stored.RevokedBy,
+		RevokedAt: stored.RevokedAt.UTC().Format(time.RFC3339),
+		ExpiresAt: stored.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
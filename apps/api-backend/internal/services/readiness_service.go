@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+)
+
+// Startup steps tracked by the readiness service
+const (
+	ReadinessStepDatabase       = "database"
+	ReadinessStepCleanupService = "cleanup_service"
+)
+
+// ReadinessService tracks which subsystems finished initializing for the /ready endpoint
+// Unlike /ping (the process is up) and /health (subsystems work right now), readiness
+// says whether startup completed, so orchestrators can hold traffic until it has.
+type ReadinessService struct {
+	mu     sync.RWMutex
+	steps  []string
+	done   map[string]time.Time
+	checks map[string]HealthCheckFunc
+}
+
+// NewReadinessService creates a readiness service that is ready once every step is marked
+func NewReadinessService(steps ...string) *ReadinessService {
+	return &ReadinessService{
+		steps:  steps,
+		done:   make(map[string]time.Time, len(steps)),
+		checks: make(map[string]HealthCheckFunc),
+	}
+}
+
+// MarkReady records that a startup step completed
+// check may be nil; when set it runs on every readiness probe, so a subsystem that
+// initialized but is no longer reachable (e.g. the database) reports not ready.
+func (s *ReadinessService) MarkReady(step string, check HealthCheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done[step] = time.Now().UTC()
+	if check != nil {
+		s.checks[step] = check
+	}
+}
+
+// Check reports each step and whether all of them completed and pass their checks
+func (s *ReadinessService) Check(ctx context.Context) (*models.HealthResponse, bool) {
+	s.mu.RLock()
+	steps := append([]string(nil), s.steps...)
+	done := make(map[string]time.Time, len(s.done))
+	for step, at := range s.done {
+		done[step] = at
+	}
+	checks := make(map[string]HealthCheckFunc, len(s.checks))
+	for step, check := range s.checks {
+		checks[step] = check
+	}
+	s.mu.RUnlock()
+	sort.Strings(steps)
+
+	response := &models.HealthResponse{
+		Status:    HealthStatusOK,
+		Timestamp: time.Now(),
+		Service:   "api-backend",
+		Checks:    make(map[string]*models.HealthCheckResult, len(steps)),
+	}
+
+	ready := true
+	for _, step := range steps {
+		var result *models.HealthCheckResult
+		if _, ok := done[step]; !ok {
+			result = runHealthCheck(ctx, func(context.Context) error {
+				return errors.New("not initialized")
+			})
+		} else if check, ok := checks[step]; ok {
+			result = runHealthCheck(ctx, check)
+		} else {
+			result = runHealthCheck(ctx, func(context.Context) error { return nil })
+		}
+
+		if result.Status != HealthStatusOK {
+			ready = false
+			response.Status = HealthStatusError
+		}
+		response.Checks[step] = result
+	}
+
+	return response, ready
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// AppliedMigrationResponse describes one applied migration step
+type AppliedMigrationResponse struct {
+	Version   int    `json:"version" example:"2"`
+	Name      string `json:"name" example:"custom_indexes"`
+	AppliedAt string `json:"applied_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// SchemaVersionResponse reports the database schema version against the version this build expects
+type SchemaVersionResponse struct {
+	// CurrentVersion is the highest applied migration; 0 if none are recorded
+	CurrentVersion int `json:"current_version" example:"2"`
+	// LatestVersion is the version this build migrates to
+	LatestVersion int `json:"latest_version" example:"2"`
+	// UpToDate is true when every migration known to this build has been applied
+	UpToDate   bool                       `json:"up_to_date" example:"true"`
+	Migrations []AppliedMigrationResponse `json:"migrations"`
+}
+
+// SchemaService reports which database migrations have been applied
+type SchemaService struct {
+	migrationRepo *repositories.SchemaMigrationRepository
+	latestVersion int
+}
+
+// NewSchemaService creates a new schema service
+// latestVersion is the schema version this build expects (database.LatestSchemaVersion())
+func NewSchemaService(migrationRepo *repositories.SchemaMigrationRepository, latestVersion int) *SchemaService {
+	return &SchemaService{
+		migrationRepo: migrationRepo,
+		latestVersion: latestVersion,
+	}
+}
+
+// GetVersion returns the current schema version and the list of applied migrations
+func (s *SchemaService) GetVersion() (*SchemaVersionResponse, error) {
+	applied, err := s.migrationRepo.ListApplied()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SchemaVersionResponse{
+		LatestVersion: s.latestVersion,
+		Migrations:    make([]AppliedMigrationResponse, 0, len(applied)),
+	}
+
+	versions := make(map[int]bool, len(applied))
+	for _, m := range applied {
+		versions[m.Version] = true
+		if m.Version > response.CurrentVersion {
+			response.CurrentVersion = m.Version
+		}
+		response.Migrations = append(response.Migrations, AppliedMigrationResponse{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: m.AppliedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	// Every step must be recorded, not just the last one, to count as up to date
+	response.UpToDate = true
+	for version := 1; version <= s.latestVersion; version++ {
+		if !versions[version] {
+			response.UpToDate = false
+			break
+		}
+	}
+
+	return response, nil
+}
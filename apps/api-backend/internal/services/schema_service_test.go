@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestSchemaService_GetVersion tests that a missing step is reported as not up to date
+func TestSchemaService_GetVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	service := NewSchemaService(repositories.NewSchemaMigrationRepository(db), 3)
+
+	for _, m := range []models.SchemaMigration{{Version: 1, Name: "initial_schema"}, {Version: 3, Name: "third"}} {
+		if err := db.Create(&m).Error; err != nil {
+			t.Fatalf("failed to record migration: %v", err)
+		}
+	}
+
+	version, err := service.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if version.CurrentVersion != 3 || version.LatestVersion != 3 {
+		t.Errorf("GetVersion() versions = %d/%d, want 3/3", version.CurrentVersion, version.LatestVersion)
+	}
+	if version.UpToDate {
+		t.Error("GetVersion() UpToDate = true with migration 2 missing")
+	}
+	if len(version.Migrations) != 2 || version.Migrations[0].Version != 1 {
+		t.Errorf("GetVersion() Migrations = %+v, want versions 1 and 3", version.Migrations)
+	}
+
+	if err := db.Create(&models.SchemaMigration{Version: 2, Name: "second"}).Error; err != nil {
+		t.Fatalf("failed to record migration: %v", err)
+	}
+	version, err = service.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if !version.UpToDate {
+		t.Error("GetVersion() UpToDate = false with all migrations applied")
+	}
+}
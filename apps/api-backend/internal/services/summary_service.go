@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// SummaryConfig contains configuration for the admin dashboard summary
+type SummaryConfig struct {
+	// InactiveThreshold is how long a node may go unseen before it counts as inactive
+	InactiveThreshold time.Duration
+}
+
+// DefaultSummaryConfig returns the default summary configuration
+func DefaultSummaryConfig() *SummaryConfig {
+	return &SummaryConfig{
+		InactiveThreshold: 24 * time.Hour,
+	}
+}
+
+// SummaryResponse contains the counts shown on the admin dashboard
+type SummaryResponse struct {
+	TotalNodes    int64 `json:"total_nodes" example:"120"`
+	ActiveNodes   int64 `json:"active_nodes" example:"110"`
+	DisabledNodes int64 `json:"disabled_nodes" example:"6"`
+	RevokedNodes  int64 `json:"revoked_nodes" example:"4"`
+	// InactiveNodes counts nodes of any status not seen within InactiveThresholdHours (or never seen)
+	InactiveNodes          int64 `json:"inactive_nodes" example:"9"`
+	InactiveThresholdHours int   `json:"inactive_threshold_hours" example:"24"`
+
+	TotalTokens   int64 `json:"total_tokens" example:"15"`
+	ActiveTokens  int64 `json:"active_tokens" example:"3"`
+	ExpiredTokens int64 `json:"expired_tokens" example:"12"`
+
+	// LastCleanupAt is when the last background token cleanup finished; null if none has run since startup
+	LastCleanupAt *string `json:"last_cleanup_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	// CleanupPaused is true while an admin has paused automatic cleanup
+	CleanupPaused bool `json:"cleanup_paused" example:"false"`
+}
+
+// SummaryService gathers dashboard counts from several repositories in one call
+type SummaryService struct {
+	nodeRepo       *repositories.NodeRepository
+	tokenRepo      *repositories.RegistrationTokenRepository
+	cleanupService *CleanupService
+	config         *SummaryConfig
+}
+
+// NewSummaryService creates a new summary service
+// cleanupService may be nil, in which case last_cleanup_at is always null
+// If config is nil, uses DefaultSummaryConfig()
+func NewSummaryService(
+	nodeRepo *repositories.NodeRepository,
+	tokenRepo *repositories.RegistrationTokenRepository,
+	cleanupService *CleanupService,
+	config *SummaryConfig,
+) *SummaryService {
+	if config == nil {
+		config = DefaultSummaryConfig()
+	}
+	return &SummaryService{
+		nodeRepo:       nodeRepo,
+		tokenRepo:      tokenRepo,
+		cleanupService: cleanupService,
+		config:         config,
+	}
+}
+
+// GetSummary returns node and token counts, the last cleanup time and whether cleanup is paused
+// The counts run concurrently; the first failure is returned.
+func (s *SummaryService) GetSummary() (*SummaryResponse, error) {
+	response := &SummaryResponse{
+		InactiveThresholdHours: int(s.config.InactiveThreshold / time.Hour),
+	}
+
+	counts := []struct {
+		name  string
+		into  *int64
+		count func() (int64, error)
+	}{
+		{"total nodes", &response.TotalNodes, s.nodeRepo.Count},
+		{"active nodes", &response.ActiveNodes, func() (int64, error) { return s.nodeRepo.CountByStatus(models.NodeStatusActive) }},
+		{"disabled nodes", &response.DisabledNodes, func() (int64, error) { return s.nodeRepo.CountByStatus(models.NodeStatusDisabled) }},
+		{"revoked nodes", &response.RevokedNodes, func() (int64, error) { return s.nodeRepo.CountByStatus(models.NodeStatusRevoked) }},
+		{"inactive nodes", &response.InactiveNodes, func() (int64, error) { return s.nodeRepo.CountInactive(s.config.InactiveThreshold) }},
+		{"total tokens", &response.TotalTokens, s.tokenRepo.Count},
+		{"active tokens", &response.ActiveTokens, s.tokenRepo.CountActive},
+		{"expired tokens", &response.ExpiredTokens, s.tokenRepo.CountExpired},
+	}
+
+	// Each goroutine writes only its own field and error slot
+	errs := make([]error, len(counts))
+	var wg sync.WaitGroup
+	for i, c := range counts {
+		wg.Add(1)
+		go func(i int, name string, into *int64, count func() (int64, error)) {
+			defer wg.Done()
+			value, err := count()
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get %s count: %w", name, err)
+				return
+			}
+			*into = value
+		}(i, c.name, c.into, c.count)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cleanupService != nil {
+		if run := s.cleanupService.LastRun(); run != nil {
+			finishedAt := run.FinishedAt
+			response.LastCleanupAt = &finishedAt
+		}
+
+		state, err := s.cleanupService.State()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cleanup state: %w", err)
+		}
+		response.CleanupPaused = state.Paused
+	}
+
+	return response, nil
+}
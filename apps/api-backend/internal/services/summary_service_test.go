@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestSummaryService_GetSummary tests that the summary combines node, token and cleanup state
+func TestSummaryService_GetSummary(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	// Every connection to :memory: is a separate database; the concurrent counts must share one
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.Node{}, &models.RegistrationToken{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+
+	recently := time.Now().UTC().Add(-time.Hour)
+	longAgo := time.Now().UTC().Add(-48 * time.Hour)
+	nodes := []struct {
+		status   string
+		lastSeen *time.Time
+	}{
+		{models.NodeStatusActive, &recently},
+		{models.NodeStatusActive, &recently},
+		{models.NodeStatusActive, &longAgo},
+		{models.NodeStatusDisabled, nil},
+		{models.NodeStatusRevoked, &recently},
+	}
+	for i, n := range nodes {
+		if err := nodeRepo.Create(&models.Node{
+			UUID:       fmt.Sprintf("550e8400-e29b-41d4-a716-44665544000%d", i),
+			MacAddress: fmt.Sprintf("AA:BB:CC:DD:EE:0%d", i),
+			JWTSecret:  "secret",
+			Status:     n.status,
+			LastSeenAt: n.lastSeen,
+		}); err != nil {
+			t.Fatalf("Create() node error = %v", err)
+		}
+	}
+
+	expiredAt := time.Now().UTC().Add(-time.Hour)
+	validAt := time.Now().UTC().Add(24 * time.Hour)
+	if err := tokenRepo.Create(&models.RegistrationToken{ID: "valid", Token: "valid_token", ExpiresAt: &validAt}); err != nil {
+		t.Fatalf("Create() token error = %v", err)
+	}
+	if err := tokenRepo.CreateAllowPastExpiry(&models.RegistrationToken{ID: "expired", Token: "expired_token", ExpiresAt: &expiredAt}); err != nil {
+		t.Fatalf("CreateAllowPastExpiry() error = %v", err)
+	}
+
+	cleanupService := NewCleanupService(tokenRepo, nil, nil, nil, nil, nil, nil)
+	service := NewSummaryService(nodeRepo, tokenRepo, cleanupService, nil)
+
+	summary, err := service.GetSummary()
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	want := SummaryResponse{
+		TotalNodes:             5,
+		ActiveNodes:            3,
+		DisabledNodes:          1,
+		RevokedNodes:           1,
+		InactiveNodes:          2,
+		InactiveThresholdHours: 24,
+		TotalTokens:            2,
+		ActiveTokens:           1,
+		ExpiredTokens:          1,
+	}
+	if *summary != want {
+		t.Errorf("GetSummary() = %+v, want %+v", *summary, want)
+	}
+
+	// After a cleanup run the summary reports when it finished
+	cleanupService.runCleanup()
+	summary, err = service.GetSummary()
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if summary.LastCleanupAt == nil || *summary.LastCleanupAt != cleanupService.LastRun().FinishedAt {
+		t.Errorf("LastCleanupAt = %v, want %s", summary.LastCleanupAt, cleanupService.LastRun().FinishedAt)
+	}
+	if summary.TotalTokens != 1 || summary.ExpiredTokens != 0 {
+		t.Errorf("tokens after cleanup = %d total, %d expired, want 1 and 0", summary.TotalTokens, summary.ExpiredTokens)
+	}
+}
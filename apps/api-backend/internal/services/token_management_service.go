@@ -2,70 +2,265 @@ package services
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"log"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/boomchecker/api-backend/internal/crypto"
 	"github.com/boomchecker/api-backend/internal/models"
 	"github.com/boomchecker/api-backend/internal/repositories"
 	"github.com/boomchecker/api-backend/internal/validators"
 	"github.com/google/uuid"
 )
 
+// TokenManagementConfig holds policy limits applied when creating registration tokens
+type TokenManagementConfig struct {
+	// DefaultExpiryHours is the lifetime (in hours) used when a request omits expires_in_hours
+	DefaultExpiryHours int
+
+	// MaxExpiryHours is the longest lifetime (in hours) a token may be created with
+	MaxExpiryHours int
+
+	// MaxUses is the highest usage limit a token may be created with
+	MaxUses int
+
+	// TokenBytes is the number of random bytes in a generated token value
+	TokenBytes int
+
+	// IdempotencyKeyTTL is how long an Idempotency-Key replays the original token
+	IdempotencyKeyTTL time.Duration
+
+	// DeleteRetention is how long a deleted token can be restored; zero or negative deletes immediately
+	// Cleanup purges deleted tokens once this has passed, so CleanupConfig.DeletedTokenRetention should match
+	DeleteRetention time.Duration
+
+	// MaxActiveTokens caps how many unexpired tokens with remaining uses may exist at once; zero or negative means no cap
+	MaxActiveTokens int
+}
+
+// DefaultTokenManagementConfig returns the default token policy
+// (24-hour default lifetime, 30 days max, 1000 uses, 32-byte tokens, idempotency keys kept for 24 hours,
+// deleted tokens restorable for 24 hours, no cap on active tokens)
+func DefaultTokenManagementConfig() *TokenManagementConfig {
+	return &TokenManagementConfig{
+		DefaultExpiryHours: 24,
+		MaxExpiryHours:     30 * 24,
+		MaxUses:            1000,
+		TokenBytes:         32,
+		IdempotencyKeyTTL:  24 * time.Hour,
+		DeleteRetention:    24 * time.Hour,
+	}
+}
+
+// Token value limits; entropy is TokenBytes*8 bits whichever format is used
+const (
+	// MinTokenEntropyBits keeps short, human-typeable tokens infeasible to guess
+	MinTokenEntropyBits = 80
+	// MaxTokenBytes bounds generated token length
+	MaxTokenBytes = 64
+)
+
+// Validate checks that the configured token length meets the entropy threshold
+// and that the default lifetime is within the maximum
+func (c *TokenManagementConfig) Validate() error {
+	if c.DefaultExpiryHours < 1 {
+		return fmt.Errorf("default token expiry must be at least 1 hour, got %d", c.DefaultExpiryHours)
+	}
+	if c.MaxExpiryHours > 0 && c.DefaultExpiryHours > c.MaxExpiryHours {
+		return fmt.Errorf("default token expiry (%d hours) must not exceed the maximum (%d hours)", c.DefaultExpiryHours, c.MaxExpiryHours)
+	}
+	if c.TokenBytes*8 < MinTokenEntropyBits {
+		return fmt.Errorf("token length must be at least %d bytes (%d bits of entropy), got %d", MinTokenEntropyBits/8, MinTokenEntropyBits, c.TokenBytes)
+	}
+	if c.TokenBytes > MaxTokenBytes {
+		return fmt.Errorf("token length must not exceed %d bytes, got %d", MaxTokenBytes, c.TokenBytes)
+	}
+	return nil
+}
+
+// Token value formats
+const (
+	// TokenFormatBase64URL is URL-safe base64 without padding (default, for machine provisioning)
+	TokenFormatBase64URL = "base64url"
+	// TokenFormatBase32 is Crockford base32 (no I, L, O, U), uppercase, for typing tokens by hand
+	TokenFormatBase32 = "base32"
+)
+
+// crockfordBase32 encodes with the Crockford alphabet, which omits characters easily misread
+var crockfordBase32 = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// maxTokenGenerationAttempts bounds retries when a generated value collides with an existing token
+const maxTokenGenerationAttempts = 3
+
 // TokenManagementService handles the business logic for registration token management
 type TokenManagementService struct {
-	tokenRepo *repositories.RegistrationTokenRepository
+	tokenRepo       *repositories.RegistrationTokenRepository
+	eventRepo       *repositories.RegistrationEventRepository
+	idempotencyRepo *repositories.IdempotencyKeyRepository
+	auditService    *AuditService
+	config          *TokenManagementConfig
 }
 
 // NewTokenManagementService creates a new token management service instance
-func NewTokenManagementService(tokenRepo *repositories.RegistrationTokenRepository) *TokenManagementService {
+// auditService may be nil, in which case revoking all tokens is only written to the server log.
+// If config is nil, DefaultTokenManagementConfig is used
+func NewTokenManagementService(
+	tokenRepo *repositories.RegistrationTokenRepository,
+	eventRepo *repositories.RegistrationEventRepository,
+	idempotencyRepo *repositories.IdempotencyKeyRepository,
+	auditService *AuditService,
+	config *TokenManagementConfig,
+) *TokenManagementService {
+	if config == nil {
+		config = DefaultTokenManagementConfig()
+	}
+
 	return &TokenManagementService{
-		tokenRepo: tokenRepo,
+		tokenRepo:       tokenRepo,
+		eventRepo:       eventRepo,
+		idempotencyRepo: idempotencyRepo,
+		auditService:    auditService,
+		config:          config,
+	}
+}
+
+// IdempotencyScopeCreateToken is the idempotency key scope for token creation
+// Keys are further scoped per admin (see idempotencyScope).
+const IdempotencyScopeCreateToken = "registration_token.create"
+
+// idempotencyScope scopes an operation's idempotency keys to the admin making the request
+// so two admins using the same key never see each other's result. Without admin
+// authentication adminEmail is empty and all requests share the operation scope.
+func idempotencyScope(operation, adminEmail string) string {
+	email := strings.ToLower(strings.TrimSpace(adminEmail))
+	if email == "" {
+		return operation
 	}
+	return operation + ":" + email
 }
 
+// MaxIdempotencyKeyLength bounds client-supplied Idempotency-Key values
+const MaxIdempotencyKeyLength = 255
+
 // CreateTokenRequest contains the data needed to create a registration token
 type CreateTokenRequest struct {
-	ExpiresInHours   int     `json:"expires_in_hours" binding:"required,min=1" example:"24" swaggertype:"integer" minimum:"1"`
-	MaxUses          *int    `json:"max_uses,omitempty" binding:"omitempty,min=1" example:"1" swaggertype:"integer" minimum:"1"` // If not provided, defaults to 1
-	AuthorizedMAC    *string `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
-	Description      *string `json:"description,omitempty" example:"Token for production nodes"`
+	ExpiresInHours      int                    `json:"expires_in_hours,omitempty" binding:"omitempty,min=1" example:"24" swaggertype:"integer" minimum:"1"`   // If not provided, defaults to DEFAULT_TOKEN_EXPIRY_HOURS
+	MaxUses             *int                   `json:"max_uses,omitempty" binding:"omitempty,min=1" example:"1" swaggertype:"integer" minimum:"1"`            // If not provided, defaults to 1
+	DistinctMacLimit    *int                   `json:"distinct_mac_limit,omitempty" binding:"omitempty,min=1" example:"50" swaggertype:"integer" minimum:"1"` // Most distinct MACs that may register; if not provided, no cap
+	AuthorizedMAC       *string                `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
+	Description         *string                `json:"description,omitempty" example:"Token for production nodes"`
+	AllowReRegistration *bool                  `json:"allow_re_registration,omitempty" example:"true"`                   // If not provided, defaults to true
+	TokenFormat         *string                `json:"token_format,omitempty" example:"base32" enums:"base64url,base32"` // If not provided, defaults to base64url
+	OwnerID             *string                `json:"owner_id,omitempty" example:"acme"`                                // Tenant that registered nodes belong to
+	CustomClaims        map[string]interface{} `json:"custom_claims,omitempty" swaggertype:"object"`                     // Signed into node JWTs under the "ext" claim
 }
 
 // CreateTokenResponse contains the data returned after creating a token
+// This is the only response that includes the full token value
 type CreateTokenResponse struct {
-	Token         string     `json:"token" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
-	ExpiresAt     string     `json:"expires_at" example:"2025-11-11T14:30:00Z"`
-	MaxUses       *int       `json:"max_uses,omitempty" example:"1"`
-	AuthorizedMAC *string    `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
-	Description   *string    `json:"description,omitempty" example:"Token for production nodes"`
-	CreatedAt     string     `json:"created_at" example:"2025-11-10T14:30:00Z"`
+	ID                  string                 `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Token               string                 `json:"token" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
+	ExpiresAt           string                 `json:"expires_at" example:"2025-11-11T14:30:00Z"`
+	MaxUses             *int                   `json:"max_uses,omitempty" example:"1"`
+	DistinctMacLimit    *int                   `json:"distinct_mac_limit,omitempty" example:"50"`
+	AuthorizedMAC       *string                `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
+	Description         *string                `json:"description,omitempty" example:"Token for production nodes"`
+	AllowReRegistration bool                   `json:"allow_re_registration" example:"true"`
+	OwnerID             *string                `json:"owner_id,omitempty" example:"acme"`
+	CustomClaims        map[string]interface{} `json:"custom_claims,omitempty" swaggertype:"object"`
+	CreatedBy           *string                `json:"created_by,omitempty" example:"admin@example.com"`
+	CreatedAt           string                 `json:"created_at" example:"2025-11-10T14:30:00Z"`
 }
 
 // TokenListResponse contains information about a token for listing
+// The secret value is never included, only its fingerprint
 type TokenListResponse struct {
-	Token         string     `json:"token" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
-	ExpiresAt     string     `json:"expires_at" example:"2025-11-11T14:30:00Z"`
-	MaxUses       *int       `json:"max_uses,omitempty" example:"1"`
-	UsedCount     int        `json:"used_count" example:"0"`
-	AuthorizedMAC *string    `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
-	Description   *string    `json:"description,omitempty" example:"Token for production nodes"`
-	IsExpired     bool       `json:"is_expired" example:"false"`
-	IsActive      bool       `json:"is_active" example:"true"`
-	CreatedAt     string     `json:"created_at" example:"2025-11-10T14:30:00Z"`
+	ID                  string                 `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TokenFingerprint    string                 `json:"token_fingerprint" example:"a1b2c3d4...9f86d081"`
+	ExpiresAt           string                 `json:"expires_at" example:"2025-11-11T14:30:00Z"`
+	MaxUses             *int                   `json:"max_uses,omitempty" example:"1"`
+	DistinctMacLimit    *int                   `json:"distinct_mac_limit,omitempty" example:"50"`
+	UsedCount           int                    `json:"used_count" example:"0"`
+	RemainingUses       *int                   `json:"remaining_uses" example:"1"` // null for unlimited tokens, never negative
+	AuthorizedMAC       *string                `json:"authorized_mac,omitempty" example:"AA:BB:CC:DD:EE:FF"`
+	Description         *string                `json:"description,omitempty" example:"Token for production nodes"`
+	AllowReRegistration bool                   `json:"allow_re_registration" example:"true"`
+	IsExpired           bool                   `json:"is_expired" example:"false"`
+	IsActive            bool                   `json:"is_active" example:"true"`
+	OwnerID             *string                `json:"owner_id,omitempty" example:"acme"`
+	CustomClaims        map[string]interface{} `json:"custom_claims,omitempty" swaggertype:"object"`
+	CreatedBy           *string                `json:"created_by,omitempty" example:"admin@example.com"`
+	CreatedAt           string                 `json:"created_at" example:"2025-11-10T14:30:00Z"`
+}
+
+// Token search page size limits
+const (
+	DefaultTokenSearchPageSize = 50
+	MaxTokenSearchPageSize     = 200
+)
+
+// TokenSearchQuery finds tokens by description, creator and owner; at least one term is required
+type TokenSearchQuery struct {
+	Q         string `form:"q" example:"batch 7"`                    // Case-insensitive substring of the description
+	CreatedBy string `form:"created_by" example:"admin@example.com"` // Case-insensitive substring of the creating admin email
+	OwnerID   string `form:"owner_id" example:"acme"`                // Exact tenant of the token
+	Page      int    `form:"page" example:"1"`                       // 1-based, defaults to 1
+	PageSize  int    `form:"page_size" example:"50"`                 // Defaults to 50, at most 200
+}
+
+// TokenSearchResponse is one page of token search results, newest first
+type TokenSearchResponse struct {
+	Tokens   []*TokenListResponse `json:"tokens"`
+	Page     int                  `json:"page" example:"1"`
+	PageSize int                  `json:"page_size" example:"50"`
+	// Total is the number of tokens matching the search across all pages
+	Total int64 `json:"total" example:"1"`
+}
+
+// TokenNodesQuery pages through the nodes registered with a token
+type TokenNodesQuery struct {
+	Page     int `form:"page" example:"1"`       // 1-based, defaults to 1
+	PageSize int `form:"page_size" example:"50"` // Defaults to 50, at most 200
+}
+
+// TokenNodesResponse is one page of the nodes registered or re-registered with a token, oldest first
+type TokenNodesResponse struct {
+	TokenID  string          `json:"token_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Nodes    []*NodeResponse `json:"nodes"`
+	Page     int             `json:"page" example:"1"`
+	PageSize int             `json:"page_size" example:"50"`
+	// Total is the number of nodes registered with the token across all pages
+	Total int64 `json:"total" example:"1"`
+}
+
+// TokenRevealResponse contains the full secret value of a token
+type TokenRevealResponse struct {
+	ID    string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Token string `json:"token" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
 }
 
 // CreateToken generates a new registration token
-func (s *TokenManagementService) CreateToken(req *CreateTokenRequest) (*CreateTokenResponse, error) {
+// createdBy is the authenticated admin email; empty when admin login is disabled
+func (s *TokenManagementService) CreateToken(req *CreateTokenRequest, createdBy string) (*CreateTokenResponse, error) {
 	// Validate request
 	if err := s.validateCreateTokenRequest(req); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
 	}
 
-	// Generate secure random token
-	tokenValue, err := generateSecureToken(32) // 32 bytes = 256 bits
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	if err := s.checkActiveTokenLimit(); err != nil {
+		return nil, err
+	}
+
+	format := TokenFormatBase64URL
+	if req.TokenFormat != nil && *req.TokenFormat != "" {
+		format = *req.TokenFormat
 	}
 
 	// Generate UUID for token ID
@@ -73,7 +268,7 @@ func (s *TokenManagementService) CreateToken(req *CreateTokenRequest) (*CreateTo
 
 	// Calculate expiration time
 	now := time.Now().UTC()
-	expiresAt := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+	expiresAt := now.Add(time.Duration(s.expiresInHours(req)) * time.Hour)
 
 	// Normalize MAC address if provided
 	var authorizedMAC *string
@@ -95,26 +290,146 @@ func (s *TokenManagementService) CreateToken(req *CreateTokenRequest) (*CreateTo
 	// Create token model
 	token := &models.RegistrationToken{
 		ID:                      tokenID,
-		Token:                   tokenValue,
 		ExpiresAt:               &expiresAt,
 		UsageLimit:              maxUses,
 		UsedCount:               0,
+		DistinctMacLimit:        req.DistinctMacLimit,
 		PreAuthorizedMacAddress: authorizedMAC,
+		AllowReRegistration:     req.AllowReRegistration,
+	}
+	if req.Description != nil && *req.Description != "" {
+		token.Description = req.Description
+	}
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		token.OwnerID = req.OwnerID
+	}
+	if len(req.CustomClaims) > 0 {
+		encoded, err := json.Marshal(req.CustomClaims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode custom claims: %w", err)
+		}
+		customClaims := string(encoded)
+		token.CustomClaims = &customClaims
+	}
+	if createdBy != "" {
+		token.CreatedBy = &createdBy
 	}
 
-	// Save to database
-	if err := s.tokenRepo.Create(token); err != nil {
-		return nil, fmt.Errorf("failed to create token: %w", err)
+	// Save to database, generating a fresh value if it collides with an existing token
+	for attempt := 1; ; attempt++ {
+		tokenValue, err := generateSecureToken(s.config.TokenBytes, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate token: %w", err)
+		}
+		token.Token = tokenValue
+
+		err = s.tokenRepo.Create(token)
+		if err == nil {
+			break
+		}
+		if attempt >= maxTokenGenerationAttempts || !strings.Contains(err.Error(), "token already exists") {
+			return nil, fmt.Errorf("failed to create token: %w", err)
+		}
 	}
 
-	return &CreateTokenResponse{
-		Token:         token.Token,
-		ExpiresAt:     token.ExpiresAt.UTC().Format(time.RFC3339),
-		MaxUses:       token.UsageLimit,
-		AuthorizedMAC: token.PreAuthorizedMacAddress,
-		Description:   req.Description,
-		CreatedAt:     token.CreatedAt.UTC().Format(time.RFC3339),
-	}, nil
+	return toCreateTokenResponse(token, req.Description), nil
+}
+
+// checkActiveTokenLimit rejects token creation once MaxActiveTokens tokens are active
+func (s *TokenManagementService) checkActiveTokenLimit() error {
+	if s.config.MaxActiveTokens <= 0 {
+		return nil
+	}
+
+	activeCount, err := s.tokenRepo.CountActive()
+	if err != nil {
+		return fmt.Errorf("failed to count active tokens: %w", err)
+	}
+	if activeCount >= int64(s.config.MaxActiveTokens) {
+		return withCode(ErrCodeTokenLimitReached, fmt.Errorf(
+			"active token limit reached: %d of %d tokens are active; delete tokens that are no longer needed or wait for them to expire before creating more",
+			activeCount, s.config.MaxActiveTokens))
+	}
+	return nil
+}
+
+// CreateTokenIdempotent creates a token at most once per Idempotency-Key
+// A retry with the same key and request returns the originally created token with replayed=true.
+// Reusing a key for a different request, or while the first request is still running, is an error.
+// Keys are scoped to createdBy. An empty key behaves like CreateToken.
+func (s *TokenManagementService) CreateTokenIdempotent(key string, req *CreateTokenRequest, createdBy string) (*CreateTokenResponse, bool, error) {
+	if key == "" {
+		response, err := s.CreateToken(req, createdBy)
+		return response, false, err
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return nil, false, fmt.Errorf("validation failed: Idempotency-Key must be at most %d characters", MaxIdempotencyKeyLength)
+	}
+	if err := s.validateCreateTokenRequest(req); err != nil {
+		return nil, false, fmt.Errorf("validation failed: %w", err)
+	}
+
+	requestHash, err := hashRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+	scope := idempotencyScope(IdempotencyScopeCreateToken, createdBy)
+
+	// Expired keys may be reused for new requests
+	if _, err := s.idempotencyRepo.DeleteExpired(); err != nil {
+		log.Printf("Warning: failed to delete expired idempotency keys: %v", err)
+	}
+
+	reserved, err := s.idempotencyRepo.Reserve(&models.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().UTC().Add(s.config.IdempotencyKeyTTL),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !reserved {
+		response, err := s.replayCreateToken(scope, key, requestHash, req)
+		return response, err == nil, err
+	}
+
+	response, err := s.CreateToken(req, createdBy)
+	if err != nil {
+		// Release the key so the client can retry the failed request
+		if delErr := s.idempotencyRepo.Delete(scope, key); delErr != nil {
+			log.Printf("Warning: failed to release idempotency key: %v", delErr)
+		}
+		return nil, false, err
+	}
+
+	if err := s.idempotencyRepo.Complete(scope, key, response.ID); err != nil {
+		log.Printf("Warning: failed to complete idempotency key for token %s: %v", response.ID, err)
+	}
+
+	return response, false, nil
+}
+
+// replayCreateToken returns the token created by an earlier request with the same idempotency key
+func (s *TokenManagementService) replayCreateToken(scope, key, requestHash string, req *CreateTokenRequest) (*CreateTokenResponse, error) {
+	record, err := s.idempotencyRepo.Find(scope, key)
+	if err != nil {
+		return nil, err
+	}
+	if record.RequestHash != requestHash {
+		return nil, withCode(ErrCodeIdempotencyKeyReused, fmt.Errorf("idempotency key reused: Idempotency-Key was already used with a different request"))
+	}
+	if record.ResourceID == nil {
+		return nil, fmt.Errorf("idempotency key in progress: a request with this Idempotency-Key is still being processed")
+	}
+
+	token, err := s.tokenRepo.FindByID(*record.ResourceID)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency key conflict: the token created for this Idempotency-Key no longer exists")
+	}
+
+	// The request matched the original, so its description is the original description
+	return toCreateTokenResponse(token, req.Description), nil
 }
 
 // ListAllTokens returns all registration tokens
@@ -137,50 +452,385 @@ func (s *TokenManagementService) ListActiveTokens() ([]*TokenListResponse, error
 	return s.convertToListResponse(tokens), nil
 }
 
-// GetToken retrieves a specific token by its value
-func (s *TokenManagementService) GetToken(tokenValue string) (*TokenListResponse, error) {
-	token, err := s.tokenRepo.FindByToken(tokenValue)
+// SearchTokens returns one page of tokens whose description and creator contain the search terms
+func (s *TokenManagementService) SearchTokens(query *TokenSearchQuery) (*TokenSearchResponse, error) {
+	filter, page, pageSize, err := buildTokenSearchFilter(query)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	tokens, total, err := s.tokenRepo.Search(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenSearchResponse{
+		Tokens:   s.convertToListResponse(tokens),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+// buildTokenSearchFilter validates the search query and converts it to a repository filter
+func buildTokenSearchFilter(query *TokenSearchQuery) (filter repositories.TokenSearchFilter, page, pageSize int, err error) {
+	filter.Description = strings.TrimSpace(query.Q)
+	filter.CreatedBy = strings.TrimSpace(query.CreatedBy)
+	filter.OwnerID = strings.TrimSpace(query.OwnerID)
+	if filter.Description == "" && filter.CreatedBy == "" && filter.OwnerID == "" {
+		return filter, 0, 0, fmt.Errorf("q, created_by or owner_id is required")
+	}
+	if len(filter.Description) > validators.MaxDescriptionLength {
+		return filter, 0, 0, fmt.Errorf("q must not exceed %d characters", validators.MaxDescriptionLength)
+	}
+	if len(filter.CreatedBy) > validators.MaxDescriptionLength {
+		return filter, 0, 0, fmt.Errorf("created_by must not exceed %d characters", validators.MaxDescriptionLength)
+	}
+	if filter.OwnerID != "" {
+		if err := validators.ValidateOwnerID(filter.OwnerID, "owner_id"); err != nil {
+			return filter, 0, 0, err
+		}
+	}
+
+	page, pageSize, err = resolveTokenPage(query.Page, query.PageSize)
+	if err != nil {
+		return filter, 0, 0, err
+	}
+
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+	return filter, page, pageSize, nil
+}
+
+// resolveTokenPage applies the page defaults and limits shared by the token listing endpoints
+func resolveTokenPage(page, pageSize int) (int, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return 0, 0, fmt.Errorf("page must be at least 1")
+	}
+
+	if pageSize == 0 {
+		pageSize = DefaultTokenSearchPageSize
+	}
+	if pageSize < 1 || pageSize > MaxTokenSearchPageSize {
+		return 0, 0, fmt.Errorf("page_size must be between 1 and %d", MaxTokenSearchPageSize)
+	}
+
+	return page, pageSize, nil
+}
+
+// ListTokenNodes returns one page of the nodes registered or re-registered with a token
+// The token is identified by its ID or value. Registration events keep the token ID after the
+// token itself is deleted, so the nodes of a deleted token can still be listed by its ID.
+func (s *TokenManagementService) ListTokenNodes(tokenRef string, query *TokenNodesQuery) (*TokenNodesResponse, error) {
+	page, pageSize, err := resolveTokenPage(query.Page, query.PageSize)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	tokenID := tokenRef
+	token, findErr := s.findToken(tokenRef)
+	if findErr == nil {
+		tokenID = token.ID
+	}
+
+	nodes, total, err := s.eventRepo.ListNodesByToken(tokenID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if findErr != nil && total == 0 {
+		return nil, withCode(ErrCodeTokenNotFound, fmt.Errorf("token not found: %w", findErr))
+	}
+
+	response := &TokenNodesResponse{
+		TokenID:  tokenID,
+		Nodes:    make([]*NodeResponse, len(nodes)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for i, node := range nodes {
+		response.Nodes[i] = toNodeResponse(node)
+	}
+
+	return response, nil
+}
+
+// GetToken retrieves a specific token by its ID or value
+func (s *TokenManagementService) GetToken(tokenRef string) (*TokenListResponse, error) {
+	token, err := s.findToken(tokenRef)
 	if err != nil {
 		return nil, fmt.Errorf("token not found: %w", err)
 	}
 
-	expiresAt := ""
-	if token.ExpiresAt != nil {
-		expiresAt = token.ExpiresAt.UTC().Format(time.RFC3339)
+	return toTokenListResponse(token), nil
+}
+
+// RevealToken returns the full secret value of a token identified by its ID or value
+// Only use when the value is truly needed; list and detail responses expose the fingerprint only
+func (s *TokenManagementService) RevealToken(tokenRef string) (*TokenRevealResponse, error) {
+	token, err := s.findToken(tokenRef)
+	if err != nil {
+		return nil, fmt.Errorf("token not found: %w", err)
 	}
 
-	return &TokenListResponse{
-		Token:         token.Token,
-		ExpiresAt:     expiresAt,
-		MaxUses:       token.UsageLimit,
-		UsedCount:     token.UsedCount,
-		AuthorizedMAC: token.PreAuthorizedMacAddress,
-		Description:   nil, // Model doesn't have Description field
-		IsExpired:     token.IsExpired(),
-		IsActive:      token.IsValid(),
-		CreatedAt:     token.CreatedAt.UTC().Format(time.RFC3339),
+	return &TokenRevealResponse{
+		ID:    token.ID,
+		Token: token.Token,
 	}, nil
 }
 
-// DeleteToken removes a token from the database
-func (s *TokenManagementService) DeleteToken(tokenValue string) error {
-	if err := s.tokenRepo.Delete(tokenValue); err != nil {
+// DeleteToken deletes a token identified by its ID or value
+// The token stops working immediately. Within DeleteRetention it can be brought back with
+// RestoreToken; without a retention window it is removed permanently.
+func (s *TokenManagementService) DeleteToken(tokenRef string) error {
+	token, err := s.findToken(tokenRef)
+	if err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+
+	deleteToken := s.tokenRepo.Delete
+	if s.config.DeleteRetention <= 0 {
+		deleteToken = s.tokenRepo.HardDelete
+	}
+	if err := deleteToken(token.Token); err != nil {
 		return fmt.Errorf("failed to delete token: %w", err)
 	}
 	return nil
 }
 
+// RestoreToken undoes the deletion of a token identified by its ID or value
+// Only possible within DeleteRetention of the deletion; the restored token keeps its
+// expiry and remaining uses, so an expired token stays unusable.
+func (s *TokenManagementService) RestoreToken(tokenRef string) (*TokenListResponse, error) {
+	token, err := s.tokenRepo.FindDeleted(tokenRef)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to") {
+			return nil, err
+		}
+		return nil, withCode(ErrCodeTokenNotFound, fmt.Errorf("token not found: %w", err))
+	}
+	if time.Since(token.DeletedAt.Time) > s.config.DeleteRetention {
+		return nil, withCode(ErrCodeTokenNotFound, fmt.Errorf("token not found: undo window of %s has passed", s.config.DeleteRetention))
+	}
+
+	if err := s.tokenRepo.Restore(token.ID); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.tokenRepo.FindByID(token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load restored token: %w", err)
+	}
+
+	return toTokenListResponse(restored), nil
+}
+
+// UpdateTokenRequest changes the usage limit of an existing token
+type UpdateTokenRequest struct {
+	// MaxUses is the new usage limit; it may be lowered, but not below the token's used count
+	MaxUses *int `json:"max_uses" binding:"required" example:"50" swaggertype:"integer" minimum:"1"`
+}
+
+// UpdateToken sets a new usage limit for a token identified by its ID or value
+// This lets an admin grow (or shrink) a batch token's capacity without issuing a new token.
+// The limit must be at least 1, within the configured maximum, and not below the uses already made.
+func (s *TokenManagementService) UpdateToken(tokenRef string, req *UpdateTokenRequest) (*TokenListResponse, error) {
+	if req.MaxUses == nil || *req.MaxUses < 1 {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: max_uses must be at least 1"))
+	}
+	if s.config.MaxUses > 0 && *req.MaxUses > s.config.MaxUses {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: max_uses must not exceed %d (configured maximum token uses)", s.config.MaxUses))
+	}
+
+	token, err := s.findToken(tokenRef)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to") {
+			return nil, err
+		}
+		return nil, withCode(ErrCodeTokenNotFound, fmt.Errorf("token not found: %w", err))
+	}
+
+	var validator validators.RegistrationTokenValidator
+	if err := validator.ValidateUsageLimit(req.MaxUses, token.UsedCount); err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	updated, err := s.tokenRepo.SetUsageLimit(token.ID, *req.MaxUses)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload in both cases: the used count may have grown since it was checked above
+	token, err = s.tokenRepo.FindByID(token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load updated token: %w", err)
+	}
+	if !updated {
+		err := validator.ValidateUsageLimit(req.MaxUses, token.UsedCount)
+		if err == nil {
+			err = fmt.Errorf("token was used while its limit was being changed")
+		}
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	return toTokenListResponse(token), nil
+}
+
+// ExpireToken makes a token identified by its ID or value expire now
+// Unlike DeleteToken the record stays listed with its usage history and registered nodes,
+// and cleanup removes it like any other expired token. An already expired token is returned unchanged.
+func (s *TokenManagementService) ExpireToken(tokenRef string) (*TokenListResponse, error) {
+	token, err := s.findToken(tokenRef)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "failed to") {
+			return nil, err
+		}
+		return nil, withCode(ErrCodeTokenNotFound, fmt.Errorf("token not found: %w", err))
+	}
+
+	if !token.IsExpired() {
+		if err := s.tokenRepo.Expire(token.ID, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		if token, err = s.tokenRepo.FindByID(token.ID); err != nil {
+			return nil, fmt.Errorf("failed to load expired token: %w", err)
+		}
+	}
+
+	return toTokenListResponse(token), nil
+}
+
+// RevokeAllTokensRequest confirms revoking every registration token
+type RevokeAllTokensRequest struct {
+	// Confirm must be true; no node can register until a new token is created
+	Confirm bool `json:"confirm" example:"true"`
+}
+
+// RevokeAllTokensResponse reports the outcome of revoking all tokens
+type RevokeAllTokensResponse struct {
+	Message       string `json:"message" example:"All registration tokens revoked"`
+	RevokedTokens int64  `json:"revoked_tokens" example:"12"`               // Tokens that were still unexpired
+	RevokedAt     string `json:"revoked_at" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+}
+
+// RevokeAllTokens is the breach kill switch: it makes every unexpired token expire now
+// Tokens are kept, like with ExpireToken, so their usage and registered nodes stay visible
+// for the investigation. revokedBy is the admin email recorded in the audit log.
+func (s *TokenManagementService) RevokeAllTokens(req *RevokeAllTokensRequest, revokedBy string) (*RevokeAllTokensResponse, error) {
+	if req == nil || !req.Confirm {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: confirm must be true; revoking all tokens stops every pending registration"))
+	}
+
+	revokedAt := time.Now().UTC()
+	revoked, err := s.tokenRepo.ExpireAll(revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: HIGH SEVERITY: all registration tokens revoked by %s at %s; %d tokens expired", auditActor(revokedBy), revokedAt.Format(time.RFC3339), revoked)
+	s.auditService.Record(AuditActionTokensRevokedAll, revokedBy, models.AuditTargetToken, "",
+		fmt.Sprintf("severity=high; %d registration tokens expired", revoked))
+
+	return &RevokeAllTokensResponse{
+		Message:       "All registration tokens revoked; create new tokens to register nodes again",
+		RevokedTokens: revoked,
+		RevokedAt:     revokedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// DeleteTokensQuery selects the tokens removed by a bulk delete
+// At least one filter is required so a bare request cannot delete every token.
+type DeleteTokensQuery struct {
+	Status        string `form:"status" example:"expired"`                      // active, expired or exhausted
+	CreatedBefore string `form:"created_before" example:"2025-11-10T14:30:00Z"` // UTC timestamp (RFC3339 format)
+	OwnerID       string `form:"owner_id" example:"acme"`                       // Exact tenant of the token
+}
+
+// DeleteTokensResponse reports the outcome of a bulk delete
+type DeleteTokensResponse struct {
+	Message       string `json:"message" example:"Tokens deleted successfully"`
+	DeletedTokens int64  `json:"deleted_tokens" example:"12"`
+	Restorable    bool   `json:"restorable" example:"true"` // Whether the tokens can be restored within the undo window
+}
+
+// DeleteTokens removes every token matching the query in one transaction
+// Like DeleteToken, tokens are soft-deleted while DeleteRetention is set and can be
+// restored individually. deletedBy is the admin email recorded in the audit log.
+func (s *TokenManagementService) DeleteTokens(query *DeleteTokensQuery, deletedBy string) (*DeleteTokensResponse, error) {
+	filter, err := buildTokenDeleteFilter(query)
+	if err != nil {
+		return nil, withCode(ErrCodeValidationFailed, fmt.Errorf("validation failed: %w", err))
+	}
+
+	hard := s.config.DeleteRetention <= 0
+	deleted, err := s.tokenRepo.DeleteMatching(filter, hard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tokens: %w", err)
+	}
+
+	details := fmt.Sprintf("%d registration tokens deleted; status=%q created_before=%q owner_id=%q",
+		deleted, query.Status, query.CreatedBefore, query.OwnerID)
+	log.Printf("AUDIT: %s by %s", details, auditActor(deletedBy))
+	s.auditService.Record(AuditActionTokensBulkDeleted, deletedBy, models.AuditTargetToken, "", details)
+
+	return &DeleteTokensResponse{
+		Message:       "Tokens deleted successfully",
+		DeletedTokens: deleted,
+		Restorable:    !hard && deleted > 0,
+	}, nil
+}
+
+// buildTokenDeleteFilter validates the bulk delete query and converts it to a repository filter
+func buildTokenDeleteFilter(query *DeleteTokensQuery) (repositories.TokenDeleteFilter, error) {
+	var filter repositories.TokenDeleteFilter
+	if query == nil || (query.Status == "" && query.CreatedBefore == "" && query.OwnerID == "") {
+		return filter, fmt.Errorf("at least one of status, created_before or owner_id is required")
+	}
+
+	switch query.Status {
+	case "", repositories.TokenStateActive, repositories.TokenStateExpired, repositories.TokenStateExhausted:
+		filter.Status = query.Status
+	default:
+		return filter, fmt.Errorf("status must be one of active, expired, exhausted")
+	}
+
+	if query.CreatedBefore != "" {
+		createdBefore, err := validators.ParseUTCTimestamp(query.CreatedBefore)
+		if err != nil {
+			return filter, fmt.Errorf("created_before must be a UTC timestamp (e.g. 2025-11-10T14:30:00Z)")
+		}
+		filter.CreatedBefore = &createdBefore
+	}
+
+	filter.OwnerID = query.OwnerID
+	return filter, nil
+}
+
 // CleanupExpiredTokens removes all expired tokens
-// Returns the number of tokens deleted
-func (s *TokenManagementService) CleanupExpiredTokens() (int64, error) {
+// Returns the number of tokens deleted. With dryRun nothing is deleted; the count and
+// IDs of the tokens that would be deleted are returned instead.
+func (s *TokenManagementService) CleanupExpiredTokens(dryRun bool) (int64, []string, error) {
+	if dryRun {
+		ids, err := s.tokenRepo.FindExpiredIDs()
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to preview expired token cleanup: %w", err)
+		}
+		return int64(len(ids)), ids, nil
+	}
+
 	count, err := s.tokenRepo.CleanupExpired()
 	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup expired tokens: %w", err)
+		return 0, nil, fmt.Errorf("failed to cleanup expired tokens: %w", err)
 	}
-	return count, nil
+	return count, nil, nil
 }
 
 // GetStatistics returns statistics about registration tokens
+// Besides the lifecycle counts it breaks tokens down by usage limit, since a growing number of
+// unlimited tokens is a risk; max_uses_per_token is the configured limit for new tokens.
 func (s *TokenManagementService) GetStatistics() (map[string]interface{}, error) {
 	totalCount, err := s.tokenRepo.Count()
 	if err != nil {
@@ -197,23 +847,135 @@ func (s *TokenManagementService) GetStatistics() (map[string]interface{}, error)
 		return nil, fmt.Errorf("failed to get expired count: %w", err)
 	}
 
+	usage, err := s.tokenRepo.CountByUsageKind()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage counts: %w", err)
+	}
+
+	byKind := make(map[string]int64, 3)
+	var totalUses, exhaustedCount int64
+	for _, count := range usage {
+		byKind[count.Kind] = count.Count
+		totalUses += count.Uses
+		exhaustedCount += count.Exhausted
+	}
+
+	averageUses := 0.0
+	if totalCount > 0 {
+		averageUses = math.Round(float64(totalUses)/float64(totalCount)*100) / 100
+	}
+
 	return map[string]interface{}{
-		"total_tokens":   totalCount,
-		"active_tokens":  activeCount,
-		"expired_tokens": expiredCount,
+		"total_tokens":           totalCount,
+		"active_tokens":          activeCount,
+		"expired_tokens":         expiredCount,
+		"exhausted_tokens":       exhaustedCount,
+		"single_use_tokens":      byKind[repositories.TokenUsageSingleUse],
+		"multi_use_tokens":       byKind[repositories.TokenUsageMultiUse],
+		"unlimited_tokens":       byKind[repositories.TokenUsageUnlimited],
+		"total_uses":             totalUses,
+		"average_uses_per_token": averageUses,
+		"max_uses_per_token":     s.config.MaxUses,
 	}, nil
 }
 
+// MaxTimelineDays is the longest period accepted by GetStatisticsTimeline
+const MaxTimelineDays = 365
+
+// TimelineDay contains token activity for one UTC day
+type TimelineDay struct {
+	Date           string `json:"date" example:"2025-11-10"` // UTC day (YYYY-MM-DD)
+	TokensCreated  int64  `json:"tokens_created" example:"3"`
+	TokensConsumed int64  `json:"tokens_consumed" example:"12"` // Successful registrations and re-registrations
+}
+
+// StatisticsTimelineResponse contains daily token activity, oldest day first
+type StatisticsTimelineResponse struct {
+	Days                int            `json:"days" example:"30"`
+	TotalTokensCreated  int64          `json:"total_tokens_created" example:"42"`
+	TotalTokensConsumed int64          `json:"total_tokens_consumed" example:"310"`
+	Timeline            []*TimelineDay `json:"timeline"`
+}
+
+// GetStatisticsTimeline returns daily counts of tokens created and consumed over the last N days
+// The current day is included; days without activity are reported with zero counts
+func (s *TokenManagementService) GetStatisticsTimeline(days int) (*StatisticsTimelineResponse, error) {
+	if days < 1 || days > MaxTimelineDays {
+		return nil, fmt.Errorf("validation failed: days must be between 1 and %d", MaxTimelineDays)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	created, err := s.tokenRepo.CountCreatedByDay(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created counts: %w", err)
+	}
+
+	consumed, err := s.eventRepo.CountByDay(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumed counts: %w", err)
+	}
+
+	response := &StatisticsTimelineResponse{
+		Days:     days,
+		Timeline: make([]*TimelineDay, 0, days),
+	}
+
+	byDate := make(map[string]*TimelineDay, days)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		entry := &TimelineDay{Date: day.Format("2006-01-02")}
+		byDate[entry.Date] = entry
+		response.Timeline = append(response.Timeline, entry)
+	}
+
+	for _, c := range created {
+		if entry, ok := byDate[c.Day]; ok {
+			entry.TokensCreated = c.Count
+			response.TotalTokensCreated += c.Count
+		}
+	}
+	for _, c := range consumed {
+		if entry, ok := byDate[c.Day]; ok {
+			entry.TokensConsumed = c.Count
+			response.TotalTokensConsumed += c.Count
+		}
+	}
+
+	return response, nil
+}
+
+// expiresInHours returns the requested token lifetime, or the configured default when omitted
+func (s *TokenManagementService) expiresInHours(req *CreateTokenRequest) int {
+	if req.ExpiresInHours == 0 {
+		return s.config.DefaultExpiryHours
+	}
+	return req.ExpiresInHours
+}
+
 // validateCreateTokenRequest validates the token creation request
 func (s *TokenManagementService) validateCreateTokenRequest(req *CreateTokenRequest) error {
-	if req.ExpiresInHours < 1 {
+	expiresInHours := s.expiresInHours(req)
+	if expiresInHours < 1 {
 		return fmt.Errorf("expires_in_hours must be at least 1")
 	}
 
+	if s.config.MaxExpiryHours > 0 && expiresInHours > s.config.MaxExpiryHours {
+		return fmt.Errorf("expires_in_hours must not exceed %d (configured maximum token lifetime)", s.config.MaxExpiryHours)
+	}
+
 	if req.MaxUses != nil && *req.MaxUses < 1 {
 		return fmt.Errorf("max_uses must be at least 1")
 	}
 
+	if req.MaxUses != nil && s.config.MaxUses > 0 && *req.MaxUses > s.config.MaxUses {
+		return fmt.Errorf("max_uses must not exceed %d (configured maximum token uses)", s.config.MaxUses)
+	}
+
+	if req.DistinctMacLimit != nil && *req.DistinctMacLimit < 1 {
+		return fmt.Errorf("distinct_mac_limit must be at least 1")
+	}
+
 	// Validate MAC address if provided
 	if req.AuthorizedMAC != nil && *req.AuthorizedMAC != "" {
 		if err := validators.ValidateMACAddress(*req.AuthorizedMAC, "authorized_mac"); err != nil {
@@ -221,42 +983,127 @@ func (s *TokenManagementService) validateCreateTokenRequest(req *CreateTokenRequ
 		}
 	}
 
+	if req.Description != nil {
+		if err := validators.ValidateDescription(*req.Description, "description"); err != nil {
+			return err
+		}
+	}
+
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		if err := validators.ValidateOwnerID(*req.OwnerID, "owner_id"); err != nil {
+			return err
+		}
+	}
+
+	if err := validators.ValidateCustomClaims(req.CustomClaims, "custom_claims"); err != nil {
+		return err
+	}
+
+	if req.TokenFormat != nil && *req.TokenFormat != "" &&
+		*req.TokenFormat != TokenFormatBase64URL && *req.TokenFormat != TokenFormatBase32 {
+		return fmt.Errorf("token_format must be %q or %q", TokenFormatBase64URL, TokenFormatBase32)
+	}
+
 	return nil
 }
 
+// hashRequest returns the SHA-256 of a request's JSON encoding
+func hashRequest(req interface{}) (string, error) {
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// toCreateTokenResponse converts a token model to the creation response, including the secret value
+func toCreateTokenResponse(token *models.RegistrationToken, description *string) *CreateTokenResponse {
+	return &CreateTokenResponse{
+		ID:                  token.ID,
+		Token:               token.Token,
+		ExpiresAt:           token.ExpiresAt.UTC().Format(time.RFC3339),
+		MaxUses:             token.UsageLimit,
+		DistinctMacLimit:    token.DistinctMacLimit,
+		AuthorizedMAC:       token.PreAuthorizedMacAddress,
+		Description:         description,
+		AllowReRegistration: token.AllowsReRegistration(),
+		OwnerID:             token.OwnerID,
+		CustomClaims:        customClaimsOf(token),
+		CreatedBy:           token.CreatedBy,
+		CreatedAt:           token.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// customClaimsOf returns the custom claims of a token for display
+// Claims that cannot be decoded are logged and omitted rather than failing the response.
+func customClaimsOf(token *models.RegistrationToken) map[string]interface{} {
+	claims, err := token.ParsedCustomClaims()
+	if err != nil {
+		log.Printf("Warning: token %s has invalid custom claims: %v", token.ID, err)
+	}
+	return claims
+}
+
+// findToken looks up a token by ID first, then by its secret value
+func (s *TokenManagementService) findToken(tokenRef string) (*models.RegistrationToken, error) {
+	if token, err := s.tokenRepo.FindByID(tokenRef); err == nil {
+		return token, nil
+	}
+	return s.tokenRepo.FindByToken(tokenRef)
+}
+
 // convertToListResponse converts token models to list response format
 func (s *TokenManagementService) convertToListResponse(tokens []*models.RegistrationToken) []*TokenListResponse {
 	response := make([]*TokenListResponse, len(tokens))
 	for i, token := range tokens {
-		expiresAt := ""
-		if token.ExpiresAt != nil {
-			expiresAt = token.ExpiresAt.UTC().Format(time.RFC3339)
-		}
-
-		response[i] = &TokenListResponse{
-			Token:         token.Token,
-			ExpiresAt:     expiresAt,
-			MaxUses:       token.UsageLimit,
-			UsedCount:     token.UsedCount,
-			AuthorizedMAC: token.PreAuthorizedMacAddress,
-			Description:   nil, // Model doesn't have Description field
-			IsExpired:     token.IsExpired(),
-			IsActive:      token.IsValid(),
-			CreatedAt:     token.CreatedAt.UTC().Format(time.RFC3339),
-		}
+		response[i] = toTokenListResponse(token)
 	}
 	return response
 }
 
-// generateSecureToken generates a cryptographically secure random token
-// The token is base64-url-encoded for safe use in URLs and JSON
-func generateSecureToken(length int) (string, error) {
+// toTokenListResponse converts a token model to its list response format
+func toTokenListResponse(token *models.RegistrationToken) *TokenListResponse {
+	expiresAt := ""
+	if token.ExpiresAt != nil {
+		expiresAt = token.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	return &TokenListResponse{
+		ID:                  token.ID,
+		TokenFingerprint:    crypto.TokenFingerprint(token.Token),
+		ExpiresAt:           expiresAt,
+		MaxUses:             token.UsageLimit,
+		DistinctMacLimit:    token.DistinctMacLimit,
+		UsedCount:           token.UsedCount,
+		RemainingUses:       token.RemainingUses(),
+		AuthorizedMAC:       token.PreAuthorizedMacAddress,
+		Description:         token.Description,
+		AllowReRegistration: token.AllowsReRegistration(),
+		IsExpired:           token.IsExpired(),
+		IsActive:            token.IsValid(),
+		OwnerID:             token.OwnerID,
+		CustomClaims:        customClaimsOf(token),
+		CreatedBy:           token.CreatedBy,
+		CreatedAt:           token.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// generateSecureToken generates a cryptographically secure random token of length bytes
+// Both formats are safe for use in URLs and JSON; base32 is also easy to type by hand
+func generateSecureToken(length int, format string) (string, error) {
 	bytes := make([]byte, length)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
-	// Use URL-safe base64 encoding (no padding)
-	token := base64.RawURLEncoding.EncodeToString(bytes)
-	return token, nil
+	switch format {
+	case TokenFormatBase32:
+		return crockfordBase32.EncodeToString(bytes), nil
+	case TokenFormatBase64URL:
+		// Use URL-safe base64 encoding (no padding)
+		return base64.RawURLEncoding.EncodeToString(bytes), nil
+	default:
+		return "", fmt.Errorf("unsupported token format: %s", format)
+	}
 }
@@ -0,0 +1,831 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/models"
+	"github.com/boomchecker/api-backend/internal/repositories"
+	"github.com/boomchecker/api-backend/internal/validators"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestTokenService creates a token management service backed by an in-memory database
+func newTestTokenService(t *testing.T) (*TokenManagementService, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.RegistrationToken{}, &models.RegistrationEvent{}, &models.IdempotencyKey{}, &models.Node{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	service := NewTokenManagementService(
+		repositories.NewRegistrationTokenRepository(db),
+		repositories.NewRegistrationEventRepository(db),
+		repositories.NewIdempotencyKeyRepository(db),
+		nil,
+		nil,
+	)
+	return service, db
+}
+
+// TestTokenManagementService_CreateTokenIdempotent tests that a retry returns the original token
+func TestTokenManagementService_CreateTokenIdempotent(t *testing.T) {
+	service, db := newTestTokenService(t)
+
+	description := "Batch 7"
+	req := &CreateTokenRequest{ExpiresInHours: 24, Description: &description}
+
+	first, replayed, err := service.CreateTokenIdempotent("retry-key-1", req, "")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent() error = %v", err)
+	}
+	if replayed {
+		t.Error("first request should not be replayed")
+	}
+
+	// Retry with the same key and request
+	second, replayed, err := service.CreateTokenIdempotent("retry-key-1", req, "")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent() retry error = %v", err)
+	}
+	if !replayed {
+		t.Error("retry should be replayed")
+	}
+	if second.ID != first.ID || second.Token != first.Token || second.ExpiresAt != first.ExpiresAt {
+		t.Errorf("retry returned %+v, want original %+v", second, first)
+	}
+	if second.Description == nil || *second.Description != description {
+		t.Errorf("retry Description = %v, want %q", second.Description, description)
+	}
+
+	var count int64
+	db.Model(&models.RegistrationToken{}).Count(&count)
+	if count != 1 {
+		t.Errorf("tokens created = %d, want 1", count)
+	}
+
+	// Same key with a different request is rejected
+	if _, _, err := service.CreateTokenIdempotent("retry-key-1", &CreateTokenRequest{ExpiresInHours: 48}, ""); err == nil ||
+		!strings.Contains(err.Error(), "idempotency key reused") {
+		t.Errorf("reused key error = %v, want idempotency key reused", err)
+	}
+
+	// A different key creates a new token
+	third, replayed, err := service.CreateTokenIdempotent("retry-key-2", req, "")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent() new key error = %v", err)
+	}
+	if replayed || third.ID == first.ID {
+		t.Error("new key should create a new token")
+	}
+
+	// A rejected request does not consume its key
+	if _, _, err := service.CreateTokenIdempotent("retry-key-3", &CreateTokenRequest{ExpiresInHours: -1}, ""); err == nil {
+		t.Fatal("invalid request should return error")
+	}
+	if _, replayed, err := service.CreateTokenIdempotent("retry-key-3", req, ""); err != nil || replayed {
+		t.Errorf("retry after failure = replayed %v, err %v, want new token", replayed, err)
+	}
+}
+
+// TestTokenManagementService_CreateTokenIdempotentPerAdmin tests that idempotency keys are scoped to the admin
+// so one admin reusing another admin's key never receives that admin's token
+func TestTokenManagementService_CreateTokenIdempotentPerAdmin(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	req := &CreateTokenRequest{ExpiresInHours: 24}
+
+	alice, _, err := service.CreateTokenIdempotent("shared-key", req, "alice@example.com")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent(alice) error = %v", err)
+	}
+
+	bob, replayed, err := service.CreateTokenIdempotent("shared-key", req, "bob@example.com")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent(bob) error = %v", err)
+	}
+	if replayed || bob.ID == alice.ID || bob.Token == alice.Token {
+		t.Errorf("bob got alice's token (replayed %v), want a new token", replayed)
+	}
+
+	// The same admin still gets the replay, whatever the case of the email
+	again, replayed, err := service.CreateTokenIdempotent("shared-key", req, "Alice@Example.com")
+	if err != nil {
+		t.Fatalf("CreateTokenIdempotent(alice retry) error = %v", err)
+	}
+	if !replayed || again.ID != alice.ID {
+		t.Errorf("alice retry = %s (replayed %v), want replay of %s", again.ID, replayed, alice.ID)
+	}
+}
+
+// TestTokenManagementService_CreateTokenCreatedBy tests that the creating admin is recorded and returned
+func TestTokenManagementService_CreateTokenCreatedBy(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "admin@example.com")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if created.CreatedBy == nil || *created.CreatedBy != "admin@example.com" {
+		t.Errorf("CreateToken() CreatedBy = %v, want admin@example.com", created.CreatedBy)
+	}
+
+	detail, err := service.GetToken(created.ID)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if detail.CreatedBy == nil || *detail.CreatedBy != "admin@example.com" {
+		t.Errorf("GetToken() CreatedBy = %v, want admin@example.com", detail.CreatedBy)
+	}
+
+	// Without an authenticated admin the field is left empty
+	anonymous, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	list, err := service.ListAllTokens()
+	if err != nil {
+		t.Fatalf("ListAllTokens() error = %v", err)
+	}
+	for _, token := range list {
+		if token.ID == anonymous.ID && token.CreatedBy != nil {
+			t.Errorf("ListAllTokens() CreatedBy = %q, want nil", *token.CreatedBy)
+		}
+	}
+}
+
+// TestTokenManagementService_CreateTokenDescriptionLength tests that over-length descriptions are rejected
+func TestTokenManagementService_CreateTokenDescriptionLength(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	description := strings.Repeat("d", validators.MaxDescriptionLength)
+	if _, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, Description: &description}, ""); err != nil {
+		t.Fatalf("CreateToken() at limit error = %v", err)
+	}
+
+	description += "d"
+	_, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, Description: &description}, "")
+	if err == nil || !strings.HasPrefix(err.Error(), "validation") {
+		t.Errorf("CreateToken() over limit error = %v, want validation error", err)
+	}
+}
+
+// TestTokenManagementService_SearchTokens tests that descriptions are stored and searchable
+func TestTokenManagementService_SearchTokens(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	description := "Batch 7 sensors"
+	if _, err := service.CreateToken(&CreateTokenRequest{Description: &description}, "ops@example.com"); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if _, err := service.CreateToken(&CreateTokenRequest{}, ""); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	results, err := service.SearchTokens(&TokenSearchQuery{Q: " batch 7 "})
+	if err != nil {
+		t.Fatalf("SearchTokens() error = %v", err)
+	}
+	if results.Total != 1 || len(results.Tokens) != 1 || results.Page != 1 || results.PageSize != DefaultTokenSearchPageSize {
+		t.Fatalf("SearchTokens() = %+v, want one token on page 1", results)
+	}
+	if got := results.Tokens[0].Description; got == nil || *got != description {
+		t.Errorf("SearchTokens() Description = %v, want %q", got, description)
+	}
+
+	for _, query := range []*TokenSearchQuery{
+		{},
+		{Q: "   "},
+		{Q: "batch", Page: -1},
+		{Q: "batch", PageSize: MaxTokenSearchPageSize + 1},
+	} {
+		if _, err := service.SearchTokens(query); err == nil || !strings.HasPrefix(err.Error(), "validation") {
+			t.Errorf("SearchTokens(%+v) error = %v, want validation error", query, err)
+		}
+	}
+}
+
+// TestTokenManagementService_ListTokenNodes tests listing the nodes of a token, including after it is deleted
+func TestTokenManagementService_ListTokenNodes(t *testing.T) {
+	service, db := newTestTokenService(t)
+
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	nodeRepo := repositories.NewNodeRepository(db)
+	eventRepo := repositories.NewRegistrationEventRepository(db)
+	for i, mac := range []string{"AA:BB:CC:DD:EE:01", "AA:BB:CC:DD:EE:02"} {
+		node := &models.Node{UUID: fmt.Sprintf("550e8400-e29b-41d4-a716-44665544000%d", i), MacAddress: mac, JWTSecret: "encrypted"}
+		if err := nodeRepo.Create(node); err != nil {
+			t.Fatalf("Create() node error = %v", err)
+		}
+		for j, eventType := range []string{models.RegistrationEventRegistered, models.RegistrationEventReRegistered} {
+			if err := eventRepo.Create(&models.RegistrationEvent{
+				ID: fmt.Sprintf("event-%d-%d", i, j), NodeUUID: node.UUID, MacAddress: mac, TokenID: created.ID, EventType: eventType,
+			}); err != nil {
+				t.Fatalf("Create() event error = %v", err)
+			}
+		}
+	}
+
+	// A re-registration event must not list the node twice
+	page, err := service.ListTokenNodes(created.Token, &TokenNodesQuery{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListTokenNodes() error = %v", err)
+	}
+	if page.TokenID != created.ID || page.Total != 2 || len(page.Nodes) != 1 || page.Nodes[0].MacAddress != "AA:BB:CC:DD:EE:01" {
+		t.Errorf("ListTokenNodes() page 1 = %+v, want first of 2 nodes", page)
+	}
+
+	if err := service.DeleteToken(created.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	page, err = service.ListTokenNodes(created.ID, &TokenNodesQuery{Page: 2, PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListTokenNodes() deleted token error = %v", err)
+	}
+	if page.Total != 2 || len(page.Nodes) != 1 || page.Nodes[0].MacAddress != "AA:BB:CC:DD:EE:02" {
+		t.Errorf("ListTokenNodes() deleted token page 2 = %+v, want second of 2 nodes", page)
+	}
+
+	if _, err := service.ListTokenNodes("missing", &TokenNodesQuery{}); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("ListTokenNodes() unknown token error = %v, want TOKEN_NOT_FOUND", err)
+	}
+	if _, err := service.ListTokenNodes(created.ID, &TokenNodesQuery{PageSize: MaxTokenSearchPageSize + 1}); err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+		t.Errorf("ListTokenNodes() oversized page error = %v, want validation error", err)
+	}
+}
+
+// TestTokenManagementService_GetStatisticsUsage tests the usage-limit breakdown of the token statistics
+func TestTokenManagementService_GetStatisticsUsage(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	one, five, unlimited := 1, 5, 0
+	for i, token := range []*models.RegistrationToken{
+		{UsageLimit: &one, UsedCount: 1},
+		{UsageLimit: &one},
+		{UsageLimit: &five, UsedCount: 2},
+		{UsageLimit: nil, UsedCount: 3},
+		{UsageLimit: &unlimited},
+	} {
+		token.ID = fmt.Sprintf("token-%d", i)
+		token.Token = fmt.Sprintf("value-%d", i)
+		if err := service.tokenRepo.Create(token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	stats, err := service.GetStatistics()
+	if err != nil {
+		t.Fatalf("GetStatistics() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"total_tokens":           int64(5),
+		"single_use_tokens":      int64(2),
+		"multi_use_tokens":       int64(1),
+		"unlimited_tokens":       int64(2),
+		"exhausted_tokens":       int64(1),
+		"total_uses":             int64(6),
+		"average_uses_per_token": 1.2,
+		"max_uses_per_token":     DefaultTokenManagementConfig().MaxUses,
+	}
+	for key, value := range want {
+		if stats[key] != value {
+			t.Errorf("GetStatistics()[%q] = %v (%T), want %v", key, stats[key], stats[key], value)
+		}
+	}
+}
+
+// TestTokenManagementService_DeleteAndRestore tests that a deleted token stops working and is usable again after undo
+func TestTokenManagementService_DeleteAndRestore(t *testing.T) {
+	service, db := newTestTokenService(t)
+
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	if err := service.DeleteToken(created.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	if _, err := service.tokenRepo.ValidateToken(created.Token, nil); err == nil {
+		t.Error("ValidateToken() accepted a deleted token")
+	}
+	if _, err := service.GetToken(created.ID); err == nil {
+		t.Error("GetToken() found a deleted token")
+	}
+
+	restored, err := service.RestoreToken(created.Token)
+	if err != nil {
+		t.Fatalf("RestoreToken() error = %v", err)
+	}
+	if restored.ID != created.ID || !restored.IsActive {
+		t.Errorf("RestoreToken() = %+v, want active token %s", restored, created.ID)
+	}
+	if _, err := service.tokenRepo.ValidateToken(created.Token, nil); err != nil {
+		t.Errorf("ValidateToken() after restore error = %v", err)
+	}
+	if _, err := service.RestoreToken(created.ID); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("RestoreToken() of a live token error = %v, want TOKEN_NOT_FOUND", err)
+	}
+
+	// Past the undo window the token can no longer be restored
+	if err := service.DeleteToken(created.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	if err := db.Unscoped().Model(&models.RegistrationToken{}).Where("id = ?", created.ID).
+		Update("deleted_at", time.Now().UTC().Add(-service.config.DeleteRetention-time.Minute)).Error; err != nil {
+		t.Fatalf("failed to backdate deletion: %v", err)
+	}
+	if _, err := service.RestoreToken(created.ID); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("RestoreToken() past the undo window error = %v, want TOKEN_NOT_FOUND", err)
+	}
+
+	// Without a retention window deletion is permanent
+	service.config.DeleteRetention = 0
+	second, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if err := service.DeleteToken(second.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	if _, err := service.tokenRepo.FindDeleted(second.ID); err == nil {
+		t.Error("DeleteToken() without retention left a restorable token")
+	}
+}
+
+// TestTokenManagementService_ExpireToken tests that an expired token fails validation at once but stays listed
+func TestTokenManagementService_ExpireToken(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	expired, err := service.ExpireToken(created.ID)
+	if err != nil {
+		t.Fatalf("ExpireToken() error = %v", err)
+	}
+	if !expired.IsExpired || expired.IsActive {
+		t.Errorf("ExpireToken() = %+v, want expired and inactive", expired)
+	}
+	_, err = service.tokenRepo.ValidateToken(created.Token, nil)
+	if reason, _ := repositories.TokenRejectReasonOf(err); reason != repositories.TokenRejectExpired {
+		t.Errorf("ValidateToken() after expire error = %v, want expired", err)
+	}
+
+	// The record and its history stay available
+	found, err := service.GetToken(created.ID)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if found.ExpiresAt != expired.ExpiresAt {
+		t.Errorf("GetToken() expires_at = %s, want %s", found.ExpiresAt, expired.ExpiresAt)
+	}
+
+	// Expiring again keeps the original expiry
+	again, err := service.ExpireToken(created.Token)
+	if err != nil {
+		t.Fatalf("ExpireToken() again error = %v", err)
+	}
+	if again.ExpiresAt != expired.ExpiresAt {
+		t.Errorf("ExpireToken() again expires_at = %s, want %s", again.ExpiresAt, expired.ExpiresAt)
+	}
+
+	if _, err := service.ExpireToken("missing"); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("ExpireToken() of unknown token error = %v, want TOKEN_NOT_FOUND", err)
+	}
+}
+
+// TestTokenManagementService_ExpireTokenKeepsConcurrentUse tests that expiring a token does not
+// overwrite a use consumed between loading and expiring it
+func TestTokenManagementService_ExpireTokenKeepsConcurrentUse(t *testing.T) {
+	service, db := newTestTokenService(t)
+
+	maxUses := 5
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, MaxUses: &maxUses}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	// Consume a use right after ExpireToken has loaded the token
+	consumed := false
+	err = db.Callback().Query().After("gorm:query").Register("test:concurrent_consume", func(tx *gorm.DB) {
+		if consumed || tx.Statement.Table != "registration_tokens" {
+			return
+		}
+		consumed = true
+		if err := service.tokenRepo.ConsumeUse(created.Token); err != nil {
+			t.Errorf("ConsumeUse() error = %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	expired, err := service.ExpireToken(created.ID)
+	if err != nil {
+		t.Fatalf("ExpireToken() error = %v", err)
+	}
+	if !consumed {
+		t.Fatal("concurrent use was not simulated")
+	}
+	if !expired.IsExpired || expired.UsedCount != 1 {
+		t.Errorf("ExpireToken() = expired %v, used %d, want expired with 1 use", expired.IsExpired, expired.UsedCount)
+	}
+
+	token, err := service.tokenRepo.FindByID(created.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if token.UsedCount != 1 {
+		t.Errorf("UsedCount = %d, want 1", token.UsedCount)
+	}
+}
+
+// TestTokenManagementService_UpdateToken tests changing a token's usage limit
+func TestTokenManagementService_UpdateToken(t *testing.T) {
+	service, _ := newTestTokenService(t)
+
+	maxUses := 3
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, MaxUses: &maxUses}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := service.tokenRepo.ConsumeUse(created.Token); err != nil {
+			t.Fatalf("ConsumeUse() error = %v", err)
+		}
+	}
+
+	limit := func(n int) *UpdateTokenRequest { return &UpdateTokenRequest{MaxUses: &n} }
+
+	updated, err := service.UpdateToken(created.ID, limit(10))
+	if err != nil {
+		t.Fatalf("UpdateToken() raise error = %v", err)
+	}
+	if updated.MaxUses == nil || *updated.MaxUses != 10 || *updated.RemainingUses != 8 {
+		t.Errorf("UpdateToken() raise = max %v, remaining %v; want 10 and 8", updated.MaxUses, updated.RemainingUses)
+	}
+
+	// Lowering to exactly the used count exhausts the token
+	updated, err = service.UpdateToken(created.Token, limit(2))
+	if err != nil {
+		t.Fatalf("UpdateToken() lower error = %v", err)
+	}
+	if updated.IsActive || *updated.RemainingUses != 0 {
+		t.Errorf("UpdateToken() lower to used count = active %t, remaining %d; want exhausted", updated.IsActive, *updated.RemainingUses)
+	}
+
+	for _, n := range []int{1, 0, service.config.MaxUses + 1} {
+		if _, err := service.UpdateToken(created.ID, limit(n)); ErrorCodeOf(err) != ErrCodeValidationFailed {
+			t.Errorf("UpdateToken(max_uses %d) error = %v, want VALIDATION_FAILED", n, err)
+		}
+	}
+	found, err := service.GetToken(created.ID)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if *found.MaxUses != 2 {
+		t.Errorf("max_uses after rejected updates = %d, want 2", *found.MaxUses)
+	}
+
+	if _, err := service.UpdateToken("missing", limit(5)); ErrorCodeOf(err) != ErrCodeTokenNotFound {
+		t.Errorf("UpdateToken() of unknown token error = %v, want TOKEN_NOT_FOUND", err)
+	}
+}
+
+// TestTokenManagementService_RevokeAllTokens tests the kill switch and its confirmation and audit entry
+func TestTokenManagementService_RevokeAllTokens(t *testing.T) {
+	service, db := newTestTokenService(t)
+	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	auditRepo := repositories.NewAuditLogRepository(db)
+	service.auditService = NewAuditService(auditRepo)
+
+	var tokens []*CreateTokenResponse
+	for i := 0; i < 3; i++ {
+		created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+		if err != nil {
+			t.Fatalf("CreateToken() error = %v", err)
+		}
+		tokens = append(tokens, created)
+	}
+	if _, err := service.ExpireToken(tokens[0].ID); err != nil {
+		t.Fatalf("ExpireToken() error = %v", err)
+	}
+
+	if _, err := service.RevokeAllTokens(&RevokeAllTokensRequest{}, "admin@example.com"); ErrorCodeOf(err) != ErrCodeValidationFailed {
+		t.Fatalf("RevokeAllTokens() without confirm error = %v, want VALIDATION_FAILED", err)
+	}
+	if active, _ := service.tokenRepo.CountActive(); active != 2 {
+		t.Fatalf("CountActive() after unconfirmed revoke = %d, want 2", active)
+	}
+
+	response, err := service.RevokeAllTokens(&RevokeAllTokensRequest{Confirm: true}, "admin@example.com")
+	if err != nil {
+		t.Fatalf("RevokeAllTokens() error = %v", err)
+	}
+	if response.RevokedTokens != 2 {
+		t.Errorf("RevokedTokens = %d, want 2 (already expired token not counted)", response.RevokedTokens)
+	}
+	for _, token := range tokens {
+		_, err := service.tokenRepo.ValidateToken(token.Token, nil)
+		if reason, _ := repositories.TokenRejectReasonOf(err); reason != repositories.TokenRejectExpired {
+			t.Errorf("ValidateToken(%s) after revoke-all error = %v, want expired", token.ID, err)
+		}
+	}
+
+	entries, total, err := auditRepo.Query(repositories.AuditLogFilter{Action: AuditActionTokensRevokedAll})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if total != 1 || entries[0].Details == nil || !strings.Contains(*entries[0].Details, "severity=high") {
+		t.Errorf("audit entries = %d, want one high-severity entry", total)
+	}
+}
+
+// TestTokenManagementService_RevokeAllTokensCoversDeleted tests that a token deleted before
+// revoke-all stays unusable when it is restored afterwards
+func TestTokenManagementService_RevokeAllTokensCoversDeleted(t *testing.T) {
+	service, db := newTestTokenService(t)
+	service.config.DeleteRetention = time.Hour
+
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if err := service.DeleteToken(created.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	if _, err := service.RevokeAllTokens(&RevokeAllTokensRequest{Confirm: true}, "admin@example.com"); err != nil {
+		t.Fatalf("RevokeAllTokens() error = %v", err)
+	}
+	if _, err := service.RestoreToken(created.ID); err != nil {
+		t.Fatalf("RestoreToken() error = %v", err)
+	}
+
+	keyProvider := crypto.StaticKeyProvider(bytes.Repeat([]byte{7}, 32))
+	registration := NewNodeRegistrationService(repositories.NewNodeRepository(db), service.tokenRepo, repositories.NewRegistrationEventRepository(db), nil, nil, keyProvider, nil, nil, &NodeRegistrationConfig{})
+	_, err = registration.RegisterNode(&RegistrationRequest{RegistrationToken: created.Token, MacAddress: "AA:BB:CC:DD:EE:01"})
+	if ErrorCodeOf(err) != ErrCodeTokenExpired {
+		t.Errorf("RegisterNode() with restored token error = %v, want TOKEN_EXPIRED", err)
+	}
+}
+
+// TestTokenManagementService_DeleteTokens tests bulk deletion for each filter and their combinations
+func TestTokenManagementService_DeleteTokens(t *testing.T) {
+	cutoff := time.Now().UTC().Add(-24 * time.Hour)
+	tests := []struct {
+		name  string
+		query DeleteTokensQuery
+		want  []string // Fixture names that are deleted
+	}{
+		{"active", DeleteTokensQuery{Status: "active"}, []string{"active", "old", "acme", "unlimited"}},
+		{"expired", DeleteTokensQuery{Status: "expired"}, []string{"expired", "old expired"}},
+		{"exhausted", DeleteTokensQuery{Status: "exhausted"}, []string{"exhausted"}},
+		{"created before", DeleteTokensQuery{CreatedBefore: cutoff.Format(time.RFC3339)}, []string{"old", "old expired"}},
+		{"owner", DeleteTokensQuery{OwnerID: "acme"}, []string{"acme"}},
+		{"expired and created before", DeleteTokensQuery{Status: "expired", CreatedBefore: cutoff.Format(time.RFC3339)}, []string{"old expired"}},
+		{"active and owner", DeleteTokensQuery{Status: "active", OwnerID: "acme"}, []string{"acme"}},
+		{"no match", DeleteTokensQuery{Status: "exhausted", OwnerID: "acme"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, db := newTestTokenService(t)
+
+			owner := "acme"
+			fixtures := map[string]*CreateTokenResponse{}
+			for _, name := range []string{"active", "old", "acme", "expired", "old expired", "exhausted", "unlimited"} {
+				req := &CreateTokenRequest{ExpiresInHours: 24}
+				if name == "acme" {
+					req.OwnerID = &owner
+				}
+				created, err := service.CreateToken(req, "")
+				if err != nil {
+					t.Fatalf("CreateToken() error = %v", err)
+				}
+				fixtures[name] = created
+			}
+			for _, name := range []string{"expired", "old expired"} {
+				if _, err := service.ExpireToken(fixtures[name].ID); err != nil {
+					t.Fatalf("ExpireToken() error = %v", err)
+				}
+			}
+			if err := db.Model(&models.RegistrationToken{}).Where("id = ?", fixtures["exhausted"].ID).
+				Update("used_count", 1).Error; err != nil {
+				t.Fatalf("failed to exhaust token: %v", err)
+			}
+			// Legacy tokens store usage_limit 0 for unlimited; they are active however often they were used
+			if err := db.Model(&models.RegistrationToken{}).Where("id = ?", fixtures["unlimited"].ID).
+				Updates(map[string]interface{}{"usage_limit": 0, "used_count": 5}).Error; err != nil {
+				t.Fatalf("failed to make token unlimited: %v", err)
+			}
+			if err := db.Model(&models.RegistrationToken{}).Where("id IN ?", []string{fixtures["old"].ID, fixtures["old expired"].ID}).
+				Update("created_at", cutoff.Add(-time.Hour)).Error; err != nil {
+				t.Fatalf("failed to backdate tokens: %v", err)
+			}
+
+			response, err := service.DeleteTokens(&tt.query, "admin@example.com")
+			if err != nil {
+				t.Fatalf("DeleteTokens() error = %v", err)
+			}
+			if response.DeletedTokens != int64(len(tt.want)) {
+				t.Errorf("DeletedTokens = %d, want %d", response.DeletedTokens, len(tt.want))
+			}
+
+			deleted := map[string]bool{}
+			for _, name := range tt.want {
+				deleted[name] = true
+			}
+			for name, created := range fixtures {
+				_, err := service.GetToken(created.ID)
+				if gone := err != nil; gone != deleted[name] {
+					t.Errorf("token %q deleted = %v, want %v", name, gone, deleted[name])
+				}
+			}
+		})
+	}
+
+	service, _ := newTestTokenService(t)
+	for _, query := range []DeleteTokensQuery{
+		{},
+		{Status: "revoked"},
+		{CreatedBefore: "2025-11-10"},
+	} {
+		if _, err := service.DeleteTokens(&query, ""); ErrorCodeOf(err) != ErrCodeValidationFailed {
+			t.Errorf("DeleteTokens(%+v) error = %v, want VALIDATION_FAILED", query, err)
+		}
+	}
+}
+
+// TestTokenManagementService_CreateTokenDefaultExpiry tests that omitted expires_in_hours uses the configured default
+func TestTokenManagementService_CreateTokenDefaultExpiry(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	service.config.DefaultExpiryHours = 48
+
+	created, err := service.CreateToken(&CreateTokenRequest{}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, created.ExpiresAt)
+	if err != nil {
+		t.Fatalf("failed to parse ExpiresAt %q: %v", created.ExpiresAt, err)
+	}
+	if remaining := time.Until(expiresAt); remaining < 47*time.Hour || remaining > 48*time.Hour {
+		t.Errorf("CreateToken() expires in %v, want 48h", remaining)
+	}
+
+	// An explicit value over the cap is still rejected
+	_, err = service.CreateToken(&CreateTokenRequest{ExpiresInHours: service.config.MaxExpiryHours + 1}, "")
+	if err == nil || !strings.HasPrefix(err.Error(), "validation") {
+		t.Errorf("CreateToken() over max error = %v, want validation error", err)
+	}
+
+	// A default above the cap is rejected at startup
+	config := DefaultTokenManagementConfig()
+	config.DefaultExpiryHours = config.MaxExpiryHours + 1
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() should reject a default expiry above the maximum")
+	}
+}
+
+// TestTokenManagementService_CreateTokenPolicyCaps tests that MAX_TOKEN_EXPIRY_HOURS and MAX_TOKEN_USES cap creation
+func TestTokenManagementService_CreateTokenPolicyCaps(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	service.config.MaxExpiryHours = 48
+	service.config.MaxUses = 5
+
+	// Values at the cap are accepted
+	maxUses := 5
+	created, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 48, MaxUses: &maxUses}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() at the caps error = %v", err)
+	}
+	if created.MaxUses == nil || *created.MaxUses != 5 {
+		t.Errorf("CreateToken() MaxUses = %v, want 5", created.MaxUses)
+	}
+
+	_, err = service.CreateToken(&CreateTokenRequest{ExpiresInHours: 49}, "")
+	if ErrorCodeOf(err) != ErrCodeValidationFailed || !strings.Contains(err.Error(), "expires_in_hours must not exceed 48") {
+		t.Errorf("CreateToken() over the expiry cap error = %v, want VALIDATION_FAILED", err)
+	}
+
+	maxUses = 6
+	_, err = service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, MaxUses: &maxUses}, "")
+	if ErrorCodeOf(err) != ErrCodeValidationFailed || !strings.Contains(err.Error(), "max_uses must not exceed 5") {
+		t.Errorf("CreateToken() over the usage cap error = %v, want VALIDATION_FAILED", err)
+	}
+
+	// A zero cap disables the check
+	service.config.MaxUses = 0
+	if _, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24, MaxUses: &maxUses}, ""); err != nil {
+		t.Errorf("CreateToken() without a usage cap error = %v", err)
+	}
+}
+
+// TestTokenManagementService_CreateTokenActiveLimit tests that MAX_ACTIVE_TOKENS caps creation
+func TestTokenManagementService_CreateTokenActiveLimit(t *testing.T) {
+	service, _ := newTestTokenService(t)
+	service.config.MaxActiveTokens = 2
+
+	first, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if _, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, ""); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	_, err = service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, "")
+	if ErrorCodeOf(err) != ErrCodeTokenLimitReached {
+		t.Fatalf("CreateToken() over the cap error = %v, want TOKEN_LIMIT_REACHED", err)
+	}
+	if _, _, err := service.CreateTokenIdempotent("retry-1", &CreateTokenRequest{ExpiresInHours: 24}, ""); ErrorCodeOf(err) != ErrCodeTokenLimitReached {
+		t.Errorf("CreateTokenIdempotent() over the cap error = %v, want TOKEN_LIMIT_REACHED", err)
+	}
+
+	// Deleting a token frees a slot
+	if err := service.DeleteToken(first.ID); err != nil {
+		t.Fatalf("DeleteToken() error = %v", err)
+	}
+	if _, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, ""); err != nil {
+		t.Errorf("CreateToken() after delete error = %v", err)
+	}
+
+	// Without a cap there is no limit
+	service.config.MaxActiveTokens = 0
+	if _, err := service.CreateToken(&CreateTokenRequest{ExpiresInHours: 24}, ""); err != nil {
+		t.Errorf("CreateToken() without a cap error = %v", err)
+	}
+}
+
+// TestGenerateSecureToken tests token length and alphabet per format
+func TestGenerateSecureToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		format  string
+		pattern string
+	}{
+		{"base64url 32 bytes", 32, TokenFormatBase64URL, `^[A-Za-z0-9_-]{43}$`},
+		{"base32 10 bytes", 10, TokenFormatBase32, `^[0-9A-HJKMNP-TV-Z]{16}$`},
+		{"base32 32 bytes", 32, TokenFormatBase32, `^[0-9A-HJKMNP-TV-Z]{52}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := generateSecureToken(tt.length, tt.format)
+			if err != nil {
+				t.Fatalf("generateSecureToken() error = %v", err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(token) {
+				t.Errorf("generateSecureToken() = %q, want match %s", token, tt.pattern)
+			}
+		})
+	}
+
+	if _, err := generateSecureToken(32, "hex"); err == nil {
+		t.Error("generateSecureToken() with unknown format should return error")
+	}
+}
+
+// TestTokenManagementConfig_Validate tests the minimum entropy threshold
+func TestTokenManagementConfig_Validate(t *testing.T) {
+	tests := []struct {
+		tokenBytes int
+		wantErr    bool
+	}{
+		{32, false},
+		{10, false},
+		{9, true},
+		{64, false},
+		{65, true},
+	}
+
+	for _, tt := range tests {
+		config := DefaultTokenManagementConfig()
+		config.TokenBytes = tt.tokenBytes
+		if err := config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with TokenBytes=%d error = %v, wantErr %v", tt.tokenBytes, err, tt.wantErr)
+		}
+	}
+}
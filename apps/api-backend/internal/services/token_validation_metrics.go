@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/boomchecker/api-backend/internal/crypto"
+	"github.com/boomchecker/api-backend/internal/repositories"
+)
+
+// Endpoints that validate registration tokens, used as the endpoint label
+const (
+	TokenValidationEndpointRegister  = "register"
+	TokenValidationEndpointMACStatus = "mac_status"
+)
+
+// TokenValidationOutcomeValid counts tokens that passed validation
+// Failures are counted under their repositories.TokenRejectReason.
+const TokenValidationOutcomeValid = "valid"
+
+// TokenValidationCount is the number of validations with one endpoint and outcome
+type TokenValidationCount struct {
+	Endpoint string
+	Outcome  string
+	Count    int64
+}
+
+// TokenValidationMetrics counts registration token validation outcomes per endpoint
+// and logs every rejection at WARN, so operators can alert on spikes in wrong-MAC
+// attempts (possible token guessing) separately from benign expiries.
+// Counters live in memory and restart from zero with the process.
+type TokenValidationMetrics struct {
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	counts map[[2]string]int64
+}
+
+// NewTokenValidationMetrics creates an empty set of counters
+// If logger is nil, slog.Default is used
+func NewTokenValidationMetrics(logger *slog.Logger) *TokenValidationMetrics {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TokenValidationMetrics{
+		logger: logger,
+		counts: make(map[[2]string]int64),
+	}
+}
+
+// Record counts the result of one ValidateToken call
+// err is what ValidateToken returned; errors that are not rejections (e.g. database
+// failures) are not about the token and are ignored. Safe to call on a nil receiver.
+func (m *TokenValidationMetrics) Record(endpoint string, err error, tokenValue, macAddress string) {
+	if m == nil {
+		return
+	}
+
+	outcome := TokenValidationOutcomeValid
+	if err != nil {
+		reason, ok := repositories.TokenRejectReasonOf(err)
+		if !ok {
+			return
+		}
+		outcome = string(reason)
+	}
+
+	m.mu.Lock()
+	m.counts[[2]string{endpoint, outcome}]++
+	m.mu.Unlock()
+
+	if err != nil {
+		m.logger.LogAttrs(context.Background(), slog.LevelWarn, "registration token rejected",
+			slog.String("endpoint", endpoint),
+			slog.String("reason", outcome),
+			slog.String("token", crypto.TokenFingerprint(tokenValue)),
+			slog.String("mac_address", macAddress),
+		)
+	}
+}
+
+// Counts returns every non-zero counter, sorted by endpoint and outcome
+func (m *TokenValidationMetrics) Counts() []TokenValidationCount {
+	m.mu.Lock()
+	counts := make([]TokenValidationCount, 0, len(m.counts))
+	for key, count := range m.counts {
+		counts = append(counts, TokenValidationCount{Endpoint: key[0], Outcome: key[1], Count: count})
+	}
+	m.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Endpoint != counts[j].Endpoint {
+			return counts[i].Endpoint < counts[j].Endpoint
+		}
+		return counts[i].Outcome < counts[j].Outcome
+	})
+	return counts
+}
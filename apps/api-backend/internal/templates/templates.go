@@ -0,0 +1,231 @@
+// Package templates renders the emails sent by the API
+// Templates are embedded into the binary so deployments need no template files on disk.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"strings"
+)
+
+//go:embed *.html
+var files embed.FS
+
+// DefaultLanguage is used when no language is requested or the requested one has no templates
+const DefaultLanguage = "en"
+
+// AdminTokenEmailSubject is the subject of the admin login email
+const AdminTokenEmailSubject = "Your BoomChecker admin login token"
+
+// adminTokenEmailSubjects holds the localized login email subjects, keyed by language code
+// Templates are named admin_token.<lang>.html; a language without a subject uses the English one.
+var adminTokenEmailSubjects = map[string]string{
+	"en": AdminTokenEmailSubject,
+	"de": "Ihr BoomChecker Admin-Anmeldetoken",
+}
+
+// AdminCodeEmailSubject is the subject of the admin login code email
+const AdminCodeEmailSubject = "Your BoomChecker admin login code"
+
+// adminCodeEmailSubjects holds the localized login code email subjects, keyed by language code
+// Templates are named admin_code.<lang>.html
+var adminCodeEmailSubjects = map[string]string{
+	"en": AdminCodeEmailSubject,
+	"de": "Ihr BoomChecker Admin-Anmeldecode",
+}
+
+// TestEmailSubject is the subject of the email delivery test message
+const TestEmailSubject = "BoomChecker test email"
+
+// AdminTokenEmailData is the data rendered into the admin login email
+type AdminTokenEmailData struct {
+	// Email is the admin address the token was issued to
+	Email string
+
+	// Token is the signed admin JWT
+	Token string
+
+	// ExpiresAt is the expiry formatted for the configured display timezone
+	ExpiresAt string
+
+	// ExpiresAtUTC is the expiry in UTC (RFC3339), matching API timestamps
+	ExpiresAtUTC string
+
+	// APIBaseURL is used in the curl example, e.g. https://api.example.com
+	APIBaseURL string
+
+	// ConsoleLoginURL is the admin console sign-in link carrying the token in its fragment
+	// Empty when no console is configured; the email then shows only the raw token
+	ConsoleLoginURL string
+}
+
+// AdminCodeEmailData is the data rendered into the admin login code email
+type AdminCodeEmailData struct {
+	// Email is the admin address the code was sent to
+	Email string
+
+	// Code is the short numeric login code
+	Code string
+
+	// ExpiresAt is the expiry formatted for the configured display timezone
+	ExpiresAt string
+
+	// ExpiresAtUTC is the expiry in UTC (RFC3339), matching API timestamps
+	ExpiresAtUTC string
+
+	// APIBaseURL is used in the curl example, e.g. https://api.example.com
+	APIBaseURL string
+}
+
+// TestEmailData is the data rendered into the email delivery test message
+type TestEmailData struct {
+	// Email is the recipient address
+	Email string
+
+	// RequestedBy is the admin who requested the test; empty when admin login is disabled
+	RequestedBy string
+
+	// SentAtUTC is the send time in UTC (RFC3339)
+	SentAtUTC string
+}
+
+// TemplateRenderer renders the embedded email templates
+type TemplateRenderer struct {
+	// adminToken holds one login email template per language code
+	adminToken map[string]*template.Template
+
+	// adminCode holds one login code email template per language code
+	adminCode map[string]*template.Template
+
+	// testEmail holds the email delivery test template, English only
+	testEmail map[string]*template.Template
+}
+
+// NewTemplateRenderer parses the embedded templates
+// Every admin_token.<lang>.html, admin_code.<lang>.html and test_email.<lang>.html file is loaded;
+// the English templates must exist.
+func NewTemplateRenderer() (*TemplateRenderer, error) {
+	adminToken, err := parseLocalized("admin_token")
+	if err != nil {
+		return nil, err
+	}
+
+	adminCode, err := parseLocalized("admin_code")
+	if err != nil {
+		return nil, err
+	}
+
+	testEmail, err := parseLocalized("test_email")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateRenderer{adminToken: adminToken, adminCode: adminCode, testEmail: testEmail}, nil
+}
+
+// parseLocalized parses every <prefix>.<lang>.html template, keyed by language code
+func parseLocalized(prefix string) (map[string]*template.Template, error) {
+	names, err := fs.Glob(files, prefix+".*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s templates: %w", prefix, err)
+	}
+
+	localized := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		lang := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"."), ".html")
+		tmpl, err := template.ParseFS(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template %s: %w", prefix, name, err)
+		}
+		localized[lang] = tmpl
+	}
+	if _, ok := localized[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("missing %s template for default language %q", prefix, DefaultLanguage)
+	}
+
+	return localized, nil
+}
+
+// RenderAdminTokenEmail renders the admin login email in English
+// Returns the subject and HTML body
+func (r *TemplateRenderer) RenderAdminTokenEmail(data *AdminTokenEmailData) (string, string, error) {
+	return r.RenderAdminTokenEmailLang(DefaultLanguage, data)
+}
+
+// RenderAdminTokenEmailLang renders the admin login email in the given language
+// lang is a code such as "de" or "de-AT"; only the primary subtag is used, case-insensitively.
+// Languages without a template fall back to English. Returns the subject and HTML body.
+func (r *TemplateRenderer) RenderAdminTokenEmailLang(lang string, data *AdminTokenEmailData) (string, string, error) {
+	if data == nil {
+		return "", "", fmt.Errorf("email data cannot be nil")
+	}
+
+	subject, body, err := render(r.adminToken, adminTokenEmailSubjects, lang, data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render admin token email: %w", err)
+	}
+
+	return subject, body, nil
+}
+
+// RenderAdminCodeEmailLang renders the admin login code email in the given language
+// Language selection and fallback work as in RenderAdminTokenEmailLang. Returns the subject and HTML body.
+func (r *TemplateRenderer) RenderAdminCodeEmailLang(lang string, data *AdminCodeEmailData) (string, string, error) {
+	if data == nil {
+		return "", "", fmt.Errorf("email data cannot be nil")
+	}
+
+	subject, body, err := render(r.adminCode, adminCodeEmailSubjects, lang, data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render admin code email: %w", err)
+	}
+
+	return subject, body, nil
+}
+
+// RenderTestEmail renders the email delivery test message
+// Returns the subject and HTML body
+func (r *TemplateRenderer) RenderTestEmail(data *TestEmailData) (string, string, error) {
+	if data == nil {
+		return "", "", fmt.Errorf("email data cannot be nil")
+	}
+
+	subject, body, err := render(r.testEmail, map[string]string{DefaultLanguage: TestEmailSubject}, DefaultLanguage, data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render test email: %w", err)
+	}
+
+	return subject, body, nil
+}
+
+// render executes the template for the resolved language and picks the matching subject
+func render(localized map[string]*template.Template, subjects map[string]string, lang string, data interface{}) (string, string, error) {
+	lang = resolveLanguage(localized, lang)
+
+	var body bytes.Buffer
+	if err := localized[lang].Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	subject, ok := subjects[lang]
+	if !ok {
+		subject = subjects[DefaultLanguage]
+	}
+
+	return subject, body.String(), nil
+}
+
+// resolveLanguage maps a requested language code to one with a template
+func resolveLanguage(localized map[string]*template.Template, lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := localized[lang]; ok {
+		return lang
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,153 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderAdminTokenEmail tests that the login email contains the token and display time
+func TestRenderAdminTokenEmail(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	subject, body, err := renderer.RenderAdminTokenEmail(&AdminTokenEmailData{
+		Email:        "admin@example.com",
+		Token:        "header.payload.signature",
+		ExpiresAt:    "Tue, 01 Jul 2025 14:30 CEST (+02:00)",
+		ExpiresAtUTC: "2025-07-01T12:30:00Z",
+		APIBaseURL:   "https://api.example.com",
+	})
+	if err != nil {
+		t.Fatalf("RenderAdminTokenEmail() error = %v", err)
+	}
+
+	if subject != AdminTokenEmailSubject {
+		t.Errorf("subject = %q, want %q", subject, AdminTokenEmailSubject)
+	}
+	for _, want := range []string{"header.payload.signature", "14:30 CEST (&#43;02:00)", "2025-07-01T12:30:00Z", "https://api.example.com/admin/registration-node-tokens"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+}
+
+// TestRenderAdminTokenEmailLang tests language selection and the English fallback
+func TestRenderAdminTokenEmailLang(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	tests := []struct {
+		lang        string
+		wantSubject string
+		wantBody    string
+	}{
+		{"de", "Ihr BoomChecker Admin-Anmeldetoken", "Admin-Anmeldung"},
+		{"DE-at", "Ihr BoomChecker Admin-Anmeldetoken", "Admin-Anmeldung"},
+		{"en", AdminTokenEmailSubject, "admin login"},
+		{"fr", AdminTokenEmailSubject, "admin login"},
+		{"", AdminTokenEmailSubject, "admin login"},
+	}
+
+	for _, tt := range tests {
+		subject, body, err := renderer.RenderAdminTokenEmailLang(tt.lang, &AdminTokenEmailData{
+			Email: "admin@example.com",
+			Token: "header.payload.signature",
+		})
+		if err != nil {
+			t.Fatalf("RenderAdminTokenEmailLang(%q) error = %v", tt.lang, err)
+		}
+		if subject != tt.wantSubject {
+			t.Errorf("RenderAdminTokenEmailLang(%q) subject = %q, want %q", tt.lang, subject, tt.wantSubject)
+		}
+		if !strings.Contains(body, tt.wantBody) || !strings.Contains(body, "header.payload.signature") {
+			t.Errorf("RenderAdminTokenEmailLang(%q) body missing %q or the token", tt.lang, tt.wantBody)
+		}
+	}
+}
+
+// TestRenderAdminTokenEmailConsoleLink tests that the sign-in link is only rendered when configured
+func TestRenderAdminTokenEmailConsoleLink(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	link := `href="https://console.example.com/login#token=header.payload.signature"`
+	for _, lang := range []string{"en", "de"} {
+		_, body, err := renderer.RenderAdminTokenEmailLang(lang, &AdminTokenEmailData{
+			Token:           "header.payload.signature",
+			ConsoleLoginURL: "https://console.example.com/login#token=header.payload.signature",
+		})
+		if err != nil {
+			t.Fatalf("RenderAdminTokenEmailLang(%q) error = %v", lang, err)
+		}
+		if !strings.Contains(body, link) {
+			t.Errorf("RenderAdminTokenEmailLang(%q) body does not contain %s", lang, link)
+		}
+
+		_, body, err = renderer.RenderAdminTokenEmailLang(lang, &AdminTokenEmailData{Token: "header.payload.signature"})
+		if err != nil {
+			t.Fatalf("RenderAdminTokenEmailLang(%q) error = %v", lang, err)
+		}
+		if strings.Contains(body, "href=") || !strings.Contains(body, "header.payload.signature") {
+			t.Errorf("RenderAdminTokenEmailLang(%q) without console URL should show only the raw token", lang)
+		}
+	}
+}
+
+// TestRenderAdminCodeEmailLang tests that the login code email contains the code in the requested language
+func TestRenderAdminCodeEmailLang(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	for lang, wantSubject := range map[string]string{"en": AdminCodeEmailSubject, "de": "Ihr BoomChecker Admin-Anmeldecode", "fr": AdminCodeEmailSubject} {
+		subject, body, err := renderer.RenderAdminCodeEmailLang(lang, &AdminCodeEmailData{
+			Email:      "admin@example.com",
+			Code:       "123456",
+			APIBaseURL: "https://api.example.com",
+		})
+		if err != nil {
+			t.Fatalf("RenderAdminCodeEmailLang(%q) error = %v", lang, err)
+		}
+		if subject != wantSubject {
+			t.Errorf("RenderAdminCodeEmailLang(%q) subject = %q, want %q", lang, subject, wantSubject)
+		}
+		for _, want := range []string{"123456", "https://api.example.com/admin/auth/verify"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("RenderAdminCodeEmailLang(%q) body does not contain %q", lang, want)
+			}
+		}
+	}
+}
+
+// TestRenderTestEmail tests that the test email names the recipient and requesting admin
+func TestRenderTestEmail(t *testing.T) {
+	renderer, err := NewTemplateRenderer()
+	if err != nil {
+		t.Fatalf("NewTemplateRenderer() error = %v", err)
+	}
+
+	subject, body, err := renderer.RenderTestEmail(&TestEmailData{
+		Email:       "ops@example.com",
+		RequestedBy: "admin@example.com",
+		SentAtUTC:   "2025-07-01T12:30:00Z",
+	})
+	if err != nil {
+		t.Fatalf("RenderTestEmail() error = %v", err)
+	}
+
+	if subject != TestEmailSubject {
+		t.Errorf("subject = %q, want %q", subject, TestEmailSubject)
+	}
+	for _, want := range []string{"ops@example.com", "admin@example.com", "2025-07-01T12:30:00Z"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+}
@@ -111,6 +111,33 @@ func ValidatePastTimestamp(t time.Time, fieldName string) error {
 	return nil
 }
 
+// DefaultMaxClockSkew is the suggested tolerance for client-provided timestamps
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// ValidateTimestampWithinSkew validates that a client-provided timestamp is at most maxSkew
+// away from server time in either direction, so devices with badly wrong clocks cannot
+// distort event ordering. A timestamp exactly maxSkew away is accepted; maxSkew <= 0
+// disables the check.
+func ValidateTimestampWithinSkew(t time.Time, maxSkew time.Duration) error {
+	return validateTimestampWithinSkewAt(t, time.Now().UTC(), maxSkew)
+}
+
+// validateTimestampWithinSkewAt checks t against the given server time
+func validateTimestampWithinSkewAt(t, now time.Time, maxSkew time.Duration) error {
+	if maxSkew <= 0 {
+		return nil
+	}
+
+	skew := t.Sub(now)
+	switch {
+	case skew > maxSkew:
+		return NewValidationError("timestamp", fmt.Sprintf("timestamp is %s ahead of server time (max skew: %s, got: %s)", skew.Round(time.Second), maxSkew, FormatUTCTimestamp(t)))
+	case skew < -maxSkew:
+		return NewValidationError("timestamp", fmt.Sprintf("timestamp is %s behind server time (max skew: %s, got: %s)", (-skew).Round(time.Second), maxSkew, FormatUTCTimestamp(t)))
+	}
+	return nil
+}
+
 // EnsureUTC ensures time is in UTC timezone
 // If time is in different timezone, converts it to UTC
 func EnsureUTC(t time.Time) time.Time {
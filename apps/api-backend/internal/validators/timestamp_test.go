@@ -127,6 +127,46 @@ func TestIsInPast(t *testing.T) {
 	}
 }
 
+// TestValidateTimestampWithinSkew tests the clock-skew tolerance across its boundaries
+func TestValidateTimestampWithinSkew(t *testing.T) {
+	now := time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC)
+	maxSkew := 5 * time.Minute
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		maxSkew   time.Duration
+		wantErr   bool
+	}{
+		{"server time", now, maxSkew, false},
+		{"just inside future skew", now.Add(maxSkew - time.Nanosecond), maxSkew, false},
+		{"exactly future skew", now.Add(maxSkew), maxSkew, false},
+		{"just beyond future skew", now.Add(maxSkew + time.Nanosecond), maxSkew, true},
+		{"exactly past skew", now.Add(-maxSkew), maxSkew, false},
+		{"just beyond past skew", now.Add(-maxSkew - time.Nanosecond), maxSkew, true},
+		{"wildly wrong clock", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), maxSkew, true},
+		{"non-UTC zone within skew", now.In(time.FixedZone("CET", 3600)).Add(time.Minute), maxSkew, false},
+		{"check disabled", now.Add(24 * time.Hour), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimestampWithinSkewAt(tt.timestamp, now, tt.maxSkew)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTimestampWithinSkewAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Against the real clock
+	if err := ValidateTimestampWithinSkew(time.Now(), DefaultMaxClockSkew); err != nil {
+		t.Errorf("ValidateTimestampWithinSkew(now) error = %v", err)
+	}
+	if err := ValidateTimestampWithinSkew(time.Now().Add(-time.Hour), DefaultMaxClockSkew); err == nil {
+		t.Error("ValidateTimestampWithinSkew(now-1h) should return error")
+	}
+}
+
 // TestTimestampRoundtrip tests parsing and formatting roundtrip
 func TestTimestampRoundtrip(t *testing.T) {
 	original := "2025-11-10T14:30:00Z"
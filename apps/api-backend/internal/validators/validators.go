@@ -1,7 +1,10 @@
 package validators
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/netip"
 	"regexp"
 	"strings"
 )
@@ -12,9 +15,42 @@ var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab]
 // MAC address validation regex (uppercase with colons)
 var macRegex = regexp.MustCompile(`^([0-9A-F]{2}:){5}[0-9A-F]{2}$`)
 
+// OUI validation regex (first three MAC octets, uppercase with colons)
+var ouiRegex = regexp.MustCompile(`^([0-9A-F]{2}:){2}[0-9A-F]{2}$`)
+
+// ownerIDRegex matches tenant identifiers: letters, digits, dots, underscores and hyphens
+var ownerIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// customClaimKeyRegex matches custom JWT claim names: a letter followed by letters, digits and underscores
+var customClaimKeyRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
 // Semantic versioning regex (basic)
 var semverRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
+// Maximum lengths of free-text input fields (in bytes)
+// Checked before any format validation so oversized input is rejected cheaply and never stored.
+const (
+	// MaxNodeNameLength caps the human-readable node name
+	MaxNodeNameLength = 100
+	// MaxFirmwareVersionLength caps firmware and target firmware versions
+	MaxFirmwareVersionLength = 64
+	// MaxDescriptionLength caps registration token descriptions
+	MaxDescriptionLength = 500
+	// MaxOwnerIDLength caps the tenant identifier of nodes and registration tokens
+	MaxOwnerIDLength = 64
+)
+
+// Limits of the custom claims a registration token adds to node JWTs
+// Every node request carries its JWT, so the claims are kept small.
+const (
+	// MaxCustomClaims caps the number of custom claims
+	MaxCustomClaims = 16
+	// MaxCustomClaimKeyLength caps the name of one custom claim
+	MaxCustomClaimKeyLength = 64
+	// MaxCustomClaimsSize caps the JSON encoding of all custom claims (in bytes)
+	MaxCustomClaimsSize = 1024
+)
+
 // ValidationError represents a validation error with field context
 type ValidationError struct {
 	Field   string
@@ -108,6 +144,64 @@ func NormalizeMACAddress(mac string) (string, error) {
 	return mac, nil
 }
 
+// NormalizeIP parses an IPv4 or IPv6 address and returns its canonical text form
+// IPv4-mapped IPv6 addresses (::ffff:192.0.2.1) become plain IPv4, IPv6 is lowercase and
+// compressed, and zones are dropped, so one client is always stored the same way.
+func NormalizeIP(ip string) (string, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", NewValidationError("ip", "invalid IP address")
+	}
+
+	return addr.Unmap().WithZone("").String(), nil
+}
+
+// NormalizeOUI converts a manufacturer prefix (OUI) to uppercase with colons
+// Accepts the same separators as NormalizeMACAddress: aa:bb:cc, aa-bb-cc, aabbcc
+func NormalizeOUI(oui string) (string, error) {
+	if oui == "" {
+		return "", NewValidationError("oui", "OUI is required")
+	}
+
+	hex := strings.NewReplacer(":", "", "-", "", ".", "", " ", "").Replace(oui)
+	if len(hex) == 6 {
+		hex = hex[0:2] + ":" + hex[2:4] + ":" + hex[4:6]
+	}
+	hex = strings.ToUpper(hex)
+
+	if !ouiRegex.MatchString(hex) {
+		return "", NewValidationError("oui", fmt.Sprintf("invalid OUI %q (expected three octets, e.g. AA:BB:CC)", oui))
+	}
+	return hex, nil
+}
+
+// MACAddressOUI returns the manufacturer prefix (first three octets) of a MAC address
+// The MAC is normalized first, so any notation accepted by NormalizeMACAddress works.
+func MACAddressOUI(mac string) (string, error) {
+	normalized, err := NormalizeMACAddress(mac)
+	if err != nil {
+		return "", err
+	}
+	return normalized[:8], nil
+}
+
+// NormalizeEmail trims and lowercases an email address and validates its format
+// Only bare addresses are accepted (no display names such as "Admin <admin@example.com>")
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", NewValidationError("email", "email address is required")
+	}
+
+	// Require a dotted domain so typos like "admin@localhost" are rejected
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@"):], ".") {
+		return "", NewValidationError("email", "invalid email address format (expected: name@example.com)")
+	}
+
+	return email, nil
+}
+
 // IsValidLatitude checks if the value is a valid GPS latitude
 // Valid range: -90.0 to 90.0
 func IsValidLatitude(lat float64) bool {
@@ -162,12 +256,116 @@ func ValidateFirmwareVersion(version string, fieldName string) error {
 	if version == "" {
 		return nil // Firmware version is optional
 	}
+	if err := ValidateStringLength(version, fieldName, 0, MaxFirmwareVersionLength); err != nil {
+		return err
+	}
 	if !IsValidSemanticVersion(version) {
 		return NewValidationError(fieldName, "invalid semantic version format (expected: MAJOR.MINOR.PATCH)")
 	}
 	return nil
 }
 
+// CompareSemanticVersions compares two semantic versions by SemVer 2.0 precedence
+// Returns -1 if a < b, 0 if equal, 1 if a > b. Build metadata is ignored;
+// a pre-release version has lower precedence than the release (1.0.0-beta < 1.0.0).
+func CompareSemanticVersions(a, b string) (int, error) {
+	if !IsValidSemanticVersion(a) {
+		return 0, NewValidationError("version", fmt.Sprintf("invalid semantic version: %s", a))
+	}
+	if !IsValidSemanticVersion(b) {
+		return 0, NewValidationError("version", fmt.Sprintf("invalid semantic version: %s", b))
+	}
+
+	aCore, aPre := splitSemanticVersion(a)
+	bCore, bPre := splitSemanticVersion(b)
+
+	// Compare MAJOR.MINOR.PATCH numerically
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < 3; i++ {
+		if c := compareNumeric(aParts[i], bParts[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	// A version without pre-release ranks higher than one with it
+	switch {
+	case aPre == "" && bPre == "":
+		return 0, nil
+	case aPre == "":
+		return 1, nil
+	case bPre == "":
+		return -1, nil
+	}
+
+	// Compare pre-release identifiers left to right
+	aIDs := strings.Split(aPre, ".")
+	bIDs := strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aNum := isNumericIdentifier(aIDs[i])
+		bNum := isNumericIdentifier(bIDs[i])
+		var c int
+		switch {
+		case aNum && bNum:
+			c = compareNumeric(aIDs[i], bIDs[i])
+		case aNum:
+			c = -1 // Numeric identifiers rank lower than alphanumeric
+		case bNum:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+
+	// All shared identifiers equal: the longer pre-release ranks higher
+	switch {
+	case len(aIDs) < len(bIDs):
+		return -1, nil
+	case len(aIDs) > len(bIDs):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// splitSemanticVersion returns the MAJOR.MINOR.PATCH core and the pre-release part (without build metadata)
+func splitSemanticVersion(version string) (core, prerelease string) {
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// isNumericIdentifier checks if a version identifier consists only of digits
+func isNumericIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumeric compares two digit strings without leading zeros by numeric value
+// Works for arbitrarily large numbers without overflow
+func compareNumeric(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
 // IsValidNodeStatus checks if the status is a valid node status
 func IsValidNodeStatus(status string) bool {
 	switch status {
@@ -206,7 +404,49 @@ func ValidateNodeName(name string, fieldName string) error {
 	if name == "" {
 		return nil // Name is optional
 	}
-	return ValidateStringLength(name, fieldName, 1, 100)
+	return ValidateStringLength(name, fieldName, 1, MaxNodeNameLength)
+}
+
+// ValidateDescription validates free-text description constraints
+func ValidateDescription(description string, fieldName string) error {
+	return ValidateStringLength(description, fieldName, 0, MaxDescriptionLength)
+}
+
+// ValidateOwnerID validates a tenant identifier (letters, digits, '.', '_' and '-')
+func ValidateOwnerID(ownerID string, fieldName string) error {
+	if err := ValidateStringLength(ownerID, fieldName, 1, MaxOwnerIDLength); err != nil {
+		return err
+	}
+	if !ownerIDRegex.MatchString(ownerID) {
+		return NewValidationError(fieldName, "may only contain letters, digits, '.', '_' and '-'")
+	}
+	return nil
+}
+
+// ValidateCustomClaims validates the custom JWT claims of a registration token
+// Claim names must match customClaimKeyRegex; values may be any JSON, but the encoded
+// claims must fit in MaxCustomClaimsSize bytes.
+func ValidateCustomClaims(claims map[string]interface{}, fieldName string) error {
+	if len(claims) > MaxCustomClaims {
+		return NewValidationError(fieldName, fmt.Sprintf("must have at most %d claims (got: %d)", MaxCustomClaims, len(claims)))
+	}
+	for key := range claims {
+		if len(key) > MaxCustomClaimKeyLength {
+			return NewValidationError(fieldName, fmt.Sprintf("claim names must be at most %d characters (got: %d)", MaxCustomClaimKeyLength, len(key)))
+		}
+		if !customClaimKeyRegex.MatchString(key) {
+			return NewValidationError(fieldName, fmt.Sprintf("claim name %q must start with a letter and contain only letters, digits and '_'", key))
+		}
+	}
+
+	encoded, err := json.Marshal(claims)
+	if err != nil {
+		return NewValidationError(fieldName, "must be valid JSON")
+	}
+	if len(encoded) > MaxCustomClaimsSize {
+		return NewValidationError(fieldName, fmt.Sprintf("must be at most %d bytes as JSON (got: %d)", MaxCustomClaimsSize, len(encoded)))
+	}
+	return nil
 }
 
 // IsValidBase64JWTSecret checks if the JWT secret is properly base64 encoded
@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -91,6 +92,110 @@ func TestNormalizeMACAddress(t *testing.T) {
 	}
 }
 
+// TestNormalizeIP tests IP address parsing and canonical form
+func TestNormalizeIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		ip      string
+		want    string
+		wantErr bool
+	}{
+		{"ipv4", "203.0.113.7", "203.0.113.7", false},
+		{"ipv4 with spaces", " 203.0.113.7 ", "203.0.113.7", false},
+		{"ipv4-mapped ipv6", "::ffff:203.0.113.7", "203.0.113.7", false},
+		{"ipv6 uppercase and expanded", "2001:0DB8:0000:0000:0000:0000:0000:0001", "2001:db8::1", false},
+		{"ipv6 zone dropped", "fe80::1%eth0", "fe80::1", false},
+		{"host and port", "203.0.113.7:8080", "", true},
+		{"hostname", "example.com", "", true},
+		{"empty string", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeIP(tt.ip)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizeIP(%q) error = %v, wantErr %v", tt.ip, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeIP(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalizeOUI tests manufacturer prefix normalization
+func TestNormalizeOUI(t *testing.T) {
+	tests := []struct {
+		name    string
+		oui     string
+		want    string
+		wantErr bool
+	}{
+		{"uppercase colons", "AA:BB:CC", "AA:BB:CC", false},
+		{"lowercase hyphens", "aa-bb-cc", "AA:BB:CC", false},
+		{"bare hex", "a4cf12", "A4:CF:12", false},
+		{"full MAC", "AA:BB:CC:DD:EE:FF", "", true},
+		{"too short", "AA:BB", "", true},
+		{"not hex", "GG:HH:II", "", true},
+		{"empty string", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeOUI(tt.oui)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizeOUI(%q) error = %v, wantErr %v", tt.oui, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeOUI(%q) = %q, want %q", tt.oui, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMACAddressOUI tests extracting the manufacturer prefix of a MAC address
+func TestMACAddressOUI(t *testing.T) {
+	if got, err := MACAddressOUI("a4-cf-12-34-56-78"); err != nil || got != "A4:CF:12" {
+		t.Errorf("MACAddressOUI() = %q, %v, want A4:CF:12", got, err)
+	}
+	if _, err := MACAddressOUI("not-a-mac"); err == nil {
+		t.Error("MACAddressOUI() accepted an invalid MAC")
+	}
+}
+
+// TestNormalizeEmail tests email normalization and validation
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		want    string
+		wantErr bool
+	}{
+		{"plain address", "admin@example.com", "admin@example.com", false},
+		{"mixed case and whitespace", "  Admin@Example.COM ", "admin@example.com", false},
+		{"plus addressing", "ops+boom@example.co.uk", "ops+boom@example.co.uk", false},
+		{"display name", "Admin <admin@example.com>", "", true},
+		{"missing domain dot", "admin@localhost", "", true},
+		{"missing at", "admin.example.com", "", true},
+		{"empty string", "   ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeEmail(tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizeEmail(%q) error = %v, wantErr %v", tt.email, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestValidateGPSCoordinates tests GPS coordinate validation
 func TestValidateGPSCoordinates(t *testing.T) {
 	tests := []struct {
@@ -153,6 +258,44 @@ func TestIsValidSemanticVersion(t *testing.T) {
 	}
 }
 
+// TestCompareSemanticVersions tests semantic version precedence
+func TestCompareSemanticVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{"equal", "1.2.3", "1.2.3", 0},
+		{"major", "2.0.0", "1.9.9", 1},
+		{"minor", "1.2.0", "1.10.0", -1},
+		{"patch", "1.0.10", "1.0.9", 1},
+		{"release beats prerelease", "1.0.0", "1.0.0-rc.1", 1},
+		{"prerelease below release", "1.0.0-alpha", "1.0.0", -1},
+		{"alpha before beta", "1.0.0-alpha", "1.0.0-beta", -1},
+		{"numeric prerelease", "1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"numeric below alphanumeric", "1.0.0-1", "1.0.0-alpha", -1},
+		{"longer prerelease wins", "1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"build metadata ignored", "1.0.0+build.1", "1.0.0+build.2", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareSemanticVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("CompareSemanticVersions(%q, %q) error = %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareSemanticVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CompareSemanticVersions("1.0", "1.0.0"); err == nil {
+		t.Error("CompareSemanticVersions() with invalid version should return error")
+	}
+}
+
 // TestValidateNodeStatus tests node status validation
 func TestValidateNodeStatus(t *testing.T) {
 	tests := []struct {
@@ -178,3 +321,69 @@ func TestValidateNodeStatus(t *testing.T) {
 		})
 	}
 }
+
+// TestMaxFieldLengths tests that over-length free-text inputs are rejected
+func TestMaxFieldLengths(t *testing.T) {
+	longVersion := "1.0.0-" + strings.Repeat("a", MaxFirmwareVersionLength)
+
+	tests := []struct {
+		name     string
+		validate func(string, string) error
+		value    string
+		wantErr  bool
+	}{
+		{"node name at limit", ValidateNodeName, strings.Repeat("n", MaxNodeNameLength), false},
+		{"node name over limit", ValidateNodeName, strings.Repeat("n", MaxNodeNameLength+1), true},
+		{"firmware version at limit", ValidateFirmwareVersion, longVersion[:MaxFirmwareVersionLength], false},
+		{"firmware version over limit", ValidateFirmwareVersion, longVersion, true},
+		{"firmware version 10KB", ValidateFirmwareVersion, "1.0.0-" + strings.Repeat("a", 10*1024), true},
+		{"description empty", ValidateDescription, "", false},
+		{"description at limit", ValidateDescription, strings.Repeat("d", MaxDescriptionLength), false},
+		{"description over limit", ValidateDescription, strings.Repeat("d", MaxDescriptionLength+1), true},
+		{"owner id", ValidateOwnerID, "acme-corp_1.eu", false},
+		{"owner id empty", ValidateOwnerID, "", true},
+		{"owner id invalid characters", ValidateOwnerID, "acme corp", true},
+		{"owner id over limit", ValidateOwnerID, strings.Repeat("o", MaxOwnerIDLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate(tt.value, "field")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCustomClaims(t *testing.T) {
+	tooMany := map[string]interface{}{}
+	for i := 0; i <= MaxCustomClaims; i++ {
+		tooMany["claim_"+strings.Repeat("x", i)] = i
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]interface{}
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"scalars and objects", map[string]interface{}{"site_id": "berlin-1", "role": "sensor", "floor": 3, "tags": []interface{}{"a"}}, false},
+		{"too many claims", tooMany, true},
+		{"empty name", map[string]interface{}{"": "x"}, true},
+		{"name starts with digit", map[string]interface{}{"1site": "x"}, true},
+		{"name with dot", map[string]interface{}{"site.id": "x"}, true},
+		{"name over limit", map[string]interface{}{strings.Repeat("k", MaxCustomClaimKeyLength+1): "x"}, true},
+		{"size at limit", map[string]interface{}{"v": strings.Repeat("a", MaxCustomClaimsSize-len(`{"v":""}`))}, false},
+		{"size over limit", map[string]interface{}{"v": strings.Repeat("a", MaxCustomClaimsSize)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomClaims(tt.claims, "custom_claims")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustomClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package version holds build information injected at link time.
+//
+// Build with:
+//
+//	go build -ldflags "-X github.com/boomchecker/api-backend/internal/version.Version=0.0.1 \
+//	  -X github.com/boomchecker/api-backend/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/boomchecker/api-backend/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Values not injected fall back to the VCS stamp Go embeds in the binary
+// (commit hash, and the commit time in place of the build time).
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set via -ldflags "-X ..."; see the package documentation
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Unknown is reported for build information that is not available
+const Unknown = "unknown"
+
+// Info is the build information of the running binary
+type Info struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get returns the build information, filling missing values from the embedded VCS stamp
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = Unknown
+	}
+	if info.Commit == "" {
+		info.Commit = Unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = Unknown
+	}
+	return info
+}
@@ -0,0 +1,28 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+)
+
+// TestGet tests that injected values are reported and missing ones are filled in
+func TestGet(t *testing.T) {
+	saved := [3]string{Version, Commit, BuildTime}
+	t.Cleanup(func() { Version, Commit, BuildTime = saved[0], saved[1], saved[2] })
+
+	Version, Commit, BuildTime = "1.2.3", "abc123", "2025-11-10T14:30:00Z"
+	info := Get()
+	if info.Version != "1.2.3" || info.Commit != "abc123" || info.BuildTime != "2025-11-10T14:30:00Z" {
+		t.Errorf("Get() = %+v, want injected values", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("Get().GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+
+	// Test binaries carry no VCS stamp, so missing values become "unknown"
+	Version, Commit, BuildTime = "", "", ""
+	info = Get()
+	if info.Version != Unknown || info.Commit != Unknown || info.BuildTime != Unknown {
+		t.Errorf("Get() = %+v, want %q for missing values", info, Unknown)
+	}
+}
@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // IANA zones for ADMIN_EMAIL_TIMEZONE on hosts without tzdata
 
+	"github.com/boomchecker/api-backend/internal/config"
 	"github.com/boomchecker/api-backend/internal/crypto"
 	"github.com/boomchecker/api-backend/internal/database"
+	"github.com/boomchecker/api-backend/internal/events"
 	"github.com/boomchecker/api-backend/internal/handlers"
 	"github.com/boomchecker/api-backend/internal/middleware"
 	"github.com/boomchecker/api-backend/internal/repositories"
 	"github.com/boomchecker/api-backend/internal/services"
+	"github.com/boomchecker/api-backend/internal/templates"
+	"github.com/boomchecker/api-backend/internal/version"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"
@@ -53,8 +64,26 @@ func main() {
 		log.Println("Loaded .env file")
 	}
 
+	// Startup steps that must complete before /ready reports ready
+	readinessService := services.NewReadinessService(
+		services.ReadinessStepDatabase,
+		services.ReadinessStepCleanupService,
+	)
+
+	// Select where the node JWT secret encryption key comes from (env by default)
+	keyProvider, err := crypto.NewKeyProvider(crypto.KeyProviderConfig{
+		Type:          config.GetEnv("KEY_PROVIDER", crypto.KeyProviderEnv),
+		ExternalURL:   os.Getenv("KEY_PROVIDER_URL"),
+		ExternalKeyID: os.Getenv("KEY_PROVIDER_KEY_ID"),
+	})
+	if err != nil {
+		log.Fatalf("Invalid KEY_PROVIDER: %v", err)
+	}
+	// Reuse the AES-GCM cipher across registrations instead of rebuilding it per secret
+	keyProvider = crypto.NewCachedKeyProvider(keyProvider)
+
 	// Validate encryption key is configured
-	if err := crypto.ValidateEncryptionKey(); err != nil {
+	if err := crypto.ValidateEncryptionKey(keyProvider); err != nil {
 		log.Fatalf("Encryption key validation failed: %v\n"+
 			"Please set JWT_ENCRYPTION_KEY in .env or environment.\n"+
 			"Generate key with: go run scripts/generate_keys.go", err)
@@ -91,11 +120,23 @@ func main() {
 		dbPath = "./data/boomchecker.db"
 		log.Println("DB_PATH not set, using default: ./data/boomchecker.db")
 	}
+	// LOG_LEVEL controls request logging and GORM verbosity (debug also logs SQL)
+	logLevel := config.LogLevel()
+	log.Printf("Log level: %s", logLevel)
+	requestLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+
 	dbConfig := database.DefaultConfig(dbPath)
+	dbConfig.LogLevel = config.GormLogLevel(logLevel)
+	dbConfig.ConnectAttempts = config.GetEnvInt("DB_CONNECT_ATTEMPTS", dbConfig.ConnectAttempts)
+	dbConfig.ConnectRetryInterval = time.Duration(config.GetEnvInt("DB_CONNECT_RETRY_INTERVAL_SECONDS", int(dbConfig.ConnectRetryInterval/time.Second))) * time.Second
 	db, err := database.InitDB(dbConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
+	// InitDB has run migrations; /ready keeps checking that the database is reachable
+	readinessService.MarkReady(services.ReadinessStepDatabase, func(ctx context.Context) error {
+		return database.Ping(db)
+	})
 
 	// Ensure database is closed on shutdown
 	defer func() {
@@ -111,68 +152,339 @@ func main() {
 	// Initialize repositories
 	nodeRepo := repositories.NewNodeRepository(db)
 	tokenRepo := repositories.NewRegistrationTokenRepository(db)
+	eventRepo := repositories.NewRegistrationEventRepository(db)
+	adminRepo := repositories.NewAdminUserRepository(db)
+	telemetryRepo := repositories.NewNodeTelemetryRepository(db)
+	idempotencyRepo := repositories.NewIdempotencyKeyRepository(db)
+	adminTokenRepo := repositories.NewAdminTokenRepository(db)
+	adminLoginCodeRepo := repositories.NewAdminLoginCodeRepository(db)
+	metadataRepo := repositories.NewNodeMetadataRepository(db)
+	schemaMigrationRepo := repositories.NewSchemaMigrationRepository(db)
+	revokedNodeTokenRepo := repositories.NewRevokedNodeTokenRepository(db)
+	settingRepo := repositories.NewSettingRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	deniedMACRepo := repositories.NewDeniedMACRepository(db)
+
+	// In-process pub/sub for live fleet events
+	eventBroker := events.NewBroker()
 
 	// Initialize services
-	registrationService := services.NewNodeRegistrationService(nodeRepo, tokenRepo)
-	tokenManagementService := services.NewTokenManagementService(tokenRepo)
+	auditService := services.NewAuditService(auditLogRepo)
+	registrationConfig := services.DefaultNodeRegistrationConfig()
+	registrationConfig.ReRegistrationCooldown = time.Duration(config.GetEnvInt("RE_REGISTRATION_COOLDOWN_SECONDS", int(registrationConfig.ReRegistrationCooldown/time.Second))) * time.Second
+	registrationConfig.AllowedOUIs = config.GetEnvList("ALLOWED_OUI", registrationConfig.AllowedOUIs)
+	registrationConfig.OneTimeJWT = config.GetEnvBool("NODE_JWT_ONE_TIME", registrationConfig.OneTimeJWT)
+	registrationConfig.FirmwareDowngradePolicy = config.GetEnv("FIRMWARE_DOWNGRADE_POLICY", registrationConfig.FirmwareDowngradePolicy)
+	registrationConfig.FreePreAuthorizedReRegistration = config.GetEnvBool("PREAUTHORIZED_REREGISTRATION_FREE", registrationConfig.FreePreAuthorizedReRegistration)
+	if err := registrationConfig.Validate(); err != nil {
+		log.Fatalf("Invalid node registration settings (ALLOWED_OUI, FIRMWARE_DOWNGRADE_POLICY): %v", err)
+	}
+	tokenValidationMetrics := services.NewTokenValidationMetrics(requestLogger)
+	// NODE_SECRET_CACHE_SIZE > 0 keeps decrypted node secrets in memory to skip per-request decryption
+	secretCacheConfig := services.DefaultNodeSecretCacheConfig()
+	secretCacheConfig.MaxEntries = config.GetEnvInt("NODE_SECRET_CACHE_SIZE", secretCacheConfig.MaxEntries)
+	secretCacheConfig.TTL = time.Duration(config.GetEnvInt("NODE_SECRET_CACHE_TTL_SECONDS", int(secretCacheConfig.TTL/time.Second))) * time.Second
+	nodeSecretCache := services.NewNodeSecretCache(secretCacheConfig)
+	if nodeSecretCache != nil {
+		log.Printf("Node secret cache enabled: up to %d secrets for %s", secretCacheConfig.MaxEntries, secretCacheConfig.TTL)
+	}
+	registrationService := services.NewNodeRegistrationService(nodeRepo, tokenRepo, eventRepo, deniedMACRepo, eventBroker, keyProvider, tokenValidationMetrics, nodeSecretCache, registrationConfig)
+	tokenConfig := services.DefaultTokenManagementConfig()
+	tokenConfig.DefaultExpiryHours = config.GetEnvInt("DEFAULT_TOKEN_EXPIRY_HOURS", tokenConfig.DefaultExpiryHours)
+	tokenConfig.MaxExpiryHours = config.GetEnvInt("MAX_TOKEN_EXPIRY_HOURS", tokenConfig.MaxExpiryHours)
+	tokenConfig.MaxUses = config.GetEnvInt("MAX_TOKEN_USES", tokenConfig.MaxUses)
+	tokenConfig.TokenBytes = config.GetEnvInt("TOKEN_BYTES", tokenConfig.TokenBytes)
+	tokenConfig.IdempotencyKeyTTL = time.Duration(config.GetEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", int(tokenConfig.IdempotencyKeyTTL/time.Hour))) * time.Hour
+	tokenConfig.DeleteRetention = time.Duration(config.GetEnvInt("TOKEN_DELETE_RETENTION_HOURS", int(tokenConfig.DeleteRetention/time.Hour))) * time.Hour
+	tokenConfig.MaxActiveTokens = config.GetEnvInt("MAX_ACTIVE_TOKENS", tokenConfig.MaxActiveTokens)
+	if err := tokenConfig.Validate(); err != nil {
+		log.Fatalf("Invalid registration token policy: %v", err)
+	}
+	tokenManagementService := services.NewTokenManagementService(tokenRepo, eventRepo, idempotencyRepo, auditService, tokenConfig)
+	cleanupConfig := services.DefaultCleanupConfig()
+	cleanupConfig.Interval = time.Duration(config.GetEnvInt("TOKEN_CLEANUP_INTERVAL_MINUTES", int(cleanupConfig.Interval/time.Minute))) * time.Minute
+	cleanupConfig.BatchSize = config.GetEnvInt("TOKEN_CLEANUP_BATCH_SIZE", cleanupConfig.BatchSize)
+	cleanupConfig.DeletedTokenRetention = tokenConfig.DeleteRetention
+	cleanupConfig.InactiveNodeDisableAfter = time.Duration(config.GetEnvInt("NODE_AUTO_DISABLE_AFTER_HOURS", 0)) * time.Hour
+	cleanupService := services.NewCleanupService(tokenRepo, revokedNodeTokenRepo, nodeRepo, settingRepo, auditService, eventBroker, cleanupConfig)
+	nodeConfig := services.DefaultNodeConfig()
+	nodeConfig.UniqueNodeNames = config.GetEnvBool("UNIQUE_NODE_NAMES", nodeConfig.UniqueNodeNames)
+	nodeService := services.NewNodeService(nodeRepo, eventRepo, eventBroker, auditService, keyProvider, nodeSecretCache, nodeConfig)
+	nodeMetadataConfig := services.DefaultNodeMetadataConfig()
+	nodeMetadataConfig.MaxKeys = config.GetEnvInt("NODE_METADATA_MAX_KEYS", nodeMetadataConfig.MaxKeys)
+	nodeMetadataService := services.NewNodeMetadataService(nodeRepo, metadataRepo, nodeMetadataConfig)
+	nodeTokenRevocationService := services.NewNodeTokenRevocationService(nodeRepo, revokedNodeTokenRepo, auditService)
+	macDenylistService := services.NewMACDenylistService(deniedMACRepo, auditService)
+	nodeAuthConfig := services.DefaultNodeAuthConfig()
+	nodeAuthConfig.LastSeenInterval = time.Duration(config.GetEnvInt("NODE_LAST_SEEN_INTERVAL_SECONDS", int(nodeAuthConfig.LastSeenInterval/time.Second))) * time.Second
+	nodeAuthService := services.NewNodeAuthService(nodeRepo, revokedNodeTokenRepo, keyProvider, nodeSecretCache, nodeAuthConfig)
+	nodeTelemetryService := services.NewNodeTelemetryService(telemetryRepo)
+	summaryConfig := services.DefaultSummaryConfig()
+	summaryConfig.InactiveThreshold = time.Duration(config.GetEnvInt("INACTIVE_NODE_THRESHOLD_HOURS", int(summaryConfig.InactiveThreshold/time.Hour))) * time.Hour
+	summaryService := services.NewSummaryService(nodeRepo, tokenRepo, cleanupService, summaryConfig)
+	schemaService := services.NewSchemaService(schemaMigrationRepo, database.LatestSchemaVersion())
+	// ADMIN_EMAILS bootstraps admin access until the first admin is stored in the database
+	adminAuthConfig := services.DefaultAdminAuthConfig()
+	adminAuthConfig.BootstrapEmails = config.GetEnvListOptions("ADMIN_EMAILS", adminAuthConfig.BootstrapEmails, config.ListOptions{Lowercase: true})
+	adminAuthConfig.JWTSecret = os.Getenv("ADMIN_JWT_SECRET")
+	adminAuthConfig.APIBaseURL = config.GetEnv("API_BASE_URL", adminAuthConfig.APIBaseURL)
+	adminAuthConfig.AdminConsoleURL = os.Getenv("ADMIN_CONSOLE_URL")
+	adminAuthConfig.EmailTimezone = config.GetEnv("ADMIN_EMAIL_TIMEZONE", adminAuthConfig.EmailTimezone)
+	adminAuthConfig.EmailLanguage = config.GetEnv("ADMIN_EMAIL_LANGUAGE", adminAuthConfig.EmailLanguage)
+	adminAuthConfig.LoginMethod = config.GetEnv("ADMIN_LOGIN_METHOD", adminAuthConfig.LoginMethod)
+	adminAuthConfig.SecondaryEmails = config.GetEnvMap("ADMIN_SECONDARY_EMAILS", adminAuthConfig.SecondaryEmails)
+	adminAuthConfig.TokenTTL = time.Duration(config.GetEnvInt("ADMIN_TOKEN_TTL_HOURS", int(adminAuthConfig.TokenTTL/time.Hour))) * time.Hour
+	if adminAuthConfig.TokenTTL < time.Hour {
+		log.Fatalf("Invalid ADMIN_TOKEN_TTL_HOURS: must be at least 1")
+	}
+	if adminAuthConfig.JWTSecret != "" {
+		if err := crypto.ValidateAdminJWTSecret(adminAuthConfig.JWTSecret); err != nil {
+			log.Fatalf("Invalid ADMIN_JWT_SECRET: %v", err)
+		}
+	} else {
+		log.Println("WARNING: ADMIN_JWT_SECRET is not set - admin endpoints are UNPROTECTED")
+	}
+	emailConfig := services.DefaultEmailConfig()
+	emailConfig.SMTPHost = os.Getenv("SMTP_HOST")
+	emailConfig.SMTPPort = config.GetEnvInt("SMTP_PORT", emailConfig.SMTPPort)
+	emailConfig.Username = os.Getenv("SMTP_USERNAME")
+	emailConfig.Password = os.Getenv("SMTP_PASSWORD")
+	emailConfig.From = os.Getenv("EMAIL_FROM")
+	emailService := services.NewEmailService(emailConfig)
+	templateRenderer, err := templates.NewTemplateRenderer()
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	adminAuthService := services.NewAdminAuthService(adminRepo, adminTokenRepo, adminLoginCodeRepo, emailService, templateRenderer, auditService, adminAuthConfig)
+	emailDiagnosticsService := services.NewEmailDiagnosticsService(emailService, templateRenderer, adminAuthService)
+
+	// Health checks: database and encryption key always, email only when EMAIL_HEALTH_CHECK is enabled
+	healthService := services.NewHealthService()
+	healthService.Register("database", func(ctx context.Context) error {
+		return database.Ping(db)
+	})
+	healthService.Register("encryption_key", services.NewEncryptionKeyHealthCheck(nodeRepo, keyProvider))
+	emailHealthConfig := services.DefaultEmailHealthConfig()
+	emailHealthConfig.Enabled = config.GetEnvBool("EMAIL_HEALTH_CHECK", emailHealthConfig.Enabled)
+	if emailHealthConfig.Enabled {
+		emailHealthConfig.SMTPHost = os.Getenv("SMTP_HOST")
+		emailHealthConfig.SMTPPort = config.GetEnvInt("SMTP_PORT", emailHealthConfig.SMTPPort)
+		emailHealthConfig.CacheTTL = time.Duration(config.GetEnvInt("EMAIL_HEALTH_CHECK_CACHE_SECONDS", int(emailHealthConfig.CacheTTL/time.Second))) * time.Second
+		emailCheck, err := services.NewEmailHealthCheck(emailHealthConfig)
+		if err != nil {
+			log.Fatalf("Invalid email health check configuration: %v", err)
+		}
+		healthService.Register("email", emailCheck)
+	}
 
 	// Initialize handlers
-	nodeRegistrationHandler := handlers.NewNodeRegistrationHandler(registrationService)
-	tokenManagementHandler := handlers.NewTokenManagementHandler(tokenManagementService)
+	// Off by default so clients sending extra fields keep working; opt in to catch misspelled fields
+	strictJSON := config.GetEnvBool("STRICT_JSON_REQUESTS", false)
+	nodeRegistrationHandler := handlers.NewNodeRegistrationHandler(registrationService, strictJSON)
+	tokenManagementHandler := handlers.NewTokenManagementHandler(tokenManagementService, strictJSON)
+	nodeManagementHandler := handlers.NewNodeManagementHandler(nodeService, nodeMetadataService, nodeTokenRevocationService)
+	nodeHandler := handlers.NewNodeHandler(nodeAuthService, nodeTelemetryService)
+	macDenylistHandler := handlers.NewMACDenylistHandler(macDenylistService)
+	eventStreamHandler := handlers.NewEventStreamHandler(eventBroker)
+	adminUserHandler := handlers.NewAdminUserHandler(adminAuthService)
+	adminAuthHandler := handlers.NewAdminAuthHandler(adminAuthService)
+	healthHandler := handlers.NewHealthHandler(healthService, readinessService)
+	cleanupHandler := handlers.NewCleanupHandler(cleanupService)
+	summaryHandler := handlers.NewSummaryHandler(summaryService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditService)
+	emailHandler := handlers.NewEmailHandler(emailDiagnosticsService)
+	schemaHandler := handlers.NewSchemaHandler(schemaService)
+	metricsHandler := handlers.NewMetricsHandler(tokenValidationMetrics)
+
+	// Create a Gin router with request IDs, panic recovery and leveled request logging
+	router := gin.New()
+	router.Use(
+		middleware.RequestIDMiddleware(),
+		middleware.ResponseEnvelopeMiddleware(config.GetEnvBool("RESPONSE_ENVELOPE", false)),
+		middleware.RequestLoggerMiddleware(requestLogger),
+		middleware.RecoveryMiddleware(requestLogger),
+		middleware.BodyLimitMiddleware(int64(config.GetEnvInt("MAX_REQUEST_BODY_BYTES", int(middleware.DefaultMaxRequestBodyBytes)))),
+	)
 
-	// Create a Gin router with default middleware (logger and recovery)
-	router := gin.Default()
+	// Only honor forwarding headers from trusted proxies so c.ClientIP() cannot be spoofed
+	trustedProxies := config.TrustedProxies()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	log.Printf("Trusted proxies: %v", trustedProxies)
 
 	// Swagger documentation endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Raw OpenAPI spec for SDK generators
+	router.GET("/openapi.json", handlers.OpenAPIHandler)
+
 	// Register health check endpoint
 	router.GET("/ping", handlers.PingHandler)
 
-	// TODO: Add database health check endpoint
-	// router.GET("/health", handlers.HealthCheckHandler(db))
+	// Register subsystem health check endpoint
+	router.GET("/health", healthHandler.Health)
+
+	// Register readiness endpoint (200 only once startup completed)
+	router.GET("/ready", healthHandler.Ready)
+
+	// Register build information endpoint
+	router.GET("/version", handlers.VersionHandler)
+
+	// Register Prometheus metrics endpoint
+	router.GET("/metrics", metricsHandler.Metrics)
 
 	// Register node registration endpoint (public)
-	router.POST("/nodes/register", nodeRegistrationHandler.RegisterNode)
-
-	// TODO: Admin Authentication - Email-based JWT login flow
-	// Current state: Admin endpoints are UNPROTECTED (middleware allows all requests)
-	// Required implementation:
-	//   1. POST /admin/auth/request - Admin provides email, receives JWT via email (24h validity)
-	//   2. Update middleware.AdminAuthMiddleware() to validate JWT from Authorization header
-	//   3. Configure email service (SMTP/SendGrid/etc) for sending login tokens
-	//   4. Add ADMIN_JWT_SECRET to .env (separate from node JWT encryption key)
-	// See internal/middleware/admin_auth.go for detailed implementation plan
-
-	// Register admin endpoints (protected by middleware)
-	// WARNING: Currently unprotected - AdminAuthMiddleware is a placeholder
+	router.POST("/nodes/register", middleware.RequireJSONMiddleware(), nodeRegistrationHandler.RegisterNode)
+
+	// Register MAC status check (requires a valid registration token)
+	router.GET("/nodes/status", nodeRegistrationHandler.GetMACStatus)
+
+	// Node endpoints (protected by node JWT)
+	nodeGroup := router.Group("/nodes")
+	nodeGroup.Use(middleware.NodeAuthMiddleware(nodeAuthService), middleware.RequireJSONMiddleware())
+	{
+		nodeGroup.POST("/heartbeat", nodeHandler.Heartbeat)
+		nodeGroup.POST("/telemetry", nodeHandler.ReportTelemetry)
+	}
+
+	// Admin login (public): emails an admin token valid for ADMIN_TOKEN_TTL_HOURS, or a login code exchanged at /verify, to an authorized address
+	router.POST("/admin/auth/request", middleware.RequireJSONMiddleware(), adminAuthHandler.RequestToken)
+	router.POST("/admin/auth/reissue", middleware.RequireJSONMiddleware(), adminAuthHandler.ReissueToken)
+	router.POST("/admin/auth/resend", middleware.RequireJSONMiddleware(), adminAuthHandler.ResendToken)
+	router.POST("/admin/auth/verify", middleware.RequireJSONMiddleware(), adminAuthHandler.VerifyCode)
+
+	// Register admin endpoints (protected by admin JWT; open when ADMIN_JWT_SECRET is unset)
 	adminGroup := router.Group("/admin")
-	adminGroup.Use(middleware.AdminAuthMiddleware()) // TODO: Implement proper JWT validation
+	if config.GetEnvBool("GZIP_ENABLED", true) {
+		adminGroup.Use(middleware.GzipMiddleware(config.GetEnvInt("GZIP_MIN_BYTES", middleware.DefaultGzipMinBytes)))
+	}
+	adminGroup.Use(middleware.AdminAuthMiddleware(adminAuthService), middleware.RequireJSONMiddleware())
 	{
+		// Dashboard summary
+		adminGroup.GET("/summary", summaryHandler.GetSummary)
+
+		// Database schema version
+		adminGroup.GET("/db/version", schemaHandler.GetVersion)
+
 		// Device registration token management
 		adminGroup.POST("/registration-node-tokens", tokenManagementHandler.CreateToken)
 		adminGroup.GET("/registration-node-tokens", tokenManagementHandler.ListAllTokens)
 		adminGroup.GET("/registration-node-tokens/active", tokenManagementHandler.ListActiveTokens)
+		adminGroup.GET("/registration-node-tokens/search", tokenManagementHandler.SearchTokens)
 		adminGroup.GET("/registration-node-tokens/statistics", tokenManagementHandler.GetStatistics)
+		adminGroup.GET("/registration-node-tokens/statistics/timeline", tokenManagementHandler.GetStatisticsTimeline)
 		adminGroup.POST("/registration-node-tokens/cleanup", tokenManagementHandler.CleanupExpiredTokens)
+		adminGroup.POST("/registration-node-tokens/revoke-all", tokenManagementHandler.RevokeAllTokens)
+		adminGroup.DELETE("/registration-node-tokens", tokenManagementHandler.DeleteTokens)
 		adminGroup.GET("/registration-node-tokens/:token", tokenManagementHandler.GetToken)
+		adminGroup.PATCH("/registration-node-tokens/:token", tokenManagementHandler.UpdateToken)
 		adminGroup.DELETE("/registration-node-tokens/:token", tokenManagementHandler.DeleteToken)
+		adminGroup.POST("/registration-node-tokens/:token/reveal", tokenManagementHandler.RevealToken)
+		adminGroup.POST("/registration-node-tokens/:token/restore", tokenManagementHandler.RestoreToken)
+		adminGroup.POST("/registration-node-tokens/:token/expire", tokenManagementHandler.ExpireToken)
+		adminGroup.GET("/registration-node-tokens/:token/nodes", tokenManagementHandler.ListTokenNodes)
+
+		// Background token cleanup
+		adminGroup.GET("/cleanup/last-run", cleanupHandler.GetLastRun)
+		adminGroup.POST("/cleanup/pause", cleanupHandler.Pause)
+		adminGroup.POST("/cleanup/resume", cleanupHandler.Resume)
+
+		// Node management
+		adminGroup.GET("/nodes", nodeManagementHandler.ListNodes)
+		adminGroup.GET("/nodes/duplicates", nodeManagementHandler.GetDuplicateReport)
+		adminGroup.POST("/nodes/import",
+			middleware.BodyLimitMiddleware(int64(config.GetEnvInt("MAX_IMPORT_REQUEST_BODY_BYTES", int(services.DefaultMaxImportBodyBytes)))),
+			nodeManagementHandler.ImportNodes)
+		adminGroup.GET("/nodes/firmware-distribution", nodeManagementHandler.GetFirmwareDistribution)
+		adminGroup.GET("/nodes/registrations-by-day", nodeManagementHandler.GetRegistrationsByDay)
+		adminGroup.GET("/nodes/by-mac/:mac", nodeManagementHandler.GetNodeByMAC)
+		adminGroup.PUT("/nodes/:uuid/target-firmware", nodeManagementHandler.SetTargetFirmware)
+		adminGroup.GET("/nodes/:uuid", nodeManagementHandler.GetNode)
+		adminGroup.PUT("/nodes/:uuid/status", nodeManagementHandler.UpdateNodeStatus)
+		adminGroup.PUT("/nodes/:uuid/location", nodeManagementHandler.UpdateNodeLocation)
+		adminGroup.PUT("/nodes/:uuid/name", nodeManagementHandler.RenameNode)
+		adminGroup.GET("/nodes/:uuid/metadata", nodeManagementHandler.GetNodeMetadata)
+		adminGroup.PUT("/nodes/:uuid/metadata/:key", nodeManagementHandler.SetNodeMetadata)
+		adminGroup.DELETE("/nodes/:uuid/metadata/:key", nodeManagementHandler.DeleteNodeMetadata)
+		adminGroup.POST("/nodes/:uuid/revoke-token", nodeManagementHandler.RevokeNodeToken)
+		adminGroup.PATCH("/nodes/:uuid", nodeManagementHandler.PatchNode)
+		adminGroup.DELETE("/nodes/:uuid", nodeManagementHandler.ForceDeleteNode)
+
+		// MAC addresses banned from registering
+		adminGroup.GET("/mac-denylist", macDenylistHandler.ListDeniedMACs)
+		adminGroup.POST("/mac-denylist", macDenylistHandler.DenyMAC)
+		adminGroup.DELETE("/mac-denylist/:mac", macDenylistHandler.AllowMAC)
+
+		// Live event stream (Server-Sent Events)
+		adminGroup.GET("/events/stream", eventStreamHandler.Stream)
+
+		// Admin signing key rotation (logs out every admin)
+		adminGroup.POST("/auth/rotate-secret", adminAuthHandler.RotateSecret)
+
+		// Admin token troubleshooting
+		adminGroup.POST("/auth/inspect", adminAuthHandler.InspectToken)
 
-		// TODO: Add admin auth endpoints here when implemented
-		// adminGroup.POST("/auth/request", adminAuthHandler.RequestLogin)
+		// Audit log of security-relevant admin actions
+		adminGroup.GET("/audit-log", auditLogHandler.ListEntries)
+
+		// Email diagnostics
+		adminGroup.POST("/email/test", emailHandler.SendTestEmail)
+
+		// Admin email allowlist
+		adminGroup.GET("/admins", adminUserHandler.ListAdmins)
+		adminGroup.POST("/admins", adminUserHandler.AddAdmin)
+		adminGroup.DELETE("/admins/:email", adminUserHandler.RemoveAdmin)
 	}
 
-	// Start server on port 8080 in a goroutine
+	// Serve HTTPS in-process when TLS_CERT_FILE/TLS_KEY_FILE are set, plain HTTP otherwise
+	certFile, keyFile, tlsEnabled, err := config.TLSFiles()
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: router,
+	}
+	scheme := "http"
+	if tlsEnabled {
+		// Load the key pair now so a bad path or key fails at startup, not in the goroutine
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		server.TLSConfig = config.ServerTLSConfig(certificate)
+		scheme = "https"
+	}
+
+	// Bind port 8080 before serving so /ready is reachable while the remaining startup runs
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("Server failed to start: %v", err)
+	}
 	go func() {
-		if err := router.Run(":8080"); err != nil {
-			log.Fatalf("Server failed to start: %v", err)
+		var err error
+		if tlsEnabled {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil {
+			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	log.Println("Server started on http://localhost:8080")
+	log.Printf("Server started on %s://localhost:8080", scheme)
+
+	// Background jobs start once the server accepts requests; /ready answers 503 until then
+	cleanupService.Start()
+	readinessService.MarkReady(services.ReadinessStepCleanupService, nil)
+	buildInfo := version.Get()
+	log.Printf("Version %s (commit %s, built %s)", buildInfo.Version, buildInfo.Commit, buildInfo.BuildTime)
 	log.Println("Press Ctrl+C to shutdown")
 
 	// Wait for interrupt signal
 	<-quit
 	log.Println("Shutting down server...")
+	cleanupService.Stop()
 }
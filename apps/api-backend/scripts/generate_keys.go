@@ -4,7 +4,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/boomchecker/api-backend/internal/crypto"
 )
@@ -43,15 +42,14 @@ func main() {
 		log.Fatalf("Failed to decode key: %v", err)
 	}
 	
-	// Temporarily set env var for GetEncryptionKey to work
-	os.Setenv(crypto.EnvKeyName, key)
-	
-	encrypted, err := crypto.Encrypt(testSecret, keyBytes)
+	provider := crypto.StaticKeyProvider(keyBytes)
+
+	encrypted, err := crypto.Encrypt(testSecret, provider)
 	if err != nil {
 		log.Fatalf("Encryption test failed: %v", err)
 	}
 
-	decrypted, err := crypto.Decrypt(encrypted, keyBytes)
+	decrypted, err := crypto.Decrypt(encrypted, provider)
 	if err != nil {
 		log.Fatalf("Decryption test failed: %v", err)
 	}